@@ -2,15 +2,27 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
+	_ "time/tzdata" // scheduled commands run in the time zone of their chat, the host may lack the zone database
 
+	"go-telegram-bot/internal/infrastructure/config"
 	"go-telegram-bot/internal/infrastructure/initialize"
 )
 
 func main() {
+	checkConfigOnly := flag.Bool("check-config", false, "Print the effective configuration with secrets redacted, validate it and exit")
+	flag.Parse()
+
+	if *checkConfigOnly {
+		os.Exit(checkConfig())
+	}
+
 	log.Println("Bot is starting...")
 
 	// Initialize container with all dependencies
@@ -24,10 +36,41 @@ func main() {
 	telegramHandler := container.TelegramHandler
 	container.Logger.Info("Delivery layer initialized")
 
+	// Start the admin server serving operational endpoints
+	if container.AdminServer != nil {
+		if err := container.AdminServer.Start(); err != nil {
+			log.Fatalf("Failed to start admin server: %v", err)
+		}
+	}
+
 	// Create context with cancel for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Apply reloadable settings when the config file changes or on SIGHUP
+	if err := container.ConfigWatcher.Start(ctx); err != nil {
+		container.Logger.Warn("Config hot reload disabled", "error", err)
+	}
+
+	// Deliver confirmed broadcasts in the background, resuming the ones interrupted by a restart
+	if container.Config.Broadcast.Enabled {
+		go container.BroadcastService.Run(ctx)
+	}
+
+	// Decline the join requests whose question was left unanswered, join_requests.enabled may be
+	// turned on by a reload so the sweep always runs
+	go container.JoinRequestService.Run(ctx)
+
+	// Run the scheduled commands, each run is claimed by a single instance
+	if container.Config.Scheduler.Enabled {
+		go container.SchedulerService.Run(ctx)
+	}
+
+	// Record the changes of the WAN address and tell the subscribed chats
+	if container.Config.IPMonitor.Enabled {
+		go container.IPMonitorService.Run(ctx)
+	}
+
 	// Setup signal handling for graceful shutdown
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
@@ -55,5 +98,43 @@ func main() {
 
 	// Perform graceful shutdown
 	cancel()
+
+	if container.AdminServer != nil {
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer shutdownCancel()
+		if err := container.AdminServer.Shutdown(shutdownCtx); err != nil {
+			container.Logger.Error("Failed to stop admin server", "error", err)
+		}
+	}
+
+	if container.Tracer != nil {
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer shutdownCancel()
+		if err := container.Tracer.Shutdown(shutdownCtx); err != nil {
+			container.Logger.Error("Failed to flush traces", "error", err)
+		}
+	}
 	log.Println("Bot stopped gracefully.")
 }
+
+// checkConfig prints the effective configuration and every validation problem, returning the exit code
+func checkConfig() int {
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		return 1
+	}
+
+	// Secrets are printed redacted
+	for _, field := range config.Fields() {
+		fmt.Printf("%s = %v\n", field.Key, field.Value(cfg))
+	}
+
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "\nConfiguration is invalid:\n%v\n", err)
+		return 1
+	}
+
+	fmt.Println("\nConfiguration is valid")
+	return 0
+}
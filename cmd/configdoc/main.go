@@ -0,0 +1,29 @@
+// Command configdoc writes the configuration reference generated from the Config struct tags.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"go-telegram-bot/internal/infrastructure/config"
+)
+
+func main() {
+	output := flag.String("o", "configs/REFERENCE.md", "Output file, - for stdout")
+	flag.Parse()
+
+	out := os.Stdout
+	if *output != "-" {
+		file, err := os.Create(*output)
+		if err != nil {
+			log.Fatalf("Failed to create %s: %v", *output, err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if err := config.WriteReference(out); err != nil {
+		log.Fatalf("Failed to write reference: %v", err)
+	}
+}
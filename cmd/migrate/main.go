@@ -16,8 +16,8 @@ func main() {
 	action := flag.String("action", "migrate", "Migrate action: migrate, drop, reset")
 	flag.Parse()
 
-	// Load configuration
-	cfg, err := config.LoadConfig()
+	// Load configuration, the bot settings validated by LoadConfig are not needed to migrate
+	cfg, err := config.ReadConfig()
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
@@ -63,6 +63,19 @@ func runMigration(db *gorm.DB) error {
 		&entity.Chat{},
 		&entity.Message{},
 		&entity.UserProfile{},
+		&entity.RoleAssignment{},
+		&entity.FloodEvent{},
+		&entity.ChatMember{},
+		&entity.JoinRequest{},
+		&entity.JoinRequestDecision{},
+		&entity.Warning{},
+		&entity.InviteLink{},
+		&entity.ChatTag{},
+		&entity.BroadcastJob{},
+		&entity.BroadcastDelivery{},
+		&entity.ScheduledJob{},
+		&entity.IPChange{},
+		&entity.IPSubscription{},
 	)
 }
 
@@ -73,5 +86,18 @@ func dropTables(db *gorm.DB) error {
 		&entity.Chat{},
 		&entity.Message{},
 		&entity.UserProfile{},
+		&entity.RoleAssignment{},
+		&entity.FloodEvent{},
+		&entity.ChatMember{},
+		&entity.JoinRequest{},
+		&entity.JoinRequestDecision{},
+		&entity.Warning{},
+		&entity.InviteLink{},
+		&entity.ChatTag{},
+		&entity.BroadcastJob{},
+		&entity.BroadcastDelivery{},
+		&entity.ScheduledJob{},
+		&entity.IPChange{},
+		&entity.IPSubscription{},
 	)
 }
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"go-telegram-bot/internal/domain/entity"
+	domainErrors "go-telegram-bot/internal/domain/errors"
+	"go-telegram-bot/internal/domain/repository"
+	"go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+)
+
+// AuthorizationServiceImpl implements AuthorizationService with owners from the configuration
+// and every other role from the role assignment repository
+type AuthorizationServiceImpl struct {
+	roleRepo repository.RoleAssignmentRepository
+	owners   atomic.Pointer[map[types.TelegramUserID]struct{}]
+	logger   service.Logger
+}
+
+// NewAuthorizationService creates a new instance of AuthorizationServiceImpl.
+// A nil roleRepo, when the database is unavailable, leaves only the configured owners authorized.
+func NewAuthorizationService(
+	roleRepo repository.RoleAssignmentRepository,
+	owners []types.TelegramUserID,
+	logger service.Logger,
+) *AuthorizationServiceImpl {
+	s := &AuthorizationServiceImpl{
+		roleRepo: roleRepo,
+		logger:   logger,
+	}
+	s.SetOwners(owners)
+	return s
+}
+
+// SetOwners replaces the configured owners, used when the configuration is reloaded
+func (s *AuthorizationServiceImpl) SetOwners(owners []types.TelegramUserID) {
+	set := make(map[types.TelegramUserID]struct{}, len(owners))
+	for _, owner := range owners {
+		set[owner] = struct{}{}
+	}
+	s.owners.Store(&set)
+}
+
+func (s *AuthorizationServiceImpl) isOwner(userID types.TelegramUserID) bool {
+	_, ok := (*s.owners.Load())[userID]
+	return ok
+}
+
+// RoleOf returns the effective role of the user in the chat
+func (s *AuthorizationServiceImpl) RoleOf(
+	ctx context.Context, userID types.TelegramUserID, chatID types.TelegramChatID,
+) (types.Role, error) {
+	if s.isOwner(userID) {
+		return types.RoleOwner, nil
+	}
+	if s.roleRepo == nil || userID == 0 {
+		return types.RoleGuest, nil
+	}
+
+	assignments, err := s.roleRepo.ListForUser(ctx, userID, chatID)
+	if err != nil {
+		return types.RoleGuest, fmt.Errorf("failed to load role assignments: %w", err)
+	}
+
+	role := types.RoleGuest
+	for _, assignment := range assignments {
+		role = types.MaxRole(role, assignment.Role)
+	}
+	return role, nil
+}
+
+// Authorize fails closed: a repository error denies everyone but the configured owners
+func (s *AuthorizationServiceImpl) Authorize(
+	ctx context.Context, userID types.TelegramUserID, chatID types.TelegramChatID, required types.Role,
+) error {
+	role, err := s.RoleOf(ctx, userID, chatID)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("Failed to resolve role, denying access", "user_id", userID, "error", err)
+	}
+
+	if !role.AtLeast(required) {
+		return fmt.Errorf("%w: role %s required, user has %s", domainErrors.ErrPermissionDenied, required, role)
+	}
+	return nil
+}
+
+// Grant assigns role to the user in the chat, or in every chat when chatID is entity.GlobalChatID
+func (s *AuthorizationServiceImpl) Grant(
+	ctx context.Context, granter, userID types.TelegramUserID, chatID types.TelegramChatID, role types.Role,
+) error {
+	if !role.IsValid() || role == types.RoleGuest {
+		return fmt.Errorf("%w: %q", domainErrors.ErrInvalidRole, role)
+	}
+	if role == types.RoleOwner {
+		return fmt.Errorf("%w: owners are configured with auth.owners", domainErrors.ErrInvalidRole)
+	}
+	if s.roleRepo == nil {
+		return domainErrors.ErrServiceUnavailable
+	}
+
+	granterRole, err := s.RoleOf(ctx, granter, chatID)
+	if err != nil {
+		return err
+	}
+	if !granterRole.Outranks(role) {
+		return fmt.Errorf("%w: granting %s requires a higher role than %s", domainErrors.ErrPermissionDenied, role, granterRole)
+	}
+
+	if err := s.checkCanModify(ctx, granterRole, userID, chatID); err != nil {
+		return err
+	}
+
+	return s.roleRepo.Upsert(ctx, entity.NewRoleAssignment(userID, chatID, role, granter))
+}
+
+// Revoke removes the user's assignment in the chat, or the global one when chatID is entity.GlobalChatID
+func (s *AuthorizationServiceImpl) Revoke(
+	ctx context.Context, granter, userID types.TelegramUserID, chatID types.TelegramChatID,
+) error {
+	if s.roleRepo == nil {
+		return domainErrors.ErrServiceUnavailable
+	}
+
+	granterRole, err := s.RoleOf(ctx, granter, chatID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.roleRepo.Get(ctx, userID, chatID); err != nil {
+		return err
+	}
+	if err := s.checkCanModify(ctx, granterRole, userID, chatID); err != nil {
+		return err
+	}
+
+	return s.roleRepo.Delete(ctx, userID, chatID)
+}
+
+// checkCanModify ensures the granter outranks the user's owner status and existing assignment
+func (s *AuthorizationServiceImpl) checkCanModify(
+	ctx context.Context, granterRole types.Role, userID types.TelegramUserID, chatID types.TelegramChatID,
+) error {
+	if s.isOwner(userID) {
+		return fmt.Errorf("%w: owners cannot be changed at runtime", domainErrors.ErrPermissionDenied)
+	}
+
+	existing, err := s.roleRepo.Get(ctx, userID, chatID)
+	if errors.Is(err, domainErrors.ErrRoleAssignmentNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !granterRole.Outranks(existing.Role) {
+		return fmt.Errorf("%w: user already has role %s", domainErrors.ErrPermissionDenied, existing.Role)
+	}
+	return nil
+}
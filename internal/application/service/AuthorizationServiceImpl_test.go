@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-telegram-bot/internal/domain/entity"
+	domainErrors "go-telegram-bot/internal/domain/errors"
+	"go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...any)                         {}
+func (nopLogger) Info(string, ...any)                          {}
+func (nopLogger) Warn(string, ...any)                          {}
+func (nopLogger) Error(string, ...any)                         {}
+func (nopLogger) Fatal(string, ...any)                         {}
+func (nopLogger) Panic(string, ...any)                         {}
+func (l nopLogger) WithContext(context.Context) service.Logger { return l }
+func (l nopLogger) WithField(string, any) service.Logger       { return l }
+func (l nopLogger) WithFields(map[string]any) service.Logger   { return l }
+
+type roleKey struct {
+	user types.TelegramUserID
+	chat types.TelegramChatID
+}
+
+// memoryRoleRepo is an in-memory RoleAssignmentRepository
+type memoryRoleRepo struct {
+	assignments map[roleKey]*entity.RoleAssignment
+	err         error
+}
+
+func newMemoryRoleRepo() *memoryRoleRepo {
+	return &memoryRoleRepo{assignments: make(map[roleKey]*entity.RoleAssignment)}
+}
+
+func (r *memoryRoleRepo) ListForUser(
+	_ context.Context, userID types.TelegramUserID, chatID types.TelegramChatID,
+) ([]*entity.RoleAssignment, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	var result []*entity.RoleAssignment
+	for key, assignment := range r.assignments {
+		if key.user == userID && (key.chat == chatID || key.chat == entity.GlobalChatID) {
+			result = append(result, assignment)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryRoleRepo) Get(
+	_ context.Context, userID types.TelegramUserID, chatID types.TelegramChatID,
+) (*entity.RoleAssignment, error) {
+	assignment, ok := r.assignments[roleKey{userID, chatID}]
+	if !ok {
+		return nil, domainErrors.ErrRoleAssignmentNotFound
+	}
+	return assignment, nil
+}
+
+func (r *memoryRoleRepo) Upsert(_ context.Context, assignment *entity.RoleAssignment) error {
+	r.assignments[roleKey{assignment.TelegramUserID, assignment.TelegramChatID}] = assignment
+	return nil
+}
+
+func (r *memoryRoleRepo) Delete(_ context.Context, userID types.TelegramUserID, chatID types.TelegramChatID) error {
+	if _, ok := r.assignments[roleKey{userID, chatID}]; !ok {
+		return domainErrors.ErrRoleAssignmentNotFound
+	}
+	delete(r.assignments, roleKey{userID, chatID})
+	return nil
+}
+
+func (r *memoryRoleRepo) ListByChat(_ context.Context, chatID types.TelegramChatID) ([]*entity.RoleAssignment, error) {
+	var result []*entity.RoleAssignment
+	for key, assignment := range r.assignments {
+		if key.chat == chatID {
+			result = append(result, assignment)
+		}
+	}
+	return result, nil
+}
+
+const (
+	owner types.TelegramUserID = 1
+	admin types.TelegramUserID = 2
+	user  types.TelegramUserID = 3
+	chat  types.TelegramChatID = -100
+)
+
+func TestAuthorizationService_RoleResolution(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryRoleRepo()
+	auth := NewAuthorizationService(repo, []types.TelegramUserID{owner}, nopLogger{})
+
+	if err := auth.Grant(ctx, owner, admin, chat, types.RoleAdmin); err != nil {
+		t.Fatalf("owner grant failed: %v", err)
+	}
+	if err := auth.Grant(ctx, owner, user, entity.GlobalChatID, types.RoleMember); err != nil {
+		t.Fatalf("global grant failed: %v", err)
+	}
+
+	cases := []struct {
+		user types.TelegramUserID
+		chat types.TelegramChatID
+		want types.Role
+	}{
+		{owner, 42, types.RoleOwner},
+		{admin, chat, types.RoleAdmin},
+		{admin, 42, types.RoleGuest},
+		{user, 42, types.RoleMember},
+		{99, chat, types.RoleGuest},
+	}
+	for _, c := range cases {
+		role, err := auth.RoleOf(ctx, c.user, c.chat)
+		if err != nil || role != c.want {
+			t.Errorf("RoleOf(%d, %d) = %s, %v; want %s", c.user, c.chat, role, err, c.want)
+		}
+	}
+
+	if err := auth.Authorize(ctx, admin, 42, types.RoleAdmin); !errors.Is(err, domainErrors.ErrPermissionDenied) {
+		t.Errorf("expected permission denied outside the admin's chat, got %v", err)
+	}
+}
+
+func TestAuthorizationService_GrantRequiresHigherRole(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryRoleRepo()
+	auth := NewAuthorizationService(repo, []types.TelegramUserID{owner}, nopLogger{})
+	_ = auth.Grant(ctx, owner, admin, chat, types.RoleAdmin)
+
+	if err := auth.Grant(ctx, admin, user, chat, types.RoleMember); err != nil {
+		t.Fatalf("admin should grant member: %v", err)
+	}
+	if err := auth.Grant(ctx, admin, user, chat, types.RoleAdmin); !errors.Is(err, domainErrors.ErrPermissionDenied) {
+		t.Errorf("admin must not grant admin, got %v", err)
+	}
+	if err := auth.Grant(ctx, admin, user, entity.GlobalChatID, types.RoleMember); !errors.Is(err, domainErrors.ErrPermissionDenied) {
+		t.Errorf("chat admin must not grant globally, got %v", err)
+	}
+	if err := auth.Grant(ctx, owner, user, chat, types.RoleOwner); !errors.Is(err, domainErrors.ErrInvalidRole) {
+		t.Errorf("owner role must not be grantable, got %v", err)
+	}
+	if err := auth.Revoke(ctx, admin, owner, chat); !errors.Is(err, domainErrors.ErrRoleAssignmentNotFound) {
+		t.Errorf("expected no assignment for a configured owner, got %v", err)
+	}
+	if err := auth.Revoke(ctx, user, admin, chat); !errors.Is(err, domainErrors.ErrPermissionDenied) {
+		t.Errorf("member must not revoke an admin, got %v", err)
+	}
+	if err := auth.Revoke(ctx, owner, admin, chat); err != nil {
+		t.Errorf("owner should revoke admin: %v", err)
+	}
+}
+
+func TestAuthorizationService_FailsClosed(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryRoleRepo()
+	auth := NewAuthorizationService(repo, []types.TelegramUserID{owner}, nopLogger{})
+	_ = auth.Grant(ctx, owner, admin, chat, types.RoleAdmin)
+
+	repo.err = errors.New("connection refused")
+	if err := auth.Authorize(ctx, admin, chat, types.RoleMember); !errors.Is(err, domainErrors.ErrPermissionDenied) {
+		t.Errorf("expected permission denied on repository error, got %v", err)
+	}
+	if err := auth.Authorize(ctx, owner, chat, types.RoleOwner); err != nil {
+		t.Errorf("owners must stay authorized on repository error: %v", err)
+	}
+
+	auth.SetOwners(nil)
+	if err := auth.Authorize(ctx, owner, chat, types.RoleMember); !errors.Is(err, domainErrors.ErrPermissionDenied) {
+		t.Errorf("expected removed owner to be denied, got %v", err)
+	}
+}
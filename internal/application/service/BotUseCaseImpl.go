@@ -2,63 +2,334 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
 	usecase "go-telegram-bot/internal/application/usecase/command"
+	domainErrors "go-telegram-bot/internal/domain/errors"
 	"go-telegram-bot/internal/domain/service"
 	"go-telegram-bot/internal/domain/types"
+	"go-telegram-bot/internal/shared/tracing"
 )
 
 // BotUseCaseImpl implements BotUseCase interface
 type BotUseCaseImpl struct {
 	ipService   service.IPService
 	telegramBot service.TelegramBotService
+	auth        service.AuthorizationService
+	membership  service.MembershipService
+	joins       service.JoinRequestService
+	moderation  service.ModerationService
+	invites     service.InviteLinkService
+	broadcasts  service.BroadcastService
+	scheduler   service.SchedulerService
+	reminders   service.ReminderService
+	ipMonitor   service.IPMonitorService
+	ddns        service.DDNSService
+	router      *CommandRouter
 	logger      service.Logger
+
+	// username of the bot, resolved with getMe on the first addressed command
+	usernameMu sync.Mutex
+	username   string
 }
 
 // NewBotUseCaseImpl creates a new instance of BotUseCaseImpl
 func NewBotUseCaseImpl(
 	ipService service.IPService,
 	telegramBot service.TelegramBotService,
+	auth service.AuthorizationService,
+	membership service.MembershipService,
+	joins service.JoinRequestService,
+	moderation service.ModerationService,
+	invites service.InviteLinkService,
+	broadcasts service.BroadcastService,
+	scheduler service.SchedulerService,
+	reminders service.ReminderService,
+	ipMonitor service.IPMonitorService,
+	ddns service.DDNSService,
 	logger service.Logger,
 ) service.BotUseCase {
-	return &BotUseCaseImpl{
+	u := &BotUseCaseImpl{
 		ipService:   ipService,
 		telegramBot: telegramBot,
+		auth:        auth,
+		membership:  membership,
+		joins:       joins,
+		moderation:  moderation,
+		invites:     invites,
+		broadcasts:  broadcasts,
+		scheduler:   scheduler,
+		reminders:   reminders,
+		ipMonitor:   ipMonitor,
+		ddns:        ddns,
+		router:      NewCommandRouter(),
 		logger:      logger,
 	}
+	u.registerRoutes()
+	return u
+}
+
+// registerRoutes declares every command with the role required to run it
+func (u *BotUseCaseImpl) registerRoutes() {
+	u.router.Register(Route{
+		Command:     types.CommandStart,
+		Role:        types.RoleGuest,
+		Description: "Bắt đầu sử dụng bot",
+		Handler: func(ctx context.Context, req *types.CommandRequest) error {
+			_, err := u.HandleStartCommand(ctx, req.ChatID)
+			return err
+		},
+	})
+	u.router.Register(Route{
+		Command:     types.CommandHelp,
+		Role:        types.RoleGuest,
+		Description: "Hiển thị hướng dẫn này",
+		Handler: func(ctx context.Context, req *types.CommandRequest) error {
+			role, err := u.auth.RoleOf(ctx, req.UserID, req.ChatID)
+			if err != nil {
+				u.logger.WithContext(ctx).Warn("Failed to resolve role for /help", "error", err)
+			}
+			_, err = usecase.HelpHandler(ctx, req.ChatID, u.router.Available(role), u.telegramBot)
+			return err
+		},
+	})
+	u.router.Register(Route{
+		Command:     types.CommandGetHomeIP,
+		Role:        types.RoleAdmin,
+		Description: "Xem thông tin IP local và WAN của máy",
+		Handler: func(ctx context.Context, req *types.CommandRequest) error {
+			_, err := u.HandleHomeIPCommand(ctx, req.ChatID)
+			return err
+		},
+	})
+	u.router.Register(Route{
+		Command:     types.CommandWhoAmI,
+		Role:        types.RoleGuest,
+		Description: "Xem ID và vai trò của bạn",
+		Handler: func(ctx context.Context, req *types.CommandRequest) error {
+			_, err := usecase.WhoAmIHandler(ctx, req, u.auth, u.telegramBot)
+			return err
+		},
+	})
+	u.router.Register(Route{
+		Command:     types.CommandGrant,
+		Role:        types.RoleAdmin,
+		Description: "Cấp vai trò cho người dùng",
+		Handler: func(ctx context.Context, req *types.CommandRequest) error {
+			_, err := usecase.GrantHandler(ctx, req, u.auth, u.telegramBot)
+			return err
+		},
+	})
+	u.router.Register(Route{
+		Command:     types.CommandRevoke,
+		Role:        types.RoleAdmin,
+		Description: "Thu hồi vai trò của người dùng",
+		Handler: func(ctx context.Context, req *types.CommandRequest) error {
+			_, err := usecase.RevokeHandler(ctx, req, u.auth, u.telegramBot)
+			return err
+		},
+	})
+	u.registerModerationRoutes()
+	u.registerInviteLinkRoutes()
+	u.registerBroadcastRoutes()
+	u.registerScheduleRoutes()
+	u.registerReminderRoutes()
+	u.registerIPMonitorRoutes()
+}
+
+// registerModerationRoutes declares the group moderation commands, all reserved to admins
+func (u *BotUseCaseImpl) registerModerationRoutes() {
+	for _, route := range []struct {
+		command     types.Command
+		description string
+		handler     func(context.Context, *types.CommandRequest, service.ModerationService, service.TelegramBotService) (*types.SendMessageResponse, error)
+	}{
+		{types.CommandBan, "Cấm người dùng khỏi nhóm", usecase.BanHandler},
+		{types.CommandUnban, "Bỏ cấm người dùng", usecase.UnbanHandler},
+		{types.CommandMute, "Cấm chat người dùng trong một thời gian", usecase.MuteHandler},
+		{types.CommandWarn, "Cảnh cáo người dùng, đủ số lần sẽ bị cấm", usecase.WarnHandler},
+		{types.CommandPurge, "Xoá các tin nhắn từ tin nhắn được trả lời", usecase.PurgeHandler},
+	} {
+		handler := route.handler
+		u.router.Register(Route{
+			Command:     route.command,
+			Role:        types.RoleAdmin,
+			Description: route.description,
+			Handler: func(ctx context.Context, req *types.CommandRequest) error {
+				_, err := handler(ctx, req, u.moderation, u.telegramBot)
+				return err
+			},
+		})
+	}
+}
+
+// registerInviteLinkRoutes declares the invite link commands, all reserved to admins
+func (u *BotUseCaseImpl) registerInviteLinkRoutes() {
+	for _, route := range []struct {
+		command     types.Command
+		description string
+		handler     func(context.Context, *types.CommandRequest, service.InviteLinkService, service.TelegramBotService) (*types.SendMessageResponse, error)
+	}{
+		{types.CommandInviteCreate, "Tạo link mời có tên, thời hạn và giới hạn người", usecase.InviteCreateHandler},
+		{types.CommandInviteList, "Xem các link mời và số người đã tham gia", usecase.InviteListHandler},
+		{types.CommandInviteRevoke, "Thu hồi link mời", usecase.InviteRevokeHandler},
+	} {
+		handler := route.handler
+		u.router.Register(Route{
+			Command:     route.command,
+			Role:        types.RoleAdmin,
+			Description: route.description,
+			Handler: func(ctx context.Context, req *types.CommandRequest) error {
+				_, err := handler(ctx, req, u.invites, u.telegramBot)
+				return err
+			},
+		})
+	}
+}
+
+// registerBroadcastRoutes declares the broadcast commands, the service also requires a global admin
+// for all but /chat_tag which labels the chat of its admin
+func (u *BotUseCaseImpl) registerBroadcastRoutes() {
+	for _, route := range []struct {
+		command     types.Command
+		description string
+		handler     func(context.Context, *types.CommandRequest, service.BroadcastService, service.TelegramBotService) (*types.SendMessageResponse, error)
+	}{
+		{types.CommandBroadcast, "Gửi thông báo đến mọi cuộc trò chuyện, có xem trước", usecase.BroadcastHandler},
+		{types.CommandBroadcastStatus, "Xem tiến độ gửi thông báo", usecase.BroadcastStatusHandler},
+		{types.CommandBroadcastCancel, "Huỷ thông báo chưa gửi xong", usecase.BroadcastCancelHandler},
+		{types.CommandChatTag, "Gắn nhãn cuộc trò chuyện để nhận thông báo theo nhãn", usecase.ChatTagHandler},
+	} {
+		handler := route.handler
+		u.router.Register(Route{
+			Command:     route.command,
+			Role:        types.RoleAdmin,
+			Description: route.description,
+			Handler: func(ctx context.Context, req *types.CommandRequest) error {
+				_, err := handler(ctx, req, u.broadcasts, u.telegramBot)
+				return err
+			},
+		})
+	}
+}
+
+// registerScheduleRoutes declares the scheduler commands. /timezone is open to everyone for their
+// private chat, the service requires an admin to change the time zone of a group.
+func (u *BotUseCaseImpl) registerScheduleRoutes() {
+	for _, route := range []struct {
+		command     types.Command
+		role        types.Role
+		description string
+		handler     func(context.Context, *types.CommandRequest, service.SchedulerService, service.TelegramBotService) (*types.SendMessageResponse, error)
+	}{
+		{types.CommandSchedule, types.RoleAdmin, "Hẹn giờ chạy một lệnh, một lần hoặc theo lịch cron", usecase.ScheduleHandler},
+		{types.CommandSchedules, types.RoleAdmin, "Xem các lệnh đã hẹn giờ", usecase.SchedulesHandler},
+		{types.CommandUnschedule, types.RoleAdmin, "Xoá lệnh đã hẹn giờ", usecase.UnscheduleHandler},
+		{types.CommandTimezone, types.RoleGuest, "Xem hoặc đổi múi giờ của cuộc trò chuyện", usecase.TimezoneHandler},
+	} {
+		handler := route.handler
+		u.router.Register(Route{
+			Command:     route.command,
+			Role:        route.role,
+			Description: route.description,
+			Handler: func(ctx context.Context, req *types.CommandRequest) error {
+				_, err := handler(ctx, req, u.scheduler, u.telegramBot)
+				return err
+			},
+		})
+	}
+}
+
+// registerReminderRoutes declares the reminder commands, open to everyone for their own reminders
+func (u *BotUseCaseImpl) registerReminderRoutes() {
+	for _, route := range []struct {
+		command     types.Command
+		description string
+		handler     func(context.Context, *types.CommandRequest, service.ReminderService, service.TelegramBotService) (*types.SendMessageResponse, error)
+	}{
+		{types.CommandRemind, "Đặt nhắc nhở, ví dụ /remind 2h kiểm tra router", usecase.RemindHandler},
+		{types.CommandReminders, "Xem và huỷ các nhắc nhở của bạn", usecase.RemindersHandler},
+	} {
+		handler := route.handler
+		u.router.Register(Route{
+			Command:     route.command,
+			Role:        types.RoleGuest,
+			Description: route.description,
+			Handler: func(ctx context.Context, req *types.CommandRequest) error {
+				_, err := handler(ctx, req, u.reminders, u.telegramBot)
+				return err
+			},
+		})
+	}
+}
+
+// registerIPMonitorRoutes declares the commands of the WAN address monitor and of DDNS, they reveal
+// the address like /home_ip so they require the same role
+func (u *BotUseCaseImpl) registerIPMonitorRoutes() {
+	for _, route := range []struct {
+		command     types.Command
+		description string
+		handler     func(context.Context, *types.CommandRequest, service.IPMonitorService, service.TelegramBotService) (*types.SendMessageResponse, error)
+	}{
+		{types.CommandIPSubscribe, "Nhận thông báo khi IP WAN thay đổi", usecase.IPSubscribeHandler},
+		{types.CommandIPUnsubscribe, "Tắt thông báo khi IP WAN thay đổi", usecase.IPUnsubscribeHandler},
+		{types.CommandIPHistory, "Xem lịch sử IP WAN", usecase.IPHistoryHandler},
+	} {
+		handler := route.handler
+		u.router.Register(Route{
+			Command:     route.command,
+			Role:        types.RoleAdmin,
+			Description: route.description,
+			Handler: func(ctx context.Context, req *types.CommandRequest) error {
+				_, err := handler(ctx, req, u.ipMonitor, u.telegramBot)
+				return err
+			},
+		})
+	}
+	u.router.Register(Route{
+		Command:     types.CommandDDNSStatus,
+		Role:        types.RoleAdmin,
+		Description: "Xem kết quả cập nhật DDNS gần nhất",
+		Handler: func(ctx context.Context, req *types.CommandRequest) error {
+			_, err := usecase.DDNSStatusHandler(ctx, req, u.ddns, u.telegramBot)
+			return err
+		},
+	})
 }
 
 // HandleHomeIPCommand processes the /home_ip command
 func (u *BotUseCaseImpl) HandleHomeIPCommand(
 	ctx context.Context, chatID types.TelegramChatID,
 ) (*types.SendMessageResponse, error) {
-	u.logger.Info("Handling /home_ip command", "chat_id", chatID)
-	return usecase.HomeIPHandler(ctx, chatID, u.ipService, u.logger, u.telegramBot)
+	u.logger.WithContext(ctx).Info("Handling /home_ip command", "chat_id", chatID)
+	return usecase.HomeIPHandler(ctx, chatID, u.ipService, u.logger.WithContext(ctx), u.telegramBot)
 }
 
 // HandleStartCommand processes the /start command
 func (u *BotUseCaseImpl) HandleStartCommand(
 	ctx context.Context, chatID types.TelegramChatID,
 ) (*types.SendMessageResponse, error) {
-	u.logger.Info("Handling /start command", "chat_id", chatID)
+	u.logger.WithContext(ctx).Info("Handling /start command", "chat_id", chatID)
 	return usecase.StartHandler(ctx, chatID, u.telegramBot)
 }
 
-// HandleHelpCommand processes the /help command
+// HandleHelpCommand processes the /help command, listing the commands available to guests
 func (u *BotUseCaseImpl) HandleHelpCommand(
 	ctx context.Context, chatID types.TelegramChatID,
 ) (*types.SendMessageResponse, error) {
-	u.logger.Info("Handling /help command", "chat_id", chatID)
-	return usecase.HelpHandler(ctx, chatID, u.telegramBot)
+	u.logger.WithContext(ctx).Info("Handling /help command", "chat_id", chatID)
+	return usecase.HelpHandler(ctx, chatID, u.router.Available(types.RoleGuest), u.telegramBot)
 }
 
 // HandleUnknownCommand processes unknown or invalid commands
 func (u *BotUseCaseImpl) HandleUnknownCommand(
 	ctx context.Context, chatID types.TelegramChatID,
 ) (*types.SendMessageResponse, error) {
-	u.logger.Info("Handling unknown command", "chat_id", chatID)
+	u.logger.WithContext(ctx).Info("Handling unknown command", "chat_id", chatID)
 	return usecase.UnknownHandler(ctx, chatID, u.telegramBot)
 }
 
@@ -66,74 +337,165 @@ func (u *BotUseCaseImpl) HandleUnknownCommand(
 func (u *BotUseCaseImpl) ProcessUpdate(
 	ctx context.Context, update types.TelegramUpdate,
 ) error {
-	// Check if the update contains a message
-	if update.Message == nil {
-		return nil // ignore non-message updates
+	switch {
+	case update.MyChatMember != nil:
+		return u.membership.HandleMyChatMember(ctx, update.MyChatMember)
+	case update.ChatMember != nil:
+		if err := u.membership.HandleChatMember(ctx, update.ChatMember); err != nil {
+			return err
+		}
+		return u.invites.RecordJoin(ctx, update.ChatMember)
+	case update.ChatJoinRequest != nil:
+		return u.joins.HandleJoinRequest(ctx, update.ChatJoinRequest)
+	case update.CallbackQuery != nil:
+		return u.handleCallback(ctx, update.CallbackQuery)
+	case update.Message == nil:
+		return nil // ignore other non-message updates
+	case update.Message.IsMembershipChange():
+		return u.membership.HandleServiceMessage(ctx, update.Message)
 	}
 
+	ctx, routeSpan := tracing.Start(ctx, "router")
+	defer routeSpan.End()
+	logger := u.logger.WithContext(ctx)
+
 	message := update.Message
-	chatID := message.Chat.ID
-	text := message.Text
-
-	u.logger.Info("Received message", "chat_id", chatID, "text", text)
-
-	command, _ := u.extractCommand(text)
-	fmt.Println("Extracted command:", command)
-
-	var err error = nil
-
-	switch command {
-	case "/home_ip":
-		_, err = u.HandleHomeIPCommand(ctx, chatID)
-	case "/start":
-		_, err = u.HandleStartCommand(ctx, chatID)
-	case "/help":
-		_, err = u.HandleHelpCommand(ctx, chatID)
-	default:
-		if command != "" {
-			_, err = u.HandleUnknownCommand(ctx, chatID)
+	logger.Info("Received message", "chat_id", message.Chat.ID, "text", message.Text)
+
+	req := types.NewCommandRequest(message)
+	if req == nil {
+		// Private messages which are not commands may answer a join request question
+		if message.Chat != nil && message.Chat.Type == types.ChatTypePrivate {
+			_, err := u.joins.HandleAnswer(ctx, message)
 			return err
 		}
 		return nil
 	}
+	logger.Debug("Extracted command", "command", req.Command, "user_id", req.UserID)
 
-	return err
+	// In a group "/ban@other_bot" is meant for another bot
+	if req.Mention != "" && !u.isAddressedToMe(ctx, req.Mention) {
+		logger.Debug("Ignoring command addressed to another bot", "command", req.Command, "mention", req.Mention)
+		return nil
+	}
+
+	return u.dispatch(ctx, routeSpan, req)
 }
 
-// ValidateUpdate validates the structure and content of an update
-func (u *BotUseCaseImpl) ValidateUpdate(update types.TelegramUpdate) error {
-	if update.UpdateID == 0 {
-		return fmt.Errorf("invalid update: missing update ID")
+// isAddressedToMe reports whether mention is the username of the bot. When getMe fails the command
+// is ignored, it cannot be told apart from one for another bot.
+func (u *BotUseCaseImpl) isAddressedToMe(ctx context.Context, mention string) bool {
+	u.usernameMu.Lock()
+	defer u.usernameMu.Unlock()
+	if u.username == "" {
+		response, err := u.telegramBot.GetMeWithResponse(ctx)
+		if err != nil || response.Result == nil || response.Result.Username == nil {
+			u.logger.WithContext(ctx).Warn("Failed to resolve the bot username", "error", err)
+			return false
+		}
+		u.username = *response.Result.Username
 	}
+	return strings.EqualFold(mention, u.username)
+}
 
-	if update.Message != nil {
-		if int64(update.Message.Chat.ID) == 0 {
-			return fmt.Errorf("invalid update: missing chat ID")
-		}
+// RunCommand runs the text of a scheduled job as a command sent by the user in the chat, the user
+// must still hold the role the command requires
+func (u *BotUseCaseImpl) RunCommand(
+	ctx context.Context,
+	chatID types.TelegramChatID,
+	chatType types.ChatType,
+	userID types.TelegramUserID,
+	text string,
+) error {
+	req := types.NewCommandRequest(&types.TelegramMessage{
+		Text: &text,
+		Chat: &types.TelegramChat{ID: chatID, Type: chatType},
+		From: &types.TelegramUser{ID: userID},
+	})
+	if req == nil {
+		return fmt.Errorf("%w: %q is not a command", domainErrors.ErrInvalidInput, text)
 	}
 
-	return nil
+	ctx, routeSpan := tracing.Start(ctx, "scheduler")
+	defer routeSpan.End()
+	u.logger.WithContext(ctx).Info("Running scheduled command", "chat_id", chatID, "user_id", userID, "text", text)
+
+	return u.dispatch(ctx, routeSpan, req)
+}
+
+// dispatch runs the handler of the command, recording its latency and errors
+func (u *BotUseCaseImpl) dispatch(ctx context.Context, routeSpan *tracing.Span, req *types.CommandRequest) error {
+	route, found := u.router.Lookup(req.Command)
+	label := string(req.Command)
+	if !found {
+		label = commandLabelUnknown
+	}
+	routeSpan.Root().SetAttributes(tracing.String("telegram.command", label))
+	start := time.Now()
+
+	ctx, handlerSpan := tracing.Start(ctx, "handler "+label, tracing.String("telegram.command", label))
+	defer handlerSpan.End()
+
+	var err error
+	if found {
+		err = u.authorizeAndRun(ctx, route, req)
+	} else {
+		_, err = u.HandleUnknownCommand(ctx, req.ChatID)
+	}
+
+	handlerDuration.With(label).ObserveDuration(time.Since(start))
+	if err != nil {
+		handlerErrors.With(label).Inc()
+		handlerSpan.RecordError(err)
+	}
+
+	return err
 }
 
-// ExtractCommand extracts command from message text
-func (u *BotUseCaseImpl) extractCommand(text *string) (command string, args []string) {
-	if text == nil || *text == "" {
-		return "", nil
+// authorizeAndRun runs the route handler when the user holds the required role, otherwise tells them why not
+func (u *BotUseCaseImpl) authorizeAndRun(ctx context.Context, route Route, req *types.CommandRequest) error {
+	err := u.auth.Authorize(ctx, req.UserID, req.ChatID, route.Role)
+	if errors.Is(err, domainErrors.ErrPermissionDenied) {
+		u.logger.WithContext(ctx).Warn("Permission denied",
+			"command", req.Command, "user_id", req.UserID, "chat_id", req.ChatID, "required_role", route.Role)
+		tracing.SpanFromContext(ctx).AddEvent("permission_denied",
+			tracing.String("auth.required_role", string(route.Role)))
+		_, err = usecase.PermissionDeniedHandler(ctx, req, route.Role, u.telegramBot)
+		return err
+	}
+	if err != nil {
+		return err
 	}
 
-	if !strings.HasPrefix(*text, "/") {
-		return "", nil
+	return route.Handler(ctx, req)
+}
+
+// handleCallback routes a pressed inline keyboard button by the prefix of its data
+func (u *BotUseCaseImpl) handleCallback(ctx context.Context, query *types.TelegramCallbackQuery) error {
+	switch {
+	case query.Data == nil:
+	case strings.HasPrefix(*query.Data, JoinRequestCallbackPrefix):
+		return u.joins.HandleCallback(ctx, query)
+	case strings.HasPrefix(*query.Data, BroadcastCallbackPrefix):
+		return u.broadcasts.HandleCallback(ctx, query)
+	case strings.HasPrefix(*query.Data, ReminderCallbackPrefix):
+		return u.reminders.HandleCallback(ctx, query)
 	}
+	u.logger.WithContext(ctx).Debug("Ignoring callback query", "data", query.Data)
+	return nil
+}
 
-	parts := strings.Fields(*text)
-	if len(parts) == 0 {
-		return "", nil
+// ValidateUpdate validates the structure and content of an update
+func (u *BotUseCaseImpl) ValidateUpdate(update types.TelegramUpdate) error {
+	if update.UpdateID == 0 {
+		return fmt.Errorf("invalid update: missing update ID")
 	}
 
-	command = parts[0]
-	if len(parts) > 1 {
-		args = parts[1:]
+	if update.Message != nil {
+		if int64(update.Message.Chat.ID) == 0 {
+			return fmt.Errorf("invalid update: missing chat ID")
+		}
 	}
 
-	return command, args
+	return nil
 }
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+)
+
+// allowAll grants every command to every user
+type allowAll struct {
+	service.AuthorizationService
+}
+
+func (allowAll) Authorize(context.Context, types.TelegramUserID, types.TelegramChatID, types.Role) error {
+	return nil
+}
+
+// namedBot is a recordingBot answering getMe with its username and counting the calls
+type namedBot struct {
+	recordingBot
+	getMeCalls int
+}
+
+func (b *namedBot) GetMeWithResponse(context.Context) (*types.GetMeResponse, error) {
+	b.getMeCalls++
+	username := "Home_IP_Bot"
+	return &types.GetMeResponse{Result: &types.TelegramUser{ID: 99, IsBot: true, Username: &username}}, nil
+}
+
+func TestBotUseCase_IgnoresCommandsForOtherBots(t *testing.T) {
+	ctx := context.Background()
+	bot := &namedBot{}
+	u := NewBotUseCaseImpl(nil, bot, allowAll{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nopLogger{})
+	send := func(text string) {
+		t.Helper()
+		err := u.ProcessUpdate(ctx, types.TelegramUpdate{Message: &types.TelegramMessage{
+			Text: &text,
+			Chat: &types.TelegramChat{ID: chat, Type: types.ChatTypeSupergroup},
+			From: &types.TelegramUser{ID: owner},
+		}})
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", text, err)
+		}
+	}
+
+	send("/start@OtherBot")
+	send("/ban@OtherBot 123")
+	if len(bot.sent) != 0 {
+		t.Fatalf("expected commands for another bot to be ignored, got %v", bot.sent)
+	}
+
+	send("/start@home_ip_bot")
+	send("/start")
+	if len(bot.sent) != 2 {
+		t.Fatalf("expected commands for this bot and unaddressed ones to run, got %v", bot.sent)
+	}
+	if bot.getMeCalls != 1 {
+		t.Fatalf("expected the username to be resolved once, got %d getMe calls", bot.getMeCalls)
+	}
+}
@@ -0,0 +1,368 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	usecase "go-telegram-bot/internal/application/usecase/command"
+	"go-telegram-bot/internal/domain/entity"
+	domainErrors "go-telegram-bot/internal/domain/errors"
+	"go-telegram-bot/internal/domain/repository"
+	"go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+)
+
+// BroadcastCallbackPrefix starts the data of the preview buttons: broadcast:<confirm|cancel>:<job ID>
+const BroadcastCallbackPrefix = "broadcast:"
+
+// maxDeliveryError is the length of the error column of broadcast_deliveries
+const maxDeliveryError = 255
+
+// BroadcastOptions is the broadcast worker configuration, see config.Broadcast
+type BroadcastOptions struct {
+	PollInterval time.Duration
+	BatchSize    int
+	Lease        time.Duration
+}
+
+// BroadcastServiceImpl implements BroadcastService, Run delivers the confirmed jobs
+type BroadcastServiceImpl struct {
+	repo        repository.BroadcastRepository
+	chats       repository.ChatRepository
+	auth        service.AuthorizationService
+	telegramBot service.TelegramBotService
+	options     BroadcastOptions
+	logger      service.Logger
+	now         func() time.Time
+}
+
+// NewBroadcastService creates a new instance of BroadcastServiceImpl.
+// A nil repo, when the database is unavailable, makes every command unavailable and Run return at once.
+func NewBroadcastService(
+	repo repository.BroadcastRepository,
+	chats repository.ChatRepository,
+	auth service.AuthorizationService,
+	telegramBot service.TelegramBotService,
+	options BroadcastOptions,
+	logger service.Logger,
+) *BroadcastServiceImpl {
+	return &BroadcastServiceImpl{
+		repo:        repo,
+		chats:       chats,
+		auth:        auth,
+		telegramBot: telegramBot,
+		options:     options,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// Prepare stores the draft and posts its preview with the number of chats it would reach today
+func (s *BroadcastServiceImpl) Prepare(
+	ctx context.Context,
+	creator types.TelegramUserID,
+	reportChatID types.TelegramChatID,
+	text string,
+	filter entity.BroadcastFilter,
+	startAt time.Time,
+) (*entity.BroadcastJob, error) {
+	if err := s.authorize(ctx, creator); err != nil {
+		return nil, err
+	}
+	if startAt.IsZero() {
+		startAt = s.now()
+	}
+
+	targets, err := s.repo.CountTargets(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count broadcast targets: %w", err)
+	}
+	job := entity.NewBroadcastJob(text, filter, startAt, creator, reportChatID)
+	if err := s.repo.Create(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to store broadcast: %w", err)
+	}
+	s.logger.WithContext(ctx).Info("Broadcast drafted",
+		"broadcast_id", job.ID, "created_by", creator, "targets", targets, "scheduled_at", startAt)
+
+	if _, err := usecase.BroadcastPreviewHandler(ctx, job, targets, previewKeyboard(job.ID), s.telegramBot); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// HandleCallback confirms or cancels the draft when a global admin presses a preview button
+func (s *BroadcastServiceImpl) HandleCallback(
+	ctx context.Context, query *types.TelegramCallbackQuery,
+) error {
+	confirm, id, ok := parsePreviewCallback(query.Data)
+	if !ok || query.From == nil || s.repo == nil {
+		_, err := usecase.BroadcastCallbackHandler(ctx, query.ID, usecase.BroadcastCallbackInvalid, s.telegramBot)
+		return err
+	}
+
+	err := s.authorize(ctx, query.From.ID)
+	if errors.Is(err, domainErrors.ErrPermissionDenied) {
+		s.logger.WithContext(ctx).Warn("Broadcast confirmation denied", "broadcast_id", id, "user_id", query.From.ID)
+		_, err = usecase.BroadcastCallbackHandler(ctx, query.ID, usecase.BroadcastCallbackForbidden, s.telegramBot)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	status, outcome := types.BroadcastStatusScheduled, usecase.BroadcastCallbackConfirmed
+	if !confirm {
+		status, outcome = types.BroadcastStatusCancelled, usecase.BroadcastCallbackCancelled
+	}
+	changed, err := s.repo.Transition(ctx, id, []types.BroadcastStatus{types.BroadcastStatusDraft}, status)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		_, err = usecase.BroadcastCallbackHandler(ctx, query.ID, usecase.BroadcastCallbackHandled, s.telegramBot)
+		return err
+	}
+	s.logger.WithContext(ctx).Info("Broadcast decided", "broadcast_id", id, "status", status, "user_id", query.From.ID)
+
+	if query.Message != nil && query.Message.Chat != nil {
+		if _, err := usecase.BroadcastDecisionHandler(ctx, query.Message, confirm, query.From, s.telegramBot); err != nil {
+			s.logger.WithContext(ctx).Warn("Failed to update broadcast preview", "error", err)
+		}
+	}
+	_, err = usecase.BroadcastCallbackHandler(ctx, query.ID, outcome, s.telegramBot)
+	return err
+}
+
+// Status returns the job with its progress, the latest job when id is zero
+func (s *BroadcastServiceImpl) Status(
+	ctx context.Context, user types.TelegramUserID, id int64,
+) (*entity.BroadcastJob, error) {
+	if err := s.authorize(ctx, user); err != nil {
+		return nil, err
+	}
+	if id == 0 {
+		return s.repo.Latest(ctx)
+	}
+	return s.repo.Get(ctx, id)
+}
+
+// Cancel stops the job, a running job stops after the batch being sent
+func (s *BroadcastServiceImpl) Cancel(
+	ctx context.Context, user types.TelegramUserID, id int64,
+) (*entity.BroadcastJob, error) {
+	if err := s.authorize(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.Transition(ctx, id, []types.BroadcastStatus{
+		types.BroadcastStatusDraft, types.BroadcastStatusScheduled, types.BroadcastStatusRunning,
+	}, types.BroadcastStatusCancelled); err != nil {
+		return nil, err
+	}
+	s.logger.WithContext(ctx).Info("Broadcast cancel requested", "broadcast_id", id, "user_id", user)
+	return s.repo.Get(ctx, id)
+}
+
+// TagChat labels the chat for the tag filter of broadcasts
+func (s *BroadcastServiceImpl) TagChat(ctx context.Context, chatID types.TelegramChatID, tag string) error {
+	if s.repo == nil {
+		return domainErrors.ErrServiceUnavailable
+	}
+	return s.chats.AddTag(ctx, chatID, tag)
+}
+
+// UntagChat removes the label, it reports false when the chat did not have it
+func (s *BroadcastServiceImpl) UntagChat(
+	ctx context.Context, chatID types.TelegramChatID, tag string,
+) (bool, error) {
+	if s.repo == nil {
+		return false, domainErrors.ErrServiceUnavailable
+	}
+	return s.chats.RemoveTag(ctx, chatID, tag)
+}
+
+// ChatTags returns the labels of the chat
+func (s *BroadcastServiceImpl) ChatTags(ctx context.Context, chatID types.TelegramChatID) ([]string, error) {
+	if s.repo == nil {
+		return nil, domainErrors.ErrServiceUnavailable
+	}
+	return s.chats.GetTags(ctx, chatID)
+}
+
+// Run delivers the due jobs until ctx is done. Progress is stored after every batch and the lease of
+// an interrupted job expires, so another run resumes it from its pending deliveries.
+func (s *BroadcastServiceImpl) Run(ctx context.Context) {
+	if s.repo == nil {
+		return
+	}
+	ticker := time.NewTicker(s.options.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for s.runDue(ctx) {
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runDue claims and delivers the oldest due job, it reports whether another one may be due
+func (s *BroadcastServiceImpl) runDue(ctx context.Context) bool {
+	logger := s.logger.WithContext(ctx)
+	job, err := s.repo.ClaimDue(ctx, s.now(), s.options.Lease)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Error("Failed to claim broadcast", "error", err)
+		}
+		return false
+	}
+	if job == nil {
+		return false
+	}
+
+	if err := s.deliver(ctx, job); err != nil {
+		if ctx.Err() == nil {
+			logger.Warn("Broadcast interrupted, it resumes once its lease expires",
+				"broadcast_id", job.ID, "error", err)
+		}
+		return false
+	}
+	return true
+}
+
+// deliver plans the deliveries of the job on its first run, then sends the pending ones batch by batch
+func (s *BroadcastServiceImpl) deliver(ctx context.Context, job *entity.BroadcastJob) error {
+	logger := s.logger.WithContext(ctx)
+	if err := s.repo.Plan(ctx, job, s.now()); err != nil {
+		return fmt.Errorf("failed to plan broadcast: %w", err)
+	}
+	logger.Info("Delivering broadcast", "broadcast_id", job.ID, "total", job.Total, "pending", job.Pending())
+
+	// Deliveries must not delay replies to interactive commands
+	sendCtx := service.WithSendPriority(ctx, service.SendPriorityBroadcast)
+	for {
+		deliveries, err := s.repo.PendingDeliveries(ctx, job.ID, s.options.BatchSize)
+		if err != nil {
+			return err
+		}
+		for _, delivery := range deliveries {
+			if err := s.send(sendCtx, job, delivery); err != nil {
+				return err
+			}
+		}
+
+		if job, err = s.repo.Checkpoint(ctx, job.ID, s.now().Add(s.options.Lease)); err != nil {
+			return err
+		}
+		if job.Status == types.BroadcastStatusCancelled {
+			logger.Info("Broadcast cancelled", "broadcast_id", job.ID, "sent", job.Sent, "total", job.Total)
+			return nil
+		}
+		if len(deliveries) < s.options.BatchSize {
+			break
+		}
+	}
+
+	if err := s.repo.Finish(ctx, job.ID, s.now()); err != nil {
+		return err
+	}
+	job.Status = types.BroadcastStatusCompleted
+	logger.Info("Broadcast completed", "broadcast_id", job.ID,
+		"sent", job.Sent, "failed", job.Failed, "blocked", job.Blocked, "total", job.Total)
+
+	if _, err := usecase.BroadcastReportHandler(ctx, job, s.telegramBot); err != nil {
+		logger.Warn("Failed to report broadcast", "broadcast_id", job.ID, "error", err)
+	}
+	return nil
+}
+
+// send delivers the job to one chat and stores the outcome. Errors which may pass, such as an open
+// circuit, a network failure or a flood wait outliving the retries, leave the delivery pending and
+// stop the job.
+func (s *BroadcastServiceImpl) send(
+	ctx context.Context, job *entity.BroadcastJob, delivery *entity.BroadcastDelivery,
+) error {
+	response, err := s.telegramBot.SendMessageWithResponse(ctx, &types.SendMessageRequest{
+		ChatID: delivery.TelegramChatID,
+		Text:   job.Text,
+	})
+
+	var responseErr *types.ResponseError
+	switch {
+	case err == nil:
+		sentAt := s.now()
+		delivery.Status, delivery.SentAt = types.DeliveryStatusSent, &sentAt
+		if response != nil && response.Result != nil {
+			delivery.MessageID = &response.Result.MessageID
+		}
+	case ctx.Err() != nil, errors.Is(err, domainErrors.ErrServiceUnavailable):
+		return err
+	case errors.As(err, &responseErr) && (responseErr.IsNetworkFailure() || responseErr.IsServerError()):
+		return err
+	case errors.As(err, &responseErr) && responseErr.Response != nil && responseErr.Response.IsRateLimited():
+		// Telegram still throttles the bot, the lease resumes the job at this chat
+		return err
+	case errors.As(err, &responseErr) && responseErr.IsChatUnreachable():
+		// The client deactivated the chat already, later broadcasts skip it
+		delivery.Status, delivery.Error = types.DeliveryStatusBlocked, truncate(err.Error(), maxDeliveryError)
+	default:
+		delivery.Status, delivery.Error = types.DeliveryStatusFailed, truncate(err.Error(), maxDeliveryError)
+	}
+
+	broadcastDeliveries.With(string(delivery.Status)).Inc()
+	if err := s.repo.UpdateDelivery(ctx, delivery); err != nil {
+		return fmt.Errorf("failed to store broadcast delivery: %w", err)
+	}
+	return nil
+}
+
+// authorize lets only global admins broadcast, a broadcast reaches chats they may not administer
+func (s *BroadcastServiceImpl) authorize(ctx context.Context, user types.TelegramUserID) error {
+	if s.repo == nil {
+		return domainErrors.ErrServiceUnavailable
+	}
+	return s.auth.Authorize(ctx, user, entity.GlobalChatID, types.RoleAdmin)
+}
+
+// previewKeyboard builds the confirm and cancel buttons of the preview
+func previewKeyboard(id int64) types.InlineKeyboardMarkup {
+	data := func(action string) string {
+		return fmt.Sprintf("%s%s:%d", BroadcastCallbackPrefix, action, id)
+	}
+	return types.InlineKeyboardMarkup{InlineKeyboard: [][]types.InlineKeyboardButton{{
+		types.NewCallbackButton("📢 Gửi", data("confirm")),
+		types.NewCallbackButton("🗑 Huỷ", data("cancel")),
+	}}}
+}
+
+// parsePreviewCallback decodes the data of a preview button, confirm is false for the cancel button
+func parsePreviewCallback(data *string) (confirm bool, id int64, ok bool) {
+	if data == nil {
+		return false, 0, false
+	}
+	action, value, found := strings.Cut(strings.TrimPrefix(*data, BroadcastCallbackPrefix), ":")
+	if !found || (action != "confirm" && action != "cancel") {
+		return false, 0, false
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id <= 0 {
+		return false, 0, false
+	}
+	return action == "confirm", id, true
+}
+
+// truncate shortens s to at most n runes
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
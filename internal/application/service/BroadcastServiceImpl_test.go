@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-telegram-bot/internal/domain/entity"
+	domainErrors "go-telegram-bot/internal/domain/errors"
+	"go-telegram-bot/internal/domain/types"
+)
+
+// memoryBroadcasts is an in-memory BroadcastRepository planning deliveries to a fixed list of chats
+type memoryBroadcasts struct {
+	jobs       map[int64]*entity.BroadcastJob
+	deliveries []*entity.BroadcastDelivery
+	targets    []types.TelegramChatID
+}
+
+func newMemoryBroadcasts(targets ...types.TelegramChatID) *memoryBroadcasts {
+	return &memoryBroadcasts{jobs: make(map[int64]*entity.BroadcastJob), targets: targets}
+}
+
+func (r *memoryBroadcasts) Create(_ context.Context, job *entity.BroadcastJob) error {
+	job.ID = int64(len(r.jobs) + 1)
+	job.CreatedAt = time.Now()
+	r.jobs[job.ID] = job
+	return nil
+}
+
+func (r *memoryBroadcasts) Get(_ context.Context, id int64) (*entity.BroadcastJob, error) {
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil, domainErrors.ErrBroadcastNotFound
+	}
+	copied := *job
+	return &copied, nil
+}
+
+func (r *memoryBroadcasts) Latest(ctx context.Context) (*entity.BroadcastJob, error) {
+	return r.Get(ctx, int64(len(r.jobs)))
+}
+
+func (r *memoryBroadcasts) CountTargets(context.Context, entity.BroadcastFilter) (int, error) {
+	return len(r.targets), nil
+}
+
+func (r *memoryBroadcasts) Transition(
+	_ context.Context, id int64, from []types.BroadcastStatus, to types.BroadcastStatus,
+) (bool, error) {
+	job, ok := r.jobs[id]
+	if !ok {
+		return false, nil
+	}
+	for _, status := range from {
+		if job.Status == status {
+			job.Status = to
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryBroadcasts) ClaimDue(_ context.Context, now time.Time, lease time.Duration) (*entity.BroadcastJob, error) {
+	for id := int64(1); id <= int64(len(r.jobs)); id++ {
+		job := r.jobs[id]
+		due := job.Status == types.BroadcastStatusScheduled || job.Status == types.BroadcastStatusRunning
+		if due && !job.ScheduledAt.After(now) && (job.LockedUntil == nil || job.LockedUntil.Before(now)) {
+			lockedUntil := now.Add(lease)
+			job.Status, job.LockedUntil = types.BroadcastStatusRunning, &lockedUntil
+			copied := *job
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryBroadcasts) Plan(_ context.Context, job *entity.BroadcastJob, at time.Time) error {
+	if job.PlannedAt != nil {
+		return nil
+	}
+	for _, chatID := range r.targets {
+		r.deliveries = append(r.deliveries, &entity.BroadcastDelivery{
+			BaseEntityWithInt: entity.BaseEntityWithInt{ID: int64(len(r.deliveries) + 1)},
+			JobID:             job.ID,
+			TelegramChatID:    chatID,
+			Status:            types.DeliveryStatusPending,
+		})
+	}
+	job.Total, job.PlannedAt = len(r.targets), &at
+	r.jobs[job.ID].Total, r.jobs[job.ID].PlannedAt = job.Total, job.PlannedAt
+	return nil
+}
+
+func (r *memoryBroadcasts) PendingDeliveries(
+	_ context.Context, jobID int64, limit int,
+) ([]*entity.BroadcastDelivery, error) {
+	var pending []*entity.BroadcastDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.JobID == jobID && delivery.Status == types.DeliveryStatusPending && len(pending) < limit {
+			copied := *delivery
+			pending = append(pending, &copied)
+		}
+	}
+	return pending, nil
+}
+
+func (r *memoryBroadcasts) UpdateDelivery(_ context.Context, delivery *entity.BroadcastDelivery) error {
+	*r.deliveries[delivery.ID-1] = *delivery
+	return nil
+}
+
+func (r *memoryBroadcasts) Checkpoint(
+	ctx context.Context, jobID int64, lockedUntil time.Time,
+) (*entity.BroadcastJob, error) {
+	job := r.jobs[jobID]
+	job.Sent, job.Failed, job.Blocked = 0, 0, 0
+	for _, delivery := range r.deliveries {
+		if delivery.JobID != jobID {
+			continue
+		}
+		switch delivery.Status {
+		case types.DeliveryStatusSent:
+			job.Sent++
+		case types.DeliveryStatusFailed:
+			job.Failed++
+		case types.DeliveryStatusBlocked:
+			job.Blocked++
+		}
+	}
+	if job.Status == types.BroadcastStatusRunning {
+		job.LockedUntil = &lockedUntil
+	}
+	return r.Get(ctx, jobID)
+}
+
+func (r *memoryBroadcasts) Finish(_ context.Context, jobID int64, at time.Time) error {
+	job := r.jobs[jobID]
+	job.Status, job.FinishedAt, job.LockedUntil = types.BroadcastStatusCompleted, &at, nil
+	return nil
+}
+
+// broadcastBot answers like Telegram for a user who blocked the bot (7), a rejected message (8),
+// an outage (9) and a flood wait (-200), it records the chats reached and the callback answers
+type broadcastBot struct {
+	joinBot
+	reached   []types.TelegramChatID
+	down      bool
+	throttled bool
+}
+
+func (b *broadcastBot) SendMessageWithResponse(
+	ctx context.Context, request *types.SendMessageRequest,
+) (*types.SendMessageResponse, error) {
+	code, description := 0, ""
+	switch {
+	case request.ChatID == 7:
+		code, description = 403, "Forbidden: bot was blocked by the user"
+	case request.ChatID == 8:
+		code, description = 400, "Bad Request: message is too long"
+	case request.ChatID == 9 && b.down:
+		return nil, &types.ResponseError{Method: "sendMessage", Cause: errors.New("connection refused")}
+	case request.ChatID == -200 && b.throttled:
+		code, description = 429, "Too Many Requests: retry after 30"
+	}
+	if code != 0 {
+		return nil, &types.ResponseError{
+			Method:     "sendMessage",
+			HTTPStatus: code,
+			Response:   &types.BaseResponse{ErrorCode: &code, Description: &description},
+		}
+	}
+	b.reached = append(b.reached, request.ChatID)
+	return b.joinBot.SendMessageWithResponse(ctx, request)
+}
+
+func TestBroadcastService_PreviewAndConfirm(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryBroadcasts(-100, -200)
+	auth := NewAuthorizationService(newMemoryRoleRepo(), []types.TelegramUserID{owner}, nopLogger{})
+	if err := auth.Grant(ctx, owner, admin, chat, types.RoleAdmin); err != nil {
+		t.Fatalf("grant failed: %v", err)
+	}
+	bot := &broadcastBot{}
+	s := NewBroadcastService(repo, nil, auth, bot, BroadcastOptions{BatchSize: 10, Lease: time.Minute}, nopLogger{})
+
+	if _, err := s.Prepare(ctx, admin, chat, "Bảo trì lúc 22h", entity.BroadcastFilter{}, time.Time{}); !errors.Is(err, domainErrors.ErrPermissionDenied) {
+		t.Fatalf("expected a chat admin to be refused, got %v", err)
+	}
+	job, err := s.Prepare(ctx, owner, chat, "Bảo trì lúc 22h", entity.BroadcastFilter{}, time.Time{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(bot.sent) != 2 || bot.sent[0] != "Bảo trì lúc 22h" {
+		t.Fatalf("expected the text then the summary to be previewed, got %v", bot.sent)
+	}
+	if claimed, _ := repo.ClaimDue(ctx, time.Now(), time.Minute); claimed != nil {
+		t.Fatal("expected a draft not to be delivered")
+	}
+
+	data := "broadcast:confirm:1"
+	text := "preview"
+	press := func(from types.TelegramUserID) {
+		t.Helper()
+		err := s.HandleCallback(ctx, &types.TelegramCallbackQuery{
+			ID:      "q",
+			From:    &types.TelegramUser{ID: from, FirstName: "Admin"},
+			Data:    &data,
+			Message: &types.TelegramMessage{MessageID: 2, Chat: &types.TelegramChat{ID: chat}, Text: &text},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	press(admin)
+	press(owner)
+	press(owner)
+	if repo.jobs[job.ID].Status != types.BroadcastStatusScheduled || bot.edits != 1 {
+		t.Fatalf("expected a single confirmation, got %s and %d edits", repo.jobs[job.ID].Status, bot.edits)
+	}
+	expected := []string{
+		"⛔ Chỉ admin toàn cục mới được gửi thông báo",
+		"📢 Đã xác nhận, thông báo sẽ được gửi",
+		"Thông báo này đã được xử lý",
+	}
+	for i, answer := range expected {
+		if bot.answers[i] != answer {
+			t.Fatalf("expected answers %v, got %v", expected, bot.answers)
+		}
+	}
+}
+
+func TestBroadcastService_DeliversAndResumes(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryBroadcasts(-100, 7, 8, 9, -200)
+	bot := &broadcastBot{down: true}
+	s := NewBroadcastService(repo, nil, nil, bot, BroadcastOptions{BatchSize: 2, Lease: time.Minute}, nopLogger{})
+	start := time.Now()
+	s.now = func() time.Time { return start }
+
+	job := entity.NewBroadcastJob("Xin chào", entity.BroadcastFilter{}, start, owner, chat)
+	if err := repo.Create(ctx, job); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	job.Status = types.BroadcastStatusScheduled
+
+	// The outage at chat 9 interrupts the job, its lease keeps it from being claimed again at once
+	if s.runDue(ctx) {
+		t.Fatal("expected the outage to interrupt the broadcast")
+	}
+	if s.runDue(ctx) {
+		t.Fatal("expected the leased broadcast not to be claimed again")
+	}
+	if stored := repo.jobs[job.ID]; stored.Status != types.BroadcastStatusRunning || stored.Sent != 1 || stored.Blocked != 1 {
+		t.Fatalf("expected the progress of the first batch to be stored, got %+v", stored)
+	}
+
+	// A flood wait outliving the client retries interrupts the job too instead of failing the chat
+	bot.down, bot.throttled = false, true
+	s.now = func() time.Time { return start.Add(2 * time.Minute) }
+	if s.runDue(ctx) {
+		t.Fatal("expected the flood wait to interrupt the broadcast")
+	}
+	if throttled := repo.deliveries[4]; throttled.Status != types.DeliveryStatusPending || throttled.Error != "" {
+		t.Fatalf("expected the throttled chat to stay pending, got %+v", throttled)
+	}
+
+	bot.throttled = false
+	s.now = func() time.Time { return start.Add(4 * time.Minute) }
+	if !s.runDue(ctx) {
+		t.Fatal("expected the broadcast to resume once its lease expired")
+	}
+
+	stored := repo.jobs[job.ID]
+	if stored.Status != types.BroadcastStatusCompleted || stored.Sent != 3 || stored.Blocked != 1 || stored.Failed != 1 {
+		t.Fatalf("expected 3 sent, 1 blocked and 1 failed, got %+v", stored)
+	}
+	reached := []types.TelegramChatID{-100, 9, -200, chat}
+	if len(bot.reached) != len(reached) {
+		t.Fatalf("expected every chat to be reached once then the report, got %v", bot.reached)
+	}
+	for i, chatID := range reached {
+		if bot.reached[i] != chatID {
+			t.Fatalf("expected every chat to be reached once then the report, got %v", bot.reached)
+		}
+	}
+	if repo.deliveries[1].Status != types.DeliveryStatusBlocked || repo.deliveries[2].Error == "" {
+		t.Fatalf("expected the blocked and failed deliveries to be recorded, got %+v, %+v",
+			repo.deliveries[1], repo.deliveries[2])
+	}
+}
+
+func TestParsePreviewCallback(t *testing.T) {
+	cases := []struct {
+		data    string
+		confirm bool
+		ok      bool
+	}{
+		{"broadcast:confirm:12", true, true},
+		{"broadcast:cancel:12", false, true},
+		{"broadcast:send:12", false, false},
+		{"broadcast:confirm:x", false, false},
+		{"broadcast:confirm", false, false},
+	}
+	for _, c := range cases {
+		confirm, id, ok := parsePreviewCallback(&c.data)
+		if ok != c.ok || confirm != c.confirm || (ok && id != 12) {
+			t.Errorf("parsePreviewCallback(%q) = %v, %d, %v", c.data, confirm, id, ok)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	usecase "go-telegram-bot/internal/application/usecase/command"
+	"go-telegram-bot/internal/domain/types"
+)
+
+// CommandHandler answers one parsed command
+type CommandHandler func(ctx context.Context, req *types.CommandRequest) error
+
+// Route binds a command to its handler and the minimum role allowed to run it
+type Route struct {
+	Command     types.Command
+	Role        types.Role
+	Description string
+	Handler     CommandHandler
+}
+
+// CommandRouter looks up the route of a command
+type CommandRouter struct {
+	routes map[types.Command]Route
+}
+
+// NewCommandRouter creates an empty CommandRouter
+func NewCommandRouter() *CommandRouter {
+	return &CommandRouter{routes: make(map[types.Command]Route)}
+}
+
+// Register adds a route, replacing any route of the same command
+func (r *CommandRouter) Register(route Route) {
+	r.routes[route.Command] = route
+}
+
+// Lookup returns the route of command
+func (r *CommandRouter) Lookup(command types.Command) (Route, bool) {
+	route, ok := r.routes[command]
+	return route, ok
+}
+
+// Available lists the commands a user with role may run, sorted by command
+func (r *CommandRouter) Available(role types.Role) []usecase.CommandInfo {
+	commands := make([]usecase.CommandInfo, 0, len(r.routes))
+	for _, route := range r.routes {
+		if role.AtLeast(route.Role) {
+			commands = append(commands, usecase.CommandInfo{
+				Command:     route.Command,
+				Description: route.Description,
+			})
+		}
+	}
+	sort.Slice(commands, func(i, j int) bool { return commands[i].Command < commands[j].Command })
+	return commands
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	usecase "go-telegram-bot/internal/application/usecase/command"
+	"go-telegram-bot/internal/domain/entity"
+	"go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+)
+
+// DDNSOptions is the DDNS configuration shared by the providers, see config.DDNS
+type DDNSOptions struct {
+	ReportChatID types.TelegramChatID
+	Timeout      time.Duration
+}
+
+// DDNSServiceImpl implements DDNSService. The outcome of the updates is kept in memory, so every
+// provider is updated once after a restart.
+type DDNSServiceImpl struct {
+	providers   []service.DDNSProvider
+	telegramBot service.TelegramBotService
+	options     DDNSOptions
+	logger      service.Logger
+	now         func() time.Time
+
+	mu       sync.Mutex
+	statuses map[string]entity.DDNSStatus
+}
+
+// NewDDNSService creates a new instance of DDNSServiceImpl
+func NewDDNSService(
+	providers []service.DDNSProvider,
+	telegramBot service.TelegramBotService,
+	options DDNSOptions,
+	logger service.Logger,
+) *DDNSServiceImpl {
+	statuses := make(map[string]entity.DDNSStatus, len(providers))
+	for _, provider := range providers {
+		statuses[provider.Name()] = entity.DDNSStatus{Provider: provider.Name()}
+	}
+	return &DDNSServiceImpl{
+		providers:   providers,
+		telegramBot: telegramBot,
+		options:     options,
+		logger:      logger,
+		now:         time.Now,
+		statuses:    statuses,
+	}
+}
+
+// ObserveIP updates the providers whose records do not point at ip yet, or whose last update failed
+func (s *DDNSServiceImpl) ObserveIP(ctx context.Context, ip string) {
+	for _, provider := range s.providers {
+		s.mu.Lock()
+		previous := s.statuses[provider.Name()]
+		s.mu.Unlock()
+		if previous.IP == ip && previous.LastError == "" {
+			continue
+		}
+		s.update(ctx, provider, previous, ip)
+	}
+}
+
+// update runs one provider and reports a success, or a failure unless the previous attempt failed too
+// so that an outage is reported once rather than on every lookup
+func (s *DDNSServiceImpl) update(ctx context.Context, provider service.DDNSProvider, previous entity.DDNSStatus, ip string) {
+	logger := s.logger.WithContext(ctx)
+	updateCtx, cancel := context.WithTimeout(ctx, s.options.Timeout)
+	err := provider.Update(updateCtx, ip)
+	cancel()
+	if err != nil && ctx.Err() != nil {
+		return
+	}
+
+	now := s.now()
+	status := previous
+	status.CheckedAt = &now
+	if err != nil {
+		status.LastError = err.Error()
+		logger.Warn("DDNS update failed", "provider", provider.Name(), "ip", ip, "error", err)
+		ddnsUpdates.With(provider.Name(), "failed").Inc()
+	} else {
+		status.IP, status.UpdatedAt, status.LastError = ip, &now, ""
+		logger.Info("DDNS records updated", "provider", provider.Name(), "ip", ip)
+		ddnsUpdates.With(provider.Name(), "ok").Inc()
+	}
+	s.mu.Lock()
+	s.statuses[provider.Name()] = status
+	s.mu.Unlock()
+
+	if s.options.ReportChatID == 0 || (err != nil && previous.LastError != "") {
+		return
+	}
+	if _, err := usecase.DDNSReportHandler(ctx, s.options.ReportChatID, status, s.telegramBot); err != nil {
+		logger.Warn("Failed to report DDNS update", "provider", provider.Name(), "error", err)
+	}
+}
+
+// Status returns the outcome of the last update of every provider, in configuration order
+func (s *DDNSServiceImpl) Status(context.Context) []entity.DDNSStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	statuses := make([]entity.DDNSStatus, 0, len(s.providers))
+	for _, provider := range s.providers {
+		statuses = append(statuses, s.statuses[provider.Name()])
+	}
+	return statuses
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go-telegram-bot/internal/domain/service"
+)
+
+// flakyProvider records the addresses it is asked for and fails while down
+type flakyProvider struct {
+	updates []string
+	down    bool
+}
+
+func (p *flakyProvider) Name() string {
+	return "flaky"
+}
+
+func (p *flakyProvider) Update(_ context.Context, ip string) error {
+	p.updates = append(p.updates, ip)
+	if p.down {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func TestDDNSService_UpdatesOnChange(t *testing.T) {
+	ctx := context.Background()
+	provider := &flakyProvider{}
+	bot := &recordingBot{}
+	s := NewDDNSService([]service.DDNSProvider{provider}, bot, DDNSOptions{ReportChatID: chat, Timeout: time.Second}, nopLogger{})
+
+	// The monitor feeds the service without a database
+	ips := &stubIPService{ip: "203.0.113.7"}
+	monitor := NewIPMonitorService(nil, ips, bot, IPMonitorOptions{Interval: time.Minute}, nopLogger{})
+	monitor.AddObserver(s)
+	monitor.check(ctx)
+	monitor.check(ctx)
+	if len(provider.updates) != 1 || len(bot.sent) != 1 || !strings.Contains(bot.sent[0], "203.0.113.7") {
+		t.Fatalf("expected one update reported, got %v and %v", provider.updates, bot.sent)
+	}
+
+	// An outage is reported once and retried on every lookup
+	provider.down = true
+	ips.ip = "198.51.100.4"
+	monitor.check(ctx)
+	monitor.check(ctx)
+	if len(provider.updates) != 3 || len(bot.sent) != 2 || !strings.Contains(bot.sent[1], "connection refused") {
+		t.Fatalf("expected the failure retried and reported once, got %v and %v", provider.updates, bot.sent)
+	}
+	if status := s.Status(ctx)[0]; status.IsHealthy() || status.IP != "203.0.113.7" {
+		t.Fatalf("expected the failure kept with the last address set, got %+v", status)
+	}
+
+	provider.down = false
+	monitor.check(ctx)
+	if len(bot.sent) != 3 || !strings.Contains(bot.sent[2], "198.51.100.4") || !s.Status(ctx)[0].IsHealthy() {
+		t.Fatalf("expected the recovery reported, got %v", bot.sent)
+	}
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+
+	usecase "go-telegram-bot/internal/application/usecase/command"
+	"go-telegram-bot/internal/domain/entity"
+	domainErrors "go-telegram-bot/internal/domain/errors"
+	"go-telegram-bot/internal/domain/repository"
+	"go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+)
+
+// IPMonitorOptions is the IP monitor configuration, see config.IPMonitor
+type IPMonitorOptions struct {
+	Interval time.Duration
+}
+
+// IPMonitorServiceImpl implements IPMonitorService, Run polls the WAN address
+type IPMonitorServiceImpl struct {
+	repo        repository.IPMonitorRepository
+	ipService   service.IPService
+	telegramBot service.TelegramBotService
+	observers   []service.IPObserver
+	options     IPMonitorOptions
+	logger      service.Logger
+	now         func() time.Time
+}
+
+// NewIPMonitorService creates a new instance of IPMonitorServiceImpl.
+// A nil repo, when the database is unavailable, makes every command unavailable and Run only tell
+// the observers, or return at once without any.
+func NewIPMonitorService(
+	repo repository.IPMonitorRepository,
+	ipService service.IPService,
+	telegramBot service.TelegramBotService,
+	options IPMonitorOptions,
+	logger service.Logger,
+) *IPMonitorServiceImpl {
+	return &IPMonitorServiceImpl{
+		repo:        repo,
+		ipService:   ipService,
+		telegramBot: telegramBot,
+		options:     options,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// AddObserver makes Run tell the observer every address it looks up, before Run starts
+func (s *IPMonitorServiceImpl) AddObserver(observer service.IPObserver) {
+	s.observers = append(s.observers, observer)
+}
+
+// Subscribe adds the chat to those notified of changes
+func (s *IPMonitorServiceImpl) Subscribe(
+	ctx context.Context,
+	user types.TelegramUserID,
+	chatID types.TelegramChatID,
+	chatType types.ChatType,
+) (bool, error) {
+	if s.repo == nil {
+		return false, domainErrors.ErrServiceUnavailable
+	}
+	created, err := s.repo.Subscribe(ctx, &entity.IPSubscription{
+		TelegramChatID: chatID,
+		ChatType:       chatType,
+		SubscribedBy:   user,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to store IP subscription: %w", err)
+	}
+	if created {
+		s.logger.WithContext(ctx).Info("Chat subscribed to IP changes", "chat_id", chatID, "user_id", user)
+	}
+	return created, nil
+}
+
+// Unsubscribe removes the chat from those notified of changes
+func (s *IPMonitorServiceImpl) Unsubscribe(ctx context.Context, chatID types.TelegramChatID) (bool, error) {
+	if s.repo == nil {
+		return false, domainErrors.ErrServiceUnavailable
+	}
+	removed, err := s.repo.Unsubscribe(ctx, chatID)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove IP subscription: %w", err)
+	}
+	if removed {
+		s.logger.WithContext(ctx).Info("Chat unsubscribed from IP changes", "chat_id", chatID)
+	}
+	return removed, nil
+}
+
+// History returns the last recorded addresses, most recent first
+func (s *IPMonitorServiceImpl) History(ctx context.Context, limit int) ([]*entity.IPChange, error) {
+	if s.repo == nil {
+		return nil, domainErrors.ErrServiceUnavailable
+	}
+	return s.repo.ListChanges(ctx, limit)
+}
+
+// Run looks the WAN address up on every interval until ctx is done
+func (s *IPMonitorServiceImpl) Run(ctx context.Context) {
+	if s.repo == nil && len(s.observers) == 0 {
+		return
+	}
+	ticker := time.NewTicker(s.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check looks the WAN address up, records it when it changed, then tells the observers
+func (s *IPMonitorServiceImpl) check(ctx context.Context) {
+	ip, err := s.ipService.GetPublicIP(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			s.logger.WithContext(ctx).Warn("Failed to look up the WAN address", "error", err)
+			ipChecks.With("failed").Inc()
+		}
+		return
+	}
+	// A captive portal or an error page may answer 200, only an address is recorded and passed on
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("The WAN address lookup answered no address", "answer", fmt.Sprintf("%.64q", ip))
+		ipChecks.With("failed").Inc()
+		return
+	}
+	ip = addr.Unmap().String()
+
+	if s.repo != nil {
+		s.record(ctx, ip)
+	}
+	for _, observer := range s.observers {
+		observer.ObserveIP(ctx, ip)
+	}
+}
+
+// record stores the address when it changed. Only the instance which recorded the change notifies
+// the subscribers; the first address seen is recorded without notification.
+func (s *IPMonitorServiceImpl) record(ctx context.Context, ip string) {
+	logger := s.logger.WithContext(ctx)
+	previous, err := s.repo.LatestChange(ctx)
+	if err != nil {
+		logger.Error("Failed to load the last WAN address", "error", err)
+		return
+	}
+	if previous != nil && previous.IP == ip {
+		ipChecks.With("unchanged").Inc()
+		return
+	}
+
+	change := entity.NewIPChange(previous, ip, s.now())
+	recorded, err := s.repo.RecordChange(ctx, change)
+	if err != nil {
+		logger.Error("Failed to record the WAN address", "ip", ip, "error", err)
+		return
+	}
+	if !recorded {
+		return
+	}
+	ipChecks.With("changed").Inc()
+	if previous == nil {
+		logger.Info("WAN address recorded", "ip", ip)
+		return
+	}
+	logger.Info("WAN address changed", "previous_ip", previous.IP, "ip", ip)
+	s.notify(ctx, previous, change)
+}
+
+// notify tells every subscribed chat about the change, chats the bot cannot reach any more are unsubscribed
+func (s *IPMonitorServiceImpl) notify(ctx context.Context, previous, change *entity.IPChange) {
+	logger := s.logger.WithContext(ctx)
+	subscriptions, err := s.repo.ListSubscriptions(ctx)
+	if err != nil {
+		logger.Error("Failed to list IP subscriptions", "error", err)
+		return
+	}
+
+	for _, subscription := range subscriptions {
+		_, err := usecase.IPChangeHandler(ctx, subscription.TelegramChatID, previous, change, s.telegramBot)
+		var responseErr *types.ResponseError
+		switch {
+		case err == nil:
+		case errors.As(err, &responseErr) && responseErr.IsChatUnreachable():
+			logger.Info("Chat unreachable, removing its IP subscription", "chat_id", subscription.TelegramChatID)
+			if _, err := s.repo.Unsubscribe(ctx, subscription.TelegramChatID); err != nil {
+				logger.Warn("Failed to remove IP subscription", "chat_id", subscription.TelegramChatID, "error", err)
+			}
+		default:
+			logger.Warn("Failed to notify IP change", "chat_id", subscription.TelegramChatID, "error", err)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go-telegram-bot/internal/domain/entity"
+	"go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+)
+
+// memoryIPMonitor is an in-memory IPMonitorRepository
+type memoryIPMonitor struct {
+	changes       []*entity.IPChange
+	subscriptions map[types.TelegramChatID]*entity.IPSubscription
+}
+
+func (r *memoryIPMonitor) LatestChange(context.Context) (*entity.IPChange, error) {
+	if len(r.changes) == 0 {
+		return nil, nil
+	}
+	return r.changes[len(r.changes)-1], nil
+}
+
+func (r *memoryIPMonitor) RecordChange(_ context.Context, change *entity.IPChange) (bool, error) {
+	for _, recorded := range r.changes {
+		if recorded.PreviousID == change.PreviousID {
+			return false, nil
+		}
+	}
+	change.ID = int64(len(r.changes) + 1)
+	r.changes = append(r.changes, change)
+	return true, nil
+}
+
+func (r *memoryIPMonitor) ListChanges(_ context.Context, limit int) ([]*entity.IPChange, error) {
+	var changes []*entity.IPChange
+	for i := len(r.changes) - 1; i >= 0 && len(changes) < limit; i-- {
+		changes = append(changes, r.changes[i])
+	}
+	return changes, nil
+}
+
+func (r *memoryIPMonitor) Subscribe(_ context.Context, subscription *entity.IPSubscription) (bool, error) {
+	if _, ok := r.subscriptions[subscription.TelegramChatID]; ok {
+		return false, nil
+	}
+	r.subscriptions[subscription.TelegramChatID] = subscription
+	return true, nil
+}
+
+func (r *memoryIPMonitor) Unsubscribe(_ context.Context, chatID types.TelegramChatID) (bool, error) {
+	_, ok := r.subscriptions[chatID]
+	delete(r.subscriptions, chatID)
+	return ok, nil
+}
+
+func (r *memoryIPMonitor) ListSubscriptions(context.Context) ([]*entity.IPSubscription, error) {
+	var subscriptions []*entity.IPSubscription
+	for _, subscription := range r.subscriptions {
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, nil
+}
+
+// stubIPService returns ip as the public address, an error when it is empty
+type stubIPService struct {
+	service.IPService
+	ip string
+}
+
+func (s *stubIPService) GetPublicIP(context.Context) (string, error) {
+	if s.ip == "" {
+		return "", errors.New("no route to host")
+	}
+	return s.ip, nil
+}
+
+func TestIPMonitorService_NotifiesChanges(t *testing.T) {
+	ctx := context.Background()
+	repo := &memoryIPMonitor{subscriptions: make(map[types.TelegramChatID]*entity.IPSubscription)}
+	ips := &stubIPService{ip: "203.0.113.7"}
+	bot := &broadcastBot{}
+	s := NewIPMonitorService(repo, ips, bot, IPMonitorOptions{Interval: time.Minute}, nopLogger{})
+	now := time.Date(2026, 10, 19, 6, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	if created, err := s.Subscribe(ctx, owner, chat, types.ChatTypeGroup); err != nil || !created {
+		t.Fatalf("expected the chat subscribed, got %v %v", created, err)
+	}
+	if created, _ := s.Subscribe(ctx, owner, chat, types.ChatTypeGroup); created {
+		t.Fatal("expected a second subscription to be reported as existing")
+	}
+	// The bot was blocked in chat 7, it is unsubscribed on the first notification
+	_, _ = s.Subscribe(ctx, owner, 7, types.ChatTypePrivate)
+
+	// The first address seen has no previous one to compare with
+	s.check(ctx)
+	ips.ip = ""
+	now = now.Add(time.Hour)
+	s.check(ctx)
+	// A captive portal answering a login page is not taken for a new address
+	ips.ip = "<html><title>Wi-Fi login</title></html>"
+	s.check(ctx)
+	ips.ip = "203.0.113.7"
+	s.check(ctx)
+	if len(repo.changes) != 1 || len(bot.reached) != 0 {
+		t.Fatalf("expected one address recorded and no notification, got %d and %v", len(repo.changes), bot.reached)
+	}
+
+	ips.ip = "198.51.100.4"
+	now = now.Add(26 * time.Hour)
+	s.check(ctx)
+	if len(repo.changes) != 2 || len(bot.reached) != 1 || bot.reached[0] != chat {
+		t.Fatalf("expected the change notified to the subscribed chat, got %d and %v", len(repo.changes), bot.reached)
+	}
+	if text := bot.sent[0]; !strings.Contains(text, "203.0.113.7") || !strings.Contains(text, "198.51.100.4") ||
+		!strings.Contains(text, "1 ngày 3 giờ") {
+		t.Fatalf("expected the old and new address and how long the old one lasted, got %q", text)
+	}
+	if _, ok := repo.subscriptions[7]; ok {
+		t.Fatal("expected the unreachable chat to be unsubscribed")
+	}
+
+	// Another instance noticing the same change does not record it nor notify again
+	if recorded, _ := repo.RecordChange(ctx, entity.NewIPChange(repo.changes[0], "198.51.100.4", now)); recorded {
+		t.Fatal("expected a concurrent change to be refused")
+	}
+
+	history, err := s.History(ctx, 10)
+	if err != nil || len(history) != 2 || history[0].IP != "198.51.100.4" || history[1].PreviousIP != "" {
+		t.Fatalf("expected the history newest first, got %v %v", history, err)
+	}
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-telegram-bot/internal/domain/entity"
+	domainErrors "go-telegram-bot/internal/domain/errors"
+	"go-telegram-bot/internal/domain/repository"
+	"go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+)
+
+// InviteLinkServiceImpl implements InviteLinkService with the invite link repository
+type InviteLinkServiceImpl struct {
+	repo        repository.InviteLinkRepository
+	telegramBot service.TelegramBotService
+	logger      service.Logger
+	now         func() time.Time
+}
+
+// NewInviteLinkService creates a new instance of InviteLinkServiceImpl.
+// A nil repo, when the database is unavailable, makes every command unavailable.
+func NewInviteLinkService(
+	repo repository.InviteLinkRepository,
+	telegramBot service.TelegramBotService,
+	logger service.Logger,
+) *InviteLinkServiceImpl {
+	return &InviteLinkServiceImpl{
+		repo:        repo,
+		telegramBot: telegramBot,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// Create creates the link with Telegram and stores it
+func (s *InviteLinkServiceImpl) Create(
+	ctx context.Context, creator types.TelegramUserID, chatID types.TelegramChatID, options service.InviteLinkOptions,
+) (*entity.InviteLink, error) {
+	if s.repo == nil {
+		return nil, domainErrors.ErrServiceUnavailable
+	}
+
+	request := &types.CreateChatInviteLinkRequest{ChatID: chatID}
+	if options.Name != "" {
+		request.Name = &options.Name
+	}
+	if options.ExpiresIn > 0 {
+		expireDate := s.now().Add(options.ExpiresIn).Unix()
+		request.ExpireDate = &expireDate
+	}
+	if options.MemberLimit > 0 {
+		request.MemberLimit = &options.MemberLimit
+	}
+
+	response, err := s.telegramBot.CreateChatInviteLink(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create invite link: %w", err)
+	}
+
+	if response.Result == nil {
+		return nil, fmt.Errorf("failed to create invite link: empty result")
+	}
+
+	link := entity.NewInviteLink(chatID, creator, response.Result)
+	if err := s.repo.Create(ctx, link); err != nil {
+		return nil, fmt.Errorf("failed to store invite link: %w", err)
+	}
+	s.logger.WithContext(ctx).Info("Invite link created",
+		"chat_id", chatID, "link_id", link.ID, "name", link.Name, "created_by", creator)
+	return link, nil
+}
+
+// List returns the stored links of the chat, newest first
+func (s *InviteLinkServiceImpl) List(
+	ctx context.Context, chatID types.TelegramChatID,
+) ([]*entity.InviteLink, error) {
+	if s.repo == nil {
+		return nil, domainErrors.ErrServiceUnavailable
+	}
+	return s.repo.ListByChat(ctx, chatID)
+}
+
+// Revoke revokes the link with Telegram, a link revoked already is returned as is
+func (s *InviteLinkServiceImpl) Revoke(
+	ctx context.Context, chatID types.TelegramChatID, id int64,
+) (*entity.InviteLink, error) {
+	if s.repo == nil {
+		return nil, domainErrors.ErrServiceUnavailable
+	}
+
+	link, err := s.repo.Get(ctx, chatID, id)
+	if err != nil {
+		return nil, err
+	}
+	if link.RevokedAt != nil {
+		return link, nil
+	}
+
+	if _, err := s.telegramBot.RevokeChatInviteLink(ctx, &types.RevokeChatInviteLinkRequest{
+		ChatID:     chatID,
+		InviteLink: link.Link,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to revoke invite link: %w", err)
+	}
+
+	now := s.now()
+	if err := s.repo.MarkRevoked(ctx, link.ID, now); err != nil {
+		return nil, err
+	}
+	link.RevokedAt = &now
+	s.logger.WithContext(ctx).Info("Invite link revoked", "chat_id", chatID, "link_id", link.ID)
+	return link, nil
+}
+
+// RecordJoin counts the join when the member entered the chat with an invite link.
+// Links not created through the bot are ignored.
+func (s *InviteLinkServiceImpl) RecordJoin(
+	ctx context.Context, update *types.TelegramChatMemberUpdated,
+) error {
+	if s.repo == nil || update.InviteLink == nil || update.Chat == nil || update.NewChatMember == nil ||
+		update.NewChatMember.User == nil || update.OldStatus().IsPresent() || !update.NewStatus().IsPresent() {
+		return nil
+	}
+
+	found, err := s.repo.RecordJoin(ctx, update.InviteLink.InviteLink)
+	if err != nil {
+		return fmt.Errorf("failed to record invite link join: %w", err)
+	}
+	if found {
+		s.logger.WithContext(ctx).Info("Member joined with invite link",
+			"chat_id", update.Chat.ID, "user_id", update.NewChatMember.User.ID)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-telegram-bot/internal/domain/entity"
+	domainErrors "go-telegram-bot/internal/domain/errors"
+	"go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+)
+
+// memoryInviteLinks is an in-memory InviteLinkRepository
+type memoryInviteLinks struct {
+	links []*entity.InviteLink
+}
+
+func (r *memoryInviteLinks) Create(_ context.Context, link *entity.InviteLink) error {
+	link.ID = int64(len(r.links) + 1)
+	r.links = append(r.links, link)
+	return nil
+}
+
+func (r *memoryInviteLinks) Get(_ context.Context, chatID types.TelegramChatID, id int64) (*entity.InviteLink, error) {
+	for _, link := range r.links {
+		if link.ID == id && link.TelegramChatID == chatID {
+			return link, nil
+		}
+	}
+	return nil, domainErrors.ErrInviteLinkNotFound
+}
+
+func (r *memoryInviteLinks) ListByChat(_ context.Context, chatID types.TelegramChatID) ([]*entity.InviteLink, error) {
+	var links []*entity.InviteLink
+	for i := len(r.links) - 1; i >= 0; i-- {
+		if r.links[i].TelegramChatID == chatID {
+			links = append(links, r.links[i])
+		}
+	}
+	return links, nil
+}
+
+func (r *memoryInviteLinks) MarkRevoked(_ context.Context, id int64, at time.Time) error {
+	r.links[id-1].RevokedAt = &at
+	return nil
+}
+
+func (r *memoryInviteLinks) RecordJoin(_ context.Context, link string) (bool, error) {
+	for _, stored := range r.links {
+		if stored.Link == link {
+			stored.JoinCount++
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// inviteBot creates links echoing the request and records the revoked ones, every other method panics
+type inviteBot struct {
+	recordingBot
+	created []*types.CreateChatInviteLinkRequest
+	revoked []string
+}
+
+func (b *inviteBot) CreateChatInviteLink(
+	_ context.Context, request *types.CreateChatInviteLinkRequest,
+) (*types.CreateChatInviteLinkResponse, error) {
+	b.created = append(b.created, request)
+	return &types.CreateChatInviteLinkResponse{Result: &types.TelegramChatInviteLink{
+		InviteLink:  "https://t.me/+link" + *request.Name,
+		Name:        request.Name,
+		ExpireDate:  request.ExpireDate,
+		MemberLimit: request.MemberLimit,
+	}}, nil
+}
+
+func (b *inviteBot) RevokeChatInviteLink(
+	_ context.Context, request *types.RevokeChatInviteLinkRequest,
+) (*types.RevokeChatInviteLinkResponse, error) {
+	b.revoked = append(b.revoked, request.InviteLink)
+	return &types.RevokeChatInviteLinkResponse{}, nil
+}
+
+func TestInviteLinkService_CountsJoinsAndRevokes(t *testing.T) {
+	ctx := context.Background()
+	repo := &memoryInviteLinks{}
+	bot := &inviteBot{}
+	s := NewInviteLinkService(repo, bot, nopLogger{})
+	now := time.Unix(1_700_000_000, 0)
+	s.now = func() time.Time { return now }
+
+	link, err := s.Create(ctx, admin, chat, service.InviteLinkOptions{Name: "event", ExpiresIn: 24 * time.Hour, MemberLimit: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if request := bot.created[0]; *request.ExpireDate != now.Add(24*time.Hour).Unix() || *request.MemberLimit != 2 {
+		t.Fatalf("unexpected create request %+v", request)
+	}
+	if link.Name != "event" || link.CreatedBy != admin || !link.ExpiresAt.Equal(now.Add(24*time.Hour)) {
+		t.Fatalf("unexpected stored link %+v", link)
+	}
+
+	join := func(old types.ChatMemberStatus, inviteLink string) {
+		t.Helper()
+		err := s.RecordJoin(ctx, &types.TelegramChatMemberUpdated{
+			Chat:          &types.TelegramChat{ID: chat},
+			OldChatMember: &types.TelegramChatMember{User: &types.TelegramUser{ID: user}, Status: old},
+			NewChatMember: &types.TelegramChatMember{User: &types.TelegramUser{ID: user}, Status: types.ChatMemberStatusMember},
+			InviteLink:    &types.TelegramChatInviteLink{InviteLink: inviteLink},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	join(types.ChatMemberStatusLeft, link.Link)
+	join(types.ChatMemberStatusKicked, link.Link)
+	join(types.ChatMemberStatusRestricted, link.Link) // already present, not a join
+	join(types.ChatMemberStatusLeft, "https://t.me/+other")
+	if link.JoinCount != 2 {
+		t.Fatalf("expected two joins, got %d", link.JoinCount)
+	}
+	if link.IsActive(now) {
+		t.Fatal("expected the link to be inactive once its member limit is reached")
+	}
+
+	if _, err := s.Revoke(ctx, -42, link.ID); !errors.Is(err, domainErrors.ErrInviteLinkNotFound) {
+		t.Fatalf("expected links of other chats to be hidden, got %v", err)
+	}
+	for range 2 {
+		if _, err := s.Revoke(ctx, chat, link.ID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(bot.revoked) != 1 || link.RevokedAt == nil {
+		t.Fatalf("expected the link to be revoked once, got %v", bot.revoked)
+	}
+}
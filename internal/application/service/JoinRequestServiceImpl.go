@@ -0,0 +1,379 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	usecase "go-telegram-bot/internal/application/usecase/command"
+	"go-telegram-bot/internal/domain/entity"
+	domainErrors "go-telegram-bot/internal/domain/errors"
+	"go-telegram-bot/internal/domain/repository"
+	"go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+	"go-telegram-bot/internal/shared/tracing"
+)
+
+// JoinRequestCallbackPrefix starts the data of the review buttons: join:<approve|decline>:<chat ID>:<user ID>
+const JoinRequestCallbackPrefix = "join:"
+
+// Unanswered questions are declined at most joinRequestSweepInterval after their answer timeout,
+// joinRequestSweepBatch at a time
+const (
+	joinRequestSweepInterval = time.Minute
+	joinRequestSweepBatch    = 50
+)
+
+// JoinRequestPolicy is the join request configuration, see config.JoinRequests
+type JoinRequestPolicy struct {
+	Enabled       bool
+	Default       types.JoinRequestPolicy
+	Chats         map[types.TelegramChatID]types.JoinRequestPolicy
+	Question      string
+	Answers       []string
+	AnswerTimeout time.Duration
+	ReviewChatID  types.TelegramChatID
+}
+
+// For returns the policy of the chat
+func (p *JoinRequestPolicy) For(chatID types.TelegramChatID) types.JoinRequestPolicy {
+	if policy, ok := p.Chats[chatID]; ok {
+		return policy
+	}
+	return p.Default
+}
+
+// accepts reports whether the answer matches one of the accepted answers
+func (p *JoinRequestPolicy) accepts(answer string) bool {
+	if len(p.Answers) == 0 {
+		return true
+	}
+	answer = strings.TrimSpace(answer)
+	for _, accepted := range p.Answers {
+		if strings.EqualFold(answer, strings.TrimSpace(accepted)) {
+			return true
+		}
+	}
+	return false
+}
+
+// JoinRequestServiceImpl implements JoinRequestService
+type JoinRequestServiceImpl struct {
+	repo        repository.JoinRequestRepository
+	auth        service.AuthorizationService
+	telegramBot service.TelegramBotService
+	policy      atomic.Pointer[JoinRequestPolicy]
+	logger      service.Logger
+	now         func() time.Time
+}
+
+// NewJoinRequestService creates a new instance of JoinRequestServiceImpl.
+// A nil repo, when the database is unavailable, only supports the approve policy.
+func NewJoinRequestService(
+	repo repository.JoinRequestRepository,
+	auth service.AuthorizationService,
+	telegramBot service.TelegramBotService,
+	policy JoinRequestPolicy,
+	logger service.Logger,
+) *JoinRequestServiceImpl {
+	s := &JoinRequestServiceImpl{
+		repo:        repo,
+		auth:        auth,
+		telegramBot: telegramBot,
+		logger:      logger,
+		now:         time.Now,
+	}
+	s.SetPolicy(policy)
+	return s
+}
+
+// SetPolicy replaces the policy, used when the configuration is reloaded
+func (s *JoinRequestServiceImpl) SetPolicy(policy JoinRequestPolicy) {
+	s.policy.Store(&policy)
+}
+
+// HandleJoinRequest applies the policy of the chat to the request
+func (s *JoinRequestServiceImpl) HandleJoinRequest(
+	ctx context.Context, request *types.TelegramChatJoinRequest,
+) error {
+	p := s.policy.Load()
+	if !p.Enabled || request.Chat == nil || request.From == nil {
+		return nil
+	}
+	logger := s.logger.WithContext(ctx)
+
+	policy := p.For(request.Chat.ID)
+	logger.Info("Received join request",
+		"chat_id", request.Chat.ID, "user_id", request.From.ID, "policy", policy)
+	tracing.SpanFromContext(ctx).SetAttributes(tracing.String("join_request.policy", string(policy)))
+
+	joinRequest := entity.NewJoinRequest(request, policy)
+	if policy == types.JoinRequestPolicyApprove {
+		return s.decide(ctx, joinRequest, types.JoinRequestStatusApproved, 0, usecase.JoinReasonPolicy)
+	}
+	if s.repo == nil {
+		logger.Warn("Join request policy needs a database, leaving the request to the chat admins",
+			"chat_id", request.Chat.ID, "policy", policy)
+		return nil
+	}
+
+	switch policy {
+	case types.JoinRequestPolicyQuestion:
+		expiresAt := s.now().Add(p.AnswerTimeout)
+		joinRequest.ExpiresAt = &expiresAt
+		if err := s.repo.Upsert(ctx, joinRequest); err != nil {
+			return err
+		}
+		_, err := usecase.JoinQuestionHandler(ctx, joinRequest, p.Question, p.AnswerTimeout, s.telegramBot)
+		return err
+
+	case types.JoinRequestPolicyReview:
+		if err := s.repo.Upsert(ctx, joinRequest); err != nil {
+			return err
+		}
+		_, err := usecase.JoinReviewHandler(ctx, p.ReviewChatID, joinRequest, request.From,
+			reviewKeyboard(joinRequest), s.telegramBot)
+		return err
+
+	default:
+		return fmt.Errorf("unknown join request policy %q", policy)
+	}
+}
+
+// HandleAnswer decides the oldest request of the sender waiting for an answer to the question
+func (s *JoinRequestServiceImpl) HandleAnswer(
+	ctx context.Context, message *types.TelegramMessage,
+) (bool, error) {
+	if s.repo == nil || message.From == nil || message.Text == nil {
+		return false, nil
+	}
+
+	joinRequest, err := s.repo.OldestPendingQuestion(ctx, message.From.ID)
+	if errors.Is(err, domainErrors.ErrJoinRequestNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	status, reason := types.JoinRequestStatusApproved, usecase.JoinReasonCorrectAnswer
+	switch {
+	case joinRequest.IsExpired(s.now()):
+		status, reason = types.JoinRequestStatusDeclined, usecase.JoinReasonAnswerTimeout
+	case !s.policy.Load().accepts(*message.Text):
+		status, reason = types.JoinRequestStatusDeclined, usecase.JoinReasonWrongAnswer
+	}
+
+	if err := s.decide(ctx, joinRequest, status, 0, reason); err != nil {
+		return true, err
+	}
+	_, err = usecase.JoinAnswerHandler(ctx, joinRequest, reason, s.telegramBot)
+	return true, err
+}
+
+// HandleCallback applies the decision of an admin of the requested chat pressing a review button
+func (s *JoinRequestServiceImpl) HandleCallback(
+	ctx context.Context, query *types.TelegramCallbackQuery,
+) error {
+	status, chatID, userID, ok := parseReviewCallback(query.Data)
+	if !ok || query.From == nil || s.repo == nil {
+		_, err := usecase.JoinCallbackHandler(ctx, query.ID, usecase.JoinCallbackInvalid, s.telegramBot)
+		return err
+	}
+
+	err := s.auth.Authorize(ctx, query.From.ID, chatID, types.RoleAdmin)
+	if errors.Is(err, domainErrors.ErrPermissionDenied) {
+		s.logger.WithContext(ctx).Warn("Join request review denied",
+			"chat_id", chatID, "user_id", userID, "admin_id", query.From.ID)
+		_, err = usecase.JoinCallbackHandler(ctx, query.ID, usecase.JoinCallbackForbidden, s.telegramBot)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	joinRequest, err := s.repo.Get(ctx, chatID, userID)
+	if errors.Is(err, domainErrors.ErrJoinRequestNotFound) ||
+		(err == nil && joinRequest.Status != types.JoinRequestStatusPending) {
+		_, err = usecase.JoinCallbackHandler(ctx, query.ID, usecase.JoinCallbackHandled, s.telegramBot)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := s.decide(ctx, joinRequest, status, query.From.ID, usecase.JoinReasonReview); err != nil {
+		return err
+	}
+
+	if query.Message != nil && query.Message.Chat != nil {
+		if _, err := usecase.JoinReviewResultHandler(ctx, query.Message, status, query.From, s.telegramBot); err != nil {
+			s.logger.WithContext(ctx).Warn("Failed to update review message", "error", err)
+		}
+	}
+	outcome := usecase.JoinCallbackApproved
+	if status == types.JoinRequestStatusDeclined {
+		outcome = usecase.JoinCallbackDeclined
+	}
+	_, err = usecase.JoinCallbackHandler(ctx, query.ID, outcome, s.telegramBot)
+	return err
+}
+
+// Run declines the question requests left unanswered past their timeout until ctx is done,
+// so a user who never writes back is not kept waiting
+func (s *JoinRequestServiceImpl) Run(ctx context.Context) {
+	if s.repo == nil {
+		return
+	}
+	ticker := time.NewTicker(joinRequestSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		s.declineExpired(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// declineExpired declines the question requests past their answer timeout and tells the users
+func (s *JoinRequestServiceImpl) declineExpired(ctx context.Context) {
+	logger := s.logger.WithContext(ctx)
+	expired, err := s.repo.ExpiredPendingQuestions(ctx, s.now(), joinRequestSweepBatch)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Error("Failed to list expired join requests", "error", err)
+		}
+		return
+	}
+
+	for _, joinRequest := range expired {
+		err := s.decide(ctx, joinRequest, types.JoinRequestStatusDeclined, 0, usecase.JoinReasonAnswerTimeout)
+		var responseErr *types.ResponseError
+		if errors.As(err, &responseErr) && (responseErr.HTTPStatus == 400 || responseErr.IsChatUnreachable()) {
+			// The user withdrew the request or the bot lost the chat, only the record is left to close
+			logger.Info("Join request can no longer be declined with Telegram",
+				"chat_id", joinRequest.TelegramChatID, "user_id", joinRequest.TelegramUserID, "error", err)
+			err = s.record(ctx, joinRequest, types.JoinRequestStatusDeclined, 0, usecase.JoinReasonAnswerTimeout)
+			if err == nil {
+				continue
+			}
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Warn("Failed to decline expired join request",
+				"chat_id", joinRequest.TelegramChatID, "user_id", joinRequest.TelegramUserID, "error", err)
+			// Requests failing again and again go behind the others instead of filling every batch
+			if err := s.repo.CountDeclineTry(ctx, joinRequest); err != nil {
+				logger.Warn("Failed to count join request decline", "error", err)
+			}
+			continue
+		}
+		if _, err := usecase.JoinAnswerHandler(ctx, joinRequest, usecase.JoinReasonAnswerTimeout, s.telegramBot); err != nil {
+			logger.Warn("Failed to tell the user about the expired join request",
+				"user_id", joinRequest.TelegramUserID, "error", err)
+		}
+	}
+}
+
+// decide approves or declines the request with Telegram, then records the decision
+func (s *JoinRequestServiceImpl) decide(
+	ctx context.Context,
+	joinRequest *entity.JoinRequest,
+	status types.JoinRequestStatus,
+	decidedBy types.TelegramUserID,
+	reason string,
+) error {
+	var err error
+	action := "approve"
+	if status == types.JoinRequestStatusApproved {
+		_, err = s.telegramBot.ApproveChatJoinRequest(ctx, &types.ApproveChatJoinRequestRequest{
+			ChatID: joinRequest.TelegramChatID,
+			UserID: joinRequest.TelegramUserID,
+		})
+	} else {
+		action = "decline"
+		_, err = s.telegramBot.DeclineChatJoinRequest(ctx, &types.DeclineChatJoinRequestRequest{
+			ChatID: joinRequest.TelegramChatID,
+			UserID: joinRequest.TelegramUserID,
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to %s join request: %w", action, err)
+	}
+
+	s.logger.WithContext(ctx).Info("Join request decided",
+		"chat_id", joinRequest.TelegramChatID, "user_id", joinRequest.TelegramUserID,
+		"decision", status, "reason", reason, "decided_by", decidedBy)
+	joinRequestDecisions.With(string(joinRequest.Policy), string(status)).Inc()
+	return s.record(ctx, joinRequest, status, decidedBy, reason)
+}
+
+// record stores the final status of the request and its audit record
+func (s *JoinRequestServiceImpl) record(
+	ctx context.Context,
+	joinRequest *entity.JoinRequest,
+	status types.JoinRequestStatus,
+	decidedBy types.TelegramUserID,
+	reason string,
+) error {
+	joinRequest.Status = status
+	if s.repo == nil {
+		return nil
+	}
+	return s.repo.Decide(ctx, joinRequest, &entity.JoinRequestDecision{
+		TelegramChatID: joinRequest.TelegramChatID,
+		TelegramUserID: joinRequest.TelegramUserID,
+		Policy:         joinRequest.Policy,
+		Decision:       status,
+		DecidedBy:      decidedBy,
+		Reason:         reason,
+	})
+}
+
+// reviewKeyboard builds the approve and decline buttons of the review message
+func reviewKeyboard(joinRequest *entity.JoinRequest) types.InlineKeyboardMarkup {
+	data := func(action string) string {
+		return fmt.Sprintf("%s%s:%d:%d", JoinRequestCallbackPrefix, action,
+			joinRequest.TelegramChatID, joinRequest.TelegramUserID)
+	}
+	return types.InlineKeyboardMarkup{InlineKeyboard: [][]types.InlineKeyboardButton{{
+		types.NewCallbackButton("✅ Chấp nhận", data("approve")),
+		types.NewCallbackButton("❌ Từ chối", data("decline")),
+	}}}
+}
+
+// parseReviewCallback decodes the data of a review button
+func parseReviewCallback(data *string) (types.JoinRequestStatus, types.TelegramChatID, types.TelegramUserID, bool) {
+	if data == nil {
+		return "", 0, 0, false
+	}
+	parts := strings.Split(strings.TrimPrefix(*data, JoinRequestCallbackPrefix), ":")
+	if len(parts) != 3 {
+		return "", 0, 0, false
+	}
+
+	var status types.JoinRequestStatus
+	switch parts[0] {
+	case "approve":
+		status = types.JoinRequestStatusApproved
+	case "decline":
+		status = types.JoinRequestStatusDeclined
+	default:
+		return "", 0, 0, false
+	}
+	chatID, chatErr := strconv.ParseInt(parts[1], 10, 64)
+	userID, userErr := strconv.ParseInt(parts[2], 10, 64)
+	if chatErr != nil || userErr != nil {
+		return "", 0, 0, false
+	}
+	return status, types.TelegramChatID(chatID), types.TelegramUserID(userID), true
+}
@@ -0,0 +1,372 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"go-telegram-bot/internal/domain/entity"
+	domainErrors "go-telegram-bot/internal/domain/errors"
+	"go-telegram-bot/internal/domain/types"
+)
+
+// memoryJoinRequests is an in-memory JoinRequestRepository keeping the audit records
+type memoryJoinRequests struct {
+	requests  map[memberKey]*entity.JoinRequest
+	decisions []*entity.JoinRequestDecision
+}
+
+func newMemoryJoinRequests() *memoryJoinRequests {
+	return &memoryJoinRequests{requests: make(map[memberKey]*entity.JoinRequest)}
+}
+
+func (r *memoryJoinRequests) Upsert(_ context.Context, request *entity.JoinRequest) error {
+	r.requests[memberKey{request.TelegramChatID, request.TelegramUserID}] = request
+	return nil
+}
+
+func (r *memoryJoinRequests) Get(
+	_ context.Context, chatID types.TelegramChatID, userID types.TelegramUserID,
+) (*entity.JoinRequest, error) {
+	request, ok := r.requests[memberKey{chatID, userID}]
+	if !ok {
+		return nil, domainErrors.ErrJoinRequestNotFound
+	}
+	return request, nil
+}
+
+func (r *memoryJoinRequests) OldestPendingQuestion(
+	_ context.Context, userID types.TelegramUserID,
+) (*entity.JoinRequest, error) {
+	for key, request := range r.requests {
+		if key.user == userID && request.Policy == types.JoinRequestPolicyQuestion &&
+			request.Status == types.JoinRequestStatusPending {
+			return request, nil
+		}
+	}
+	return nil, domainErrors.ErrJoinRequestNotFound
+}
+
+func (r *memoryJoinRequests) ExpiredPendingQuestions(
+	_ context.Context, now time.Time, limit int,
+) ([]*entity.JoinRequest, error) {
+	var expired []*entity.JoinRequest
+	for _, request := range r.requests {
+		if request.Policy == types.JoinRequestPolicyQuestion && request.Status == types.JoinRequestStatusPending &&
+			request.IsExpired(now) {
+			expired = append(expired, request)
+		}
+	}
+	slices.SortFunc(expired, func(a, b *entity.JoinRequest) int {
+		return cmp.Or(cmp.Compare(a.DeclineTries, b.DeclineTries), a.ExpiresAt.Compare(*b.ExpiresAt))
+	})
+	return expired[:min(limit, len(expired))], nil
+}
+
+func (r *memoryJoinRequests) CountDeclineTry(_ context.Context, request *entity.JoinRequest) error {
+	r.requests[memberKey{request.TelegramChatID, request.TelegramUserID}].DeclineTries++
+	return nil
+}
+
+func (r *memoryJoinRequests) Decide(
+	_ context.Context, request *entity.JoinRequest, decision *entity.JoinRequestDecision,
+) error {
+	r.requests[memberKey{request.TelegramChatID, request.TelegramUserID}] = request
+	r.decisions = append(r.decisions, decision)
+	return nil
+}
+
+// joinBot records the join request decisions and the callback answers on top of recordingBot
+type joinBot struct {
+	recordingBot
+	approved, declined []types.TelegramUserID
+	answers            []string
+	edits              int
+}
+
+func (b *joinBot) ApproveChatJoinRequest(
+	_ context.Context, request *types.ApproveChatJoinRequestRequest,
+) (*types.ApproveChatJoinRequestResponse, error) {
+	b.approved = append(b.approved, request.UserID)
+	return &types.ApproveChatJoinRequestResponse{}, nil
+}
+
+func (b *joinBot) DeclineChatJoinRequest(
+	_ context.Context, request *types.DeclineChatJoinRequestRequest,
+) (*types.DeclineChatJoinRequestResponse, error) {
+	b.declined = append(b.declined, request.UserID)
+	return &types.DeclineChatJoinRequestResponse{}, nil
+}
+
+func (b *joinBot) AnswerCallbackQuery(
+	_ context.Context, request *types.AnswerCallbackQueryRequest,
+) (*types.AnswerCallbackQueryResponse, error) {
+	b.answers = append(b.answers, *request.Text)
+	return &types.AnswerCallbackQueryResponse{}, nil
+}
+
+func (b *joinBot) EditMessageText(
+	_ context.Context, _ *types.EditMessageTextRequest,
+) (*types.EditMessageTextResponse, error) {
+	b.edits++
+	return &types.EditMessageTextResponse{}, nil
+}
+
+func joinRequestFrom(userID types.TelegramUserID) *types.TelegramChatJoinRequest {
+	title := "Home"
+	return &types.TelegramChatJoinRequest{
+		Chat:       &types.TelegramChat{ID: chat, Type: types.ChatTypeSupergroup, Title: &title},
+		From:       &types.TelegramUser{ID: userID, FirstName: "Bình"},
+		UserChatID: types.TelegramChatID(userID),
+	}
+}
+
+func TestJoinRequestService_QuestionPolicy(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryJoinRequests()
+	bot := &joinBot{}
+	s := NewJoinRequestService(repo, nil, bot, JoinRequestPolicy{
+		Enabled:       true,
+		Default:       types.JoinRequestPolicyQuestion,
+		Question:      "Thủ đô của Việt Nam?",
+		Answers:       []string{"Hà Nội"},
+		AnswerTimeout: 10 * time.Minute,
+	}, nopLogger{})
+	start := time.Now()
+	s.now = func() time.Time { return start }
+
+	answer := func(userID types.TelegramUserID, text string) {
+		t.Helper()
+		handled, err := s.HandleAnswer(ctx, &types.TelegramMessage{
+			Chat: &types.TelegramChat{ID: types.TelegramChatID(userID), Type: types.ChatTypePrivate},
+			From: &types.TelegramUser{ID: userID},
+			Text: &text,
+		})
+		if err != nil || !handled {
+			t.Fatalf("expected the answer of %d to be handled, got %v, %v", userID, handled, err)
+		}
+	}
+
+	for _, userID := range []types.TelegramUserID{user, 4, 5} {
+		if err := s.HandleJoinRequest(ctx, joinRequestFrom(userID)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(bot.sent) != 3 {
+		t.Fatalf("expected the question to be asked three times, got %v", bot.sent)
+	}
+
+	answer(user, "  hà nội ")
+	answer(4, "Huế")
+	s.now = func() time.Time { return start.Add(11 * time.Minute) }
+	answer(5, "Hà Nội")
+
+	if len(bot.approved) != 1 || bot.approved[0] != user {
+		t.Fatalf("expected only user %d to be approved, got %v", user, bot.approved)
+	}
+	if len(bot.declined) != 2 {
+		t.Fatalf("expected the wrong and late answers to be declined, got %v", bot.declined)
+	}
+	reasons := map[types.TelegramUserID]string{}
+	for _, decision := range repo.decisions {
+		reasons[decision.TelegramUserID] = decision.Reason
+	}
+	if reasons[user] != "correct_answer" || reasons[4] != "wrong_answer" || reasons[5] != "answer_timeout" {
+		t.Fatalf("unexpected audit reasons %v", reasons)
+	}
+
+	again := "Hà Nội"
+	handled, err := s.HandleAnswer(ctx, &types.TelegramMessage{From: &types.TelegramUser{ID: user}, Text: &again})
+	if err != nil || handled {
+		t.Fatalf("expected no pending question once decided, got %v, %v", handled, err)
+	}
+}
+
+// refusingBot answers the declines of the listed users with the error of Telegram of the given
+// status, 400 for a request the user withdrew, 403 for a chat the bot left and 502 for an outage
+type refusingBot struct {
+	*joinBot
+	codes map[types.TelegramUserID]int
+}
+
+func (b refusingBot) DeclineChatJoinRequest(
+	ctx context.Context, request *types.DeclineChatJoinRequestRequest,
+) (*types.DeclineChatJoinRequestResponse, error) {
+	code, ok := b.codes[request.UserID]
+	if !ok {
+		return b.joinBot.DeclineChatJoinRequest(ctx, request)
+	}
+	description := map[int]string{
+		400: "Bad Request: HIDE_REQUESTER_MISSING",
+		403: "Forbidden: bot was kicked from the supergroup chat",
+		502: "Bad Gateway",
+	}[code]
+	return nil, &types.ResponseError{
+		Method:     "declineChatJoinRequest",
+		HTTPStatus: code,
+		Response:   &types.BaseResponse{ErrorCode: &code, Description: &description},
+	}
+}
+
+func TestJoinRequestService_DeclinesExpiredQuestions(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryJoinRequests()
+	bot := &joinBot{}
+	s := NewJoinRequestService(repo, nil, refusingBot{bot, map[types.TelegramUserID]int{4: 400}}, JoinRequestPolicy{
+		Enabled:       true,
+		Default:       types.JoinRequestPolicyQuestion,
+		Question:      "Thủ đô của Việt Nam?",
+		AnswerTimeout: 10 * time.Minute,
+	}, nopLogger{})
+	start := time.Now()
+
+	for i, userID := range []types.TelegramUserID{user, 4, 5} {
+		s.now = func() time.Time { return start.Add(time.Duration(i) * 4 * time.Minute) }
+		if err := s.HandleJoinRequest(ctx, joinRequestFrom(userID)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// The requests of user 3 and 4 expired without an answer, the one of user 5 has time left
+	s.now = func() time.Time { return start.Add(15 * time.Minute) }
+	s.declineExpired(ctx)
+
+	if len(bot.declined) != 1 || bot.declined[0] != user {
+		t.Fatalf("expected only user %d to be declined with Telegram, got %v", user, bot.declined)
+	}
+	if len(bot.sent) != 4 || bot.sent[3] != "⌛ Đã hết thời gian trả lời, yêu cầu tham gia «Home» đã bị từ chối." {
+		t.Fatalf("expected the questions then the timeout notice, got %v", bot.sent)
+	}
+	if len(repo.decisions) != 2 || repo.decisions[0].Reason != "answer_timeout" || repo.decisions[1].Reason != "answer_timeout" {
+		t.Fatalf("expected the expired and withdrawn requests to be recorded, got %v", repo.decisions)
+	}
+	if pending := repo.requests[memberKey{chat, 5}]; pending.Status != types.JoinRequestStatusPending {
+		t.Fatalf("expected the request of user 5 to stay pending, got %s", pending.Status)
+	}
+
+	s.declineExpired(ctx)
+	if len(repo.decisions) != 2 {
+		t.Fatalf("expected decided requests not to be swept again, got %v", repo.decisions)
+	}
+}
+
+func TestJoinRequestService_SweepSkipsFailingDeclines(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryJoinRequests()
+	bot := &joinBot{}
+	codes := map[types.TelegramUserID]int{6: 403}
+	s := NewJoinRequestService(repo, nil, refusingBot{bot, codes}, JoinRequestPolicy{
+		Enabled:       true,
+		Default:       types.JoinRequestPolicyQuestion,
+		Question:      "Thủ đô của Việt Nam?",
+		AnswerTimeout: 10 * time.Minute,
+	}, nopLogger{})
+	start := time.Now()
+
+	// More requests than a sweep reads fail with an outage, the ones of user 5 and 6 expired later
+	var userIDs []types.TelegramUserID
+	for i := range joinRequestSweepBatch + 1 {
+		userID := types.TelegramUserID(1000 + i)
+		codes[userID] = 502
+		userIDs = append(userIDs, userID)
+	}
+	for i, userID := range append(userIDs, 5, 6) {
+		s.now = func() time.Time { return start.Add(time.Duration(i) * time.Second) }
+		if err := s.HandleJoinRequest(ctx, joinRequestFrom(userID)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	s.now = func() time.Time { return start.Add(time.Hour) }
+	s.declineExpired(ctx)
+	s.declineExpired(ctx)
+
+	if len(bot.declined) != 1 || bot.declined[0] != 5 {
+		t.Fatalf("expected user 5 to be declined despite the failing requests, got %v", bot.declined)
+	}
+	if len(repo.decisions) != 2 {
+		t.Fatalf("expected user 5 and the unreachable chat of user 6 to be recorded, got %v", repo.decisions)
+	}
+	for _, userID := range userIDs {
+		if request := repo.requests[memberKey{chat, userID}]; request.Status != types.JoinRequestStatusPending ||
+			request.DeclineTries == 0 {
+			t.Fatalf("expected the failing request of user %d to stay pending with its tries counted, got %+v", userID, request)
+		}
+	}
+}
+
+func TestJoinRequestService_ReviewCallback(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryJoinRequests()
+	roles := newMemoryRoleRepo()
+	auth := NewAuthorizationService(roles, []types.TelegramUserID{owner}, nopLogger{})
+	if err := auth.Grant(ctx, owner, admin, chat, types.RoleAdmin); err != nil {
+		t.Fatalf("grant failed: %v", err)
+	}
+	bot := &joinBot{}
+	s := NewJoinRequestService(repo, auth, bot, JoinRequestPolicy{
+		Enabled:      true,
+		Default:      types.JoinRequestPolicyReview,
+		ReviewChatID: -200,
+	}, nopLogger{})
+
+	if err := s.HandleJoinRequest(ctx, joinRequestFrom(user)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(bot.sent) != 1 {
+		t.Fatalf("expected the request to be posted for review, got %v", bot.sent)
+	}
+
+	data := "join:approve:-100:3"
+	text := "review"
+	press := func(from types.TelegramUserID) {
+		t.Helper()
+		err := s.HandleCallback(ctx, &types.TelegramCallbackQuery{
+			ID:      "q",
+			From:    &types.TelegramUser{ID: from, FirstName: "Admin"},
+			Data:    &data,
+			Message: &types.TelegramMessage{MessageID: 1, Chat: &types.TelegramChat{ID: -200}, Text: &text},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	press(user)
+	if len(bot.approved) != 0 || bot.answers[0] != "⛔ Bạn không có quyền duyệt yêu cầu này" {
+		t.Fatalf("expected a member to be refused, got %v", bot.answers)
+	}
+	press(admin)
+	press(admin)
+	if len(bot.approved) != 1 || bot.edits != 1 {
+		t.Fatalf("expected a single approval, got %v and %d edits", bot.approved, bot.edits)
+	}
+	if bot.answers[2] != "Yêu cầu này đã được xử lý" {
+		t.Fatalf("expected the second press to be refused, got %v", bot.answers)
+	}
+	if len(repo.decisions) != 1 || repo.decisions[0].DecidedBy != admin {
+		t.Fatalf("expected the decision of the admin to be audited, got %+v", repo.decisions)
+	}
+}
+
+func TestParseReviewCallback(t *testing.T) {
+	cases := []struct {
+		data   string
+		status types.JoinRequestStatus
+		ok     bool
+	}{
+		{"join:approve:-100:3", types.JoinRequestStatusApproved, true},
+		{"join:decline:-100:3", types.JoinRequestStatusDeclined, true},
+		{"join:ban:-100:3", "", false},
+		{"join:approve:-100", "", false},
+		{"join:approve:x:3", "", false},
+	}
+	for _, c := range cases {
+		status, chatID, userID, ok := parseReviewCallback(&c.data)
+		if ok != c.ok || status != c.status || (ok && (chatID != chat || userID != user)) {
+			t.Errorf("parseReviewCallback(%q) = %s, %d, %d, %v", c.data, status, chatID, userID, ok)
+		}
+	}
+}
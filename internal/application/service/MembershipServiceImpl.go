@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	usecase "go-telegram-bot/internal/application/usecase/command"
+	"go-telegram-bot/internal/domain/entity"
+	domainErrors "go-telegram-bot/internal/domain/errors"
+	"go-telegram-bot/internal/domain/repository"
+	"go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+	"go-telegram-bot/internal/shared/tracing"
+)
+
+// Membership events, also the values of the event label of bot_membership_changes_total
+const (
+	membershipJoined   = "joined"
+	membershipLeft     = "left"
+	membershipPromoted = "promoted"
+	membershipDemoted  = "demoted"
+)
+
+// WelcomeMessages are the templates sent when the bot joins a group and when a user joins it,
+// an empty template disables the message
+type WelcomeMessages struct {
+	Group  string
+	Member string
+}
+
+// MembershipServiceImpl implements MembershipService with the chat and chat member repositories
+type MembershipServiceImpl struct {
+	chats       repository.ChatRepository
+	members     repository.ChatMemberRepository
+	telegramBot service.TelegramBotService
+	welcome     atomic.Pointer[WelcomeMessages]
+	logger      service.Logger
+}
+
+// NewMembershipService creates a new instance of MembershipServiceImpl.
+// Nil repositories, when the database is unavailable, leave only the welcome messages.
+func NewMembershipService(
+	chats repository.ChatRepository,
+	members repository.ChatMemberRepository,
+	telegramBot service.TelegramBotService,
+	welcome WelcomeMessages,
+	logger service.Logger,
+) *MembershipServiceImpl {
+	s := &MembershipServiceImpl{
+		chats:       chats,
+		members:     members,
+		telegramBot: telegramBot,
+		logger:      logger,
+	}
+	s.SetWelcome(welcome)
+	return s
+}
+
+// SetWelcome replaces the welcome templates, used when the configuration is reloaded
+func (s *MembershipServiceImpl) SetWelcome(welcome WelcomeMessages) {
+	s.welcome.Store(&welcome)
+}
+
+// HandleMyChatMember records the bot being added to, removed from, promoted or demoted in a chat,
+// and sends the group welcome message when the bot joins a group
+func (s *MembershipServiceImpl) HandleMyChatMember(
+	ctx context.Context, update *types.TelegramChatMemberUpdated,
+) error {
+	if update.Chat == nil || update.NewChatMember == nil || update.NewChatMember.User == nil {
+		return nil
+	}
+	status := update.NewStatus()
+
+	if err := s.syncChat(ctx, update.Chat, status.IsPresent(), nil); err != nil {
+		return err
+	}
+	event, err := s.recordMember(ctx, update.Chat.ID, update.NewChatMember.User,
+		update.OldStatus(), status, time.Unix(update.Date, 0))
+	if err != nil {
+		return err
+	}
+	if event == "" {
+		return nil
+	}
+
+	s.logger.WithContext(ctx).Info("Bot membership changed",
+		"chat_id", update.Chat.ID, "event", event, "status", status)
+	membershipChanges.With("bot", event).Inc()
+	tracing.SpanFromContext(ctx).AddEvent("bot_"+event, tracing.String("telegram.member_status", string(status)))
+
+	isGroup := update.Chat.Type == types.ChatTypeGroup || update.Chat.Type == types.ChatTypeSupergroup
+	if template := s.welcome.Load().Group; event == membershipJoined && isGroup && template != "" {
+		_, err = usecase.WelcomeHandler(ctx, update.Chat, update.From, template, s.telegramBot)
+	}
+	return err
+}
+
+// HandleChatMember records the status change of a member
+func (s *MembershipServiceImpl) HandleChatMember(
+	ctx context.Context, update *types.TelegramChatMemberUpdated,
+) error {
+	if update.Chat == nil || update.NewChatMember == nil || update.NewChatMember.User == nil {
+		return nil
+	}
+
+	event, err := s.recordMember(ctx, update.Chat.ID, update.NewChatMember.User,
+		update.OldStatus(), update.NewStatus(), time.Unix(update.Date, 0))
+	if err != nil || event == "" {
+		return err
+	}
+	membershipChanges.With("member", event).Inc()
+	return nil
+}
+
+// HandleServiceMessage applies title changes and records joining and leaving members.
+// Members are welcomed from the service message, which every bot in the group receives.
+func (s *MembershipServiceImpl) HandleServiceMessage(
+	ctx context.Context, message *types.TelegramMessage,
+) error {
+	if message.Chat == nil {
+		return nil
+	}
+	at := time.Unix(message.Date, 0)
+
+	if message.NewChatTitle != nil || message.GroupChatCreated != nil || message.SupergroupCreated != nil {
+		if err := s.syncChat(ctx, message.Chat, true, message.NewChatTitle); err != nil {
+			return err
+		}
+	}
+
+	template := s.welcome.Load().Member
+	for _, user := range message.NewChatMembers {
+		event, err := s.recordMember(ctx, message.Chat.ID, user,
+			types.ChatMemberStatusLeft, types.ChatMemberStatusMember, at)
+		if err != nil {
+			return err
+		}
+		if event != "" {
+			membershipChanges.With("member", event).Inc()
+		}
+		if user.IsBot || template == "" {
+			continue
+		}
+		if _, err := usecase.WelcomeHandler(ctx, message.Chat, user, template, s.telegramBot); err != nil {
+			return err
+		}
+	}
+
+	if user := message.LeftChatMember; user != nil {
+		event, err := s.recordMember(ctx, message.Chat.ID, user,
+			types.ChatMemberStatusMember, types.ChatMemberStatusLeft, at)
+		if err != nil {
+			return err
+		}
+		if event != "" {
+			membershipChanges.With("member", event).Inc()
+		}
+	}
+	return nil
+}
+
+// syncChat creates or updates the stored chat from the Telegram chat, marking it active or not
+func (s *MembershipServiceImpl) syncChat(
+	ctx context.Context, tgChat *types.TelegramChat, active bool, title *string,
+) error {
+	if s.chats == nil {
+		return nil
+	}
+	if title == nil {
+		title = tgChat.Title
+	}
+
+	chat, err := s.chats.GetByTelegramChatID(ctx, tgChat.ID)
+	if errors.Is(err, domainErrors.ErrChatNotFound) {
+		if !active {
+			return nil // nothing to deactivate
+		}
+		chat = entity.NewChat(tgChat.ID, tgChat.Type)
+		chat.Activate()
+		chat.UpdateDetails(title, tgChat.Username, nil)
+		return s.chats.Create(ctx, chat)
+	}
+	if err != nil {
+		return err
+	}
+
+	if active {
+		chat.Activate()
+	} else {
+		chat.Deactivate()
+	}
+	chat.ChatType = tgChat.Type
+	chat.UpdateDetails(title, tgChat.Username, nil)
+	return s.chats.Update(ctx, chat)
+}
+
+// recordMember stores the new status of the user in the chat and returns the resulting event,
+// computed from the stored status when known, otherwise from the old status reported by Telegram
+func (s *MembershipServiceImpl) recordMember(
+	ctx context.Context,
+	chatID types.TelegramChatID,
+	user *types.TelegramUser,
+	old, status types.ChatMemberStatus,
+	at time.Time,
+) (string, error) {
+	if s.members == nil {
+		return membershipEvent(old, status), nil
+	}
+
+	member, err := s.members.Get(ctx, chatID, user.ID)
+	if errors.Is(err, domainErrors.ErrChatMemberNotFound) {
+		member = entity.NewChatMember(chatID, user)
+		member.Status = old
+	} else if err != nil {
+		return "", err
+	}
+
+	event := membershipEvent(member.Status, status)
+	member.SetStatus(status, at)
+	return event, s.members.Upsert(ctx, member)
+}
+
+// membershipEvent names the change between two statuses, empty when nothing relevant changed
+func membershipEvent(old, status types.ChatMemberStatus) string {
+	switch {
+	case !old.IsPresent() && status.IsPresent():
+		return membershipJoined
+	case old.IsPresent() && !status.IsPresent():
+		return membershipLeft
+	case !old.IsAdmin() && status.IsAdmin():
+		return membershipPromoted
+	case old.IsAdmin() && !status.IsAdmin():
+		return membershipDemoted
+	default:
+		return ""
+	}
+}
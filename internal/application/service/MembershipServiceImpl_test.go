@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"go-telegram-bot/internal/domain/entity"
+	domainErrors "go-telegram-bot/internal/domain/errors"
+	"go-telegram-bot/internal/domain/repository"
+	"go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+)
+
+// memoryChats is an in-memory ChatRepository, every other method panics
+type memoryChats struct {
+	repository.ChatRepository
+	chats map[types.TelegramChatID]*entity.Chat
+}
+
+func (r *memoryChats) GetByTelegramChatID(_ context.Context, chatID types.TelegramChatID) (*entity.Chat, error) {
+	chat, ok := r.chats[chatID]
+	if !ok {
+		return nil, domainErrors.ErrChatNotFound
+	}
+	return chat, nil
+}
+
+func (r *memoryChats) Create(_ context.Context, chat *entity.Chat) error {
+	r.chats[chat.TelegramChatID] = chat
+	return nil
+}
+
+func (r *memoryChats) Update(_ context.Context, chat *entity.Chat) error {
+	r.chats[chat.TelegramChatID] = chat
+	return nil
+}
+
+type memberKey struct {
+	chat types.TelegramChatID
+	user types.TelegramUserID
+}
+
+// memoryMembers is an in-memory ChatMemberRepository
+type memoryMembers map[memberKey]*entity.ChatMember
+
+func (r memoryMembers) Get(
+	_ context.Context, chatID types.TelegramChatID, userID types.TelegramUserID,
+) (*entity.ChatMember, error) {
+	member, ok := r[memberKey{chatID, userID}]
+	if !ok {
+		return nil, domainErrors.ErrChatMemberNotFound
+	}
+	return member, nil
+}
+
+func (r memoryMembers) Upsert(_ context.Context, member *entity.ChatMember) error {
+	r[memberKey{member.TelegramChatID, member.TelegramUserID}] = member
+	return nil
+}
+
+// recordingBot records the messages sent, every other method panics
+type recordingBot struct {
+	service.TelegramBotService
+	sent []string
+}
+
+func (b *recordingBot) SendMessageWithResponse(
+	_ context.Context, request *types.SendMessageRequest,
+) (*types.SendMessageResponse, error) {
+	b.sent = append(b.sent, request.Text)
+	return &types.SendMessageResponse{}, nil
+}
+
+func TestMembershipService_TracksBotMembership(t *testing.T) {
+	ctx := context.Background()
+	chats := &memoryChats{chats: make(map[types.TelegramChatID]*entity.Chat)}
+	members := memoryMembers{}
+	bot := &recordingBot{}
+	s := NewMembershipService(chats, members, bot, WelcomeMessages{Group: "Xin chào {chat}!"}, nopLogger{})
+
+	title := "Home"
+	group := &types.TelegramChat{ID: chat, Type: types.ChatTypeGroup, Title: &title}
+	self := &types.TelegramUser{ID: 99, IsBot: true}
+	update := func(old, status types.ChatMemberStatus) *types.TelegramChatMemberUpdated {
+		return &types.TelegramChatMemberUpdated{
+			Chat:          group,
+			From:          &types.TelegramUser{ID: owner, FirstName: "An"},
+			OldChatMember: &types.TelegramChatMember{User: self, Status: old},
+			NewChatMember: &types.TelegramChatMember{User: self, Status: status},
+		}
+	}
+
+	if err := s.HandleMyChatMember(ctx, update(types.ChatMemberStatusLeft, types.ChatMemberStatusMember)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored := chats.chats[chat]; stored == nil || !stored.IsActive || *stored.Title != title {
+		t.Fatalf("expected an active chat titled %q, got %+v", title, stored)
+	}
+	if len(bot.sent) != 1 || bot.sent[0] != "Xin chào Home!" {
+		t.Fatalf("expected the group welcome, got %v", bot.sent)
+	}
+
+	_ = s.HandleMyChatMember(ctx, update(types.ChatMemberStatusMember, types.ChatMemberStatusAdministrator))
+	if member := members[memberKey{chat, 99}]; member.Status != types.ChatMemberStatusAdministrator {
+		t.Fatalf("expected the bot to be recorded as administrator, got %s", member.Status)
+	}
+
+	_ = s.HandleMyChatMember(ctx, update(types.ChatMemberStatusAdministrator, types.ChatMemberStatusKicked))
+	if chats.chats[chat].IsActive {
+		t.Fatal("expected the chat to be deactivated once the bot is removed")
+	}
+	if member := members[memberKey{chat, 99}]; member.LeftAt == nil {
+		t.Fatal("expected the departure of the bot to be stamped")
+	}
+	if len(bot.sent) != 1 {
+		t.Fatalf("expected no message after the first welcome, got %v", bot.sent)
+	}
+}
+
+func TestMembershipService_WelcomesNewMembers(t *testing.T) {
+	ctx := context.Background()
+	members := memoryMembers{}
+	bot := &recordingBot{}
+	s := NewMembershipService(nil, members, bot, WelcomeMessages{Member: "Chào {name}"}, nopLogger{})
+
+	title := "Home"
+	message := &types.TelegramMessage{
+		Chat: &types.TelegramChat{ID: chat, Type: types.ChatTypeSupergroup, Title: &title},
+		NewChatMembers: []*types.TelegramUser{
+			{ID: user, FirstName: "Bình"},
+			{ID: 98, IsBot: true, FirstName: "OtherBot"},
+		},
+	}
+	if err := s.HandleServiceMessage(ctx, message); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(bot.sent) != 1 || bot.sent[0] != "Chào Bình" {
+		t.Fatalf("expected only the user to be welcomed, got %v", bot.sent)
+	}
+	if member := members[memberKey{chat, user}]; member == nil || member.JoinedAt == nil {
+		t.Fatalf("expected the join to be recorded, got %+v", member)
+	}
+
+	left := &types.TelegramMessage{Chat: message.Chat, LeftChatMember: &types.TelegramUser{ID: user}}
+	_ = s.HandleServiceMessage(ctx, left)
+	if member := members[memberKey{chat, user}]; member.Status != types.ChatMemberStatusLeft {
+		t.Fatalf("expected the member to have left, got %s", member.Status)
+	}
+}
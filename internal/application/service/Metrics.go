@@ -0,0 +1,54 @@
+package service
+
+import "go-telegram-bot/internal/shared/metrics"
+
+// commandLabelUnknown groups unknown commands so user input cannot create new label values
+const commandLabelUnknown = "unknown"
+
+// Command handler metrics, exposed on the admin /metrics endpoint
+var (
+	handlerDuration = metrics.NewHistogramVec(metrics.DefaultLatencyBuckets, "command")
+	handlerErrors   = metrics.NewCounterVec("command")
+
+	// membershipChanges counts joins, departures, promotions and demotions of the bot and of members
+	membershipChanges = metrics.NewCounterVec("subject", "event")
+
+	// joinRequestDecisions counts approved and declined join requests by policy
+	joinRequestDecisions = metrics.NewCounterVec("policy", "decision")
+
+	// moderationActions counts the bans, mutes, warnings and purges by action
+	moderationActions = metrics.NewCounterVec("action")
+
+	// broadcastDeliveries counts the broadcast deliveries by outcome: sent, failed or blocked
+	broadcastDeliveries = metrics.NewCounterVec("status")
+
+	// scheduledRuns counts the runs of scheduled commands and reminders by outcome: ok or failed
+	scheduledRuns = metrics.NewCounterVec("kind", "status")
+
+	// ipChecks counts the lookups of the WAN address by outcome: unchanged, changed or failed
+	ipChecks = metrics.NewCounterVec("status")
+
+	// ddnsUpdates counts the DDNS updates by provider and outcome: ok or failed
+	ddnsUpdates = metrics.NewCounterVec("provider", "status")
+)
+
+func init() {
+	metrics.Default.RegisterHistogramVec("bot_handler_duration_seconds",
+		"Latency of command handlers by command.", handlerDuration)
+	metrics.Default.RegisterCounterVec("bot_handler_errors_total",
+		"Errors returned by command handlers by command.", handlerErrors)
+	metrics.Default.RegisterCounterVec("bot_membership_changes_total",
+		"Membership changes by subject, bot or member, and event.", membershipChanges)
+	metrics.Default.RegisterCounterVec("bot_join_requests_total",
+		"Join requests decided by policy and decision.", joinRequestDecisions)
+	metrics.Default.RegisterCounterVec("bot_moderation_actions_total",
+		"Moderation actions by action.", moderationActions)
+	metrics.Default.RegisterCounterVec("bot_broadcast_deliveries_total",
+		"Broadcast deliveries by status.", broadcastDeliveries)
+	metrics.Default.RegisterCounterVec("bot_scheduled_runs_total",
+		"Runs of scheduled jobs by kind, command or reminder, and status.", scheduledRuns)
+	metrics.Default.RegisterCounterVec("bot_ip_checks_total",
+		"Lookups of the WAN address by status.", ipChecks)
+	metrics.Default.RegisterCounterVec("bot_ddns_updates_total",
+		"DDNS updates by provider and status.", ddnsUpdates)
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"go-telegram-bot/internal/domain/entity"
+	domainErrors "go-telegram-bot/internal/domain/errors"
+	"go-telegram-bot/internal/domain/repository"
+	"go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+)
+
+// Moderation actions, also the values of the action label of bot_moderation_actions_total
+const (
+	moderationBan     = "ban"
+	moderationAutoBan = "auto_ban"
+	moderationUnban   = "unban"
+	moderationMute    = "mute"
+	moderationWarn    = "warn"
+	moderationPurge   = "purge"
+)
+
+// ModerationPolicy is the moderation configuration, see config.Moderation
+type ModerationPolicy struct {
+	WarnLimit       int
+	WarnBanDuration time.Duration
+	PurgeLimit      int
+}
+
+// ModerationServiceImpl implements ModerationService with the Telegram moderation methods
+type ModerationServiceImpl struct {
+	warnings    repository.WarningRepository
+	auth        service.AuthorizationService
+	telegramBot service.TelegramBotService
+	policy      atomic.Pointer[ModerationPolicy]
+	logger      service.Logger
+	now         func() time.Time
+}
+
+// NewModerationService creates a new instance of ModerationServiceImpl.
+// A nil warnings repository, when the database is unavailable, disables /warn.
+func NewModerationService(
+	warnings repository.WarningRepository,
+	auth service.AuthorizationService,
+	telegramBot service.TelegramBotService,
+	policy ModerationPolicy,
+	logger service.Logger,
+) *ModerationServiceImpl {
+	s := &ModerationServiceImpl{
+		warnings:    warnings,
+		auth:        auth,
+		telegramBot: telegramBot,
+		logger:      logger,
+		now:         time.Now,
+	}
+	s.SetPolicy(policy)
+	return s
+}
+
+// SetPolicy replaces the policy, used when the configuration is reloaded
+func (s *ModerationServiceImpl) SetPolicy(policy ModerationPolicy) {
+	s.policy.Store(&policy)
+}
+
+// Ban removes the user from the chat for duration, forever when zero
+func (s *ModerationServiceImpl) Ban(
+	ctx context.Context, moderator, userID types.TelegramUserID, chatID types.TelegramChatID, duration time.Duration,
+) error {
+	if err := s.checkOutranks(ctx, moderator, userID, chatID); err != nil {
+		return err
+	}
+	return s.ban(ctx, moderator, userID, chatID, duration, moderationBan)
+}
+
+// Unban lets the user join again, it does nothing when the user is not banned
+func (s *ModerationServiceImpl) Unban(
+	ctx context.Context, moderator, userID types.TelegramUserID, chatID types.TelegramChatID,
+) error {
+	if err := s.checkOutranks(ctx, moderator, userID, chatID); err != nil {
+		return err
+	}
+
+	onlyIfBanned := true
+	if _, err := s.telegramBot.UnbanChatMember(ctx, &types.UnbanChatMemberRequest{
+		ChatID:       chatID,
+		UserID:       userID,
+		OnlyIfBanned: &onlyIfBanned,
+	}); err != nil {
+		return fmt.Errorf("failed to unban user: %w", err)
+	}
+	s.record(ctx, moderationUnban, moderator, userID, chatID, 0)
+
+	if s.warnings == nil {
+		return nil
+	}
+	return s.warnings.Clear(ctx, chatID, userID)
+}
+
+// Mute takes away the permission to send messages of the user for duration, forever when zero
+func (s *ModerationServiceImpl) Mute(
+	ctx context.Context, moderator, userID types.TelegramUserID, chatID types.TelegramChatID, duration time.Duration,
+) error {
+	if err := s.checkOutranks(ctx, moderator, userID, chatID); err != nil {
+		return err
+	}
+
+	if _, err := s.telegramBot.RestrictChatMember(ctx, &types.RestrictChatMemberRequest{
+		ChatID:      chatID,
+		UserID:      userID,
+		Permissions: types.MutedPermissions(),
+		UntilDate:   s.untilDate(duration),
+	}); err != nil {
+		return fmt.Errorf("failed to mute user: %w", err)
+	}
+	s.record(ctx, moderationMute, moderator, userID, chatID, duration)
+	return nil
+}
+
+// Warn stores a warning, the warning reaching the limit bans the user and clears their warnings
+func (s *ModerationServiceImpl) Warn(
+	ctx context.Context, moderator, userID types.TelegramUserID, chatID types.TelegramChatID, reason string,
+) (*service.WarnOutcome, error) {
+	if s.warnings == nil {
+		return nil, domainErrors.ErrServiceUnavailable
+	}
+	if err := s.checkOutranks(ctx, moderator, userID, chatID); err != nil {
+		return nil, err
+	}
+
+	count, err := s.warnings.Add(ctx, entity.NewWarning(chatID, userID, moderator, reason))
+	if err != nil {
+		return nil, fmt.Errorf("failed to store warning: %w", err)
+	}
+	s.record(ctx, moderationWarn, moderator, userID, chatID, 0)
+
+	policy := s.policy.Load()
+	outcome := &service.WarnOutcome{Count: count, Limit: policy.WarnLimit}
+	if policy.WarnLimit == 0 || count < policy.WarnLimit {
+		return outcome, nil
+	}
+
+	if err := s.ban(ctx, moderator, userID, chatID, policy.WarnBanDuration, moderationAutoBan); err != nil {
+		return nil, err
+	}
+	outcome.Banned, outcome.BanDuration = true, policy.WarnBanDuration
+	return outcome, s.warnings.Clear(ctx, chatID, userID)
+}
+
+// Purge deletes the messages from fromID to toID included, in batches of types.MaxDeleteMessages.
+// Telegram skips the IDs that do not exist or are too old to be deleted by bots.
+func (s *ModerationServiceImpl) Purge(
+	ctx context.Context, chatID types.TelegramChatID, fromID, toID int64,
+) (int, error) {
+	if fromID <= 0 || fromID > toID {
+		return 0, fmt.Errorf("%w: message range %d..%d", domainErrors.ErrInvalidInput, fromID, toID)
+	}
+	count := int(toID - fromID + 1)
+	if limit := s.policy.Load().PurgeLimit; limit > 0 && count > limit {
+		return 0, fmt.Errorf("%w: %d messages, the limit is %d", domainErrors.ErrTooManyMessages, count, limit)
+	}
+
+	for start := fromID; start <= toID; start += types.MaxDeleteMessages {
+		end := min(start+types.MaxDeleteMessages-1, toID)
+		ids := make([]int64, 0, end-start+1)
+		for id := start; id <= end; id++ {
+			ids = append(ids, id)
+		}
+		if _, err := s.telegramBot.DeleteMessages(ctx, &types.DeleteMessagesRequest{
+			ChatID:     chatID,
+			MessageIDs: ids,
+		}); err != nil {
+			return 0, fmt.Errorf("failed to delete messages: %w", err)
+		}
+	}
+
+	s.logger.WithContext(ctx).Info("Purged messages", "chat_id", chatID, "from", fromID, "to", toID)
+	moderationActions.With(moderationPurge).Inc()
+	return count, nil
+}
+
+// ban bans the user, action tells a /ban from the ban following the last warning
+func (s *ModerationServiceImpl) ban(
+	ctx context.Context,
+	moderator, userID types.TelegramUserID,
+	chatID types.TelegramChatID,
+	duration time.Duration,
+	action string,
+) error {
+	if _, err := s.telegramBot.BanChatMember(ctx, &types.BanChatMemberRequest{
+		ChatID:    chatID,
+		UserID:    userID,
+		UntilDate: s.untilDate(duration),
+	}); err != nil {
+		return fmt.Errorf("failed to ban user: %w", err)
+	}
+	s.record(ctx, action, moderator, userID, chatID, duration)
+	return nil
+}
+
+// checkOutranks refuses to moderate a user whose role is not below the role of the moderator
+func (s *ModerationServiceImpl) checkOutranks(
+	ctx context.Context, moderator, userID types.TelegramUserID, chatID types.TelegramChatID,
+) error {
+	moderatorRole, err := s.auth.RoleOf(ctx, moderator, chatID)
+	if err != nil {
+		return err
+	}
+	userRole, err := s.auth.RoleOf(ctx, userID, chatID)
+	if err != nil {
+		return err
+	}
+	if !moderatorRole.Outranks(userRole) {
+		return fmt.Errorf("%w: a %s cannot moderate a %s", domainErrors.ErrPermissionDenied, moderatorRole, userRole)
+	}
+	return nil
+}
+
+// untilDate converts a duration into the until_date of Telegram, nil meaning forever
+func (s *ModerationServiceImpl) untilDate(duration time.Duration) *int64 {
+	if duration <= 0 {
+		return nil
+	}
+	until := s.now().Add(duration).Unix()
+	return &until
+}
+
+// record logs and counts a moderation action
+func (s *ModerationServiceImpl) record(
+	ctx context.Context,
+	action string,
+	moderator, userID types.TelegramUserID,
+	chatID types.TelegramChatID,
+	duration time.Duration,
+) {
+	s.logger.WithContext(ctx).Info("Moderation action",
+		"action", action, "chat_id", chatID, "user_id", userID, "moderator_id", moderator, "duration", duration)
+	moderationActions.With(action).Inc()
+}
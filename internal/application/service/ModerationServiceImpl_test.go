@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-telegram-bot/internal/domain/entity"
+	domainErrors "go-telegram-bot/internal/domain/errors"
+	"go-telegram-bot/internal/domain/repository"
+	"go-telegram-bot/internal/domain/types"
+)
+
+// memoryWarnings is an in-memory WarningRepository
+type memoryWarnings map[memberKey][]*entity.Warning
+
+func (r memoryWarnings) Add(_ context.Context, warning *entity.Warning) (int, error) {
+	key := memberKey{warning.TelegramChatID, warning.TelegramUserID}
+	r[key] = append(r[key], warning)
+	return len(r[key]), nil
+}
+
+func (r memoryWarnings) Count(_ context.Context, chatID types.TelegramChatID, userID types.TelegramUserID) (int, error) {
+	return len(r[memberKey{chatID, userID}]), nil
+}
+
+func (r memoryWarnings) Clear(_ context.Context, chatID types.TelegramChatID, userID types.TelegramUserID) error {
+	delete(r, memberKey{chatID, userID})
+	return nil
+}
+
+// moderationBot records the bans and the deleted message batches, every other method panics
+type moderationBot struct {
+	recordingBot
+	bans    []*types.BanChatMemberRequest
+	deleted [][]int64
+}
+
+func (b *moderationBot) BanChatMember(
+	_ context.Context, request *types.BanChatMemberRequest,
+) (*types.BanChatMemberResponse, error) {
+	b.bans = append(b.bans, request)
+	return &types.BanChatMemberResponse{}, nil
+}
+
+func (b *moderationBot) DeleteMessages(
+	_ context.Context, request *types.DeleteMessagesRequest,
+) (*types.DeleteMessagesResponse, error) {
+	b.deleted = append(b.deleted, request.MessageIDs)
+	return &types.DeleteMessagesResponse{}, nil
+}
+
+func newTestModeration(t *testing.T, warnings repository.WarningRepository, bot *moderationBot, policy ModerationPolicy) *ModerationServiceImpl {
+	t.Helper()
+	auth := NewAuthorizationService(newMemoryRoleRepo(), []types.TelegramUserID{owner}, nopLogger{})
+	if err := auth.Grant(context.Background(), owner, admin, chat, types.RoleAdmin); err != nil {
+		t.Fatalf("grant failed: %v", err)
+	}
+	return NewModerationService(warnings, auth, bot, policy, nopLogger{})
+}
+
+func TestModerationService_WarningsLeadToBan(t *testing.T) {
+	ctx := context.Background()
+	warnings := memoryWarnings{}
+	bot := &moderationBot{}
+	s := newTestModeration(t, warnings, bot, ModerationPolicy{WarnLimit: 2, WarnBanDuration: time.Hour})
+	now := time.Unix(1_700_000_000, 0)
+	s.now = func() time.Time { return now }
+
+	outcome, err := s.Warn(ctx, admin, user, chat, "spam")
+	if err != nil || outcome.Count != 1 || outcome.Banned {
+		t.Fatalf("expected a first warning, got %+v, %v", outcome, err)
+	}
+	outcome, err = s.Warn(ctx, admin, user, chat, "spam")
+	if err != nil || !outcome.Banned || outcome.BanDuration != time.Hour {
+		t.Fatalf("expected the second warning to ban, got %+v, %v", outcome, err)
+	}
+	if len(bot.bans) != 1 || *bot.bans[0].UntilDate != now.Add(time.Hour).Unix() {
+		t.Fatalf("expected a one hour ban, got %+v", bot.bans)
+	}
+	if count, _ := warnings.Count(ctx, chat, user); count != 0 {
+		t.Fatalf("expected the warnings to be cleared by the ban, got %d", count)
+	}
+
+	if _, err := s.Warn(ctx, admin, owner, chat, ""); !errors.Is(err, domainErrors.ErrPermissionDenied) {
+		t.Fatalf("expected an admin not to warn an owner, got %v", err)
+	}
+	if err := s.Ban(ctx, admin, admin, chat, 0); !errors.Is(err, domainErrors.ErrPermissionDenied) {
+		t.Fatalf("expected an admin not to ban an admin, got %v", err)
+	}
+}
+
+func TestModerationService_WarnWithoutDatabase(t *testing.T) {
+	s := newTestModeration(t, nil, &moderationBot{}, ModerationPolicy{WarnLimit: 3})
+	if _, err := s.Warn(context.Background(), admin, user, chat, ""); !errors.Is(err, domainErrors.ErrServiceUnavailable) {
+		t.Fatalf("expected warnings to be unavailable, got %v", err)
+	}
+}
+
+func TestModerationService_PurgeInBatches(t *testing.T) {
+	ctx := context.Background()
+	bot := &moderationBot{}
+	s := newTestModeration(t, memoryWarnings{}, bot, ModerationPolicy{PurgeLimit: 200})
+
+	count, err := s.Purge(ctx, chat, 1001, 1150)
+	if err != nil || count != 150 {
+		t.Fatalf("expected 150 messages to be purged, got %d, %v", count, err)
+	}
+	if len(bot.deleted) != 2 || len(bot.deleted[0]) != types.MaxDeleteMessages || bot.deleted[1][49] != 1150 {
+		t.Fatalf("expected batches of 100 and 50 messages, got %d batches", len(bot.deleted))
+	}
+
+	if _, err := s.Purge(ctx, chat, 1, 201); !errors.Is(err, domainErrors.ErrTooManyMessages) {
+		t.Fatalf("expected the purge limit to apply, got %v", err)
+	}
+}
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	usecase "go-telegram-bot/internal/application/usecase/command"
+	"go-telegram-bot/internal/domain/entity"
+	domainErrors "go-telegram-bot/internal/domain/errors"
+	"go-telegram-bot/internal/domain/types"
+)
+
+// ReminderCallbackPrefix starts the data of the reminder buttons: remind:<snooze|cancel|drop>:<job ID>[:<minutes>].
+// cancel answers the confirmation of a reminder, drop answers the list of /reminders.
+const ReminderCallbackPrefix = "remind:"
+
+// reminderRetryDelay delays a reminder whose delivery failed on an error which may pass
+const reminderRetryDelay = time.Minute
+
+// reminderSnoozes are the snooze buttons of a delivered reminder, in minutes
+var reminderSnoozes = []struct {
+	label   string
+	minutes int
+}{
+	{"💤 10 phút", 10},
+	{"💤 1 giờ", 60},
+	{"💤 Ngày mai", 24 * 60},
+}
+
+// Remind stores the reminder and confirms it in reply to the message which asked for it
+func (s *SchedulerServiceImpl) Remind(
+	ctx context.Context,
+	user types.TelegramUserID,
+	chatID types.TelegramChatID,
+	chatType types.ChatType,
+	messageID int64,
+	text string,
+	at time.Time,
+) (*entity.ScheduledJob, error) {
+	if s.repo == nil {
+		return nil, domainErrors.ErrServiceUnavailable
+	}
+	now := s.now()
+	if !at.After(now) {
+		return nil, fmt.Errorf("%w: %s is not in the future", domainErrors.ErrInvalidInput, at.Format(time.DateTime))
+	}
+
+	pending, err := s.repo.ListByCreator(ctx, user, types.ScheduleKindReminder)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count reminders: %w", err)
+	}
+	if s.options.MaxReminders > 0 && len(pending) >= s.options.MaxReminders {
+		return nil, fmt.Errorf("%w: the user has %d reminders", domainErrors.ErrTooManySchedules, len(pending))
+	}
+
+	loc, err := s.UserLocation(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	job := entity.NewReminder(user, chatID, chatType, messageID, text, at.In(now.Location()), loc.String())
+	if err := s.repo.Create(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to store reminder: %w", err)
+	}
+	s.logger.WithContext(ctx).Info("Reminder set", "schedule_id", job.ID, "user_id", user, "chat_id", chatID, "at", at)
+
+	if _, err := usecase.ReminderCreatedHandler(ctx, job, reminderCancelKeyboard(job.ID), s.telegramBot); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// UserLocation returns the time zone of the private chat of the user, whose ID is the ID of the chat
+func (s *SchedulerServiceImpl) UserLocation(ctx context.Context, user types.TelegramUserID) (*time.Location, error) {
+	return s.location(ctx, types.TelegramChatID(user), "")
+}
+
+// ListReminders posts the reminders the user may see in the chat
+func (s *SchedulerServiceImpl) ListReminders(
+	ctx context.Context, user types.TelegramUserID, chatID types.TelegramChatID,
+) error {
+	if s.repo == nil {
+		return domainErrors.ErrServiceUnavailable
+	}
+	jobs, err := s.visibleReminders(ctx, user, chatID)
+	if err != nil {
+		return err
+	}
+	_, err = usecase.ReminderListHandler(ctx, chatID, jobs, reminderListKeyboard(jobs), s.telegramBot)
+	return err
+}
+
+// visibleReminders returns the reminders of the user, in a group only those set in the group so
+// that reminders set in private are not shown to its members
+func (s *SchedulerServiceImpl) visibleReminders(
+	ctx context.Context, user types.TelegramUserID, chatID types.TelegramChatID,
+) ([]*entity.ScheduledJob, error) {
+	jobs, err := s.repo.ListByCreator(ctx, user, types.ScheduleKindReminder)
+	if err != nil || types.TelegramChatID(user) == chatID {
+		return jobs, err
+	}
+	visible := jobs[:0]
+	for _, job := range jobs {
+		if job.TelegramChatID == chatID {
+			visible = append(visible, job)
+		}
+	}
+	return visible, nil
+}
+
+// HandleCallback snoozes or cancels the reminder when its creator presses one of its buttons
+func (s *SchedulerServiceImpl) HandleCallback(ctx context.Context, query *types.TelegramCallbackQuery) error {
+	action, id, minutes, ok := parseReminderCallback(query.Data)
+	if !ok || query.From == nil || s.repo == nil {
+		_, err := usecase.ReminderCallbackHandler(ctx, query.ID, usecase.ReminderCallbackInvalid, s.telegramBot)
+		return err
+	}
+
+	job, err := s.repo.Get(ctx, id)
+	if errors.Is(err, domainErrors.ErrScheduleNotFound) || (err == nil && job.Kind != types.ScheduleKindReminder) {
+		_, err = usecase.ReminderCallbackHandler(ctx, query.ID, usecase.ReminderCallbackGone, s.telegramBot)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if query.From.ID != job.CreatedBy {
+		_, err = usecase.ReminderCallbackHandler(ctx, query.ID, usecase.ReminderCallbackForbidden, s.telegramBot)
+		return err
+	}
+
+	var outcome string
+	switch action {
+	case "snooze":
+		at := s.now().Add(time.Duration(minutes) * time.Minute)
+		if _, err := s.repo.Reschedule(ctx, job.ID, at); err != nil {
+			return err
+		}
+		job.NextRunAt, outcome = &at, usecase.ReminderCallbackSnoozed
+		s.logger.WithContext(ctx).Info("Reminder snoozed", "schedule_id", job.ID, "user_id", job.CreatedBy, "at", at)
+	default:
+		if _, err := s.repo.Delete(ctx, job.TelegramChatID, job.ID); err != nil {
+			return err
+		}
+		outcome = usecase.ReminderCallbackCancelled
+		s.logger.WithContext(ctx).Info("Reminder cancelled", "schedule_id", job.ID, "user_id", job.CreatedBy)
+	}
+
+	if query.Message != nil && query.Message.Chat != nil {
+		if err := s.updateReminderMessage(ctx, query.Message, action, job); err != nil {
+			s.logger.WithContext(ctx).Warn("Failed to update reminder message", "schedule_id", job.ID, "error", err)
+		}
+	}
+	_, err = usecase.ReminderCallbackHandler(ctx, query.ID, outcome, s.telegramBot)
+	return err
+}
+
+// updateReminderMessage replaces the buttons of the message pressed: a list is rendered again without
+// the cancelled reminder, a single reminder gets the outcome of the press
+func (s *SchedulerServiceImpl) updateReminderMessage(
+	ctx context.Context, message *types.TelegramMessage, action string, job *entity.ScheduledJob,
+) error {
+	if action == "drop" {
+		jobs, err := s.visibleReminders(ctx, job.CreatedBy, message.Chat.ID)
+		if err != nil {
+			return err
+		}
+		_, err = usecase.ReminderListUpdateHandler(ctx, message, jobs, reminderListKeyboard(jobs), s.telegramBot)
+		return err
+	}
+	_, err := usecase.ReminderOutcomeHandler(ctx, message, job, action == "snooze", s.telegramBot)
+	return err
+}
+
+// remind delivers a claimed reminder in reply to the message which asked for it. A delivery failing on
+// an error which may pass is tried again a minute later rather than lost.
+func (s *SchedulerServiceImpl) remind(ctx context.Context, job *entity.ScheduledJob) error {
+	_, err := usecase.ReminderDeliveryHandler(ctx, job, reminderSnoozeKeyboard(job.ID), s.telegramBot)
+
+	var responseErr *types.ResponseError
+	retry := errors.Is(err, domainErrors.ErrServiceUnavailable) ||
+		(errors.As(err, &responseErr) && (responseErr.IsNetworkFailure() || responseErr.IsServerError()))
+	if retry && ctx.Err() == nil {
+		if _, rescheduleErr := s.repo.Reschedule(ctx, job.ID, s.now().Add(reminderRetryDelay)); rescheduleErr != nil {
+			s.logger.WithContext(ctx).Error("Failed to retry reminder, it is lost",
+				"schedule_id", job.ID, "error", rescheduleErr)
+		}
+	}
+	return err
+}
+
+// reminderCancelKeyboard builds the cancel button of the confirmation of a reminder
+func reminderCancelKeyboard(id int64) types.InlineKeyboardMarkup {
+	return types.InlineKeyboardMarkup{InlineKeyboard: [][]types.InlineKeyboardButton{{
+		types.NewCallbackButton("🗑 Huỷ", reminderCallbackData("cancel", id, 0)),
+	}}}
+}
+
+// reminderListKeyboard builds the cancel button of every reminder of a /reminders list
+func reminderListKeyboard(jobs []*entity.ScheduledJob) types.InlineKeyboardMarkup {
+	rows := make([][]types.InlineKeyboardButton, 0, len(jobs))
+	for _, job := range jobs {
+		rows = append(rows, []types.InlineKeyboardButton{
+			types.NewCallbackButton(fmt.Sprintf("🗑 Huỷ #%d", job.ID), reminderCallbackData("drop", job.ID, 0)),
+		})
+	}
+	return types.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// reminderSnoozeKeyboard builds the snooze buttons of a delivered reminder
+func reminderSnoozeKeyboard(id int64) types.InlineKeyboardMarkup {
+	row := make([]types.InlineKeyboardButton, 0, len(reminderSnoozes))
+	for _, snooze := range reminderSnoozes {
+		row = append(row, types.NewCallbackButton(snooze.label, reminderCallbackData("snooze", id, snooze.minutes)))
+	}
+	return types.InlineKeyboardMarkup{InlineKeyboard: [][]types.InlineKeyboardButton{row}}
+}
+
+// reminderCallbackData encodes a reminder button, minutes only for snooze
+func reminderCallbackData(action string, id int64, minutes int) string {
+	if action == "snooze" {
+		return fmt.Sprintf("%s%s:%d:%d", ReminderCallbackPrefix, action, id, minutes)
+	}
+	return fmt.Sprintf("%s%s:%d", ReminderCallbackPrefix, action, id)
+}
+
+// parseReminderCallback decodes the data of a reminder button
+func parseReminderCallback(data *string) (action string, id int64, minutes int, ok bool) {
+	if data == nil {
+		return "", 0, 0, false
+	}
+	parts := strings.Split(strings.TrimPrefix(*data, ReminderCallbackPrefix), ":")
+	action = parts[0]
+	switch {
+	case action == "snooze" && len(parts) == 3:
+		minutes, err := strconv.Atoi(parts[2])
+		if err != nil || minutes <= 0 {
+			return "", 0, 0, false
+		}
+		id, err := strconv.ParseInt(parts[1], 10, 64)
+		return action, id, minutes, err == nil && id > 0
+	case (action == "cancel" || action == "drop") && len(parts) == 2:
+		id, err := strconv.ParseInt(parts[1], 10, 64)
+		return action, id, 0, err == nil && id > 0
+	}
+	return "", 0, 0, false
+}
@@ -0,0 +1,319 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-telegram-bot/internal/domain/entity"
+	domainErrors "go-telegram-bot/internal/domain/errors"
+	"go-telegram-bot/internal/domain/repository"
+	"go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+	"go-telegram-bot/internal/shared/cron"
+)
+
+// maxScheduleError is the length of the last_error column of scheduled_jobs
+const maxScheduleError = 255
+
+// SchedulerOptions is the scheduler configuration, see config.Scheduler
+type SchedulerOptions struct {
+	PollInterval time.Duration
+	Timezone     *time.Location
+	MaxPerChat   int
+	MaxReminders int // per user
+}
+
+// SchedulerServiceImpl implements SchedulerService and ReminderService, Run executes the due commands
+// with the runner and delivers the due reminders
+type SchedulerServiceImpl struct {
+	repo        repository.ScheduleRepository
+	chats       repository.ChatRepository
+	auth        service.AuthorizationService
+	telegramBot service.TelegramBotService
+	runner      service.CommandRunner
+	options     SchedulerOptions
+	logger      service.Logger
+	now         func() time.Time
+}
+
+// NewSchedulerService creates a new instance of SchedulerServiceImpl.
+// A nil repo, when the database is unavailable, makes every command unavailable and Run return at once.
+func NewSchedulerService(
+	repo repository.ScheduleRepository,
+	chats repository.ChatRepository,
+	auth service.AuthorizationService,
+	telegramBot service.TelegramBotService,
+	options SchedulerOptions,
+	logger service.Logger,
+) *SchedulerServiceImpl {
+	if options.Timezone == nil {
+		options.Timezone = time.UTC
+	}
+	return &SchedulerServiceImpl{
+		repo:        repo,
+		chats:       chats,
+		auth:        auth,
+		telegramBot: telegramBot,
+		options:     options,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// SetRunner sets what runs the commands of the jobs. The bot use case runs them, and it is created
+// after the services it depends on, this one included.
+func (s *SchedulerServiceImpl) SetRunner(runner service.CommandRunner) {
+	s.runner = runner
+}
+
+// Schedule validates the timing in the time zone of the job, then stores the job with its first run
+func (s *SchedulerServiceImpl) Schedule(
+	ctx context.Context,
+	creator types.TelegramUserID,
+	chatID types.TelegramChatID,
+	chatType types.ChatType,
+	commandText string,
+	spec service.ScheduleSpec,
+) (*entity.ScheduledJob, error) {
+	if s.repo == nil {
+		return nil, domainErrors.ErrServiceUnavailable
+	}
+
+	loc, err := s.location(ctx, chatID, spec.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	job := &entity.ScheduledJob{
+		Kind:           types.ScheduleKindCommand,
+		TelegramChatID: chatID,
+		ChatType:       chatType,
+		CreatedBy:      creator,
+		Text:           commandText,
+		Cron:           spec.Cron,
+		Timezone:       loc.String(),
+	}
+	if job.NextRunAt, err = s.firstRun(job, spec.At, loc); err != nil {
+		return nil, err
+	}
+
+	count, err := s.repo.CountByChat(ctx, chatID, types.ScheduleKindCommand)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count schedules: %w", err)
+	}
+	if s.options.MaxPerChat > 0 && count >= s.options.MaxPerChat {
+		return nil, fmt.Errorf("%w: the chat has %d schedules", domainErrors.ErrTooManySchedules, count)
+	}
+
+	if err := s.repo.Create(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to store schedule: %w", err)
+	}
+	s.logger.WithContext(ctx).Info("Command scheduled", "schedule_id", job.ID, "chat_id", chatID,
+		"created_by", creator, "cron", job.Cron, "timezone", job.Timezone, "next_run_at", job.NextRunAt)
+	return job, nil
+}
+
+// List returns the commands scheduled in the chat which still have a run ahead
+func (s *SchedulerServiceImpl) List(
+	ctx context.Context, chatID types.TelegramChatID,
+) ([]*entity.ScheduledJob, error) {
+	if s.repo == nil {
+		return nil, domainErrors.ErrServiceUnavailable
+	}
+	return s.repo.ListByChat(ctx, chatID, types.ScheduleKindCommand)
+}
+
+// Unschedule removes the job of the chat
+func (s *SchedulerServiceImpl) Unschedule(ctx context.Context, chatID types.TelegramChatID, id int64) error {
+	if s.repo == nil {
+		return domainErrors.ErrServiceUnavailable
+	}
+	deleted, err := s.repo.Delete(ctx, chatID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return domainErrors.ErrScheduleNotFound
+	}
+	s.logger.WithContext(ctx).Info("Schedule removed", "schedule_id", id, "chat_id", chatID)
+	return nil
+}
+
+// SetTimezone stores the time zone, jobs already scheduled keep the one they were created with
+func (s *SchedulerServiceImpl) SetTimezone(
+	ctx context.Context,
+	user types.TelegramUserID,
+	chatID types.TelegramChatID,
+	chatType types.ChatType,
+	timezone string,
+) (*time.Location, error) {
+	if s.repo == nil {
+		return nil, domainErrors.ErrServiceUnavailable
+	}
+	loc, err := loadLocation(timezone)
+	if err != nil {
+		return nil, err
+	}
+	// Everyone sets the time zone of their private chat, which is theirs
+	if types.TelegramChatID(user) != chatID {
+		if err := s.auth.Authorize(ctx, user, chatID, types.RoleAdmin); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.chats.SetTimezone(ctx, chatID, chatType, loc.String()); err != nil {
+		return nil, fmt.Errorf("failed to store time zone: %w", err)
+	}
+	s.logger.WithContext(ctx).Info("Time zone changed", "chat_id", chatID, "user_id", user, "timezone", loc.String())
+	return loc, nil
+}
+
+// Location returns the time zone of the chat, the configured default when it has none
+func (s *SchedulerServiceImpl) Location(ctx context.Context, chatID types.TelegramChatID) (*time.Location, error) {
+	return s.location(ctx, chatID, "")
+}
+
+// Run executes the due jobs until ctx is done
+func (s *SchedulerServiceImpl) Run(ctx context.Context) {
+	if s.repo == nil || s.runner == nil {
+		return
+	}
+	ticker := time.NewTicker(s.options.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for s.runDue(ctx) {
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runDue claims and runs the oldest due job, it reports whether another one may be due.
+// Claiming moves the job to its next run first, so a failing command is not retried in a loop
+// and runs missed while the bot was down are run once.
+func (s *SchedulerServiceImpl) runDue(ctx context.Context) bool {
+	logger := s.logger.WithContext(ctx)
+	now := s.now()
+	job, err := s.repo.ClaimDue(ctx, now, func(job *entity.ScheduledJob) *time.Time {
+		return s.nextRun(job, now)
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Error("Failed to claim scheduled job", "error", err)
+		}
+		return false
+	}
+	if job == nil {
+		return false
+	}
+
+	if job.Kind == types.ScheduleKindReminder {
+		err = s.remind(ctx, job)
+	} else {
+		err = s.runner.RunCommand(ctx, job.TelegramChatID, job.ChatType, job.CreatedBy, job.Text)
+	}
+
+	var lastError string
+	status := "ok"
+	if err != nil {
+		logger.Warn("Scheduled job failed", "schedule_id", job.ID, "kind", job.Kind, "chat_id", job.TelegramChatID, "error", err)
+		lastError, status = truncate(err.Error(), maxScheduleError), "failed"
+	}
+	scheduledRuns.With(string(job.Kind), status).Inc()
+
+	if lastError != "" || job.LastError != "" {
+		if err := s.repo.RecordResult(ctx, job.ID, lastError); err != nil {
+			logger.Warn("Failed to store scheduled run", "schedule_id", job.ID, "error", err)
+		}
+	}
+	return true
+}
+
+// firstRun returns the first run of a new job: the next match of its expression, or at for a one-shot job
+func (s *SchedulerServiceImpl) firstRun(job *entity.ScheduledJob, at time.Time, loc *time.Location) (*time.Time, error) {
+	now := s.now()
+	if !job.IsRecurring() {
+		if !at.After(now) {
+			return nil, fmt.Errorf("%w: %s is not in the future", domainErrors.ErrInvalidInput, at.In(loc).Format(time.DateTime))
+		}
+		at = at.In(now.Location())
+		return &at, nil
+	}
+
+	schedule, err := cron.Parse(job.Cron)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domainErrors.ErrInvalidInput, err)
+	}
+	next := schedule.Next(now.In(loc))
+	if next.IsZero() {
+		return nil, fmt.Errorf("%w: %q never matches", domainErrors.ErrInvalidInput, job.Cron)
+	}
+	// Runs are stored in the location of the clock, the time zone only decides which instant matches
+	next = next.In(now.Location())
+	return &next, nil
+}
+
+// nextRun returns the run of the job following now in its time zone, nil when it does not recur
+func (s *SchedulerServiceImpl) nextRun(job *entity.ScheduledJob, now time.Time) *time.Time {
+	if !job.IsRecurring() {
+		return nil
+	}
+	schedule, err := cron.Parse(job.Cron)
+	if err != nil {
+		s.logger.Error("Stored schedule is invalid, it stops", "schedule_id", job.ID, "cron", job.Cron, "error", err)
+		return nil
+	}
+	loc, err := time.LoadLocation(job.Timezone)
+	if err != nil {
+		loc = s.options.Timezone
+	}
+	next := schedule.Next(now.In(loc))
+	if next.IsZero() {
+		return nil
+	}
+	next = next.In(now.Location())
+	return &next
+}
+
+// location resolves the time zone named, else the one of the chat, else the configured default
+func (s *SchedulerServiceImpl) location(
+	ctx context.Context, chatID types.TelegramChatID, name string,
+) (*time.Location, error) {
+	if name != "" {
+		return loadLocation(name)
+	}
+	if s.chats == nil {
+		return s.options.Timezone, nil
+	}
+
+	chat, err := s.chats.GetByTelegramChatID(ctx, chatID)
+	switch {
+	case errors.Is(err, domainErrors.ErrChatNotFound):
+		return s.options.Timezone, nil
+	case err != nil:
+		return nil, err
+	case chat.Timezone == "":
+		return s.options.Timezone, nil
+	}
+	if loc, err := time.LoadLocation(chat.Timezone); err == nil {
+		return loc, nil
+	}
+	return s.options.Timezone, nil
+}
+
+// loadLocation loads an IANA time zone such as Asia/Ho_Chi_Minh, refusing "Local" which depends on the host
+func loadLocation(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, fmt.Errorf("%w: %q", domainErrors.ErrInvalidTimezone, name)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", domainErrors.ErrInvalidTimezone, name)
+	}
+	return loc, nil
+}
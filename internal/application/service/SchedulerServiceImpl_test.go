@@ -0,0 +1,336 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-telegram-bot/internal/domain/entity"
+	domainErrors "go-telegram-bot/internal/domain/errors"
+	"go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+)
+
+// memorySchedules is an in-memory ScheduleRepository
+type memorySchedules struct {
+	jobs []*entity.ScheduledJob
+}
+
+func (r *memorySchedules) Create(_ context.Context, job *entity.ScheduledJob) error {
+	job.ID = int64(len(r.jobs) + 1)
+	r.jobs = append(r.jobs, job)
+	return nil
+}
+
+func (r *memorySchedules) Get(_ context.Context, id int64) (*entity.ScheduledJob, error) {
+	for _, job := range r.jobs {
+		if job.ID == id && !job.DeletedAt.Valid {
+			return job, nil
+		}
+	}
+	return nil, domainErrors.ErrScheduleNotFound
+}
+
+func (r *memorySchedules) list(match func(job *entity.ScheduledJob) bool) []*entity.ScheduledJob {
+	var jobs []*entity.ScheduledJob
+	for _, job := range r.jobs {
+		if match(job) && job.NextRunAt != nil && !job.DeletedAt.Valid {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs
+}
+
+func (r *memorySchedules) ListByChat(
+	_ context.Context, chatID types.TelegramChatID, kind types.ScheduleKind,
+) ([]*entity.ScheduledJob, error) {
+	return r.list(func(job *entity.ScheduledJob) bool {
+		return job.TelegramChatID == chatID && job.Kind == kind
+	}), nil
+}
+
+func (r *memorySchedules) ListByCreator(
+	_ context.Context, user types.TelegramUserID, kind types.ScheduleKind,
+) ([]*entity.ScheduledJob, error) {
+	return r.list(func(job *entity.ScheduledJob) bool {
+		return job.CreatedBy == user && job.Kind == kind
+	}), nil
+}
+
+func (r *memorySchedules) CountByChat(
+	ctx context.Context, chatID types.TelegramChatID, kind types.ScheduleKind,
+) (int, error) {
+	jobs, err := r.ListByChat(ctx, chatID, kind)
+	return len(jobs), err
+}
+
+func (r *memorySchedules) Delete(_ context.Context, chatID types.TelegramChatID, id int64) (bool, error) {
+	for _, job := range r.jobs {
+		if job.ID == id && job.TelegramChatID == chatID && !job.DeletedAt.Valid {
+			job.DeletedAt.Valid = true
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memorySchedules) Reschedule(_ context.Context, id int64, at time.Time) (bool, error) {
+	job, err := r.Get(context.Background(), id)
+	if err != nil {
+		return false, nil
+	}
+	job.NextRunAt = &at
+	return true, nil
+}
+
+func (r *memorySchedules) ClaimDue(
+	_ context.Context, now time.Time, next func(job *entity.ScheduledJob) *time.Time,
+) (*entity.ScheduledJob, error) {
+	for _, job := range r.jobs {
+		if job.NextRunAt != nil && !job.NextRunAt.After(now) && !job.DeletedAt.Valid {
+			job.NextRunAt, job.LastRunAt = next(job), &now
+			job.RunCount++
+			copied := *job
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memorySchedules) RecordResult(_ context.Context, id int64, lastError string) error {
+	r.jobs[id-1].LastError = lastError
+	return nil
+}
+
+func (r *memoryChats) SetTimezone(
+	_ context.Context, chatID types.TelegramChatID, chatType types.ChatType, timezone string,
+) error {
+	chat, ok := r.chats[chatID]
+	if !ok {
+		chat = entity.NewChat(chatID, chatType)
+		r.chats[chatID] = chat
+	}
+	chat.Timezone = timezone
+	return nil
+}
+
+// recordingRunner records the commands run, those in fail return an error
+type recordingRunner struct {
+	ran  []string
+	fail map[string]bool
+}
+
+func (r *recordingRunner) RunCommand(
+	_ context.Context, _ types.TelegramChatID, _ types.ChatType, _ types.TelegramUserID, text string,
+) error {
+	r.ran = append(r.ran, text)
+	if r.fail[text] {
+		return errors.New("upstream failure")
+	}
+	return nil
+}
+
+func TestSchedulerService_RunsInChatTimezone(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	hcm, err := time.LoadLocation("Asia/Ho_Chi_Minh")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	ctx := context.Background()
+	repo := &memorySchedules{}
+	chats := &memoryChats{chats: make(map[types.TelegramChatID]*entity.Chat)}
+	auth := NewAuthorizationService(newMemoryRoleRepo(), []types.TelegramUserID{owner}, nopLogger{})
+	runner := &recordingRunner{fail: map[string]bool{"/home_ip": true}}
+	s := NewSchedulerService(repo, chats, auth, &recordingBot{}, SchedulerOptions{Timezone: hcm, MaxPerChat: 2}, nopLogger{})
+	s.SetRunner(runner)
+	// 2026-10-19 06:00 UTC is 08:00 in Berlin and 13:00 in Ho Chi Minh City
+	now := time.Date(2026, 10, 19, 6, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	if _, err := s.SetTimezone(ctx, admin, chat, types.ChatTypeGroup, "Europe/Berlin"); !errors.Is(err, domainErrors.ErrPermissionDenied) {
+		t.Fatalf("expected a member to be refused, got %v", err)
+	}
+	if _, err := s.SetTimezone(ctx, owner, chat, types.ChatTypeGroup, "Mars/Olympus"); !errors.Is(err, domainErrors.ErrInvalidTimezone) {
+		t.Fatalf("expected an unknown zone to be refused, got %v", err)
+	}
+	if _, err := s.SetTimezone(ctx, owner, chat, types.ChatTypeGroup, "Europe/Berlin"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	daily, err := s.Schedule(ctx, owner, chat, types.ChatTypeGroup, "/home_ip", service.ScheduleSpec{Cron: "0 9 * * *"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := time.Date(2026, 10, 19, 9, 0, 0, 0, berlin); !daily.NextRunAt.Equal(want) || daily.Timezone != "Europe/Berlin" {
+		t.Fatalf("expected the first run at %v in the chat time zone, got %v in %s", want, daily.NextRunAt, daily.Timezone)
+	}
+	once, err := s.Schedule(ctx, owner, chat, types.ChatTypeGroup, "/whoami", service.ScheduleSpec{At: now.Add(30 * time.Minute)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := s.Schedule(ctx, owner, chat, types.ChatTypeGroup, "/help", service.ScheduleSpec{Cron: "@hourly"}); !errors.Is(err, domainErrors.ErrTooManySchedules) {
+		t.Fatalf("expected the limit per chat to apply, got %v", err)
+	}
+	if _, err := s.Schedule(ctx, owner, -200, types.ChatTypeGroup, "/help", service.ScheduleSpec{Cron: "0 0 30 2 *"}); !errors.Is(err, domainErrors.ErrInvalidInput) {
+		t.Fatalf("expected an expression which never matches to be refused, got %v", err)
+	}
+
+	if s.runDue(ctx) {
+		t.Fatal("expected nothing to be due yet")
+	}
+
+	// Two hours later both are due, the one-shot job ends and the daily one moves to the next day
+	now = now.Add(2 * time.Hour)
+	for s.runDue(ctx) {
+	}
+	if len(runner.ran) != 2 {
+		t.Fatalf("expected each job to run once, got %v", runner.ran)
+	}
+	if want := time.Date(2026, 10, 20, 9, 0, 0, 0, berlin); !daily.NextRunAt.Equal(want) || daily.LastError == "" {
+		t.Fatalf("expected the failure recorded and the next run at %v, got %v and %q", want, daily.NextRunAt, daily.LastError)
+	}
+	if once.NextRunAt != nil || once.RunCount != 1 {
+		t.Fatalf("expected the one-shot job to end after its run, got %+v", once)
+	}
+
+	// Runs missed while the bot was down are coalesced into one
+	now = now.Add(72 * time.Hour)
+	for s.runDue(ctx) {
+	}
+	if len(runner.ran) != 3 || daily.RunCount != 2 {
+		t.Fatalf("expected a single catch-up run, got %v", runner.ran)
+	}
+
+	if err := s.Unschedule(ctx, -200, daily.ID); !errors.Is(err, domainErrors.ErrScheduleNotFound) {
+		t.Fatalf("expected another chat not to remove the job, got %v", err)
+	}
+	if err := s.Unschedule(ctx, chat, daily.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if jobs, _ := s.List(ctx, chat); len(jobs) != 0 {
+		t.Fatalf("expected no job left, got %d", len(jobs))
+	}
+}
+
+// reminderBot records the messages sent and the answers to button presses
+type reminderBot struct {
+	service.TelegramBotService
+	sent    []*types.SendMessageRequest
+	edited  []string
+	answers []string
+}
+
+func (b *reminderBot) SendMessageWithResponse(
+	_ context.Context, request *types.SendMessageRequest,
+) (*types.SendMessageResponse, error) {
+	b.sent = append(b.sent, request)
+	return &types.SendMessageResponse{}, nil
+}
+
+func (b *reminderBot) EditMessageText(
+	_ context.Context, request *types.EditMessageTextRequest,
+) (*types.EditMessageTextResponse, error) {
+	b.edited = append(b.edited, request.Text)
+	return &types.EditMessageTextResponse{}, nil
+}
+
+func (b *reminderBot) AnswerCallbackQuery(
+	_ context.Context, request *types.AnswerCallbackQueryRequest,
+) (*types.AnswerCallbackQueryResponse, error) {
+	b.answers = append(b.answers, *request.Text)
+	return &types.AnswerCallbackQueryResponse{}, nil
+}
+
+func TestSchedulerService_Reminders(t *testing.T) {
+	ctx := context.Background()
+	repo := &memorySchedules{}
+	chats := &memoryChats{chats: make(map[types.TelegramChatID]*entity.Chat)}
+	bot := &reminderBot{}
+	s := NewSchedulerService(repo, chats, nil, bot, SchedulerOptions{MaxReminders: 1}, nopLogger{})
+	s.SetRunner(&recordingRunner{})
+	now := time.Date(2026, 10, 19, 6, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	if _, err := s.Remind(ctx, owner, chat, types.ChatTypeGroup, 7, "check router", now.Add(-time.Minute)); !errors.Is(err, domainErrors.ErrInvalidInput) {
+		t.Fatalf("expected a past time to be refused, got %v", err)
+	}
+	job, err := s.Remind(ctx, owner, chat, types.ChatTypeGroup, 7, "check router", now.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := s.Remind(ctx, owner, chat, types.ChatTypeGroup, 8, "renew domain", now.Add(time.Hour)); !errors.Is(err, domainErrors.ErrTooManySchedules) {
+		t.Fatalf("expected the limit per user to apply, got %v", err)
+	}
+	if len(bot.sent) != 1 || *bot.sent[0].ReplyToMessageID != 7 {
+		t.Fatalf("expected the confirmation in reply to the message, got %+v", bot.sent)
+	}
+
+	// Reminders are not listed as scheduled commands
+	if jobs, _ := s.List(ctx, chat); len(jobs) != 0 {
+		t.Fatalf("expected no scheduled command, got %d", len(jobs))
+	}
+
+	now = now.Add(2 * time.Hour)
+	for s.runDue(ctx) {
+	}
+	if len(bot.sent) != 2 || bot.sent[1].Text != "🔔 Nhắc nhở: check router" || *bot.sent[1].ReplyToMessageID != 7 {
+		t.Fatalf("expected the reminder delivered in reply to the message, got %+v", bot.sent)
+	}
+	if job.NextRunAt != nil {
+		t.Fatalf("expected the reminder to end after its delivery, got %v", job.NextRunAt)
+	}
+
+	press := func(from types.TelegramUserID, data string) {
+		t.Helper()
+		query := &types.TelegramCallbackQuery{
+			ID:      "q",
+			From:    &types.TelegramUser{ID: from},
+			Data:    &data,
+			Message: &types.TelegramMessage{MessageID: 9, Chat: &types.TelegramChat{ID: chat}},
+		}
+		if err := s.HandleCallback(ctx, query); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	snooze := reminderCallbackData("snooze", job.ID, 10)
+	press(admin, snooze)
+	if job.NextRunAt != nil {
+		t.Fatal("expected another user not to snooze the reminder")
+	}
+	press(owner, snooze)
+	if want := now.Add(10 * time.Minute); job.NextRunAt == nil || !job.NextRunAt.Equal(want) {
+		t.Fatalf("expected the reminder snoozed to %v, got %v", want, job.NextRunAt)
+	}
+
+	press(owner, reminderCallbackData("cancel", job.ID, 0))
+	now = now.Add(time.Hour)
+	if s.runDue(ctx) {
+		t.Fatal("expected the cancelled reminder not to be delivered")
+	}
+	press(owner, reminderCallbackData("cancel", job.ID, 0))
+	if len(bot.answers) != 4 || bot.answers[3] != "Nhắc nhở này không còn nữa" {
+		t.Fatalf("unexpected answers %v", bot.answers)
+	}
+}
+
+func TestParseReminderCallback(t *testing.T) {
+	for data, valid := range map[string]bool{
+		"remind:snooze:4:60": true,
+		"remind:cancel:4":    true,
+		"remind:drop:4":      true,
+		"remind:snooze:4":    false,
+		"remind:snooze:4:0":  false,
+		"remind:cancel:x":    false,
+		"remind:cancel:4:60": false,
+		"remind:open:4":      false,
+	} {
+		if _, _, _, ok := parseReminderCallback(&data); ok != valid {
+			t.Errorf("parseReminderCallback(%q) = %v, want %v", data, ok, valid)
+		}
+	}
+}
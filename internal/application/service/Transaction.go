@@ -88,7 +88,7 @@ func (tm *TransactionManager) HealthCheck(ctx context.Context) error {
 		return fmt.Errorf("database ping failed: %w", err)
 	}
 
-	tm.logger.Info("Database connection is healthy")
+	tm.logger.Debug("Database connection is healthy")
 	return nil
 }
 
@@ -0,0 +1,395 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"go-telegram-bot/internal/domain/entity"
+	domainErrors "go-telegram-bot/internal/domain/errors"
+	"go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+)
+
+const (
+	broadcastUsage = "/broadcast [type:<loại>] [tag:<nhãn>] [in:<thời gian>] <nội dung>\n" +
+		"/broadcast [tuỳ chọn] khi trả lời tin nhắn cần gửi\n" +
+		"Loại là private, group, supergroup hoặc channel, thời gian dạng 30m, 12h hoặc 7d"
+	broadcastStatusUsage = "/broadcast_status [id], bỏ trống để xem thông báo gần nhất"
+	broadcastCancelUsage = "/broadcast_cancel <id>"
+	chatTagUsage         = "/chat_tag để xem nhãn của nhóm\n" +
+		"/chat_tag add <nhãn> hoặc /chat_tag remove <nhãn>\n" +
+		"Nhãn gồm chữ thường, số, - và _"
+
+	// maxBroadcastText is the longest text Telegram accepts in a message
+	maxBroadcastText = 4096
+)
+
+// chatTagPattern restricts tags to what can be typed as a single argument
+var chatTagPattern = regexp.MustCompile(`^[a-z0-9_-]{1,64}$`)
+
+// Outcomes of a press on a broadcast preview button, shown to the admin who pressed it
+const (
+	BroadcastCallbackConfirmed = "confirmed"
+	BroadcastCallbackCancelled = "cancelled"
+	BroadcastCallbackForbidden = "forbidden"
+	BroadcastCallbackHandled   = "handled"
+	BroadcastCallbackInvalid   = "invalid"
+)
+
+var broadcastCallbackMessages = map[string]string{
+	BroadcastCallbackConfirmed: "📢 Đã xác nhận, thông báo sẽ được gửi",
+	BroadcastCallbackCancelled: "🗑 Đã huỷ thông báo",
+	BroadcastCallbackForbidden: "⛔ Chỉ admin toàn cục mới được gửi thông báo",
+	BroadcastCallbackHandled:   "Thông báo này đã được xử lý",
+	BroadcastCallbackInvalid:   "Nút bấm không hợp lệ",
+}
+
+var broadcastStatusNames = map[types.BroadcastStatus]string{
+	types.BroadcastStatusDraft:     "chờ xác nhận",
+	types.BroadcastStatusScheduled: "đã lên lịch",
+	types.BroadcastStatusRunning:   "đang gửi",
+	types.BroadcastStatusCompleted: "đã hoàn tất",
+	types.BroadcastStatusCancelled: "đã huỷ",
+}
+
+// BroadcastHandler handles the /broadcast command, the service posts the preview to confirm
+func BroadcastHandler(
+	ctx context.Context,
+	req *types.CommandRequest,
+	broadcasts service.BroadcastService,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	text, filter, startIn, err := parseBroadcast(req)
+	if err != nil {
+		return nil, domainErrors.NewUsageError(err.Error(), broadcastUsage)
+	}
+
+	var startAt time.Time
+	if startIn > 0 {
+		startAt = time.Now().Add(startIn)
+	}
+	_, err = broadcasts.Prepare(ctx, req.UserID, req.ChatID, text, filter, startAt)
+	return nil, err
+}
+
+// BroadcastPreviewHandler posts the text as it will be delivered, then its targets with the confirm and cancel buttons
+func BroadcastPreviewHandler(
+	ctx context.Context,
+	job *entity.BroadcastJob,
+	targets int,
+	keyboard types.InlineKeyboardMarkup,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	if _, err := sendText(ctx, bot, job.ReportChatID, job.Text); err != nil {
+		return nil, fmt.Errorf("failed to post broadcast preview: %w", err)
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "📢 Xem trước thông báo #%d ở trên\n", job.ID)
+	fmt.Fprintf(&text, "🎯 Gửi đến %d cuộc trò chuyện: %s\n", targets, filterText(job.Filter))
+	if job.ScheduledAt.After(job.CreatedAt) {
+		fmt.Fprintf(&text, "⏰ Bắt đầu lúc %s\n", job.ScheduledAt.Format("15:04 02/01/2006"))
+	} else {
+		text.WriteString("⏰ Bắt đầu ngay khi xác nhận\n")
+	}
+
+	response, err := bot.SendMessageWithResponse(ctx, &types.SendMessageRequest{
+		ChatID:      job.ReportChatID,
+		Text:        text.String(),
+		ReplyMarkup: keyboard,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to post broadcast preview: %w", err)
+	}
+	return response, nil
+}
+
+// BroadcastDecisionHandler replaces the buttons of the preview with the decision and its author
+func BroadcastDecisionHandler(
+	ctx context.Context,
+	message *types.TelegramMessage,
+	confirmed bool,
+	admin *types.TelegramUser,
+	bot service.TelegramBotService,
+) (*types.EditMessageTextResponse, error) {
+	result := "✅ Đã xác nhận bởi "
+	if !confirmed {
+		result = "🗑 Đã huỷ bởi "
+	}
+	var original string
+	if message.Text != nil {
+		original = *message.Text
+	}
+
+	chatID := message.Chat.ID
+	response, err := bot.EditMessageText(ctx, &types.EditMessageTextRequest{
+		ChatID:    &chatID,
+		MessageID: &message.MessageID,
+		Text:      original + "\n" + result + userLabel(admin),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update broadcast preview: %w", err)
+	}
+	return response, nil
+}
+
+// BroadcastCallbackHandler answers the press on a preview button with the outcome
+func BroadcastCallbackHandler(
+	ctx context.Context,
+	queryID string,
+	outcome string,
+	bot service.TelegramBotService,
+) (*types.AnswerCallbackQueryResponse, error) {
+	text := broadcastCallbackMessages[outcome]
+	showAlert := outcome == BroadcastCallbackForbidden
+	response, err := bot.AnswerCallbackQuery(ctx, &types.AnswerCallbackQueryRequest{
+		CallbackQueryID: queryID,
+		Text:            &text,
+		ShowAlert:       &showAlert,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to answer callback query: %w", err)
+	}
+	return response, nil
+}
+
+// BroadcastReportHandler tells the chat the job was created in that its delivery finished
+func BroadcastReportHandler(
+	ctx context.Context,
+	job *entity.BroadcastJob,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	response, err := sendText(ctx, bot, job.ReportChatID, "📢 Thông báo đã gửi xong\n"+broadcastSummary(job))
+	if err != nil {
+		return nil, fmt.Errorf("failed to report broadcast: %w", err)
+	}
+	return response, nil
+}
+
+// BroadcastStatusHandler handles the /broadcast_status command showing the progress of a job
+func BroadcastStatusHandler(
+	ctx context.Context,
+	req *types.CommandRequest,
+	broadcasts service.BroadcastService,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	var id int64
+	switch len(req.Args) {
+	case 0:
+	case 1:
+		var err error
+		if id, err = parseBroadcastID(req.Args[0]); err != nil {
+			return nil, domainErrors.NewUsageError(err.Error(), broadcastStatusUsage)
+		}
+	default:
+		return nil, domainErrors.NewUsageError("unexpected arguments", broadcastStatusUsage)
+	}
+
+	job, err := broadcasts.Status(ctx, req.UserID, id)
+	if errors.Is(err, domainErrors.ErrBroadcastNotFound) {
+		return sendText(ctx, bot, req.ChatID, "❌ Không tìm thấy thông báo nào.")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return sendText(ctx, bot, req.ChatID, broadcastSummary(job))
+}
+
+// BroadcastCancelHandler handles the /broadcast_cancel command, deliveries already sent stay sent
+func BroadcastCancelHandler(
+	ctx context.Context,
+	req *types.CommandRequest,
+	broadcasts service.BroadcastService,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	if len(req.Args) != 1 {
+		return nil, domainErrors.NewUsageError("expected one broadcast ID", broadcastCancelUsage)
+	}
+	id, err := parseBroadcastID(req.Args[0])
+	if err != nil {
+		return nil, domainErrors.NewUsageError(err.Error(), broadcastCancelUsage)
+	}
+
+	job, err := broadcasts.Cancel(ctx, req.UserID, id)
+	if errors.Is(err, domainErrors.ErrBroadcastNotFound) {
+		return sendText(ctx, bot, req.ChatID, fmt.Sprintf("❌ Không tìm thấy thông báo #%d.", id))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != types.BroadcastStatusCancelled {
+		return sendText(ctx, bot, req.ChatID,
+			fmt.Sprintf("Thông báo #%d %s, không thể huỷ.", job.ID, broadcastStatusNames[job.Status]))
+	}
+	return sendText(ctx, bot, req.ChatID, "🗑 Đã huỷ thông báo\n"+broadcastSummary(job))
+}
+
+// ChatTagHandler handles the /chat_tag command labelling the chat for targeted broadcasts
+func ChatTagHandler(
+	ctx context.Context,
+	req *types.CommandRequest,
+	broadcasts service.BroadcastService,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	if len(req.Args) == 0 {
+		tags, err := broadcasts.ChatTags(ctx, req.ChatID)
+		if err != nil {
+			return nil, err
+		}
+		if len(tags) == 0 {
+			return sendText(ctx, bot, req.ChatID, "🏷 Cuộc trò chuyện này chưa có nhãn nào.")
+		}
+		return sendText(ctx, bot, req.ChatID, "🏷 Nhãn: #"+strings.Join(tags, " #"))
+	}
+	if len(req.Args) != 2 {
+		return nil, domainErrors.NewUsageError("expected an action and a tag", chatTagUsage)
+	}
+
+	tag := strings.ToLower(strings.TrimPrefix(req.Args[1], "#"))
+	if !chatTagPattern.MatchString(tag) {
+		return nil, domainErrors.NewUsageError(fmt.Sprintf("invalid tag %q", req.Args[1]), chatTagUsage)
+	}
+	switch req.Args[0] {
+	case "add":
+		if err := broadcasts.TagChat(ctx, req.ChatID, tag); err != nil {
+			return nil, err
+		}
+		return sendText(ctx, bot, req.ChatID, fmt.Sprintf("🏷 Đã gắn nhãn #%s.", tag))
+	case "remove":
+		removed, err := broadcasts.UntagChat(ctx, req.ChatID, tag)
+		if err != nil {
+			return nil, err
+		}
+		if !removed {
+			return sendText(ctx, bot, req.ChatID, fmt.Sprintf("Cuộc trò chuyện này không có nhãn #%s.", tag))
+		}
+		return sendText(ctx, bot, req.ChatID, fmt.Sprintf("🏷 Đã gỡ nhãn #%s.", tag))
+	default:
+		return nil, domainErrors.NewUsageError(fmt.Sprintf("unknown action %q", req.Args[0]), chatTagUsage)
+	}
+}
+
+// parseBroadcast reads the options at the start of the command, the rest of the text is the announcement
+// with its line breaks kept. Replying to a message announces the text of that message instead.
+func parseBroadcast(req *types.CommandRequest) (string, entity.BroadcastFilter, time.Duration, error) {
+	var filter entity.BroadcastFilter
+	var startIn time.Duration
+	if req.Message == nil || req.Message.Text == nil {
+		return "", filter, 0, fmt.Errorf("missing text")
+	}
+
+	// Drop the command, then consume the options one field at a time
+	raw, rest := strings.TrimSpace(*req.Message.Text), ""
+	if i := strings.IndexFunc(raw, unicode.IsSpace); i >= 0 {
+		rest = strings.TrimLeftFunc(raw[i:], unicode.IsSpace)
+	}
+	for rest != "" {
+		field := strings.Fields(rest)[0]
+		isOption, err := applyBroadcastOption(field, &filter, &startIn)
+		if err != nil {
+			return "", filter, 0, err
+		}
+		if !isOption {
+			break
+		}
+		rest = strings.TrimLeftFunc(rest[len(field):], unicode.IsSpace)
+	}
+
+	text := strings.TrimSpace(rest)
+	if reply := req.Message.ReplyToMessage; reply != nil && reply.Text != nil {
+		if text != "" {
+			return "", filter, 0, fmt.Errorf("either reply to a message or write the text")
+		}
+		text = *reply.Text
+	}
+	switch {
+	case text == "":
+		return "", filter, 0, fmt.Errorf("missing text")
+	case utf8.RuneCountInString(text) > maxBroadcastText:
+		return "", filter, 0, fmt.Errorf("text longer than %d characters", maxBroadcastText)
+	}
+	return text, filter, startIn, nil
+}
+
+// applyBroadcastOption applies a key:value option, it reports false when field is not an option
+// so that a text such as "Lưu ý: ..." is announced as written
+func applyBroadcastOption(field string, filter *entity.BroadcastFilter, startIn *time.Duration) (bool, error) {
+	key, value, _ := strings.Cut(field, ":")
+	switch {
+	case value == "":
+		return false, nil
+	case key == "type":
+		filter.ChatType = types.ChatType(value)
+		if !filter.ChatType.IsValid() {
+			return true, fmt.Errorf("invalid chat type %q", value)
+		}
+	case key == "tag":
+		filter.Tag = strings.ToLower(strings.TrimPrefix(value, "#"))
+		if !chatTagPattern.MatchString(filter.Tag) {
+			return true, fmt.Errorf("invalid tag %q", value)
+		}
+	case key == "in":
+		duration, err := parseDuration(value)
+		if err != nil {
+			return true, err
+		}
+		*startIn = duration
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// parseBroadcastID reads a job ID, with or without its leading #
+func parseBroadcastID(value string) (int64, error) {
+	id, err := strconv.ParseInt(strings.TrimPrefix(value, "#"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid broadcast ID %q", value)
+	}
+	return id, nil
+}
+
+// broadcastSummary describes the job and its progress
+func broadcastSummary(job *entity.BroadcastJob) string {
+	var text strings.Builder
+	fmt.Fprintf(&text, "📢 Thông báo #%d: %s\n", job.ID, broadcastStatusNames[job.Status])
+	fmt.Fprintf(&text, "🎯 %s\n", filterText(job.Filter))
+	if job.PlannedAt == nil {
+		fmt.Fprintf(&text, "⏰ Bắt đầu lúc %s\n", job.ScheduledAt.Format("15:04 02/01/2006"))
+		return text.String()
+	}
+	fmt.Fprintf(&text, "✅ Đã gửi %d/%d", job.Sent, job.Total)
+	if job.Blocked > 0 {
+		fmt.Fprintf(&text, " · 🚫 %d chặn bot", job.Blocked)
+	}
+	if job.Failed > 0 {
+		fmt.Fprintf(&text, " · ❌ %d lỗi", job.Failed)
+	}
+	if pending := job.Pending(); pending > 0 && !job.Status.IsFinal() {
+		fmt.Fprintf(&text, " · ⏳ còn %d", pending)
+	}
+	text.WriteString("\n")
+	return text.String()
+}
+
+// filterText describes the chats a broadcast targets
+func filterText(filter entity.BroadcastFilter) string {
+	var parts []string
+	if filter.ChatType != "" {
+		parts = append(parts, "loại "+string(filter.ChatType))
+	}
+	if filter.Tag != "" {
+		parts = append(parts, "nhãn #"+filter.Tag)
+	}
+	if len(parts) == 0 {
+		return "mọi cuộc trò chuyện đang hoạt động"
+	}
+	return "các cuộc trò chuyện " + strings.Join(parts, ", ")
+}
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go-telegram-bot/internal/domain/entity"
+	"go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+)
+
+// DDNSStatusHandler handles the /ddns_status command showing the last update of every provider
+func DDNSStatusHandler(
+	ctx context.Context,
+	req *types.CommandRequest,
+	ddns service.DDNSService,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	statuses := ddns.Status(ctx)
+	if len(statuses) == 0 {
+		return sendText(ctx, bot, req.ChatID, "🌐 Chưa cấu hình nhà cung cấp DDNS nào.")
+	}
+
+	var text strings.Builder
+	text.WriteString("🌐 Trạng thái DDNS:\n")
+	for _, status := range statuses {
+		text.WriteString("\n" + ddnsSummary(status))
+	}
+	return sendText(ctx, bot, req.ChatID, text.String())
+}
+
+// DDNSReportHandler tells the report chat the records of a provider were updated, or could not be
+func DDNSReportHandler(
+	ctx context.Context,
+	chatID types.TelegramChatID,
+	status entity.DDNSStatus,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	title := "🌐 Đã cập nhật DDNS"
+	if !status.IsHealthy() {
+		title = "⚠️ Cập nhật DDNS thất bại"
+	}
+	response, err := sendText(ctx, bot, chatID, title+"\n"+ddnsSummary(status))
+	if err != nil {
+		return nil, fmt.Errorf("failed to report DDNS update: %w", err)
+	}
+	return response, nil
+}
+
+// ddnsSummary describes the last update of a provider
+func ddnsSummary(status entity.DDNSStatus) string {
+	var text strings.Builder
+	switch {
+	case status.CheckedAt == nil:
+		fmt.Fprintf(&text, "⏳ %s: chưa cập nhật\n", status.Provider)
+	case status.IsHealthy():
+		fmt.Fprintf(&text, "✅ %s: %s\n", status.Provider, status.IP)
+	default:
+		fmt.Fprintf(&text, "❌ %s: %s\n", status.Provider, status.LastError)
+		if status.IP != "" {
+			fmt.Fprintf(&text, "Bản ghi vẫn trỏ về %s\n", status.IP)
+		}
+	}
+	if status.UpdatedAt != nil {
+		fmt.Fprintf(&text, "Cập nhật lúc %s\n", status.UpdatedAt.Format("15:04 02/01/2006"))
+	}
+	if status.CheckedAt != nil && !status.IsHealthy() {
+		fmt.Fprintf(&text, "Thử lần cuối lúc %s\n", status.CheckedAt.Format("15:04 02/01/2006"))
+	}
+	return text.String()
+}
@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	domainErrors "go-telegram-bot/internal/domain/errors"
+	"go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+)
+
+const grantUsage = "/grant <user_id> <member|admin> [global]\n" +
+	"/grant <member|admin> [global] khi trả lời tin nhắn của người dùng"
+
+// GrantHandler handles the /grant command assigning a role in the current chat or globally
+func GrantHandler(
+	ctx context.Context,
+	req *types.CommandRequest,
+	auth service.AuthorizationService,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	userID, args, err := parseTargetUser(req)
+	if err != nil {
+		return nil, domainErrors.NewUsageError(err.Error(), grantUsage)
+	}
+	chatID, args := parseScope(req, args)
+	if len(args) != 1 {
+		return nil, domainErrors.NewUsageError("expected one role", grantUsage)
+	}
+	role := types.Role(args[0])
+
+	err = auth.Grant(ctx, req.UserID, userID, chatID, role)
+	switch {
+	case err == nil:
+		return sendText(ctx, bot, req.ChatID,
+			fmt.Sprintf("✅ Đã cấp vai trò %s cho người dùng %d trong %s.", role, userID, scopeName(chatID)))
+	case errors.Is(err, domainErrors.ErrInvalidRole):
+		return nil, domainErrors.NewUsageError(err.Error(), grantUsage)
+	default:
+		// Permission and availability errors are answered by the error middleware
+		return nil, fmt.Errorf("failed to grant role: %w", err)
+	}
+}
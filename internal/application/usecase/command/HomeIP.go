@@ -3,8 +3,11 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
+	"go-telegram-bot/internal/domain/entity"
+	domainErrors "go-telegram-bot/internal/domain/errors"
 	"go-telegram-bot/internal/domain/service"
 	"go-telegram-bot/internal/domain/types"
 	"go-telegram-bot/internal/shared/util"
@@ -21,29 +24,41 @@ func HomeIPHandler(
 	// Retrieve IP information from the IP service
 	ipInfo, err := ipService.GetIPInfo(ctx)
 	if err != nil {
+		// The error middleware tells the user to retry later
 		logger.Error("Failed to get IP info", "error", err)
-
-		errorMsg := "❌ Failed to retrieve IP information. Please try again later."
-		response, err := bot.SendMessageWithResponse(ctx, &types.SendMessageRequest{
-			ChatID: chatID,
-			Text:   errorMsg,
-		})
-		if err != nil {
-			return nil, fmt.Errorf("failed to send error message: %w", err)
-		}
-
-		return response, fmt.Errorf("Failed to send error message to Telegram", "error", err)
+		return nil, fmt.Errorf("failed to retrieve IP information: %w: %w", domainErrors.ErrServiceUnavailable, err)
 	}
 
 	// Create response message with proper MarkdownV2 formatting
+	var details strings.Builder
+	if location := ipInfo.Location(); location != "" {
+		fmt.Fprintf(&details, "📍 *Vị trí:* %s\n", util.EscapeMarkdownV2(location))
+	}
+	if network := ipInfo.Network(); network != "" {
+		fmt.Fprintf(&details, "🏢 *Nhà mạng:* %s\n", util.EscapeMarkdownV2(network))
+	}
+	if ipInfo.ReverseDNS != "" {
+		fmt.Fprintf(&details, "🔁 *Reverse DNS:* `%s`\n", util.EscapeMarkdownV2(ipInfo.ReverseDNS))
+	}
+	if details.Len() > 0 {
+		details.WriteString("\n")
+	}
+	if interfaces := interfaceList(ipInfo.Interfaces); interfaces != "" {
+		details.WriteString(interfaces + "\n")
+	}
+
 	message := fmt.Sprintf(
 		"🏠 *%s*\n\n"+
-			"🔗 *Local IP:* `%s`\n"+
-			"🌍 *WAN IP:* `%s`\n\n"+
+			"🔗 *Local IP:* %s\n"+
+			"🌍 *WAN IPv4:* %s\n"+
+			"🌐 *WAN IPv6:* %s\n\n"+
+			"%s"+
 			"⏰ *%s*",
 		util.EscapeMarkdownV2("Thông tin IP hiện tại"),
-		util.EscapeMarkdownV2(ipInfo.LocalIP),
-		util.EscapeMarkdownV2(ipInfo.PublicIP),
+		addressText(ipInfo.LocalIP),
+		addressText(ipInfo.PublicIPv4),
+		addressText(ipInfo.PublicIPv6),
+		details.String(),
 		util.EscapeMarkdownV2(
 			fmt.Sprintf(
 				"Cập nhật: %s",
@@ -65,3 +80,43 @@ func HomeIPHandler(
 
 	return response, nil
 }
+
+// addressText formats an address as code, or tells it is missing
+func addressText(ip string) string {
+	if ip == "" {
+		return "_" + util.EscapeMarkdownV2("không có") + "_"
+	}
+	return "`" + util.EscapeMarkdownV2(ip) + "`"
+}
+
+// interfaceList describes the interfaces other than loopback with their state and addresses
+func interfaceList(interfaces []entity.NetworkInterface) string {
+	var text strings.Builder
+	for _, iface := range interfaces {
+		if iface.Loopback {
+			continue
+		}
+		if text.Len() == 0 {
+			text.WriteString("🖧 *Giao diện mạng:*\n")
+		}
+		state := "🔴"
+		if iface.Up {
+			state = "🟢"
+		}
+		line := iface.Name
+		if iface.MAC != "" {
+			line += " · " + iface.MAC
+		}
+		if iface.DefaultRoute {
+			line += " · mặc định"
+			if len(iface.Gateways) > 0 {
+				line += " qua " + strings.Join(iface.Gateways, ", ")
+			}
+		}
+		fmt.Fprintf(&text, "%s %s\n", state, util.EscapeMarkdownV2(line))
+		for _, address := range iface.Addresses {
+			fmt.Fprintf(&text, "    `%s`\n", util.EscapeMarkdownV2(address))
+		}
+	}
+	return text.String()
+}
@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go-telegram-bot/internal/domain/entity"
+	"go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+)
+
+// ipHistoryLimit is the number of addresses /ip_history shows
+const ipHistoryLimit = 10
+
+// IPSubscribeHandler handles the /ip_subscribe command notifying the chat of WAN address changes
+func IPSubscribeHandler(
+	ctx context.Context,
+	req *types.CommandRequest,
+	monitor service.IPMonitorService,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	created, err := monitor.Subscribe(ctx, req.UserID, req.ChatID, req.ChatType)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return sendText(ctx, bot, req.ChatID, "🔔 Cuộc trò chuyện này đã nhận thông báo khi IP WAN thay đổi.")
+	}
+	return sendText(ctx, bot, req.ChatID, "🔔 Sẽ thông báo ở đây khi IP WAN thay đổi. Tắt bằng /ip_unsubscribe.")
+}
+
+// IPUnsubscribeHandler handles the /ip_unsubscribe command stopping the notifications to the chat
+func IPUnsubscribeHandler(
+	ctx context.Context,
+	req *types.CommandRequest,
+	monitor service.IPMonitorService,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	removed, err := monitor.Unsubscribe(ctx, req.ChatID)
+	if err != nil {
+		return nil, err
+	}
+	if !removed {
+		return sendText(ctx, bot, req.ChatID, "🔕 Cuộc trò chuyện này chưa đăng ký thông báo IP.")
+	}
+	return sendText(ctx, bot, req.ChatID, "🔕 Đã tắt thông báo khi IP WAN thay đổi.")
+}
+
+// IPHistoryHandler handles the /ip_history command listing the last WAN addresses
+func IPHistoryHandler(
+	ctx context.Context,
+	req *types.CommandRequest,
+	monitor service.IPMonitorService,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	changes, err := monitor.History(ctx, ipHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return sendText(ctx, bot, req.ChatID, "🌍 Chưa ghi nhận IP WAN nào.")
+	}
+
+	var text strings.Builder
+	text.WriteString("🌍 Lịch sử IP WAN:\n")
+	for i, change := range changes {
+		fmt.Fprintf(&text, "\n%s · %s", change.IP, change.DetectedAt.Format("15:04 02/01/2006"))
+		// Changes are listed newest first, the one before lasted until the next one
+		if i > 0 {
+			fmt.Fprintf(&text, " · dùng %s", spanText(changes[i-1].DetectedAt.Sub(change.DetectedAt)))
+		} else {
+			text.WriteString(" · hiện tại")
+		}
+	}
+	return sendText(ctx, bot, req.ChatID, text.String())
+}
+
+// IPChangeHandler tells a subscribed chat the WAN address changed and how long the previous one lasted
+func IPChangeHandler(
+	ctx context.Context,
+	chatID types.TelegramChatID,
+	previous *entity.IPChange,
+	change *entity.IPChange,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	text := fmt.Sprintf("🌍 IP WAN đã thay đổi\n\nCũ: %s\nMới: %s\nIP cũ đã dùng %s, từ %s",
+		previous.IP, change.IP,
+		spanText(change.DetectedAt.Sub(previous.DetectedAt)),
+		previous.DetectedAt.Format("15:04 02/01/2006"))
+	response, err := sendText(ctx, bot, chatID, text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to notify IP change: %w", err)
+	}
+	return response, nil
+}
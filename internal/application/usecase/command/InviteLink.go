@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	domainErrors "go-telegram-bot/internal/domain/errors"
+	"go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+)
+
+const (
+	inviteCreateUsage = "/invite_create <tên> [thời hạn] [số người tối đa]\n" +
+		"Ví dụ: /invite_create hoi_thao 7d 50, thời hạn dạng 12h, 7d hoặc 2w"
+	inviteRevokeUsage = "/invite_revoke <id> với id lấy từ /invite_list"
+
+	maxInviteLinkName   = 32
+	maxInviteLinkMember = 99999
+)
+
+// InviteCreateHandler handles the /invite_create command creating a named invite link
+func InviteCreateHandler(
+	ctx context.Context,
+	req *types.CommandRequest,
+	invites service.InviteLinkService,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	options, err := parseInviteOptions(req)
+	if err != nil {
+		return nil, domainErrors.NewUsageError(err.Error(), inviteCreateUsage)
+	}
+
+	link, err := invites.Create(ctx, req.UserID, req.ChatID, options)
+	if err != nil {
+		return nil, err
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "🔗 Đã tạo link mời #%d «%s»:\n%s", link.ID, link.Name, link.Link)
+	if link.ExpiresAt != nil {
+		fmt.Fprintf(&text, "\n⏳ Hết hạn lúc %s", link.ExpiresAt.Format("15:04 02/01/2006"))
+	}
+	if link.MemberLimit != nil {
+		fmt.Fprintf(&text, "\n👥 Tối đa %d người", *link.MemberLimit)
+	}
+	return sendText(ctx, bot, req.ChatID, text.String())
+}
+
+// InviteListHandler handles the /invite_list command listing the links of the chat with their joins
+func InviteListHandler(
+	ctx context.Context,
+	req *types.CommandRequest,
+	invites service.InviteLinkService,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	if req.IsPrivate() {
+		return nil, domainErrors.NewUsageError("only available in groups", "/invite_list")
+	}
+
+	links, err := invites.List(ctx, req.ChatID)
+	if err != nil {
+		return nil, err
+	}
+	if len(links) == 0 {
+		return sendText(ctx, bot, req.ChatID, "Chưa có link mời nào được tạo qua bot. Dùng /invite_create để tạo.")
+	}
+
+	now := time.Now()
+	var text strings.Builder
+	text.WriteString("🔗 Link mời của nhóm:\n")
+	for _, link := range links {
+		fmt.Fprintf(&text, "\n#%d «%s» — %s\n", link.ID, link.Name, link.Link)
+		if link.MemberLimit != nil {
+			fmt.Fprintf(&text, "   👥 %d/%d người đã tham gia", link.JoinCount, *link.MemberLimit)
+		} else {
+			fmt.Fprintf(&text, "   👥 %d người đã tham gia", link.JoinCount)
+		}
+		switch {
+		case link.RevokedAt != nil:
+			text.WriteString(" · đã thu hồi")
+		case !link.IsActive(now):
+			text.WriteString(" · hết hiệu lực")
+		case link.ExpiresAt != nil:
+			fmt.Fprintf(&text, " · hết hạn %s", link.ExpiresAt.Format("15:04 02/01/2006"))
+		}
+		text.WriteString("\n")
+	}
+	return sendText(ctx, bot, req.ChatID, text.String())
+}
+
+// InviteRevokeHandler handles the /invite_revoke command
+func InviteRevokeHandler(
+	ctx context.Context,
+	req *types.CommandRequest,
+	invites service.InviteLinkService,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	if req.IsPrivate() {
+		return nil, domainErrors.NewUsageError("only available in groups", inviteRevokeUsage)
+	}
+	if len(req.Args) != 1 {
+		return nil, domainErrors.NewUsageError("expected one link ID", inviteRevokeUsage)
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(req.Args[0], "#"), 10, 64)
+	if err != nil || id <= 0 {
+		return nil, domainErrors.NewUsageError(fmt.Sprintf("invalid link ID %q", req.Args[0]), inviteRevokeUsage)
+	}
+
+	link, err := invites.Revoke(ctx, req.ChatID, id)
+	if errors.Is(err, domainErrors.ErrInviteLinkNotFound) {
+		return sendText(ctx, bot, req.ChatID, fmt.Sprintf("❌ Không tìm thấy link mời #%d trong nhóm này.", id))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return sendText(ctx, bot, req.ChatID,
+		fmt.Sprintf("🗑 Đã thu hồi link mời #%d «%s», %d người đã tham gia bằng link này.", link.ID, link.Name, link.JoinCount))
+}
+
+// parseInviteOptions reads the name, then in any order an optional duration and an optional member limit
+func parseInviteOptions(req *types.CommandRequest) (service.InviteLinkOptions, error) {
+	var options service.InviteLinkOptions
+	if req.IsPrivate() {
+		return options, fmt.Errorf("only available in groups")
+	}
+	if len(req.Args) == 0 || len(req.Args) > 3 {
+		return options, fmt.Errorf("expected a name and up to two settings")
+	}
+
+	options.Name = req.Args[0]
+	if utf8.RuneCountInString(options.Name) > maxInviteLinkName {
+		return options, fmt.Errorf("name longer than %d characters", maxInviteLinkName)
+	}
+
+	for _, arg := range req.Args[1:] {
+		if limit, err := strconv.Atoi(arg); err == nil {
+			if limit < 1 || limit > maxInviteLinkMember || options.MemberLimit != 0 {
+				return options, fmt.Errorf("invalid member limit %q", arg)
+			}
+			options.MemberLimit = limit
+			continue
+		}
+		duration, err := parseDuration(arg)
+		if err != nil || options.ExpiresIn != 0 {
+			return options, fmt.Errorf("invalid setting %q", arg)
+		}
+		options.ExpiresIn = duration
+	}
+	return options, nil
+}
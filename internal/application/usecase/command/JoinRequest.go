@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-telegram-bot/internal/domain/entity"
+	"go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+)
+
+// Reasons of join request decisions, stored in the audit records
+const (
+	JoinReasonPolicy        = "policy"
+	JoinReasonCorrectAnswer = "correct_answer"
+	JoinReasonWrongAnswer   = "wrong_answer"
+	JoinReasonAnswerTimeout = "answer_timeout"
+	JoinReasonReview        = "review"
+)
+
+// joinAnswerMessages tell the user the outcome of their answer, %s is the chat title
+var joinAnswerMessages = map[string]string{
+	JoinReasonCorrectAnswer: "✅ Yêu cầu tham gia «%s» đã được chấp nhận. Chào mừng bạn!",
+	JoinReasonWrongAnswer:   "❌ Câu trả lời chưa đúng, yêu cầu tham gia «%s» đã bị từ chối.",
+	JoinReasonAnswerTimeout: "⌛ Đã hết thời gian trả lời, yêu cầu tham gia «%s» đã bị từ chối.",
+}
+
+// Outcomes of a press on a review button, shown to the admin who pressed it
+const (
+	JoinCallbackApproved  = "approved"
+	JoinCallbackDeclined  = "declined"
+	JoinCallbackForbidden = "forbidden"
+	JoinCallbackHandled   = "handled"
+	JoinCallbackInvalid   = "invalid"
+)
+
+var joinCallbackMessages = map[string]string{
+	JoinCallbackApproved:  "✅ Đã chấp nhận yêu cầu",
+	JoinCallbackDeclined:  "❌ Đã từ chối yêu cầu",
+	JoinCallbackForbidden: "⛔ Bạn không có quyền duyệt yêu cầu này",
+	JoinCallbackHandled:   "Yêu cầu này đã được xử lý",
+	JoinCallbackInvalid:   "Nút bấm không hợp lệ",
+}
+
+// JoinQuestionHandler asks the question of the question policy in the private chat with the user
+func JoinQuestionHandler(
+	ctx context.Context,
+	request *entity.JoinRequest,
+	question string,
+	timeout time.Duration,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	text := fmt.Sprintf("👋 Để tham gia «%s», vui lòng trả lời câu hỏi sau trong vòng %s:\n\n%s",
+		request.ChatTitle, formatMinutes(timeout), question)
+
+	response, err := sendText(ctx, bot, request.UserChatID, text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send join question: %w", err)
+	}
+	return response, nil
+}
+
+// JoinAnswerHandler tells the user whether their answer got them into the chat
+func JoinAnswerHandler(
+	ctx context.Context,
+	request *entity.JoinRequest,
+	reason string,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	template, ok := joinAnswerMessages[reason]
+	if !ok {
+		return nil, nil
+	}
+
+	response, err := sendText(ctx, bot, request.UserChatID, fmt.Sprintf(template, request.ChatTitle))
+	if err != nil {
+		return nil, fmt.Errorf("failed to send join answer result: %w", err)
+	}
+	return response, nil
+}
+
+// JoinReviewHandler posts the request with its approve and decline buttons to the review chat
+func JoinReviewHandler(
+	ctx context.Context,
+	reviewChatID types.TelegramChatID,
+	request *entity.JoinRequest,
+	user *types.TelegramUser,
+	keyboard types.InlineKeyboardMarkup,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	var text strings.Builder
+	fmt.Fprintf(&text, "📨 Yêu cầu tham gia «%s»\n", request.ChatTitle)
+	fmt.Fprintf(&text, "👤 %s\n", userLabel(user))
+	fmt.Fprintf(&text, "🆔 %d\n", user.ID)
+	if request.Bio != nil && *request.Bio != "" {
+		fmt.Fprintf(&text, "📝 Giới thiệu: %s\n", *request.Bio)
+	}
+	if request.InviteLink != nil {
+		fmt.Fprintf(&text, "🔗 Link mời: %s\n", *request.InviteLink)
+	}
+
+	response, err := bot.SendMessageWithResponse(ctx, &types.SendMessageRequest{
+		ChatID:      reviewChatID,
+		Text:        text.String(),
+		ReplyMarkup: keyboard,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to post join request for review: %w", err)
+	}
+	return response, nil
+}
+
+// JoinReviewResultHandler replaces the buttons of the review message with the decision and its author
+func JoinReviewResultHandler(
+	ctx context.Context,
+	message *types.TelegramMessage,
+	status types.JoinRequestStatus,
+	admin *types.TelegramUser,
+	bot service.TelegramBotService,
+) (*types.EditMessageTextResponse, error) {
+	result := "✅ Đã chấp nhận bởi "
+	if status == types.JoinRequestStatusDeclined {
+		result = "❌ Đã từ chối bởi "
+	}
+	var original string
+	if message.Text != nil {
+		original = *message.Text
+	}
+
+	chatID := message.Chat.ID
+	response, err := bot.EditMessageText(ctx, &types.EditMessageTextRequest{
+		ChatID:    &chatID,
+		MessageID: &message.MessageID,
+		Text:      original + "\n" + result + userLabel(admin),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update review message: %w", err)
+	}
+	return response, nil
+}
+
+// JoinCallbackHandler answers the press on a review button with the outcome
+func JoinCallbackHandler(
+	ctx context.Context,
+	queryID string,
+	outcome string,
+	bot service.TelegramBotService,
+) (*types.AnswerCallbackQueryResponse, error) {
+	text := joinCallbackMessages[outcome]
+	showAlert := outcome == JoinCallbackForbidden
+	response, err := bot.AnswerCallbackQuery(ctx, &types.AnswerCallbackQueryRequest{
+		CallbackQueryID: queryID,
+		Text:            &text,
+		ShowAlert:       &showAlert,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to answer callback query: %w", err)
+	}
+	return response, nil
+}
+
+// userLabel names the user with their username when they have one
+func userLabel(user *types.TelegramUser) string {
+	name := user.FirstName
+	if user.LastName != nil {
+		name += " " + *user.LastName
+	}
+	if user.Username != nil {
+		name += " (@" + *user.Username + ")"
+	}
+	return name
+}
+
+// formatMinutes renders a duration in whole minutes, at least one
+func formatMinutes(d time.Duration) string {
+	return fmt.Sprintf("%d phút", max(1, int(d.Round(time.Minute)/time.Minute)))
+}
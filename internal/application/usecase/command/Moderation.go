@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	domainErrors "go-telegram-bot/internal/domain/errors"
+	"go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+)
+
+const (
+	banUsage = "/ban <user_id> [thời gian]\n" +
+		"/ban [thời gian] khi trả lời tin nhắn của người dùng\n" +
+		"Thời gian dạng 30m, 12h, 7d hoặc 2w, tối đa 366 ngày, bỏ trống để cấm vĩnh viễn"
+	unbanUsage = "/unban <user_id>\n" +
+		"/unban khi trả lời tin nhắn của người dùng"
+	muteUsage = "/mute <user_id> <thời gian>\n" +
+		"/mute <thời gian> khi trả lời tin nhắn của người dùng\n" +
+		"Thời gian dạng 30m, 12h, 7d hoặc 2w, tối đa 366 ngày, \"forever\" để cấm chat vĩnh viễn"
+	warnUsage = "/warn <user_id> [lý do]\n" +
+		"/warn [lý do] khi trả lời tin nhắn của người dùng"
+	purgeUsage = "/purge khi trả lời tin nhắn đầu tiên cần xoá, mọi tin nhắn từ đó đến lệnh sẽ bị xoá"
+)
+
+// BanHandler handles the /ban command removing a user from the group
+func BanHandler(
+	ctx context.Context,
+	req *types.CommandRequest,
+	moderation service.ModerationService,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	userID, args, err := parseModerationTarget(req)
+	if err != nil {
+		return nil, domainErrors.NewUsageError(err.Error(), banUsage)
+	}
+	var duration time.Duration
+	switch len(args) {
+	case 0:
+	case 1:
+		if duration, err = parseRestriction(args[0]); err != nil {
+			return nil, domainErrors.NewUsageError(err.Error(), banUsage)
+		}
+	default:
+		return nil, domainErrors.NewUsageError("unexpected arguments", banUsage)
+	}
+
+	if err := moderation.Ban(ctx, req.UserID, userID, req.ChatID, duration); err != nil {
+		return nil, err
+	}
+	return sendText(ctx, bot, req.ChatID,
+		fmt.Sprintf("🚫 Đã cấm %s %s.", targetName(req, userID), durationText(duration)))
+}
+
+// UnbanHandler handles the /unban command letting a banned user join the group again
+func UnbanHandler(
+	ctx context.Context,
+	req *types.CommandRequest,
+	moderation service.ModerationService,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	userID, args, err := parseModerationTarget(req)
+	if err != nil {
+		return nil, domainErrors.NewUsageError(err.Error(), unbanUsage)
+	}
+	if len(args) != 0 {
+		return nil, domainErrors.NewUsageError("unexpected arguments", unbanUsage)
+	}
+
+	if err := moderation.Unban(ctx, req.UserID, userID, req.ChatID); err != nil {
+		return nil, err
+	}
+	return sendText(ctx, bot, req.ChatID,
+		fmt.Sprintf("✅ Đã bỏ cấm %s, cảnh cáo của người này đã được xoá.", targetName(req, userID)))
+}
+
+// MuteHandler handles the /mute command taking away the permission to send messages
+func MuteHandler(
+	ctx context.Context,
+	req *types.CommandRequest,
+	moderation service.ModerationService,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	userID, args, err := parseModerationTarget(req)
+	if err != nil {
+		return nil, domainErrors.NewUsageError(err.Error(), muteUsage)
+	}
+	if len(args) != 1 {
+		return nil, domainErrors.NewUsageError("expected one duration", muteUsage)
+	}
+	var duration time.Duration
+	if args[0] != "forever" {
+		if duration, err = parseRestriction(args[0]); err != nil {
+			return nil, domainErrors.NewUsageError(err.Error(), muteUsage)
+		}
+	}
+
+	if err := moderation.Mute(ctx, req.UserID, userID, req.ChatID, duration); err != nil {
+		return nil, err
+	}
+	return sendText(ctx, bot, req.ChatID,
+		fmt.Sprintf("🔇 Đã cấm chat %s %s.", targetName(req, userID), durationText(duration)))
+}
+
+// WarnHandler handles the /warn command, the user is banned once their warnings reach the limit
+func WarnHandler(
+	ctx context.Context,
+	req *types.CommandRequest,
+	moderation service.ModerationService,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	userID, args, err := parseModerationTarget(req)
+	if err != nil {
+		return nil, domainErrors.NewUsageError(err.Error(), warnUsage)
+	}
+	reason := strings.Join(args, " ")
+
+	outcome, err := moderation.Warn(ctx, req.UserID, userID, req.ChatID, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	var text strings.Builder
+	name := targetName(req, userID)
+	if outcome.Limit > 0 {
+		fmt.Fprintf(&text, "⚠️ %s bị cảnh cáo (%d/%d).", name, outcome.Count, outcome.Limit)
+	} else {
+		fmt.Fprintf(&text, "⚠️ %s bị cảnh cáo (lần %d).", name, outcome.Count)
+	}
+	if reason != "" {
+		fmt.Fprintf(&text, "\nLý do: %s", reason)
+	}
+	if outcome.Banned {
+		fmt.Fprintf(&text, "\n🚫 Đã đủ số lần cảnh cáo, %s bị cấm %s.", name, durationText(outcome.BanDuration))
+	}
+	return sendText(ctx, bot, req.ChatID, text.String())
+}
+
+// PurgeHandler handles the /purge command deleting the messages from the replied one up to the command
+func PurgeHandler(
+	ctx context.Context,
+	req *types.CommandRequest,
+	moderation service.ModerationService,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	if req.IsPrivate() {
+		return nil, domainErrors.NewUsageError("only available in groups", purgeUsage)
+	}
+	if req.Message == nil || req.Message.ReplyToMessage == nil {
+		return nil, domainErrors.NewUsageError("missing replied message", purgeUsage)
+	}
+
+	count, err := moderation.Purge(ctx, req.ChatID, req.Message.ReplyToMessage.MessageID, req.MessageID)
+	if errors.Is(err, domainErrors.ErrTooManyMessages) {
+		return nil, domainErrors.NewUsageError(err.Error(), purgeUsage)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return sendText(ctx, bot, req.ChatID, fmt.Sprintf("🧹 Đã xoá %d tin nhắn.", count))
+}
+
+// parseModerationTarget resolves the user of a moderation command, which only applies to groups
+func parseModerationTarget(req *types.CommandRequest) (types.TelegramUserID, []string, error) {
+	if req.IsPrivate() {
+		return 0, nil, fmt.Errorf("only available in groups")
+	}
+	return parseTargetUser(req)
+}
+
+// parseRestriction parses the length of a ban or mute, refusing the ones Telegram would make permanent
+func parseRestriction(value string) (time.Duration, error) {
+	duration, err := parseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if duration > types.MaxRestrictionDuration {
+		return 0, fmt.Errorf("duration %q is longer than 366 days", value)
+	}
+	return duration, nil
+}
+
+// durationText describes how long a ban or mute lasts
+func durationText(d time.Duration) string {
+	if d <= 0 {
+		return "vĩnh viễn"
+	}
+	return "trong " + spanText(d)
+}
+
+// spanText writes a positive duration in days, hours and minutes
+func spanText(d time.Duration) string {
+	days, hours, minutes := int(d/(24*time.Hour)), int(d%(24*time.Hour)/time.Hour), int(d%time.Hour/time.Minute)
+	var parts []string
+	if days > 0 {
+		parts = append(parts, fmt.Sprintf("%d ngày", days))
+	}
+	if hours > 0 {
+		parts = append(parts, fmt.Sprintf("%d giờ", hours))
+	}
+	if minutes > 0 || len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("%d phút", max(1, minutes)))
+	}
+	return strings.Join(parts, " ")
+}
+
+// targetName names the moderated user, by name when the command replies to one of their messages
+func targetName(req *types.CommandRequest, userID types.TelegramUserID) string {
+	if target := req.ReplyTarget(); target != nil && target.ID == userID {
+		return userLabel(target)
+	}
+	return fmt.Sprintf("người dùng %d", userID)
+}
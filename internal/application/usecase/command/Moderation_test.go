@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRestriction(t *testing.T) {
+	cases := []struct {
+		value    string
+		duration time.Duration
+		ok       bool
+	}{
+		{"30m", 30 * time.Minute, true},
+		{"7d", 7 * 24 * time.Hour, true},
+		{"52w", 52 * 7 * 24 * time.Hour, true},
+		{"366d", 366 * 24 * time.Hour, true},
+		{"30s", 0, false},
+		{"367d", 0, false},
+		{"60w", 0, false},
+		{"9000h", 0, false},
+		{"99999999999w", 0, false},
+		{"99999999999999999999d", 0, false},
+		{"-1d", 0, false},
+		{"d", 0, false},
+	}
+	for _, c := range cases {
+		duration, err := parseRestriction(c.value)
+		if (err == nil) != c.ok || duration != c.duration {
+			t.Errorf("parseRestriction(%q) = %v, %v", c.value, duration, err)
+		}
+	}
+}
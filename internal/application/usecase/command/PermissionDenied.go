@@ -0,0 +1,25 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+)
+
+// PermissionDeniedHandler tells the user that the command requires a higher role
+func PermissionDeniedHandler(
+	ctx context.Context,
+	req *types.CommandRequest,
+	required types.Role,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	response, err := sendText(ctx, bot, req.ChatID,
+		fmt.Sprintf("⛔ Lệnh %s yêu cầu vai trò %s. Dùng /whoami để xem vai trò của bạn.", req.Command, required))
+	if err != nil {
+		return nil, fmt.Errorf("failed to send permission denied message: %w", err)
+	}
+
+	return response, nil
+}
@@ -0,0 +1,255 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go-telegram-bot/internal/domain/entity"
+	domainErrors "go-telegram-bot/internal/domain/errors"
+	"go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+	"go-telegram-bot/internal/shared/naturaltime"
+)
+
+const remindUsage = "/remind <thời gian> <nội dung>, hoặc /remind <thời gian> khi trả lời tin nhắn cần nhắc\n" +
+	"Ví dụ: /remind 2h kiểm tra router, /remind tomorrow 9:00 renew domain,\n" +
+	"/remind 9 giờ sáng thứ 2 họp nhóm, /remind 30 phút nữa tắt bếp"
+
+// Outcomes of a press on a reminder button, shown to the user who pressed it
+const (
+	ReminderCallbackSnoozed   = "snoozed"
+	ReminderCallbackCancelled = "cancelled"
+	ReminderCallbackForbidden = "forbidden"
+	ReminderCallbackGone      = "gone"
+	ReminderCallbackInvalid   = "invalid"
+)
+
+var reminderCallbackMessages = map[string]string{
+	ReminderCallbackSnoozed:   "💤 Đã hoãn nhắc nhở",
+	ReminderCallbackCancelled: "🗑 Đã huỷ nhắc nhở",
+	ReminderCallbackForbidden: "⛔ Đây không phải nhắc nhở của bạn",
+	ReminderCallbackGone:      "Nhắc nhở này không còn nữa",
+	ReminderCallbackInvalid:   "Nút bấm không hợp lệ",
+}
+
+// RemindHandler handles the /remind command, the time is read in the time zone of the user and the
+// service confirms the reminder. Replying to a message reminds of it, its text when none is given.
+func RemindHandler(
+	ctx context.Context,
+	req *types.CommandRequest,
+	reminders service.ReminderService,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	if req.Message == nil || req.Message.Text == nil {
+		return nil, domainErrors.NewUsageError("missing time", remindUsage)
+	}
+	_, rest, _ := strings.Cut(strings.TrimSpace(*req.Message.Text), " ")
+
+	loc, err := reminders.UserLocation(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	at, text, err := naturaltime.Parse(rest, time.Now().In(loc))
+	switch {
+	case errors.Is(err, naturaltime.ErrNoTime):
+		return nil, domainErrors.NewUsageError("missing time", remindUsage)
+	case errors.Is(err, naturaltime.ErrPast):
+		return nil, domainErrors.NewUsageError(fmt.Sprintf("%s has already passed", at.Format("15:04 02/01/2006")), remindUsage)
+	case err != nil:
+		return nil, domainErrors.NewUsageError(err.Error(), remindUsage)
+	}
+
+	messageID := req.MessageID
+	if replied := req.Message.ReplyToMessage; replied != nil {
+		messageID = replied.MessageID
+		if text == "" && replied.Text != nil {
+			text = strings.TrimSpace(*replied.Text)
+		}
+	}
+	if text == "" {
+		return nil, domainErrors.NewUsageError("missing text", remindUsage)
+	}
+	if utf8.RuneCountInString(text) > maxScheduledCommand {
+		return nil, domainErrors.NewUsageError(fmt.Sprintf("text longer than %d characters", maxScheduledCommand), remindUsage)
+	}
+
+	_, err = reminders.Remind(ctx, req.UserID, req.ChatID, req.ChatType, messageID, text, at)
+	if errors.Is(err, domainErrors.ErrInvalidInput) {
+		return nil, domainErrors.NewUsageError(err.Error(), remindUsage)
+	}
+	if errors.Is(err, domainErrors.ErrTooManySchedules) {
+		return sendText(ctx, bot, req.ChatID, "❌ Bạn đã có quá nhiều nhắc nhở, hãy huỷ bớt trong /reminders.")
+	}
+	return nil, err
+}
+
+// RemindersHandler handles the /reminders command, the service lists the reminders with their buttons
+func RemindersHandler(
+	ctx context.Context,
+	req *types.CommandRequest,
+	reminders service.ReminderService,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	return nil, reminders.ListReminders(ctx, req.UserID, req.ChatID)
+}
+
+// ReminderCreatedHandler confirms the reminder in reply to the message which asked for it
+func ReminderCreatedHandler(
+	ctx context.Context,
+	job *entity.ScheduledJob,
+	keyboard types.InlineKeyboardMarkup,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	allowWithoutReply := true
+	response, err := bot.SendMessageWithResponse(ctx, &types.SendMessageRequest{
+		ChatID:                   job.TelegramChatID,
+		Text:                     fmt.Sprintf("⏰ Sẽ nhắc lúc %s (#%d)\n📝 %s", reminderTime(job), job.ID, job.Text),
+		ReplyToMessageID:         job.ReplyToMessageID,
+		AllowSendingWithoutReply: &allowWithoutReply,
+		ReplyMarkup:              keyboard,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to confirm reminder: %w", err)
+	}
+	return response, nil
+}
+
+// ReminderListHandler posts the reminders of the user with the cancel button of each
+func ReminderListHandler(
+	ctx context.Context,
+	chatID types.TelegramChatID,
+	jobs []*entity.ScheduledJob,
+	keyboard types.InlineKeyboardMarkup,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	response, err := bot.SendMessageWithResponse(ctx, &types.SendMessageRequest{
+		ChatID:      chatID,
+		Text:        reminderList(jobs),
+		ReplyMarkup: keyboard,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reminders: %w", err)
+	}
+	return response, nil
+}
+
+// ReminderListUpdateHandler renders a list posted by ReminderListHandler again, after a reminder was cancelled
+func ReminderListUpdateHandler(
+	ctx context.Context,
+	message *types.TelegramMessage,
+	jobs []*entity.ScheduledJob,
+	keyboard types.InlineKeyboardMarkup,
+	bot service.TelegramBotService,
+) (*types.EditMessageTextResponse, error) {
+	chatID := message.Chat.ID
+	response, err := bot.EditMessageText(ctx, &types.EditMessageTextRequest{
+		ChatID:      &chatID,
+		MessageID:   &message.MessageID,
+		Text:        reminderList(jobs),
+		ReplyMarkup: keyboard,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update reminder list: %w", err)
+	}
+	return response, nil
+}
+
+// ReminderOutcomeHandler replaces the buttons of a confirmation or a delivered reminder with the outcome of the press
+func ReminderOutcomeHandler(
+	ctx context.Context,
+	message *types.TelegramMessage,
+	job *entity.ScheduledJob,
+	snoozed bool,
+	bot service.TelegramBotService,
+) (*types.EditMessageTextResponse, error) {
+	result := "🗑 Đã huỷ"
+	if snoozed {
+		result = "💤 Đã hoãn đến " + reminderTime(job)
+	}
+	var original string
+	if message.Text != nil {
+		original = *message.Text
+	}
+
+	chatID := message.Chat.ID
+	response, err := bot.EditMessageText(ctx, &types.EditMessageTextRequest{
+		ChatID:    &chatID,
+		MessageID: &message.MessageID,
+		Text:      original + "\n" + result,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update reminder: %w", err)
+	}
+	return response, nil
+}
+
+// ReminderDeliveryHandler delivers the reminder in reply to the message which asked for it, or without
+// reply when that message was deleted, with the snooze buttons
+func ReminderDeliveryHandler(
+	ctx context.Context,
+	job *entity.ScheduledJob,
+	keyboard types.InlineKeyboardMarkup,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	allowWithoutReply := true
+	response, err := bot.SendMessageWithResponse(ctx, &types.SendMessageRequest{
+		ChatID:                   job.TelegramChatID,
+		Text:                     "🔔 Nhắc nhở: " + job.Text,
+		ReplyToMessageID:         job.ReplyToMessageID,
+		AllowSendingWithoutReply: &allowWithoutReply,
+		ReplyMarkup:              keyboard,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to deliver reminder: %w", err)
+	}
+	return response, nil
+}
+
+// ReminderCallbackHandler answers the press on a reminder button with the outcome
+func ReminderCallbackHandler(
+	ctx context.Context,
+	queryID string,
+	outcome string,
+	bot service.TelegramBotService,
+) (*types.AnswerCallbackQueryResponse, error) {
+	text := reminderCallbackMessages[outcome]
+	showAlert := outcome == ReminderCallbackForbidden
+	response, err := bot.AnswerCallbackQuery(ctx, &types.AnswerCallbackQueryRequest{
+		CallbackQueryID: queryID,
+		Text:            &text,
+		ShowAlert:       &showAlert,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to answer callback query: %w", err)
+	}
+	return response, nil
+}
+
+// reminderList describes the reminders of a /reminders list
+func reminderList(jobs []*entity.ScheduledJob) string {
+	if len(jobs) == 0 {
+		return "⏰ Không có nhắc nhở nào."
+	}
+	var text strings.Builder
+	fmt.Fprintf(&text, "⏰ %d nhắc nhở:\n", len(jobs))
+	for _, job := range jobs {
+		fmt.Fprintf(&text, "\n#%d · %s\n📝 %s\n", job.ID, reminderTime(job), job.Text)
+	}
+	return text.String()
+}
+
+// reminderTime shows the next delivery of the reminder in the time zone of its creator
+func reminderTime(job *entity.ScheduledJob) string {
+	if job.NextRunAt == nil {
+		return "đã nhắc"
+	}
+	at := *job.NextRunAt
+	if loc, err := time.LoadLocation(job.Timezone); err == nil {
+		at = at.In(loc)
+	}
+	return at.Format("15:04 02/01/2006")
+}
@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-telegram-bot/internal/domain/entity"
+	"go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+)
+
+// sendMarkdown sends text already escaped for MarkdownV2 to the chat
+func sendMarkdown(
+	ctx context.Context, bot service.TelegramBotService, chatID types.TelegramChatID, text string,
+) (*types.SendMessageResponse, error) {
+	parseMode := types.ParseModeMarkdownV2
+	return bot.SendMessageWithResponse(ctx, &types.SendMessageRequest{
+		ChatID:    chatID,
+		Text:      text,
+		ParseMode: &parseMode,
+	})
+}
+
+// sendText sends plain text to the chat
+func sendText(
+	ctx context.Context, bot service.TelegramBotService, chatID types.TelegramChatID, text string,
+) (*types.SendMessageResponse, error) {
+	return bot.SendMessageWithResponse(ctx, &types.SendMessageRequest{
+		ChatID: chatID,
+		Text:   text,
+	})
+}
+
+// parseTargetUser resolves the user a command applies to: the author of the replied message,
+// otherwise a numeric user ID as first argument. It returns the remaining arguments.
+func parseTargetUser(req *types.CommandRequest) (types.TelegramUserID, []string, error) {
+	if target := req.ReplyTarget(); target != nil {
+		return target.ID, req.Args, nil
+	}
+	if len(req.Args) == 0 {
+		return 0, nil, fmt.Errorf("missing user")
+	}
+	id, err := strconv.ParseInt(req.Args[0], 10, 64)
+	if err != nil || id <= 0 {
+		return 0, nil, fmt.Errorf("invalid user ID %q", req.Args[0])
+	}
+	return types.TelegramUserID(id), req.Args[1:], nil
+}
+
+// parseScope returns entity.GlobalChatID when the last argument is "global", otherwise the chat of the request
+func parseScope(req *types.CommandRequest, args []string) (types.TelegramChatID, []string) {
+	if n := len(args); n > 0 && args[n-1] == "global" {
+		return entity.GlobalChatID, args[:n-1]
+	}
+	return req.ChatID, args
+}
+
+// scopeName describes the chat a role applies to
+func scopeName(chatID types.TelegramChatID) string {
+	if chatID == entity.GlobalChatID {
+		return "mọi cuộc trò chuyện"
+	}
+	return "cuộc trò chuyện này"
+}
+
+// parseDuration parses a duration of at least a minute, accepting d for days and w for weeks
+// in addition to the units of time.ParseDuration
+func parseDuration(value string) (time.Duration, error) {
+	var unit time.Duration
+	switch {
+	case strings.HasSuffix(value, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(value, "w"):
+		unit = 7 * 24 * time.Hour
+	}
+
+	var duration time.Duration
+	if unit != 0 {
+		n, err := strconv.ParseInt(value[:len(value)-1], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		if n > math.MaxInt64/int64(unit) {
+			return 0, fmt.Errorf("duration %q is too long", value)
+		}
+		duration = time.Duration(n) * unit
+	} else {
+		var err error
+		if duration, err = time.ParseDuration(value); err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+	}
+	// Telegram treats restrictions shorter than 30 seconds as permanent
+	if duration < time.Minute {
+		return 0, fmt.Errorf("duration %q is shorter than a minute", value)
+	}
+	return duration, nil
+}
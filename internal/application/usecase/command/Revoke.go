@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	domainErrors "go-telegram-bot/internal/domain/errors"
+	"go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+)
+
+const revokeUsage = "/revoke <user_id> [global]\n" +
+	"/revoke [global] khi trả lời tin nhắn của người dùng"
+
+// RevokeHandler handles the /revoke command removing a role assignment
+func RevokeHandler(
+	ctx context.Context,
+	req *types.CommandRequest,
+	auth service.AuthorizationService,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	userID, args, err := parseTargetUser(req)
+	if err != nil {
+		return nil, domainErrors.NewUsageError(err.Error(), revokeUsage)
+	}
+	chatID, args := parseScope(req, args)
+	if len(args) != 0 {
+		return nil, domainErrors.NewUsageError("unexpected arguments", revokeUsage)
+	}
+
+	err = auth.Revoke(ctx, req.UserID, userID, chatID)
+	switch {
+	case err == nil:
+		return sendText(ctx, bot, req.ChatID,
+			fmt.Sprintf("✅ Đã thu hồi vai trò của người dùng %d trong %s.", userID, scopeName(chatID)))
+	case errors.Is(err, domainErrors.ErrRoleAssignmentNotFound):
+		return sendText(ctx, bot, req.ChatID, "ℹ️ Người dùng này không có vai trò nào để thu hồi.")
+	default:
+		// Permission and availability errors are answered by the error middleware
+		return nil, fmt.Errorf("failed to revoke role: %w", err)
+	}
+}
@@ -0,0 +1,263 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go-telegram-bot/internal/domain/entity"
+	domainErrors "go-telegram-bot/internal/domain/errors"
+	"go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+)
+
+const (
+	scheduleUsage = "/schedule <phút giờ ngày tháng thứ> [tz:<múi giờ>] /lệnh [tham số]\n" +
+		"/schedule @daily|@hourly|@weekly|@monthly [tz:<múi giờ>] /lệnh\n" +
+		"/schedule in:<thời gian> /lệnh hoặc /schedule at:<HH:MM|YYYY-MM-DDTHH:MM> /lệnh\n" +
+		"Ví dụ: /schedule 0 8 * * 1-5 /home_ip"
+	unscheduleUsage = "/unschedule <id>"
+	timezoneUsage   = "/timezone [múi giờ], ví dụ /timezone Asia/Ho_Chi_Minh"
+
+	// maxScheduledCommand is the length of the text column of scheduled_jobs
+	maxScheduledCommand = 512
+)
+
+// scheduledCommandStart finds the command to run, the first field starting with "/" after the timing
+var scheduledCommandStart = regexp.MustCompile(`\s/`)
+
+// unschedulable are the commands a job may not run, a job scheduling jobs or reminders would multiply
+var unschedulable = map[types.Command]struct{}{
+	types.CommandSchedule:   {},
+	types.CommandSchedules:  {},
+	types.CommandUnschedule: {},
+	types.CommandRemind:     {},
+}
+
+// ScheduleHandler handles the /schedule command running another command on a timer
+func ScheduleHandler(
+	ctx context.Context,
+	req *types.CommandRequest,
+	scheduler service.SchedulerService,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	commandText, spec, startIn, at, err := parseSchedule(req)
+	if err != nil {
+		return nil, domainErrors.NewUsageError(err.Error(), scheduleUsage)
+	}
+
+	// A one-shot time of day is read in the time zone of the job
+	if at != "" {
+		loc, err := scheduleLocation(ctx, req.ChatID, spec.Timezone, scheduler)
+		if err != nil {
+			return nil, err
+		}
+		if spec.At, err = parseClock(at, time.Now().In(loc)); err != nil {
+			return nil, domainErrors.NewUsageError(err.Error(), scheduleUsage)
+		}
+	} else if startIn > 0 {
+		spec.At = time.Now().Add(startIn)
+	}
+
+	job, err := scheduler.Schedule(ctx, req.UserID, req.ChatID, req.ChatType, commandText, spec)
+	if errors.Is(err, domainErrors.ErrInvalidInput) {
+		return nil, domainErrors.NewUsageError(err.Error(), scheduleUsage)
+	}
+	if errors.Is(err, domainErrors.ErrTooManySchedules) {
+		return sendText(ctx, bot, req.ChatID, "❌ Cuộc trò chuyện này đã có quá nhiều lịch, hãy xoá bớt bằng /unschedule.")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return sendText(ctx, bot, req.ChatID, "⏰ Đã lên lịch\n"+scheduleSummary(job))
+}
+
+// SchedulesHandler handles the /schedules command listing the jobs of the chat
+func SchedulesHandler(
+	ctx context.Context,
+	req *types.CommandRequest,
+	scheduler service.SchedulerService,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	jobs, err := scheduler.List(ctx, req.ChatID)
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return sendText(ctx, bot, req.ChatID, "⏰ Cuộc trò chuyện này chưa có lịch nào.")
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "⏰ %d lịch:\n", len(jobs))
+	for _, job := range jobs {
+		text.WriteString("\n" + scheduleSummary(job))
+	}
+	return sendText(ctx, bot, req.ChatID, text.String())
+}
+
+// UnscheduleHandler handles the /unschedule command removing a job of the chat
+func UnscheduleHandler(
+	ctx context.Context,
+	req *types.CommandRequest,
+	scheduler service.SchedulerService,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	if len(req.Args) != 1 {
+		return nil, domainErrors.NewUsageError("expected one schedule ID", unscheduleUsage)
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(req.Args[0], "#"), 10, 64)
+	if err != nil || id <= 0 {
+		return nil, domainErrors.NewUsageError(fmt.Sprintf("invalid schedule ID %q", req.Args[0]), unscheduleUsage)
+	}
+
+	err = scheduler.Unschedule(ctx, req.ChatID, id)
+	if errors.Is(err, domainErrors.ErrScheduleNotFound) {
+		return sendText(ctx, bot, req.ChatID, fmt.Sprintf("❌ Không tìm thấy lịch #%d.", id))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return sendText(ctx, bot, req.ChatID, fmt.Sprintf("🗑 Đã xoá lịch #%d.", id))
+}
+
+// TimezoneHandler handles the /timezone command showing or changing the time zone of the chat
+func TimezoneHandler(
+	ctx context.Context,
+	req *types.CommandRequest,
+	scheduler service.SchedulerService,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	switch len(req.Args) {
+	case 0:
+		loc, err := scheduler.Location(ctx, req.ChatID)
+		if err != nil {
+			return nil, err
+		}
+		return sendText(ctx, bot, req.ChatID, fmt.Sprintf("🕰 Múi giờ: %s, bây giờ là %s.",
+			loc, time.Now().In(loc).Format("15:04 02/01/2006")))
+	case 1:
+	default:
+		return nil, domainErrors.NewUsageError("unexpected arguments", timezoneUsage)
+	}
+
+	loc, err := scheduler.SetTimezone(ctx, req.UserID, req.ChatID, req.ChatType, req.Args[0])
+	if errors.Is(err, domainErrors.ErrInvalidTimezone) {
+		return nil, domainErrors.NewUsageError(err.Error(), timezoneUsage)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return sendText(ctx, bot, req.ChatID, fmt.Sprintf("🕰 Đã đổi múi giờ thành %s, bây giờ là %s.",
+		loc, time.Now().In(loc).Format("15:04 02/01/2006")))
+}
+
+// parseSchedule splits the command into the timing and the command to run. The timing is either
+// the five cron fields or a macro, or in:<duration> or at:<time> for a single run, then an optional
+// tz:<zone>. at is returned as written since it is read in the time zone of the job.
+func parseSchedule(req *types.CommandRequest) (
+	commandText string, spec service.ScheduleSpec, startIn time.Duration, at string, err error,
+) {
+	if req.Message == nil || req.Message.Text == nil {
+		return "", spec, 0, "", fmt.Errorf("missing command")
+	}
+	raw := strings.TrimSpace(*req.Message.Text)
+	// Everything between /schedule and the command to run is the timing
+	start := scheduledCommandStart.FindStringIndex(raw)
+	if start == nil {
+		return "", spec, 0, "", fmt.Errorf("missing command")
+	}
+	commandText = strings.TrimSpace(raw[start[0]:])
+	timing := strings.Fields(raw[:start[0]])[1:]
+
+	command, _, _ := types.ParseCommand(commandText)
+	if _, denied := unschedulable[command]; denied || !command.IsValid() {
+		return "", spec, 0, "", fmt.Errorf("command %s cannot be scheduled", command)
+	}
+	if utf8.RuneCountInString(commandText) > maxScheduledCommand {
+		return "", spec, 0, "", fmt.Errorf("command longer than %d characters", maxScheduledCommand)
+	}
+
+	var fields []string
+	for _, field := range timing {
+		key, value, _ := strings.Cut(field, ":")
+		switch {
+		case key == "tz" && value != "":
+			spec.Timezone = value
+		case key == "in" && value != "":
+			if startIn, err = parseDuration(value); err != nil {
+				return "", spec, 0, "", err
+			}
+		case key == "at" && value != "":
+			at = value
+		default:
+			fields = append(fields, field)
+		}
+	}
+
+	oneShot := startIn > 0 || at != ""
+	switch {
+	case oneShot && len(fields) > 0, startIn > 0 && at != "":
+		return "", spec, 0, "", fmt.Errorf("expected either a cron expression, in: or at:")
+	case !oneShot && len(fields) == 0:
+		return "", spec, 0, "", fmt.Errorf("missing cron expression")
+	}
+	spec.Cron = strings.Join(fields, " ")
+	return commandText, spec, startIn, at, nil
+}
+
+// scheduleLocation loads the time zone named by the tz: option, else the one of the chat
+func scheduleLocation(
+	ctx context.Context, chatID types.TelegramChatID, name string, scheduler service.SchedulerService,
+) (*time.Location, error) {
+	if name == "" {
+		return scheduler.Location(ctx, chatID)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil || name == "Local" {
+		return nil, domainErrors.NewUsageError(fmt.Sprintf("invalid time zone %q", name), scheduleUsage)
+	}
+	return loc, nil
+}
+
+// parseClock reads YYYY-MM-DDTHH:MM, or HH:MM as its next occurrence after now, in the location of now
+func parseClock(value string, now time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02T15:04", value, now.Location()); err == nil {
+		return t, nil
+	}
+	clock, err := time.Parse("15:04", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q", value)
+	}
+	t := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+	if !t.After(now) {
+		t = time.Date(now.Year(), now.Month(), now.Day()+1, clock.Hour(), clock.Minute(), 0, 0, now.Location())
+	}
+	return t, nil
+}
+
+// scheduleSummary describes the job, its next run is shown in the time zone of the job
+func scheduleSummary(job *entity.ScheduledJob) string {
+	var text strings.Builder
+	timing := "một lần"
+	if job.IsRecurring() {
+		timing = job.Cron
+	}
+	fmt.Fprintf(&text, "#%d · %s · %s\n", job.ID, timing, job.Timezone)
+	fmt.Fprintf(&text, "▶️ %s\n", job.Text)
+	if job.NextRunAt != nil {
+		next := *job.NextRunAt
+		if loc, err := time.LoadLocation(job.Timezone); err == nil {
+			next = next.In(loc)
+		}
+		fmt.Fprintf(&text, "⏭ Lần tới: %s\n", next.Format("15:04 02/01/2006"))
+	}
+	if job.LastError != "" {
+		fmt.Fprintf(&text, "⚠️ Lần trước lỗi: %s\n", job.LastError)
+	}
+	return text.String()
+}
@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+)
+
+// WelcomeHandler sends the welcome template to the chat, replacing {name} with the first
+// name of the user and {chat} with the chat title. The template is sent as plain text.
+func WelcomeHandler(
+	ctx context.Context,
+	chat *types.TelegramChat,
+	user *types.TelegramUser,
+	template string,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	var name, title string
+	if user != nil {
+		name = user.FirstName
+	}
+	if chat.Title != nil {
+		title = *chat.Title
+	}
+	text := strings.NewReplacer("{name}", name, "{chat}", title).Replace(template)
+
+	response, err := sendText(ctx, bot, chat.ID, text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send welcome message: %w", err)
+	}
+	return response, nil
+}
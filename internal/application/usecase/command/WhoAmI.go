@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+	"go-telegram-bot/internal/shared/util"
+)
+
+// WhoAmIHandler handles the /whoami command, reporting the caller's IDs and effective role
+func WhoAmIHandler(
+	ctx context.Context,
+	req *types.CommandRequest,
+	auth service.AuthorizationService,
+	bot service.TelegramBotService,
+) (*types.SendMessageResponse, error) {
+	role, err := auth.RoleOf(ctx, req.UserID, req.ChatID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve role: %w", err)
+	}
+
+	message := fmt.Sprintf("👤 *%s*\n\n"+
+		"🆔 *User ID:* `%d`\n"+
+		"💬 *Chat ID:* `%d`\n"+
+		"🔑 *%s:* `%s`",
+		util.EscapeMarkdownV2("Thông tin của bạn"),
+		req.UserID,
+		req.ChatID,
+		util.EscapeMarkdownV2("Vai trò"),
+		role,
+	)
+
+	response, err := sendMarkdown(ctx, bot, req.ChatID, message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send whoami message: %w", err)
+	}
+
+	return response, nil
+}
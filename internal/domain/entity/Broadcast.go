@@ -0,0 +1,68 @@
+package entity
+
+import (
+	"time"
+
+	"go-telegram-bot/internal/domain/types"
+)
+
+// BroadcastFilter selects the active chats receiving a broadcast, an empty field matches every chat
+type BroadcastFilter struct {
+	ChatType types.ChatType `json:"chat_type,omitempty" gorm:"column:target_chat_type;type:varchar(32);not null;default:''"`
+	Tag      string         `json:"tag,omitempty" gorm:"column:target_tag;type:varchar(64);not null;default:''"`
+}
+
+// BroadcastJob is an announcement sent to every chat matching its filter. The deliveries are planned
+// once when the job starts, the counters reflect them and the lease keeps a second worker away.
+type BroadcastJob struct {
+	BaseEntityWithInt
+	Text         string                `json:"text" gorm:"type:text;not null"`
+	Filter       BroadcastFilter       `json:"filter" gorm:"embedded"`
+	Status       types.BroadcastStatus `json:"status" gorm:"type:varchar(16);not null;index"`
+	ScheduledAt  time.Time             `json:"scheduled_at" gorm:"type:timestamp;not null"`
+	CreatedBy    types.TelegramUserID  `json:"created_by" gorm:"type:bigint;not null"`
+	ReportChatID types.TelegramChatID  `json:"report_chat_id" gorm:"type:bigint;not null"`
+
+	Total   int `json:"total" gorm:"type:integer;not null;default:0"`
+	Sent    int `json:"sent" gorm:"type:integer;not null;default:0"`
+	Failed  int `json:"failed" gorm:"type:integer;not null;default:0"`
+	Blocked int `json:"blocked" gorm:"type:integer;not null;default:0"`
+
+	PlannedAt   *time.Time `json:"planned_at,omitempty" gorm:"type:timestamp;default:null"`
+	StartedAt   *time.Time `json:"started_at,omitempty" gorm:"type:timestamp;default:null"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty" gorm:"type:timestamp;default:null"`
+	LockedUntil *time.Time `json:"locked_until,omitempty" gorm:"type:timestamp;default:null"`
+}
+
+func NewBroadcastJob(
+	text string,
+	filter BroadcastFilter,
+	scheduledAt time.Time,
+	createdBy types.TelegramUserID,
+	reportChatID types.TelegramChatID,
+) *BroadcastJob {
+	return &BroadcastJob{
+		Text:         text,
+		Filter:       filter,
+		Status:       types.BroadcastStatusDraft,
+		ScheduledAt:  scheduledAt,
+		CreatedBy:    createdBy,
+		ReportChatID: reportChatID,
+	}
+}
+
+// Pending returns the number of planned deliveries not attempted yet
+func (j *BroadcastJob) Pending() int {
+	return max(0, j.Total-j.Sent-j.Failed-j.Blocked)
+}
+
+// BroadcastDelivery is the broadcast of a job to one chat
+type BroadcastDelivery struct {
+	BaseEntityWithInt
+	JobID          int64                `json:"job_id" gorm:"type:bigint;not null;uniqueIndex:idx_delivery_job_chat;index:idx_delivery_job_status"`
+	TelegramChatID types.TelegramChatID `json:"telegram_chat_id" gorm:"type:bigint;not null;uniqueIndex:idx_delivery_job_chat"`
+	Status         types.DeliveryStatus `json:"status" gorm:"type:varchar(16);not null;index:idx_delivery_job_status"`
+	Error          string               `json:"error" gorm:"type:varchar(255);not null;default:''"`
+	MessageID      *int64               `json:"message_id,omitempty" gorm:"type:bigint;default:null"`
+	SentAt         *time.Time           `json:"sent_at,omitempty" gorm:"type:timestamp;default:null"`
+}
@@ -10,6 +10,7 @@ type Chat struct {
 	Username       *string              `json:"username,omitempty" gorm:"type:varchar(255);default:null"`
 	Description    *string              `json:"description,omitempty" gorm:"type:text;default:null"`
 	IsActive       bool                 `json:"is_active" gorm:"type:boolean;not null;default:true"`
+	Timezone       string               `json:"timezone" gorm:"type:varchar(64);not null;default:''"`
 }
 
 func NewChat(telegramChatID types.TelegramChatID, chatType types.ChatType) *Chat {
@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"time"
+
+	"go-telegram-bot/internal/domain/types"
+)
+
+// ChatMember is the last known status of a user, or of the bot itself, in a chat
+type ChatMember struct {
+	BaseEntityWithUUID
+	TelegramChatID types.TelegramChatID   `json:"telegram_chat_id" gorm:"type:bigint;not null;uniqueIndex:idx_chat_member_chat_user"`
+	TelegramUserID types.TelegramUserID   `json:"telegram_user_id" gorm:"type:bigint;not null;uniqueIndex:idx_chat_member_chat_user"`
+	IsBot          bool                   `json:"is_bot" gorm:"type:boolean;not null;default:false"`
+	Status         types.ChatMemberStatus `json:"status" gorm:"type:varchar(16);not null"`
+	JoinedAt       *time.Time             `json:"joined_at,omitempty" gorm:"type:timestamp;default:null"`
+	LeftAt         *time.Time             `json:"left_at,omitempty" gorm:"type:timestamp;default:null"`
+}
+
+func NewChatMember(chatID types.TelegramChatID, user *types.TelegramUser) *ChatMember {
+	return &ChatMember{
+		TelegramChatID: chatID,
+		TelegramUserID: user.ID,
+		IsBot:          user.IsBot,
+		Status:         types.ChatMemberStatusLeft,
+	}
+}
+
+// SetStatus records the new status, stamping when the member joined or left the chat
+func (m *ChatMember) SetStatus(status types.ChatMemberStatus, at time.Time) {
+	switch {
+	case status.IsPresent() && !m.Status.IsPresent():
+		m.JoinedAt = &at
+	case !status.IsPresent() && m.Status.IsPresent():
+		m.LeftAt = &at
+	}
+	m.Status = status
+}
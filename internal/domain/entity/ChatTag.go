@@ -0,0 +1,10 @@
+package entity
+
+import "go-telegram-bot/internal/domain/types"
+
+// ChatTag labels a chat so broadcasts can target a group of chats
+type ChatTag struct {
+	BaseEntityWithInt
+	TelegramChatID types.TelegramChatID `json:"telegram_chat_id" gorm:"type:bigint;not null;uniqueIndex:idx_chat_tag"`
+	Tag            string               `json:"tag" gorm:"type:varchar(64);not null;uniqueIndex:idx_chat_tag;index"`
+}
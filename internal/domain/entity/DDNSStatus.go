@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+// DDNSStatus is the outcome of the last update of a DDNS provider, it is kept in memory
+type DDNSStatus struct {
+	Provider string `json:"provider"`
+	// IP is the address the records were last pointed at, empty before the first success
+	IP        string     `json:"ip"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	// CheckedAt is the last attempt, LastError its error when it failed
+	CheckedAt *time.Time `json:"checked_at,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+}
+
+// IsHealthy reports whether the last attempt succeeded
+func (s DDNSStatus) IsHealthy() bool {
+	return s.CheckedAt != nil && s.LastError == ""
+}
@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// FloodEvent is one event counted by an anti-flood sliding window, such as a command sent by a user
+type FloodEvent struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	Key        string    `json:"key" gorm:"type:varchar(128);not null;index:idx_flood_event_key_time"`
+	OccurredAt time.Time `json:"occurred_at" gorm:"not null;index:idx_flood_event_key_time"`
+}
+
+func NewFloodEvent(key string, occurredAt time.Time) *FloodEvent {
+	return &FloodEvent{
+		Key:        key,
+		OccurredAt: occurredAt,
+	}
+}
@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+
+	"go-telegram-bot/internal/domain/types"
+)
+
+// IPChange records the WAN address seen from DetectedAt on. PreviousID links it to the change it
+// follows, 0 for the first address seen, and is unique so concurrent monitors record a change once.
+type IPChange struct {
+	BaseEntityWithInt
+	IP         string    `json:"ip" gorm:"type:varchar(45);not null"`
+	PreviousIP string    `json:"previous_ip" gorm:"type:varchar(45);not null;default:''"`
+	PreviousID int64     `json:"previous_id" gorm:"type:bigint;not null;default:0;uniqueIndex"`
+	DetectedAt time.Time `json:"detected_at" gorm:"type:timestamp;not null;index"`
+}
+
+// NewIPChange creates the change from previous, nil for the first address seen, to ip
+func NewIPChange(previous *IPChange, ip string, at time.Time) *IPChange {
+	change := &IPChange{IP: ip, DetectedAt: at}
+	if previous != nil {
+		change.PreviousIP, change.PreviousID = previous.IP, previous.ID
+	}
+	return change
+}
+
+// IPSubscription makes the IP monitor tell the chat when the WAN address changes
+type IPSubscription struct {
+	BaseEntityWithInt
+	TelegramChatID types.TelegramChatID `json:"telegram_chat_id" gorm:"type:bigint;not null;uniqueIndex"`
+	ChatType       types.ChatType       `json:"chat_type" gorm:"type:varchar(32);not null"`
+	SubscribedBy   types.TelegramUserID `json:"subscribed_by" gorm:"type:bigint;not null"`
+}
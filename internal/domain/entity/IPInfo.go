@@ -1,9 +1,47 @@
 package entity
 
+import (
+	"fmt"
+	"strings"
+)
+
 // IPInfo represents IP address information
 type IPInfo struct {
 	LocalIP  string `json:"local_ip"`
 	PublicIP string `json:"public_ip"`
+
+	// Public address of each family, empty when the network has no route for it
+	PublicIPv4 string `json:"public_ipv4,omitempty"`
+	PublicIPv6 string `json:"public_ipv6,omitempty"`
+
+	Interfaces []NetworkInterface `json:"interfaces,omitempty"`
+
+	// Details of the public address, left empty when they could not be looked up
+	IPDetails
+}
+
+// NetworkInterface describes a network interface of the machine and its addresses
+type NetworkInterface struct {
+	Name      string   `json:"name"`
+	MAC       string   `json:"mac,omitempty"`
+	Addresses []string `json:"addresses,omitempty"` // CIDR notation, e.g. 192.168.1.10/24
+	Up        bool     `json:"up"`
+	Loopback  bool     `json:"loopback"`
+
+	// DefaultRoute is set when a default route goes through the interface, Gateways holds its next hops
+	DefaultRoute bool     `json:"default_route"`
+	Gateways     []string `json:"gateways,omitempty"`
+}
+
+// IPDetails describes where an address is and which network announces it, unknown fields are empty
+type IPDetails struct {
+	Country      string `json:"country,omitempty"`
+	CountryCode  string `json:"country_code,omitempty"`
+	Region       string `json:"region,omitempty"`
+	City         string `json:"city,omitempty"`
+	ASN          uint32 `json:"asn,omitempty"`
+	Organization string `json:"organization,omitempty"`
+	ReverseDNS   string `json:"reverse_dns,omitempty"`
 }
 
 // NewIPInfo creates a new IPInfo instance
@@ -18,3 +56,55 @@ func NewIPInfo(localIP, publicIP string) *IPInfo {
 func (ip *IPInfo) IsValid() bool {
 	return ip.LocalIP != "" && ip.PublicIP != ""
 }
+
+// Merge fills the empty fields of d from other, so that the results of several sources combine
+func (d *IPDetails) Merge(other IPDetails) {
+	for _, field := range []struct {
+		dst *string
+		src string
+	}{
+		{&d.Country, other.Country},
+		{&d.CountryCode, other.CountryCode},
+		{&d.Region, other.Region},
+		{&d.City, other.City},
+		{&d.Organization, other.Organization},
+		{&d.ReverseDNS, other.ReverseDNS},
+	} {
+		if *field.dst == "" {
+			*field.dst = field.src
+		}
+	}
+	if d.ASN == 0 {
+		d.ASN = other.ASN
+	}
+}
+
+// Location joins the known parts of the location, from the city to the country
+func (d *IPDetails) Location() string {
+	var parts []string
+	for _, part := range []string{d.City, d.Region} {
+		if part != "" && (len(parts) == 0 || parts[len(parts)-1] != part) {
+			parts = append(parts, part)
+		}
+	}
+	switch {
+	case d.Country != "" && d.CountryCode != "":
+		parts = append(parts, fmt.Sprintf("%s (%s)", d.Country, d.CountryCode))
+	case d.Country != "":
+		parts = append(parts, d.Country)
+	case d.CountryCode != "":
+		parts = append(parts, d.CountryCode)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Network describes the autonomous system announcing the address, e.g. AS7552 Viettel Group
+func (d *IPDetails) Network() string {
+	switch {
+	case d.ASN != 0 && d.Organization != "":
+		return fmt.Sprintf("AS%d %s", d.ASN, d.Organization)
+	case d.ASN != 0:
+		return fmt.Sprintf("AS%d", d.ASN)
+	}
+	return d.Organization
+}
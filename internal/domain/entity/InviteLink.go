@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"time"
+
+	"go-telegram-bot/internal/domain/types"
+)
+
+// InviteLink is an invite link created through the bot, with the number of users who joined with it
+type InviteLink struct {
+	BaseEntityWithInt
+	TelegramChatID types.TelegramChatID `json:"telegram_chat_id" gorm:"type:bigint;not null;index"`
+	Link           string               `json:"link" gorm:"type:varchar(255);not null;uniqueIndex"`
+	Name           string               `json:"name" gorm:"type:varchar(32);not null;default:''"`
+	CreatedBy      types.TelegramUserID `json:"created_by" gorm:"type:bigint;not null"`
+	ExpiresAt      *time.Time           `json:"expires_at,omitempty" gorm:"type:timestamp;default:null"`
+	MemberLimit    *int                 `json:"member_limit,omitempty" gorm:"type:integer;default:null"`
+	JoinCount      int                  `json:"join_count" gorm:"type:integer;not null;default:0"`
+	RevokedAt      *time.Time           `json:"revoked_at,omitempty" gorm:"type:timestamp;default:null"`
+}
+
+func NewInviteLink(chatID types.TelegramChatID, createdBy types.TelegramUserID, link *types.TelegramChatInviteLink) *InviteLink {
+	inviteLink := &InviteLink{
+		TelegramChatID: chatID,
+		Link:           link.InviteLink,
+		CreatedBy:      createdBy,
+		MemberLimit:    link.MemberLimit,
+	}
+	if link.Name != nil {
+		inviteLink.Name = *link.Name
+	}
+	if link.ExpireDate != nil {
+		expiresAt := time.Unix(*link.ExpireDate, 0)
+		inviteLink.ExpiresAt = &expiresAt
+	}
+	return inviteLink
+}
+
+// IsActive reports whether users may still join with the link
+func (l *InviteLink) IsActive(now time.Time) bool {
+	switch {
+	case l.RevokedAt != nil:
+		return false
+	case l.ExpiresAt != nil && !now.Before(*l.ExpiresAt):
+		return false
+	case l.MemberLimit != nil && l.JoinCount >= *l.MemberLimit:
+		return false
+	default:
+		return true
+	}
+}
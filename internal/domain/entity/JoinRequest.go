@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"time"
+
+	"go-telegram-bot/internal/domain/types"
+)
+
+// JoinRequest is the last request of a user to join a chat and the state of its handling
+type JoinRequest struct {
+	BaseEntityWithUUID
+	TelegramChatID types.TelegramChatID    `json:"telegram_chat_id" gorm:"type:bigint;not null;uniqueIndex:idx_join_request_chat_user"`
+	TelegramUserID types.TelegramUserID    `json:"telegram_user_id" gorm:"type:bigint;not null;uniqueIndex:idx_join_request_chat_user;index:idx_join_request_user_status"`
+	UserChatID     types.TelegramChatID    `json:"user_chat_id" gorm:"type:bigint;not null"`
+	ChatTitle      string                  `json:"chat_title" gorm:"type:varchar(255);not null;default:''"`
+	Policy         types.JoinRequestPolicy `json:"policy" gorm:"type:varchar(16);not null"`
+	Status         types.JoinRequestStatus `json:"status" gorm:"type:varchar(16);not null;index:idx_join_request_user_status"`
+	Bio            *string                 `json:"bio,omitempty" gorm:"type:text;default:null"`
+	InviteLink     *string                 `json:"invite_link,omitempty" gorm:"type:varchar(255);default:null"`
+	ExpiresAt      *time.Time              `json:"expires_at,omitempty" gorm:"type:timestamp;default:null"` // deadline of the answer to the question
+	DeclineTries   int                     `json:"decline_tries" gorm:"type:int;not null;default:0"`        // failed declines once expired
+}
+
+func NewJoinRequest(request *types.TelegramChatJoinRequest, policy types.JoinRequestPolicy) *JoinRequest {
+	joinRequest := &JoinRequest{
+		TelegramChatID: request.Chat.ID,
+		TelegramUserID: request.From.ID,
+		UserChatID:     request.UserChatID,
+		Policy:         policy,
+		Status:         types.JoinRequestStatusPending,
+		Bio:            request.Bio,
+	}
+	if request.Chat.Title != nil {
+		joinRequest.ChatTitle = *request.Chat.Title
+	}
+	if link := request.InviteLink; link != nil {
+		name := link.InviteLink
+		if link.Name != nil {
+			name = *link.Name
+		}
+		joinRequest.InviteLink = &name
+	}
+	return joinRequest
+}
+
+// IsExpired reports whether the question was not answered in time
+func (r *JoinRequest) IsExpired(now time.Time) bool {
+	return r.ExpiresAt != nil && now.After(*r.ExpiresAt)
+}
+
+// JoinRequestDecision is the audit record of an approved or declined join request
+type JoinRequestDecision struct {
+	BaseEntityWithInt
+	TelegramChatID types.TelegramChatID    `json:"telegram_chat_id" gorm:"type:bigint;not null;index"`
+	TelegramUserID types.TelegramUserID    `json:"telegram_user_id" gorm:"type:bigint;not null;index"`
+	Policy         types.JoinRequestPolicy `json:"policy" gorm:"type:varchar(16);not null"`
+	Decision       types.JoinRequestStatus `json:"decision" gorm:"type:varchar(16);not null"`
+	DecidedBy      types.TelegramUserID    `json:"decided_by" gorm:"type:bigint;not null;default:0"` // 0 when decided by the policy
+	Reason         string                  `json:"reason" gorm:"type:varchar(255);not null;default:''"`
+}
@@ -0,0 +1,31 @@
+package entity
+
+import "go-telegram-bot/internal/domain/types"
+
+// GlobalChatID is the chat of role assignments that apply in every chat
+const GlobalChatID types.TelegramChatID = 0
+
+// RoleAssignment grants a role to a user in one chat, or in every chat when TelegramChatID is GlobalChatID
+type RoleAssignment struct {
+	BaseEntityWithUUID
+	TelegramUserID types.TelegramUserID `json:"telegram_user_id" gorm:"type:bigint;not null;uniqueIndex:idx_role_assignment_user_chat"`
+	TelegramChatID types.TelegramChatID `json:"telegram_chat_id" gorm:"type:bigint;not null;default:0;uniqueIndex:idx_role_assignment_user_chat"`
+	Role           types.Role           `json:"role" gorm:"type:varchar(16);not null"`
+	GrantedBy      types.TelegramUserID `json:"granted_by" gorm:"type:bigint;not null"`
+}
+
+func NewRoleAssignment(
+	userID types.TelegramUserID, chatID types.TelegramChatID, role types.Role, grantedBy types.TelegramUserID,
+) *RoleAssignment {
+	return &RoleAssignment{
+		TelegramUserID: userID,
+		TelegramChatID: chatID,
+		Role:           role,
+		GrantedBy:      grantedBy,
+	}
+}
+
+// IsGlobal reports whether the assignment applies in every chat
+func (a *RoleAssignment) IsGlobal() bool {
+	return a.TelegramChatID == GlobalChatID
+}
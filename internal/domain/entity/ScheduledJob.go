@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"time"
+
+	"go-telegram-bot/internal/domain/types"
+)
+
+// ScheduledJob runs a bot command or sends a reminder in a chat on behalf of its creator, on every
+// match of its cron expression or once when the expression is empty. NextRunAt is cleared once a
+// one-shot job ran, snoozing a reminder sets it again.
+type ScheduledJob struct {
+	BaseEntityWithInt
+	Kind             types.ScheduleKind   `json:"kind" gorm:"type:varchar(16);not null;default:'command';index"`
+	TelegramChatID   types.TelegramChatID `json:"telegram_chat_id" gorm:"type:bigint;not null;index"`
+	ChatType         types.ChatType       `json:"chat_type" gorm:"type:varchar(32);not null"`
+	CreatedBy        types.TelegramUserID `json:"created_by" gorm:"type:bigint;not null;index"`
+	Text             string               `json:"text" gorm:"type:varchar(512);not null"`
+	ReplyToMessageID *int64               `json:"reply_to_message_id,omitempty" gorm:"type:bigint;default:null"`
+	Cron             string               `json:"cron" gorm:"type:varchar(128);not null;default:''"`
+	Timezone         string               `json:"timezone" gorm:"type:varchar(64);not null"`
+	NextRunAt        *time.Time           `json:"next_run_at,omitempty" gorm:"type:timestamp;default:null;index"`
+	LastRunAt        *time.Time           `json:"last_run_at,omitempty" gorm:"type:timestamp;default:null"`
+	LastError        string               `json:"last_error" gorm:"type:varchar(255);not null;default:''"`
+	RunCount         int                  `json:"run_count" gorm:"type:integer;not null;default:0"`
+}
+
+// NewReminder creates a one-shot reminder of text for the user, replying to messageID when it is set
+func NewReminder(
+	user types.TelegramUserID,
+	chatID types.TelegramChatID,
+	chatType types.ChatType,
+	messageID int64,
+	text string,
+	at time.Time,
+	timezone string,
+) *ScheduledJob {
+	job := &ScheduledJob{
+		Kind:           types.ScheduleKindReminder,
+		TelegramChatID: chatID,
+		ChatType:       chatType,
+		CreatedBy:      user,
+		Text:           text,
+		Timezone:       timezone,
+		NextRunAt:      &at,
+	}
+	if messageID != 0 {
+		job.ReplyToMessageID = &messageID
+	}
+	return job
+}
+
+// IsRecurring reports whether the job runs on a cron expression rather than once
+func (j *ScheduledJob) IsRecurring() bool {
+	return j.Cron != ""
+}
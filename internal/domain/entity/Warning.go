@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"go-telegram-bot/internal/domain/types"
+)
+
+// Warning is one /warn given to a user in a chat, the active warnings of a user are counted
+// toward the automatic ban and removed once it happens or when the user is unbanned
+type Warning struct {
+	BaseEntityWithInt
+	TelegramChatID types.TelegramChatID `json:"telegram_chat_id" gorm:"type:bigint;not null;index:idx_warning_chat_user"`
+	TelegramUserID types.TelegramUserID `json:"telegram_user_id" gorm:"type:bigint;not null;index:idx_warning_chat_user"`
+	IssuedBy       types.TelegramUserID `json:"issued_by" gorm:"type:bigint;not null"`
+	Reason         string               `json:"reason" gorm:"type:varchar(255);not null;default:''"`
+}
+
+func NewWarning(chatID types.TelegramChatID, userID, issuedBy types.TelegramUserID, reason string) *Warning {
+	return &Warning{
+		TelegramChatID: chatID,
+		TelegramUserID: userID,
+		IssuedBy:       issuedBy,
+		Reason:         reason,
+	}
+}
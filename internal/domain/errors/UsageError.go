@@ -0,0 +1,25 @@
+package errors
+
+// UsageError reports a command used with wrong arguments together with its correct usage.
+// It matches ErrInvalidInput with errors.Is.
+type UsageError struct {
+	Reason string // what was wrong, may be empty
+	Usage  string // the accepted syntax, one form per line
+}
+
+// NewUsageError creates a UsageError
+func NewUsageError(reason, usage string) *UsageError {
+	return &UsageError{Reason: reason, Usage: usage}
+}
+
+func (e *UsageError) Error() string {
+	if e.Reason == "" {
+		return ErrInvalidInput.Error()
+	}
+	return ErrInvalidInput.Error() + ": " + e.Reason
+}
+
+// Is makes the error match ErrInvalidInput
+func (e *UsageError) Is(target error) bool {
+	return target == ErrInvalidInput
+}
@@ -21,14 +21,16 @@ var (
 	ErrInvalidTimezone      = errors.New("invalid timezone")
 
 	// Chat errors
-	ErrChatNotFound      = errors.New("chat not found")
-	ErrChatAlreadyExists = errors.New("chat already exists")
-	ErrInvalidChatData   = errors.New("invalid chat data")
+	ErrChatNotFound       = errors.New("chat not found")
+	ErrChatAlreadyExists  = errors.New("chat already exists")
+	ErrInvalidChatData    = errors.New("invalid chat data")
+	ErrChatMemberNotFound = errors.New("chat member not found")
 
 	// Message errors
 	ErrMessageNotFound       = errors.New("message not found")
 	ErrInvalidMessageData    = errors.New("invalid message data")
 	ErrMessageAlreadyDeleted = errors.New("message already deleted")
+	ErrTooManyMessages       = errors.New("too many messages")
 
 	// Session errors
 	ErrSessionNotFound    = errors.New("session not found")
@@ -41,8 +43,28 @@ var (
 	ErrInvalidCallbackData  = errors.New("invalid callback data")
 	ErrFlowValidationFailed = errors.New("flow validation failed")
 
+	// Join request errors
+	ErrJoinRequestNotFound = errors.New("join request not found")
+
+	// Invite link errors
+	ErrInviteLinkNotFound = errors.New("invite link not found")
+
+	// Broadcast errors
+	ErrBroadcastNotFound = errors.New("broadcast not found")
+
+	// Schedule errors
+	ErrScheduleNotFound = errors.New("schedule not found")
+	ErrTooManySchedules = errors.New("too many schedules")
+
+	// Authorization errors
+	ErrRoleAssignmentNotFound = errors.New("role assignment not found")
+	ErrInvalidRole            = errors.New("invalid role")
+
 	// General errors
 	ErrInvalidInput     = errors.New("invalid input")
 	ErrPermissionDenied = errors.New("permission denied")
 	ErrInternalError    = errors.New("internal error")
+
+	// Upstream errors
+	ErrServiceUnavailable = errors.New("upstream service unavailable")
 )
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-telegram-bot/internal/domain/entity"
+	"go-telegram-bot/internal/domain/types"
+)
+
+type BroadcastRepository interface {
+	Create(ctx context.Context, job *entity.BroadcastJob) error
+	Get(ctx context.Context, id int64) (*entity.BroadcastJob, error)
+	// Latest returns the most recently created job
+	Latest(ctx context.Context) (*entity.BroadcastJob, error)
+	// CountTargets returns the number of active chats matching the filter
+	CountTargets(ctx context.Context, filter entity.BroadcastFilter) (int, error)
+	// Transition moves the job to status when it is in one of from, it reports false otherwise
+	Transition(ctx context.Context, id int64, from []types.BroadcastStatus, to types.BroadcastStatus) (bool, error)
+
+	// ClaimDue leases the oldest scheduled or running job due at now whose lease expired and marks
+	// it running, it returns nil when there is none. Concurrent workers never claim the same job.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*entity.BroadcastJob, error)
+	// Plan creates a pending delivery to every active chat matching the filter of the job and sets its
+	// total, it does nothing once the job is planned
+	Plan(ctx context.Context, job *entity.BroadcastJob, at time.Time) error
+	// PendingDeliveries returns up to limit deliveries of the job not attempted yet, in planning order
+	PendingDeliveries(ctx context.Context, jobID int64, limit int) ([]*entity.BroadcastDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *entity.BroadcastDelivery) error
+	// Checkpoint recounts the deliveries into the counters of the job, extends its lease and returns it
+	Checkpoint(ctx context.Context, jobID int64, lockedUntil time.Time) (*entity.BroadcastJob, error)
+	// Finish completes the running job and releases its lease
+	Finish(ctx context.Context, jobID int64, at time.Time) error
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"go-telegram-bot/internal/domain/entity"
+	"go-telegram-bot/internal/domain/types"
+)
+
+type ChatMemberRepository interface {
+	Get(ctx context.Context, chatID types.TelegramChatID, userID types.TelegramUserID) (*entity.ChatMember, error)
+	// Upsert creates the member or replaces the status of the existing one for the same chat and user
+	Upsert(ctx context.Context, member *entity.ChatMember) error
+}
@@ -17,4 +17,19 @@ type ChatRepository interface {
 	Update(ctx context.Context, chat *entity.Chat) error
 	Delete(ctx context.Context, telegramChatID types.TelegramChatID) error
 	GetActiveChats(ctx context.Context, limit int) ([]*entity.Chat, error)
+	// DeactivateChat marks the chat as unreachable for the bot
+	DeactivateChat(ctx context.Context, telegramChatID types.TelegramChatID) error
+	// MigrateChatID moves the chat and every record referencing its Telegram ID to the new ID,
+	// used when Telegram upgrades a group to a supergroup
+	MigrateChatID(ctx context.Context, from, to types.TelegramChatID) error
+
+	// AddTag labels the chat, adding a tag it already has does nothing
+	AddTag(ctx context.Context, telegramChatID types.TelegramChatID, tag string) error
+	// RemoveTag removes the label, it reports false when the chat did not have it
+	RemoveTag(ctx context.Context, telegramChatID types.TelegramChatID, tag string) (bool, error)
+	// GetTags returns the labels of the chat in alphabetical order
+	GetTags(ctx context.Context, telegramChatID types.TelegramChatID) ([]string, error)
+
+	// SetTimezone stores the IANA time zone of the chat, creating the chat when it is not stored yet
+	SetTimezone(ctx context.Context, telegramChatID types.TelegramChatID, chatType types.ChatType, timezone string) error
 }
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// FloodEventRepository stores the events counted by the anti-flood sliding windows.
+// Events older than the window of their key may be discarded by any call.
+type FloodEventRepository interface {
+	// Count returns the events of key within (now-window, now]
+	Count(ctx context.Context, key string, now time.Time, window time.Duration) (int, error)
+	// Take records an event of key at now unless limit events already happened within window,
+	// a zero limit always records. It returns the events within window including the recorded one.
+	Take(ctx context.Context, key string, now time.Time, window time.Duration, limit int) (count int, taken bool, err error)
+}
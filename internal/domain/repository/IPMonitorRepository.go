@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+
+	"go-telegram-bot/internal/domain/entity"
+	"go-telegram-bot/internal/domain/types"
+)
+
+type IPMonitorRepository interface {
+	// LatestChange returns the current WAN address, nil when none was recorded yet
+	LatestChange(ctx context.Context) (*entity.IPChange, error)
+	// RecordChange stores the change, it reports false when another instance recorded a change
+	// following the same one first
+	RecordChange(ctx context.Context, change *entity.IPChange) (bool, error)
+	// ListChanges returns the last changes, most recent first
+	ListChanges(ctx context.Context, limit int) ([]*entity.IPChange, error)
+
+	// Subscribe adds the chat to those notified of changes, it reports false when it already was
+	Subscribe(ctx context.Context, subscription *entity.IPSubscription) (bool, error)
+	// Unsubscribe removes the chat, it reports false when it was not subscribed
+	Unsubscribe(ctx context.Context, chatID types.TelegramChatID) (bool, error)
+	ListSubscriptions(ctx context.Context) ([]*entity.IPSubscription, error)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-telegram-bot/internal/domain/entity"
+	"go-telegram-bot/internal/domain/types"
+)
+
+type InviteLinkRepository interface {
+	Create(ctx context.Context, link *entity.InviteLink) error
+	Get(ctx context.Context, chatID types.TelegramChatID, id int64) (*entity.InviteLink, error)
+	// ListByChat returns the links of the chat, newest first
+	ListByChat(ctx context.Context, chatID types.TelegramChatID) ([]*entity.InviteLink, error)
+	MarkRevoked(ctx context.Context, id int64, at time.Time) error
+	// RecordJoin counts a user joining with the link, it reports false when the link is not stored
+	RecordJoin(ctx context.Context, link string) (bool, error)
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-telegram-bot/internal/domain/entity"
+	"go-telegram-bot/internal/domain/types"
+)
+
+type JoinRequestRepository interface {
+	// Upsert creates the request or resets the existing one of the same user for the same chat
+	Upsert(ctx context.Context, request *entity.JoinRequest) error
+	Get(ctx context.Context, chatID types.TelegramChatID, userID types.TelegramUserID) (*entity.JoinRequest, error)
+	// OldestPendingQuestion returns the oldest request of the user still waiting for an answer
+	OldestPendingQuestion(ctx context.Context, userID types.TelegramUserID) (*entity.JoinRequest, error)
+	// ExpiredPendingQuestions returns the requests still waiting for an answer after their answer
+	// timeout, the ones with the fewest failed declines first, then the longest expired
+	ExpiredPendingQuestions(ctx context.Context, now time.Time, limit int) ([]*entity.JoinRequest, error)
+	// CountDeclineTry records a failed decline of the expired request
+	CountDeclineTry(ctx context.Context, request *entity.JoinRequest) error
+	// Decide stores the final status of the request together with its audit record
+	Decide(ctx context.Context, request *entity.JoinRequest, decision *entity.JoinRequestDecision) error
+}
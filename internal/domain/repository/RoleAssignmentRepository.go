@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"go-telegram-bot/internal/domain/entity"
+	"go-telegram-bot/internal/domain/types"
+)
+
+type RoleAssignmentRepository interface {
+	// ListForUser returns the user's assignments in the chat and the global ones
+	ListForUser(ctx context.Context, userID types.TelegramUserID, chatID types.TelegramChatID) ([]*entity.RoleAssignment, error)
+	Get(ctx context.Context, userID types.TelegramUserID, chatID types.TelegramChatID) (*entity.RoleAssignment, error)
+	// Upsert creates the assignment or replaces the role of the existing one for the same user and chat
+	Upsert(ctx context.Context, assignment *entity.RoleAssignment) error
+	Delete(ctx context.Context, userID types.TelegramUserID, chatID types.TelegramChatID) error
+	ListByChat(ctx context.Context, chatID types.TelegramChatID) ([]*entity.RoleAssignment, error)
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-telegram-bot/internal/domain/entity"
+	"go-telegram-bot/internal/domain/types"
+)
+
+type ScheduleRepository interface {
+	Create(ctx context.Context, job *entity.ScheduledJob) error
+	Get(ctx context.Context, id int64) (*entity.ScheduledJob, error)
+	// ListByChat returns the jobs of the kind in the chat still due to run, soonest first
+	ListByChat(ctx context.Context, chatID types.TelegramChatID, kind types.ScheduleKind) ([]*entity.ScheduledJob, error)
+	// ListByCreator returns the jobs of the kind created by the user still due to run, soonest first
+	ListByCreator(ctx context.Context, user types.TelegramUserID, kind types.ScheduleKind) ([]*entity.ScheduledJob, error)
+	CountByChat(ctx context.Context, chatID types.TelegramChatID, kind types.ScheduleKind) (int, error)
+	// Delete removes the job of the chat, it reports false when the chat has no such job
+	Delete(ctx context.Context, chatID types.TelegramChatID, id int64) (bool, error)
+	// Reschedule sets the next run of the job, it reports false when there is no such job
+	Reschedule(ctx context.Context, id int64, at time.Time) (bool, error)
+
+	// ClaimDue locks the oldest job due at now, stores the run and the next run returned by next,
+	// nil ending a one-shot job, then returns the job. It returns nil when no job is due.
+	// The lock and the update share a transaction, so each run is claimed by a single instance.
+	ClaimDue(ctx context.Context, now time.Time, next func(job *entity.ScheduledJob) *time.Time) (*entity.ScheduledJob, error)
+	// RecordResult stores the error of the last run, empty when it succeeded
+	RecordResult(ctx context.Context, id int64, lastError string) error
+}
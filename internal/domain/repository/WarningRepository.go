@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"go-telegram-bot/internal/domain/entity"
+	"go-telegram-bot/internal/domain/types"
+)
+
+type WarningRepository interface {
+	// Add stores the warning and returns the number of active warnings of the user in the chat
+	Add(ctx context.Context, warning *entity.Warning) (int, error)
+	Count(ctx context.Context, chatID types.TelegramChatID, userID types.TelegramUserID) (int, error)
+	// Clear removes the active warnings of the user in the chat
+	Clear(ctx context.Context, chatID types.TelegramChatID, userID types.TelegramUserID) error
+}
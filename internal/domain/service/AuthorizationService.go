@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+
+	"go-telegram-bot/internal/domain/types"
+)
+
+// AuthorizationService resolves user roles and enforces the role required by commands
+type AuthorizationService interface {
+	// RoleOf returns the effective role of the user in the chat: the highest of the configured
+	// owner role, the global assignment and the chat assignment, RoleGuest when none applies
+	RoleOf(ctx context.Context, userID types.TelegramUserID, chatID types.TelegramChatID) (types.Role, error)
+
+	// Authorize returns errors.ErrPermissionDenied when the user's role is below required
+	Authorize(ctx context.Context, userID types.TelegramUserID, chatID types.TelegramChatID, required types.Role) error
+
+	// Grant assigns role to the user, the granter must outrank both the role and the user's current assignment
+	Grant(ctx context.Context, granter, userID types.TelegramUserID, chatID types.TelegramChatID, role types.Role) error
+
+	// Revoke removes the user's assignment, the granter must outrank the assigned role
+	Revoke(ctx context.Context, granter, userID types.TelegramUserID, chatID types.TelegramChatID) error
+}
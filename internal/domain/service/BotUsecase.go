@@ -23,6 +23,9 @@ type BotUseCase interface {
 	// ProcessUpdate processes incoming Telegram updates
 	ProcessUpdate(ctx context.Context, update types.TelegramUpdate) error
 
+	// CommandRunner runs the commands of scheduled jobs
+	CommandRunner
+
 	// ValidateUpdate validates the structure and content of an update
 	ValidateUpdate(update types.TelegramUpdate) error
 }
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go-telegram-bot/internal/domain/entity"
+	"go-telegram-bot/internal/domain/types"
+)
+
+// BroadcastService announces a message to every active chat matching a filter. Jobs are drafted,
+// confirmed from a preview, then delivered in the background with their progress stored.
+type BroadcastService interface {
+	// Prepare stores a draft job starting at startAt, or now when zero, and posts its preview with the
+	// confirm and cancel buttons to reportChatID. Only global admins may broadcast.
+	Prepare(ctx context.Context, creator types.TelegramUserID, reportChatID types.TelegramChatID, text string, filter entity.BroadcastFilter, startAt time.Time) (*entity.BroadcastJob, error)
+
+	// HandleCallback confirms or cancels the draft of a pressed preview button
+	HandleCallback(ctx context.Context, query *types.TelegramCallbackQuery) error
+
+	// Status returns the job with its progress, the latest job when id is zero
+	Status(ctx context.Context, user types.TelegramUserID, id int64) (*entity.BroadcastJob, error)
+
+	// Cancel stops a job which did not finish yet, errors.ErrBroadcastNotFound when there is no such job
+	Cancel(ctx context.Context, user types.TelegramUserID, id int64) (*entity.BroadcastJob, error)
+
+	// TagChat and UntagChat label the chat for the tag filter of broadcasts, ChatTags lists its labels
+	TagChat(ctx context.Context, chatID types.TelegramChatID, tag string) error
+	UntagChat(ctx context.Context, chatID types.TelegramChatID, tag string) (bool, error)
+	ChatTags(ctx context.Context, chatID types.TelegramChatID) ([]string, error)
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+
+	"go-telegram-bot/internal/domain/entity"
+)
+
+// DDNSProvider points DNS records at the WAN address
+type DDNSProvider interface {
+	// Name identifies the provider in logs, metrics and reports
+	Name() string
+
+	// Update points every record of the provider at ip, A records for IPv4 and AAAA for IPv6
+	Update(ctx context.Context, ip string) error
+}
+
+// DDNSService keeps the records of every configured provider pointed at the WAN address, it observes
+// the lookups of the IP monitor
+type DDNSService interface {
+	IPObserver
+
+	// Status returns the outcome of the last update of every provider
+	Status(ctx context.Context) []entity.DDNSStatus
+}
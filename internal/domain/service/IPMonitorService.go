@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+
+	"go-telegram-bot/internal/domain/entity"
+	"go-telegram-bot/internal/domain/types"
+)
+
+// IPMonitorService watches the WAN address in the background, records its changes and tells the
+// subscribed chats about them
+type IPMonitorService interface {
+	// Subscribe makes the monitor notify the chat of changes, it reports false when it already did
+	Subscribe(ctx context.Context, user types.TelegramUserID, chatID types.TelegramChatID, chatType types.ChatType) (bool, error)
+
+	// Unsubscribe stops the notifications to the chat, it reports false when it was not subscribed
+	Unsubscribe(ctx context.Context, chatID types.TelegramChatID) (bool, error)
+
+	// History returns the last recorded addresses, most recent first
+	History(ctx context.Context, limit int) ([]*entity.IPChange, error)
+}
+
+// IPObserver is told the WAN address after every successful lookup of the IP monitor, whether or not
+// it changed, so it can retry what failed with the same address
+type IPObserver interface {
+	ObserveIP(ctx context.Context, ip string)
+}
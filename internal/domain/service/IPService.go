@@ -7,15 +7,28 @@ import (
 
 // IPService defines the interface for IP-related operations
 type IPService interface {
-	// GetLocalIP retrieves the local IP address of the machine
+	// GetLocalIP retrieves the local IP address of the machine, the one of the interface with the default route
 	GetLocalIP(ctx context.Context) (string, error)
 
-	// GetPublicIP retrieves the public/WAN IP address of the machine
+	// GetInterfaces lists the network interfaces of the machine with their addresses
+	GetInterfaces(ctx context.Context) ([]entity.NetworkInterface, error)
+
+	// GetPublicIP retrieves the public/WAN IP address of the machine, always of the same family (IPv4)
 	GetPublicIP(ctx context.Context) (string, error)
 
-	// GetIPInfo retrieves both local and public IP information using existing entity
+	// GetPublicIPv4 retrieves the public IPv4 address, connecting over IPv4 only
+	GetPublicIPv4(ctx context.Context) (string, error)
+
+	// GetPublicIPv6 retrieves the public IPv6 address, connecting over IPv6 only
+	GetPublicIPv6(ctx context.Context) (string, error)
+
+	// GetIPInfo retrieves both local and public IP information using existing entity, with the details
+	// of the public address when they can be looked up
 	GetIPInfo(ctx context.Context) (*entity.IPInfo, error)
 
+	// GetIPDetails looks up the location, network and reverse DNS name of a public address
+	GetIPDetails(ctx context.Context, ip string) (*entity.IPDetails, error)
+
 	// ValidateIP validates if the provided string is a valid IP address
 	ValidateIP(ip string) bool
 
@@ -25,3 +38,12 @@ type IPService interface {
 	// IsPublicIP checks if the provided IP is a public IP address
 	IsPublicIP(ip string) bool
 }
+
+// IPGeoProvider looks up the location and network of a public address in a geolocation database
+type IPGeoProvider interface {
+	// Name identifies the provider in logs
+	Name() string
+
+	// Lookup returns what the database knows about ip
+	Lookup(ctx context.Context, ip string) (*entity.IPDetails, error)
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go-telegram-bot/internal/domain/entity"
+	"go-telegram-bot/internal/domain/types"
+)
+
+// InviteLinkOptions are the settings of a new invite link, zero values leave the setting out
+type InviteLinkOptions struct {
+	Name        string
+	ExpiresIn   time.Duration
+	MemberLimit int
+}
+
+// InviteLinkService creates and revokes the invite links of a chat and counts who joins with them
+type InviteLinkService interface {
+	// Create creates the link with Telegram and stores it
+	Create(ctx context.Context, creator types.TelegramUserID, chatID types.TelegramChatID, options InviteLinkOptions) (*entity.InviteLink, error)
+
+	// List returns the stored links of the chat, newest first
+	List(ctx context.Context, chatID types.TelegramChatID) ([]*entity.InviteLink, error)
+
+	// Revoke revokes the stored link with Telegram, errors.ErrInviteLinkNotFound when the chat has no such link
+	Revoke(ctx context.Context, chatID types.TelegramChatID, id int64) (*entity.InviteLink, error)
+
+	// RecordJoin attributes a member joining with an invite link to the stored link
+	RecordJoin(ctx context.Context, update *types.TelegramChatMemberUpdated) error
+}
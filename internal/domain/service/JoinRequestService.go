@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+
+	"go-telegram-bot/internal/domain/types"
+)
+
+// JoinRequestService applies the join request policy of each chat and records every decision
+type JoinRequestService interface {
+	// HandleJoinRequest approves the request, asks the question or posts it for review
+	HandleJoinRequest(ctx context.Context, request *types.TelegramChatJoinRequest) error
+
+	// HandleAnswer decides the oldest request of the sender waiting for an answer,
+	// it reports false when the private message is not an answer
+	HandleAnswer(ctx context.Context, message *types.TelegramMessage) (bool, error)
+
+	// HandleCallback applies the decision of an admin pressing a review button
+	HandleCallback(ctx context.Context, query *types.TelegramCallbackQuery) error
+}
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+
+	"go-telegram-bot/internal/domain/types"
+)
+
+// MembershipService keeps the stored chats and members in sync with the membership updates
+type MembershipService interface {
+	// HandleMyChatMember records the bot being added to, removed from, promoted or demoted in a chat
+	HandleMyChatMember(ctx context.Context, update *types.TelegramChatMemberUpdated) error
+
+	// HandleChatMember records the status change of a member, sent to administrator bots only
+	HandleChatMember(ctx context.Context, update *types.TelegramChatMemberUpdated) error
+
+	// HandleServiceMessage applies the service messages about new, left members and title changes
+	HandleServiceMessage(ctx context.Context, message *types.TelegramMessage) error
+}
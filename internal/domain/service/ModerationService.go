@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go-telegram-bot/internal/domain/types"
+)
+
+// WarnOutcome is the state of the user after a warning
+type WarnOutcome struct {
+	Count       int           // active warnings, including this one
+	Limit       int           // warnings leading to a ban, 0 when warnings never ban
+	Banned      bool          // the warning reached the limit and the user was banned
+	BanDuration time.Duration // length of the ban, 0 when forever
+}
+
+// ModerationService applies the moderation commands of chat admins.
+// The moderator must outrank the user they moderate.
+type ModerationService interface {
+	// Ban removes the user from the chat for duration, forever when zero
+	Ban(ctx context.Context, moderator, userID types.TelegramUserID, chatID types.TelegramChatID, duration time.Duration) error
+
+	// Unban lets a banned user join again and clears their warnings
+	Unban(ctx context.Context, moderator, userID types.TelegramUserID, chatID types.TelegramChatID) error
+
+	// Mute forbids the user to send messages for duration, forever when zero
+	Mute(ctx context.Context, moderator, userID types.TelegramUserID, chatID types.TelegramChatID, duration time.Duration) error
+
+	// Warn records a warning and bans the user once their warnings reach the limit
+	Warn(ctx context.Context, moderator, userID types.TelegramUserID, chatID types.TelegramChatID, reason string) (*WarnOutcome, error)
+
+	// Purge deletes the messages of the chat from fromID to toID included, returning how many IDs were covered
+	Purge(ctx context.Context, chatID types.TelegramChatID, fromID, toID int64) (int, error)
+}
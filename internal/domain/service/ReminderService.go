@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go-telegram-bot/internal/domain/entity"
+	"go-telegram-bot/internal/domain/types"
+)
+
+// ReminderService reminds users of a text at a time, in reply to the message which asked for it.
+// Reminders are scheduled jobs, they survive restarts and are delivered by a single instance.
+type ReminderService interface {
+	// Remind stores a reminder of text for the user at the given time, delivered in the chat in reply
+	// to messageID, and confirms it with a cancel button. errors.ErrInvalidInput for a time in the past,
+	// errors.ErrTooManySchedules when the user reached their limit.
+	Remind(ctx context.Context, user types.TelegramUserID, chatID types.TelegramChatID, chatType types.ChatType, messageID int64, text string, at time.Time) (*entity.ScheduledJob, error)
+
+	// UserLocation returns the time zone of the user, the one of their private chat with the bot
+	UserLocation(ctx context.Context, user types.TelegramUserID) (*time.Location, error)
+
+	// ListReminders posts to the chat the reminders of the user still to be delivered with a cancel
+	// button each, all of them in the private chat and only those of the chat in a group
+	ListReminders(ctx context.Context, user types.TelegramUserID, chatID types.TelegramChatID) error
+
+	// HandleCallback snoozes or cancels the reminder of a pressed button, only for its creator
+	HandleCallback(ctx context.Context, query *types.TelegramCallbackQuery) error
+}
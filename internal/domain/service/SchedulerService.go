@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go-telegram-bot/internal/domain/entity"
+	"go-telegram-bot/internal/domain/types"
+)
+
+// ScheduleSpec is when a job runs: on every match of Cron, or once at At when Cron is empty
+type ScheduleSpec struct {
+	Cron     string
+	At       time.Time
+	Timezone string // IANA name, empty uses the time zone of the chat
+}
+
+// SchedulerService runs bot commands in a chat on a timer, in the time zone of the chat.
+// Jobs are stored so they survive restarts, each run is claimed by a single instance.
+type SchedulerService interface {
+	// Schedule stores a job running commandText in the chat on behalf of creator.
+	// errors.ErrInvalidInput for an expression which never matches or a time in the past,
+	// errors.ErrTooManySchedules when the chat reached its limit.
+	Schedule(ctx context.Context, creator types.TelegramUserID, chatID types.TelegramChatID, chatType types.ChatType, commandText string, spec ScheduleSpec) (*entity.ScheduledJob, error)
+
+	// List returns the jobs of the chat which still have a run ahead, soonest first
+	List(ctx context.Context, chatID types.TelegramChatID) ([]*entity.ScheduledJob, error)
+
+	// Unschedule removes the job, errors.ErrScheduleNotFound when the chat has no such job
+	Unschedule(ctx context.Context, chatID types.TelegramChatID, id int64) error
+
+	// SetTimezone changes the time zone of the chat, of the user when the chat is private with them.
+	// Only chat admins may change the time zone of a group.
+	SetTimezone(ctx context.Context, user types.TelegramUserID, chatID types.TelegramChatID, chatType types.ChatType, timezone string) (*time.Location, error)
+
+	// Location returns the time zone of the chat, the configured default when it has none
+	Location(ctx context.Context, chatID types.TelegramChatID) (*time.Location, error)
+}
+
+// CommandRunner runs a command text in a chat as if the user had sent it
+type CommandRunner interface {
+	RunCommand(ctx context.Context, chatID types.TelegramChatID, chatType types.ChatType, userID types.TelegramUserID, text string) error
+}
@@ -6,6 +6,31 @@ import (
 	"go-telegram-bot/internal/domain/types"
 )
 
+// SendPriority orders outbound sends competing for the same rate limit capacity
+type SendPriority int
+
+const (
+	// SendPriorityInteractive is used for replies to users and always goes first
+	SendPriorityInteractive SendPriority = iota
+	// SendPriorityBroadcast is used for bulk sends that may wait behind interactive replies
+	SendPriorityBroadcast
+)
+
+type sendPriorityKey struct{}
+
+// WithSendPriority returns a context carrying the priority used by the client rate limiter
+func WithSendPriority(ctx context.Context, priority SendPriority) context.Context {
+	return context.WithValue(ctx, sendPriorityKey{}, priority)
+}
+
+// SendPriorityFromContext returns the send priority stored in ctx, interactive by default
+func SendPriorityFromContext(ctx context.Context) SendPriority {
+	if priority, ok := ctx.Value(sendPriorityKey{}).(SendPriority); ok {
+		return priority
+	}
+	return SendPriorityInteractive
+}
+
 // TelegramBotService defines the interface for Telegram bot operations
 type TelegramBotService interface {
 	// Enhanced methods with better response handling
@@ -14,7 +39,7 @@ type TelegramBotService interface {
 	GetMeWithResponse(ctx context.Context) (*types.GetMeResponse, error)
 	DeleteWebhookWithResponse(ctx context.Context) (*types.DeleteWebhookResponse, error)
 
-	// Batch operations
+	// Batch operations, a failed message leaves a nil response and its error joined into the returned one
 	SendMessages(ctx context.Context, requests []*types.SendMessageRequest) ([]*types.SendMessageResponse, error)
 
 	// Advanced operations with retry logic
@@ -32,6 +57,7 @@ type TelegramBotService interface {
 	// Message management
 	EditMessageText(ctx context.Context, request *types.EditMessageTextRequest) (*types.EditMessageTextResponse, error)
 	DeleteMessage(ctx context.Context, chatID types.TelegramChatID, messageID int64) (*types.DeleteMessageResponse, error)
+	DeleteMessages(ctx context.Context, request *types.DeleteMessagesRequest) (*types.DeleteMessagesResponse, error)
 	ForwardMessage(ctx context.Context, request *types.ForwardMessageRequest) (*types.ForwardMessageResponse, error)
 
 	// Media sending
@@ -42,8 +68,17 @@ type TelegramBotService interface {
 	GetChat(ctx context.Context, chatID types.TelegramChatID) (*types.GetChatResponse, error)
 	BanChatMember(ctx context.Context, request *types.BanChatMemberRequest) (*types.BanChatMemberResponse, error)
 	UnbanChatMember(ctx context.Context, request *types.UnbanChatMemberRequest) (*types.UnbanChatMemberResponse, error)
+	RestrictChatMember(ctx context.Context, request *types.RestrictChatMemberRequest) (*types.RestrictChatMemberResponse, error)
+	PromoteChatMember(ctx context.Context, request *types.PromoteChatMemberRequest) (*types.PromoteChatMemberResponse, error)
+	SetChatAdministratorCustomTitle(ctx context.Context, request *types.SetChatAdministratorCustomTitleRequest) (*types.SetChatAdministratorCustomTitleResponse, error)
 	GetChatMember(ctx context.Context, chatID types.TelegramChatID, userID types.TelegramUserID) (*types.GetChatMemberResponse, error)
 	GetChatMembersCount(ctx context.Context, chatID types.TelegramChatID) (*types.GetChatMembersCountResponse, error)
+	CreateChatInviteLink(ctx context.Context, request *types.CreateChatInviteLinkRequest) (*types.CreateChatInviteLinkResponse, error)
+	EditChatInviteLink(ctx context.Context, request *types.EditChatInviteLinkRequest) (*types.EditChatInviteLinkResponse, error)
+	RevokeChatInviteLink(ctx context.Context, request *types.RevokeChatInviteLinkRequest) (*types.RevokeChatInviteLinkResponse, error)
+	ExportChatInviteLink(ctx context.Context, chatID types.TelegramChatID) (*types.ExportChatInviteLinkResponse, error)
+	ApproveChatJoinRequest(ctx context.Context, request *types.ApproveChatJoinRequestRequest) (*types.ApproveChatJoinRequestResponse, error)
+	DeclineChatJoinRequest(ctx context.Context, request *types.DeclineChatJoinRequestRequest) (*types.DeclineChatJoinRequestResponse, error)
 
 	// Callback and inline query handling
 	AnswerCallbackQuery(ctx context.Context, request *types.AnswerCallbackQueryRequest) (*types.AnswerCallbackQueryResponse, error)
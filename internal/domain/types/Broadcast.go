@@ -0,0 +1,31 @@
+package types
+
+// BroadcastStatus is the state of a broadcast job
+type BroadcastStatus string
+
+const (
+	// BroadcastStatusDraft waits for its creator to confirm the preview
+	BroadcastStatusDraft BroadcastStatus = "draft"
+	// BroadcastStatusScheduled is confirmed and starts once its scheduled time is reached
+	BroadcastStatusScheduled BroadcastStatus = "scheduled"
+	// BroadcastStatusRunning is being delivered, it resumes from the pending deliveries after a restart
+	BroadcastStatusRunning   BroadcastStatus = "running"
+	BroadcastStatusCompleted BroadcastStatus = "completed"
+	BroadcastStatusCancelled BroadcastStatus = "cancelled"
+)
+
+// IsFinal reports whether the job will not send anything anymore
+func (s BroadcastStatus) IsFinal() bool {
+	return s == BroadcastStatusCompleted || s == BroadcastStatusCancelled
+}
+
+// DeliveryStatus is the state of a broadcast to one chat
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending DeliveryStatus = "pending"
+	DeliveryStatusSent    DeliveryStatus = "sent"
+	DeliveryStatusFailed  DeliveryStatus = "failed"
+	// DeliveryStatusBlocked means the bot cannot write to the chat anymore, the chat was deactivated
+	DeliveryStatusBlocked DeliveryStatus = "blocked"
+)
@@ -0,0 +1,28 @@
+package types
+
+// ChatMemberStatus is the status of a user in a chat, as reported by Telegram
+type ChatMemberStatus string
+
+const (
+	ChatMemberStatusCreator       ChatMemberStatus = "creator"
+	ChatMemberStatusAdministrator ChatMemberStatus = "administrator"
+	ChatMemberStatusMember        ChatMemberStatus = "member"
+	ChatMemberStatusRestricted    ChatMemberStatus = "restricted"
+	ChatMemberStatusLeft          ChatMemberStatus = "left"
+	ChatMemberStatusKicked        ChatMemberStatus = "kicked"
+)
+
+// IsPresent reports whether the user is in the chat
+func (s ChatMemberStatus) IsPresent() bool {
+	switch s {
+	case ChatMemberStatusCreator, ChatMemberStatusAdministrator, ChatMemberStatusMember, ChatMemberStatusRestricted:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsAdmin reports whether the user administers the chat
+func (s ChatMemberStatus) IsAdmin() bool {
+	return s == ChatMemberStatusCreator || s == ChatMemberStatusAdministrator
+}
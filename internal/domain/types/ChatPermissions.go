@@ -0,0 +1,42 @@
+package types
+
+import "time"
+
+// MaxRestrictionDuration is the longest temporary ban or mute, Telegram treats an until_date
+// further ahead as forever
+const MaxRestrictionDuration = 366 * 24 * time.Hour
+
+// ChatPermissions are the actions allowed to a restricted member, unset fields keep the chat default
+type ChatPermissions struct {
+	CanSendMessages       *bool `json:"can_send_messages,omitempty"`
+	CanSendAudios         *bool `json:"can_send_audios,omitempty"`
+	CanSendDocuments      *bool `json:"can_send_documents,omitempty"`
+	CanSendPhotos         *bool `json:"can_send_photos,omitempty"`
+	CanSendVideos         *bool `json:"can_send_videos,omitempty"`
+	CanSendVideoNotes     *bool `json:"can_send_video_notes,omitempty"`
+	CanSendVoiceNotes     *bool `json:"can_send_voice_notes,omitempty"`
+	CanSendPolls          *bool `json:"can_send_polls,omitempty"`
+	CanSendOtherMessages  *bool `json:"can_send_other_messages,omitempty"`
+	CanAddWebPagePreviews *bool `json:"can_add_web_page_previews,omitempty"`
+	CanChangeInfo         *bool `json:"can_change_info,omitempty"`
+	CanInviteUsers        *bool `json:"can_invite_users,omitempty"`
+	CanPinMessages        *bool `json:"can_pin_messages,omitempty"`
+	CanManageTopics       *bool `json:"can_manage_topics,omitempty"`
+}
+
+// MutedPermissions forbids sending anything, the other permissions keep the chat default
+func MutedPermissions() ChatPermissions {
+	no := false
+	return ChatPermissions{
+		CanSendMessages:       &no,
+		CanSendAudios:         &no,
+		CanSendDocuments:      &no,
+		CanSendPhotos:         &no,
+		CanSendVideos:         &no,
+		CanSendVideoNotes:     &no,
+		CanSendVoiceNotes:     &no,
+		CanSendPolls:          &no,
+		CanSendOtherMessages:  &no,
+		CanAddWebPagePreviews: &no,
+	}
+}
@@ -6,12 +6,72 @@ const (
 	CommandStart     Command = "/start"
 	CommandHelp      Command = "/help"
 	CommandGetHomeIP Command = "/home_ip"
+	CommandGrant     Command = "/grant"
+	CommandRevoke    Command = "/revoke"
+	CommandWhoAmI    Command = "/whoami"
+	CommandBan       Command = "/ban"
+	CommandUnban     Command = "/unban"
+	CommandMute      Command = "/mute"
+	CommandWarn      Command = "/warn"
+	CommandPurge     Command = "/purge"
+
+	CommandInviteCreate Command = "/invite_create"
+	CommandInviteList   Command = "/invite_list"
+	CommandInviteRevoke Command = "/invite_revoke"
+
+	CommandBroadcast       Command = "/broadcast"
+	CommandBroadcastStatus Command = "/broadcast_status"
+	CommandBroadcastCancel Command = "/broadcast_cancel"
+	CommandChatTag         Command = "/chat_tag"
+
+	CommandSchedule   Command = "/schedule"
+	CommandSchedules  Command = "/schedules"
+	CommandUnschedule Command = "/unschedule"
+	CommandTimezone   Command = "/timezone"
+
+	CommandRemind    Command = "/remind"
+	CommandReminders Command = "/reminders"
+
+	CommandIPSubscribe   Command = "/ip_subscribe"
+	CommandIPUnsubscribe Command = "/ip_unsubscribe"
+	CommandIPHistory     Command = "/ip_history"
+	CommandDDNSStatus    Command = "/ddns_status"
 )
 
 var validCommands = map[Command]struct{}{
 	CommandStart:     {},
 	CommandHelp:      {},
 	CommandGetHomeIP: {},
+	CommandGrant:     {},
+	CommandRevoke:    {},
+	CommandWhoAmI:    {},
+	CommandBan:       {},
+	CommandUnban:     {},
+	CommandMute:      {},
+	CommandWarn:      {},
+	CommandPurge:     {},
+
+	CommandInviteCreate: {},
+	CommandInviteList:   {},
+	CommandInviteRevoke: {},
+
+	CommandBroadcast:       {},
+	CommandBroadcastStatus: {},
+	CommandBroadcastCancel: {},
+	CommandChatTag:         {},
+
+	CommandSchedule:   {},
+	CommandSchedules:  {},
+	CommandUnschedule: {},
+	CommandTimezone:   {},
+
+	CommandRemind:    {},
+	CommandReminders: {},
+
+	CommandIPSubscribe:   {},
+	CommandIPUnsubscribe: {},
+	CommandIPHistory:     {},
+	CommandDDNSStatus:    {},
 }
 
 func (c Command) IsValid() bool {
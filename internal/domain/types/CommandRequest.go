@@ -0,0 +1,74 @@
+package types
+
+import "strings"
+
+// CommandRequest is a bot command parsed from a message, with the context needed to authorize and answer it
+type CommandRequest struct {
+	Command   Command
+	Mention   string // bot username after "@" in "/cmd@bot", empty when the command was not addressed
+	Args      []string
+	ChatID    TelegramChatID
+	ChatType  ChatType
+	UserID    TelegramUserID
+	MessageID int64
+	Message   *TelegramMessage
+}
+
+// ParseCommand splits a message text such as "/grant@my_bot 42 admin" into the command,
+// the mention and the arguments. It returns an empty command when text is not a command.
+func ParseCommand(text string) (command Command, mention string, args []string) {
+	if !strings.HasPrefix(text, "/") {
+		return "", "", nil
+	}
+
+	parts := strings.Fields(text)
+	if len(parts) == 0 {
+		return "", "", nil
+	}
+
+	name, mention, _ := strings.Cut(parts[0], "@")
+	if name == "/" {
+		return "", "", nil
+	}
+
+	return Command(strings.ToLower(name)), mention, parts[1:]
+}
+
+// NewCommandRequest parses the command of a message, returning nil when the message carries none
+func NewCommandRequest(message *TelegramMessage) *CommandRequest {
+	if message == nil || message.Text == nil || message.Chat == nil {
+		return nil
+	}
+
+	command, mention, args := ParseCommand(*message.Text)
+	if command == "" {
+		return nil
+	}
+
+	request := &CommandRequest{
+		Command:   command,
+		Mention:   mention,
+		Args:      args,
+		ChatID:    message.Chat.ID,
+		ChatType:  message.Chat.Type,
+		MessageID: message.MessageID,
+		Message:   message,
+	}
+	if message.From != nil {
+		request.UserID = message.From.ID
+	}
+	return request
+}
+
+// IsPrivate reports whether the command was sent in a private chat with the bot
+func (r *CommandRequest) IsPrivate() bool {
+	return r.ChatType == ChatTypePrivate
+}
+
+// ReplyTarget returns the sender of the message the command replies to, or nil
+func (r *CommandRequest) ReplyTarget() *TelegramUser {
+	if r.Message == nil || r.Message.ReplyToMessage == nil {
+		return nil
+	}
+	return r.Message.ReplyToMessage.From
+}
@@ -0,0 +1,28 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		text    string
+		command Command
+		mention string
+		args    []string
+	}{
+		{"/start", "/start", "", []string{}},
+		{"/Grant@my_bot 42  admin", "/grant", "my_bot", []string{"42", "admin"}},
+		{"hello /start", "", "", nil},
+		{"/", "", "", nil},
+		{"/@bot", "", "", nil},
+	}
+
+	for _, test := range tests {
+		command, mention, args := ParseCommand(test.text)
+		if command != test.command || mention != test.mention || !reflect.DeepEqual(args, test.args) {
+			t.Errorf("ParseCommand(%q) = %q, %q, %v", test.text, command, mention, args)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package types
+
+// InlineKeyboardMarkup is a keyboard shown below a message, sent as ReplyMarkup
+type InlineKeyboardMarkup struct {
+	InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard"`
+}
+
+// InlineKeyboardButton is a button of an inline keyboard, exactly one of the optional fields is set
+type InlineKeyboardButton struct {
+	Text         string  `json:"text"`
+	CallbackData *string `json:"callback_data,omitempty"` // up to 64 bytes, sent back in a callback query
+	URL          *string `json:"url,omitempty"`
+}
+
+// NewCallbackButton creates a button sending data back in a callback query when pressed
+func NewCallbackButton(text, data string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, CallbackData: &data}
+}
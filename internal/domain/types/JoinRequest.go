@@ -0,0 +1,22 @@
+package types
+
+// JoinRequestPolicy decides how the join requests of a chat are handled
+type JoinRequestPolicy string
+
+const (
+	// JoinRequestPolicyApprove approves every request
+	JoinRequestPolicyApprove JoinRequestPolicy = "approve"
+	// JoinRequestPolicyQuestion asks a question in a private chat and approves a correct answer
+	JoinRequestPolicyQuestion JoinRequestPolicy = "question"
+	// JoinRequestPolicyReview posts the request with approve and decline buttons to an admin chat
+	JoinRequestPolicyReview JoinRequestPolicy = "review"
+)
+
+// JoinRequestStatus is the state of a join request
+type JoinRequestStatus string
+
+const (
+	JoinRequestStatusPending  JoinRequestStatus = "pending"
+	JoinRequestStatusApproved JoinRequestStatus = "approved"
+	JoinRequestStatusDeclined JoinRequestStatus = "declined"
+)
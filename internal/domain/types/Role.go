@@ -0,0 +1,45 @@
+package types
+
+// Role is the access level of a user, either in one chat or in every chat
+type Role string
+
+const (
+	RoleGuest  Role = "guest"
+	RoleMember Role = "member"
+	RoleAdmin  Role = "admin"
+	RoleOwner  Role = "owner"
+)
+
+// roleRanks orders the roles, a higher rank includes the permissions of every lower one
+var roleRanks = map[Role]int{
+	RoleGuest:  0,
+	RoleMember: 1,
+	RoleAdmin:  2,
+	RoleOwner:  3,
+}
+
+func (r Role) IsValid() bool {
+	_, ok := roleRanks[r]
+	return ok
+}
+
+// AtLeast reports whether r grants every permission of required
+func (r Role) AtLeast(required Role) bool {
+	return roleRanks[r] >= roleRanks[required]
+}
+
+// Outranks reports whether r is strictly higher than other
+func (r Role) Outranks(other Role) bool {
+	return roleRanks[r] > roleRanks[other]
+}
+
+// MaxRole returns the highest of the given roles, RoleGuest when none is given
+func MaxRole(roles ...Role) Role {
+	highest := RoleGuest
+	for _, role := range roles {
+		if role.Outranks(highest) {
+			highest = role
+		}
+	}
+	return highest
+}
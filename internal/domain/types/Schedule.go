@@ -0,0 +1,11 @@
+package types
+
+// ScheduleKind is what a scheduled job does when it runs
+type ScheduleKind string
+
+const (
+	// ScheduleKindCommand runs a bot command on behalf of its creator, see /schedule
+	ScheduleKindCommand ScheduleKind = "command"
+	// ScheduleKindReminder sends its text back to its creator, see /remind
+	ScheduleKindReminder ScheduleKind = "reminder"
+)
@@ -2,13 +2,14 @@ package types
 
 // Request structures for enhanced methods
 type SendMessageRequest struct {
-	ChatID                TelegramChatID `json:"chat_id"`
-	Text                  string         `json:"text"`
-	ParseMode             *ParseMode     `json:"parse_mode,omitempty"`
-	DisableWebPagePreview *bool          `json:"disable_web_page_preview,omitempty"`
-	DisableNotification   *bool          `json:"disable_notification,omitempty"`
-	ReplyToMessageID      *int64         `json:"reply_to_message_id,omitempty"`
-	ReplyMarkup           any            `json:"reply_markup,omitempty"`
+	ChatID                   TelegramChatID `json:"chat_id"`
+	Text                     string         `json:"text"`
+	ParseMode                *ParseMode     `json:"parse_mode,omitempty"`
+	DisableWebPagePreview    *bool          `json:"disable_web_page_preview,omitempty"`
+	DisableNotification      *bool          `json:"disable_notification,omitempty"`
+	ReplyToMessageID         *int64         `json:"reply_to_message_id,omitempty"`
+	AllowSendingWithoutReply *bool          `json:"allow_sending_without_reply,omitempty"`
+	ReplyMarkup              any            `json:"reply_markup,omitempty"`
 }
 
 type GetUpdatesRequest struct {
@@ -80,6 +81,76 @@ type UnbanChatMemberRequest struct {
 	OnlyIfBanned *bool          `json:"only_if_banned,omitempty"`
 }
 
+type RestrictChatMemberRequest struct {
+	ChatID                        TelegramChatID  `json:"chat_id"`
+	UserID                        TelegramUserID  `json:"user_id"`
+	Permissions                   ChatPermissions `json:"permissions"`
+	UseIndependentChatPermissions *bool           `json:"use_independent_chat_permissions,omitempty"`
+	UntilDate                     *int64          `json:"until_date,omitempty"`
+}
+
+type PromoteChatMemberRequest struct {
+	ChatID              TelegramChatID `json:"chat_id"`
+	UserID              TelegramUserID `json:"user_id"`
+	IsAnonymous         *bool          `json:"is_anonymous,omitempty"`
+	CanManageChat       *bool          `json:"can_manage_chat,omitempty"`
+	CanDeleteMessages   *bool          `json:"can_delete_messages,omitempty"`
+	CanManageVideoChats *bool          `json:"can_manage_video_chats,omitempty"`
+	CanRestrictMembers  *bool          `json:"can_restrict_members,omitempty"`
+	CanPromoteMembers   *bool          `json:"can_promote_members,omitempty"`
+	CanChangeInfo       *bool          `json:"can_change_info,omitempty"`
+	CanInviteUsers      *bool          `json:"can_invite_users,omitempty"`
+	CanPinMessages      *bool          `json:"can_pin_messages,omitempty"`
+	CanManageTopics     *bool          `json:"can_manage_topics,omitempty"`
+}
+
+type SetChatAdministratorCustomTitleRequest struct {
+	ChatID      TelegramChatID `json:"chat_id"`
+	UserID      TelegramUserID `json:"user_id"`
+	CustomTitle string         `json:"custom_title"`
+}
+
+// DeleteMessagesRequest deletes up to MaxDeleteMessages messages of a chat, missing ones are skipped
+type DeleteMessagesRequest struct {
+	ChatID     TelegramChatID `json:"chat_id"`
+	MessageIDs []int64        `json:"message_ids"`
+}
+
+// MaxDeleteMessages is the most messages one deleteMessages call accepts
+const MaxDeleteMessages = 100
+
+type CreateChatInviteLinkRequest struct {
+	ChatID             TelegramChatID `json:"chat_id"`
+	Name               *string        `json:"name,omitempty"` // up to 32 characters
+	ExpireDate         *int64         `json:"expire_date,omitempty"`
+	MemberLimit        *int           `json:"member_limit,omitempty"` // 1 to 99999
+	CreatesJoinRequest *bool          `json:"creates_join_request,omitempty"`
+}
+
+type EditChatInviteLinkRequest struct {
+	ChatID             TelegramChatID `json:"chat_id"`
+	InviteLink         string         `json:"invite_link"`
+	Name               *string        `json:"name,omitempty"`
+	ExpireDate         *int64         `json:"expire_date,omitempty"`
+	MemberLimit        *int           `json:"member_limit,omitempty"`
+	CreatesJoinRequest *bool          `json:"creates_join_request,omitempty"`
+}
+
+type RevokeChatInviteLinkRequest struct {
+	ChatID     TelegramChatID `json:"chat_id"`
+	InviteLink string         `json:"invite_link"`
+}
+
+type ApproveChatJoinRequestRequest struct {
+	ChatID TelegramChatID `json:"chat_id"`
+	UserID TelegramUserID `json:"user_id"`
+}
+
+type DeclineChatJoinRequestRequest struct {
+	ChatID TelegramChatID `json:"chat_id"`
+	UserID TelegramUserID `json:"user_id"`
+}
+
 type AnswerCallbackQueryRequest struct {
 	CallbackQueryID string  `json:"callback_query_id"`
 	Text            *string `json:"text,omitempty"`
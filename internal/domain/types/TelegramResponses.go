@@ -1,6 +1,8 @@
 package types
 
 import (
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -61,6 +63,9 @@ type (
 	// DeleteMessageResponse represents the response from deleteMessage API
 	DeleteMessageResponse = APIResponse[bool]
 
+	// DeleteMessagesResponse represents the response from deleteMessages API
+	DeleteMessagesResponse = APIResponse[bool]
+
 	// ForwardMessageResponse represents the response from forwardMessage API
 	ForwardMessageResponse = APIResponse[TelegramMessage]
 
@@ -79,12 +84,39 @@ type (
 	// UnbanChatMemberResponse represents the response from unbanChatMember API
 	UnbanChatMemberResponse = APIResponse[bool]
 
+	// RestrictChatMemberResponse represents the response from restrictChatMember API
+	RestrictChatMemberResponse = APIResponse[bool]
+
+	// PromoteChatMemberResponse represents the response from promoteChatMember API
+	PromoteChatMemberResponse = APIResponse[bool]
+
+	// SetChatAdministratorCustomTitleResponse represents the response from setChatAdministratorCustomTitle API
+	SetChatAdministratorCustomTitleResponse = APIResponse[bool]
+
 	// GetChatMemberResponse represents the response from getChatMember API
 	GetChatMemberResponse = APIResponse[TelegramChatMember]
 
 	// GetChatMembersCountResponse represents the response from getChatMembersCount API
 	GetChatMembersCountResponse = APIResponse[int]
 
+	// CreateChatInviteLinkResponse represents the response from createChatInviteLink API
+	CreateChatInviteLinkResponse = APIResponse[TelegramChatInviteLink]
+
+	// EditChatInviteLinkResponse represents the response from editChatInviteLink API
+	EditChatInviteLinkResponse = APIResponse[TelegramChatInviteLink]
+
+	// RevokeChatInviteLinkResponse represents the response from revokeChatInviteLink API
+	RevokeChatInviteLinkResponse = APIResponse[TelegramChatInviteLink]
+
+	// ExportChatInviteLinkResponse represents the response from exportChatInviteLink API
+	ExportChatInviteLinkResponse = APIResponse[string]
+
+	// ApproveChatJoinRequestResponse represents the response from approveChatJoinRequest API
+	ApproveChatJoinRequestResponse = APIResponse[bool]
+
+	// DeclineChatJoinRequestResponse represents the response from declineChatJoinRequest API
+	DeclineChatJoinRequestResponse = APIResponse[bool]
+
 	// AnswerCallbackQueryResponse represents the response from answerCallbackQuery API
 	AnswerCallbackQueryResponse = APIResponse[bool]
 
@@ -195,16 +227,45 @@ func (r *BaseResponse) IsRateLimited() bool {
 	return r.ErrorCode != nil && *r.ErrorCode == 429
 }
 
-// IsBotBlocked checks if the bot was blocked by user
+// IsBotBlocked checks if the bot can no longer write to the chat, because the user blocked it,
+// deleted their account or removed the bot from the group
 func (r *BaseResponse) IsBotBlocked() bool {
-	return r.ErrorCode != nil && *r.ErrorCode == 403 &&
-		r.Parameters != nil && r.Parameters.BotBlocked != nil && *r.Parameters.BotBlocked
+	if r.GetErrorCode() != 403 {
+		return false
+	}
+	if r.Parameters != nil && ((r.Parameters.BotBlocked != nil && *r.Parameters.BotBlocked) ||
+		(r.Parameters.BotKicked != nil && *r.Parameters.BotKicked)) {
+		return true
+	}
+	description := strings.ToLower(r.GetErrorDescription())
+	for _, reason := range botBlockedReasons {
+		if strings.Contains(description, reason) {
+			return true
+		}
+	}
+	return false
 }
 
+// botBlockedReasons are the fragments of the 403 descriptions meaning the chat is gone for the bot
+var botBlockedReasons = []string{"bot was blocked", "bot was kicked", "user is deactivated", "bot is not a member"}
+
 // IsChatNotFound checks if the chat was not found
 func (r *BaseResponse) IsChatNotFound() bool {
-	return r.ErrorCode != nil && *r.ErrorCode == 400 &&
-		r.Parameters != nil && r.Parameters.ChatNotFound != nil && *r.Parameters.ChatNotFound
+	if r.GetErrorCode() != 400 {
+		return false
+	}
+	if r.Parameters != nil && r.Parameters.ChatNotFound != nil && *r.Parameters.ChatNotFound {
+		return true
+	}
+	return strings.Contains(strings.ToLower(r.GetErrorDescription()), "chat not found")
+}
+
+// MigrateToChatID returns the new ID of a group that was upgraded to a supergroup
+func (r *BaseResponse) MigrateToChatID() (TelegramChatID, bool) {
+	if r.Parameters == nil || r.Parameters.MigrateToChatID == nil {
+		return 0, false
+	}
+	return TelegramChatID(*r.Parameters.MigrateToChatID), true
 }
 
 // IsNetworkError checks if it's a network-related error
@@ -326,6 +387,7 @@ type ResponseError struct {
 	Timestamp   time.Time      `json:"timestamp"`
 	Duration    time.Duration  `json:"duration"`
 	Retries     int            `json:"retries"`
+	Cause       error          `json:"-"`
 }
 
 // Error implements the error interface
@@ -333,11 +395,44 @@ func (e *ResponseError) Error() string {
 	if e.Response != nil && e.Response.HasError() {
 		return e.Response.GetErrorDescription()
 	}
+	if e.Cause != nil {
+		return fmt.Sprintf("%s: %v", e.Method, e.Cause)
+	}
+	if e.HTTPStatus != 0 {
+		return fmt.Sprintf("%s: unexpected HTTP status %d", e.Method, e.HTTPStatus)
+	}
 	return "Unknown API error"
 }
 
+// Unwrap returns the underlying transport error, if any
+func (e *ResponseError) Unwrap() error {
+	return e.Cause
+}
+
+// IsNetworkFailure reports whether the request never got an HTTP response
+func (e *ResponseError) IsNetworkFailure() bool {
+	return e.HTTPStatus == 0
+}
+
+// IsServerError reports whether Telegram answered with a 5xx status
+func (e *ResponseError) IsServerError() bool {
+	return e.HTTPStatus >= 500
+}
+
+// IsChatUnreachable reports whether the bot cannot write to the chat at all, for example
+// because the user blocked the bot, the bot was removed from the group or the chat does not exist
+func (e *ResponseError) IsChatUnreachable() bool {
+	if e.Response == nil {
+		return false
+	}
+	return e.Response.GetErrorCode() == 403 || e.Response.IsChatNotFound()
+}
+
 // ShouldRetry indicates if the request should be retried based on the response
 func (e *ResponseError) ShouldRetry() bool {
+	if e.IsNetworkFailure() || e.IsServerError() {
+		return true
+	}
 	if e.Response != nil {
 		return e.Response.ShouldRetry()
 	}
@@ -18,6 +18,112 @@ type TelegramUpdate struct {
 	ChatJoinRequest   *TelegramChatJoinRequest   `json:"chat_join_request,omitempty"`
 }
 
+// UpdateType names the kind of payload carried by an update, using Telegram's field names
+type UpdateType string
+
+const (
+	UpdateTypeMessage           UpdateType = "message"
+	UpdateTypeEditedMessage     UpdateType = "edited_message"
+	UpdateTypeChannelPost       UpdateType = "channel_post"
+	UpdateTypeEditedChannelPost UpdateType = "edited_channel_post"
+	UpdateTypeInlineQuery       UpdateType = "inline_query"
+	UpdateTypeCallbackQuery     UpdateType = "callback_query"
+	UpdateTypeShippingQuery     UpdateType = "shipping_query"
+	UpdateTypePreCheckoutQuery  UpdateType = "pre_checkout_query"
+	UpdateTypePoll              UpdateType = "poll"
+	UpdateTypePollAnswer        UpdateType = "poll_answer"
+	UpdateTypeMyChatMember      UpdateType = "my_chat_member"
+	UpdateTypeChatMember        UpdateType = "chat_member"
+	UpdateTypeChatJoinRequest   UpdateType = "chat_join_request"
+	UpdateTypeUnknown           UpdateType = "unknown"
+)
+
+// Type returns the kind of payload carried by the update
+func (u *TelegramUpdate) Type() UpdateType {
+	switch {
+	case u.Message != nil:
+		return UpdateTypeMessage
+	case u.EditedMessage != nil:
+		return UpdateTypeEditedMessage
+	case u.ChannelPost != nil:
+		return UpdateTypeChannelPost
+	case u.EditedChannelPost != nil:
+		return UpdateTypeEditedChannelPost
+	case u.InlineQuery != nil:
+		return UpdateTypeInlineQuery
+	case u.CallbackQuery != nil:
+		return UpdateTypeCallbackQuery
+	case u.ShippingQuery != nil:
+		return UpdateTypeShippingQuery
+	case u.PreCheckoutQuery != nil:
+		return UpdateTypePreCheckoutQuery
+	case u.Poll != nil:
+		return UpdateTypePoll
+	case u.PollAnswer != nil:
+		return UpdateTypePollAnswer
+	case u.MyChatMember != nil:
+		return UpdateTypeMyChatMember
+	case u.ChatMember != nil:
+		return UpdateTypeChatMember
+	case u.ChatJoinRequest != nil:
+		return UpdateTypeChatJoinRequest
+	default:
+		return UpdateTypeUnknown
+	}
+}
+
+// ChatID returns the chat the update belongs to, or 0 when the payload has no chat
+func (u *TelegramUpdate) ChatID() TelegramChatID {
+	var chat *TelegramChat
+	switch {
+	case u.Message != nil:
+		chat = u.Message.Chat
+	case u.EditedMessage != nil:
+		chat = u.EditedMessage.Chat
+	case u.ChannelPost != nil:
+		chat = u.ChannelPost.Chat
+	case u.EditedChannelPost != nil:
+		chat = u.EditedChannelPost.Chat
+	case u.CallbackQuery != nil && u.CallbackQuery.Message != nil:
+		chat = u.CallbackQuery.Message.Chat
+	case u.MyChatMember != nil:
+		chat = u.MyChatMember.Chat
+	case u.ChatMember != nil:
+		chat = u.ChatMember.Chat
+	case u.ChatJoinRequest != nil:
+		chat = u.ChatJoinRequest.Chat
+	}
+	if chat == nil {
+		return 0
+	}
+	return chat.ID
+}
+
+// From returns the user who caused the update, or nil when the payload has no sender
+func (u *TelegramUpdate) From() *TelegramUser {
+	switch {
+	case u.Message != nil:
+		return u.Message.From
+	case u.EditedMessage != nil:
+		return u.EditedMessage.From
+	case u.InlineQuery != nil:
+		return u.InlineQuery.From
+	case u.CallbackQuery != nil:
+		return u.CallbackQuery.From
+	case u.ShippingQuery != nil:
+		return u.ShippingQuery.From
+	case u.PreCheckoutQuery != nil:
+		return u.PreCheckoutQuery.From
+	case u.MyChatMember != nil:
+		return u.MyChatMember.From
+	case u.ChatMember != nil:
+		return u.ChatMember.From
+	case u.ChatJoinRequest != nil:
+		return u.ChatJoinRequest.From
+	}
+	return nil
+}
+
 type TelegramInlineQuery struct {
 	ID       string        `json:"id"`
 	From     *TelegramUser `json:"from"`
@@ -46,18 +152,36 @@ type TelegramChatMemberUpdated struct {
 }
 
 type TelegramChatMember struct {
-	User   *TelegramUser `json:"user"`
-	Status string        `json:"status"`
+	User   *TelegramUser    `json:"user"`
+	Status ChatMemberStatus `json:"status"`
+}
+
+// OldStatus returns the status of the member before the change
+func (u *TelegramChatMemberUpdated) OldStatus() ChatMemberStatus {
+	if u.OldChatMember == nil {
+		return ChatMemberStatusLeft
+	}
+	return u.OldChatMember.Status
+}
+
+// NewStatus returns the status of the member after the change
+func (u *TelegramChatMemberUpdated) NewStatus() ChatMemberStatus {
+	if u.NewChatMember == nil {
+		return ChatMemberStatusLeft
+	}
+	return u.NewChatMember.Status
 }
 
 type TelegramChatInviteLink struct {
-	InviteLink  string        `json:"invite_link"`
-	Creator     *TelegramUser `json:"creator"`
-	IsPrimary   bool          `json:"is_primary"`
-	IsRevoked   bool          `json:"is_revoked"`
-	Name        *string       `json:"name,omitempty"`
-	ExpireDate  *int64        `json:"expire_date,omitempty"`
-	MemberLimit *int          `json:"member_limit,omitempty"`
+	InviteLink              string        `json:"invite_link"`
+	Creator                 *TelegramUser `json:"creator"`
+	CreatesJoinRequest      bool          `json:"creates_join_request"`
+	IsPrimary               bool          `json:"is_primary"`
+	IsRevoked               bool          `json:"is_revoked"`
+	Name                    *string       `json:"name,omitempty"`
+	ExpireDate              *int64        `json:"expire_date,omitempty"`
+	MemberLimit             *int          `json:"member_limit,omitempty"`
+	PendingJoinRequestCount *int          `json:"pending_join_request_count,omitempty"`
 }
 
 type TelegramPollAnswer struct {
@@ -69,6 +193,7 @@ type TelegramPollAnswer struct {
 type TelegramChatJoinRequest struct {
 	Chat       *TelegramChat           `json:"chat"`
 	From       *TelegramUser           `json:"from"`
+	UserChatID TelegramChatID          `json:"user_chat_id"` // private chat with the user, open until the request is processed
 	Date       int64                   `json:"date"`
 	Bio        *string                 `json:"bio,omitempty"`
 	InviteLink *TelegramChatInviteLink `json:"invite_link,omitempty"`
@@ -153,6 +278,12 @@ type TelegramMessage struct {
 	ChannelChatCreated *bool                `json:"channel_chat_created,omitempty"`
 }
 
+// IsMembershipChange reports whether the message is a service message about the chat or its members
+func (m *TelegramMessage) IsMembershipChange() bool {
+	return len(m.NewChatMembers) > 0 || m.LeftChatMember != nil || m.NewChatTitle != nil ||
+		m.GroupChatCreated != nil || m.SupergroupCreated != nil
+}
+
 // TelegramUser represents a Telegram user or bot
 type TelegramUser struct {
 	ID           TelegramUserID `json:"id"`
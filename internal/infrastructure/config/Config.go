@@ -2,7 +2,10 @@ package config
 
 import (
 	"fmt"
+	"log/slog"
+	"net"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -12,55 +15,260 @@ import (
 const (
 	ConfigPath = "./configs/"
 	ConfigType = "yaml"
+
+	// DefaultAPIBaseURL is the Bot API endpoint prefix, the token is appended to it
+	DefaultAPIBaseURL = "https://api.telegram.org/bot"
 )
 
+// Config is the root of the configuration schema. Every leaf field carries:
+//
+//   - mapstructure: its snake_case key below the parent section
+//   - env: the environment variable overriding it, bound automatically
+//   - default: the value used when neither the file nor the environment sets it
+//   - desc: a one-line description for the generated reference in configs/REFERENCE.md
+//   - reload: "true" when a running bot applies changes without a restart, see Watcher
 type Config struct {
-	App      App          `mapstructure:"app"`
-	Logger   Logger       `mapstructure:"logger"`
-	Postgres Postgres     `mapstructure:"postgres"`
-	Client   ClientConfig `mapstructure:"client"`
+	App       App          `mapstructure:"app"`
+	Logger    Logger       `mapstructure:"logger"`
+	Postgres  Postgres     `mapstructure:"postgres"`
+	Client    ClientConfig `mapstructure:"client"`
+	Admin     Admin        `mapstructure:"admin"`
+	Tracing   Tracing      `mapstructure:"tracing"`
+	Auth      Auth         `mapstructure:"auth"`
+	AntiFlood AntiFlood    `mapstructure:"anti_flood"`
+	Alerts    Alerts       `mapstructure:"alerts"`
+	Welcome   Welcome      `mapstructure:"welcome"`
+
+	JoinRequests JoinRequests `mapstructure:"join_requests"`
+	Moderation   Moderation   `mapstructure:"moderation"`
+	Broadcast    Broadcast    `mapstructure:"broadcast"`
+	Scheduler    Scheduler    `mapstructure:"scheduler"`
+	IPMonitor    IPMonitor    `mapstructure:"ip_monitor"`
+	DDNS         DDNS         `mapstructure:"ddns"`
+	GeoIP        GeoIP        `mapstructure:"geoip"`
 }
 
 type App struct {
-	Environment string `mapstructure:"environment" env:"ENVIRONMENT"`
+	Environment string `mapstructure:"environment" env:"ENVIRONMENT" desc:"Deployment environment, also selects the config file"`
 }
 
 type Logger struct {
-	LogLevel   string `mapstructure:"log_level" env:"LOG_LEVEL"`
-	FilePath   string `mapstructure:"file_path"`
-	MaxSize    int    `mapstructure:"max_size"`
-	MaxBackups int    `mapstructure:"max_backups"`
-	MaxAge     int    `mapstructure:"max_age"`
-	Compress   bool   `mapstructure:"compress"`
+	LogLevel   string `mapstructure:"log_level" reload:"true" env:"LOG_LEVEL" default:"info" desc:"Minimum level: debug, info, warn, error, dpanic, panic or fatal"`
+	FilePath   string `mapstructure:"file_path" env:"LOG_FILE_PATH" default:"./logs/app.log" desc:"File the logs are written to in addition to stdout"`
+	MaxSize    int    `mapstructure:"max_size" env:"LOG_MAX_SIZE" default:"100" desc:"Size in megabytes before the log file is rotated"`
+	MaxBackups int    `mapstructure:"max_backups" env:"LOG_MAX_BACKUPS" default:"3" desc:"Rotated files to keep"`
+	MaxAge     int    `mapstructure:"max_age" env:"LOG_MAX_AGE" default:"28" desc:"Days to keep rotated files"`
+	Compress   bool   `mapstructure:"compress" env:"LOG_COMPRESS" default:"true" desc:"Gzip rotated files"`
+}
+
+// Admin holds settings of the operational HTTP server serving metrics and health endpoints
+type Admin struct {
+	Enabled bool   `mapstructure:"enabled" env:"ADMIN_ENABLED" default:"false" desc:"Serve /metrics, /healthz, /readyz and /debug/status"`
+	Host    string `mapstructure:"host" env:"ADMIN_HOST" default:"127.0.0.1" desc:"Address the admin server listens on"`
+	Port    int    `mapstructure:"port" env:"ADMIN_PORT" default:"9090" desc:"Port the admin server listens on"`
+
+	// MaxPollAge is how old the last successful getUpdates may be before /readyz fails
+	MaxPollAge time.Duration `mapstructure:"max_poll_age" env:"ADMIN_MAX_POLL_AGE" default:"60s" desc:"Age of the last successful poll after which /readyz fails"`
+}
+
+// Address returns the host:port the admin server listens on
+func (a Admin) Address() string {
+	return net.JoinHostPort(a.Host, strconv.Itoa(a.Port))
+}
+
+// Auth holds the access control settings that cannot live in the database
+type Auth struct {
+	Owners []int64 `mapstructure:"owners" env:"AUTH_OWNERS" reload:"true" desc:"Telegram user IDs with the owner role in every chat, comma separated in the env var"`
+}
+
+// AntiFlood limits how fast users and chats may send commands and how repeated violations escalate
+type AntiFlood struct {
+	Enabled bool   `mapstructure:"enabled" reload:"true" env:"ANTIFLOOD_ENABLED" default:"true" desc:"Drop commands exceeding the limits below"`
+	Store   string `mapstructure:"store" env:"ANTIFLOOD_STORE" default:"memory" desc:"Where counters live: memory, or postgres to share them between instances"`
+
+	// Sliding windows, a zero limit disables the check
+	UserLimit  int           `mapstructure:"user_limit" reload:"true" env:"ANTIFLOOD_USER_LIMIT" default:"10" desc:"Commands one user may send per user_window"`
+	UserWindow time.Duration `mapstructure:"user_window" reload:"true" env:"ANTIFLOOD_USER_WINDOW" default:"1m" desc:"Sliding window of user_limit"`
+	ChatLimit  int           `mapstructure:"chat_limit" reload:"true" env:"ANTIFLOOD_CHAT_LIMIT" default:"30" desc:"Commands one chat may send per chat_window"`
+	ChatWindow time.Duration `mapstructure:"chat_window" reload:"true" env:"ANTIFLOOD_CHAT_WINDOW" default:"1m" desc:"Sliding window of chat_limit"`
+
+	Cooldowns map[string]time.Duration `mapstructure:"cooldowns" reload:"true" desc:"Minimum delay between two uses of a command by one user, keyed by command without the slash"`
+
+	// Escalation: violations are dropped silently, the warn_after-th is answered once,
+	// from the mute_after-th on the user is ignored for mute_duration
+	WarnAfter       int           `mapstructure:"warn_after" reload:"true" env:"ANTIFLOOD_WARN_AFTER" default:"3" desc:"Violation that is answered with a warning, 0 never warns"`
+	MuteAfter       int           `mapstructure:"mute_after" reload:"true" env:"ANTIFLOOD_MUTE_AFTER" default:"6" desc:"Violation after which the user is ignored, 0 never mutes"`
+	ViolationWindow time.Duration `mapstructure:"violation_window" reload:"true" env:"ANTIFLOOD_VIOLATION_WINDOW" default:"10m" desc:"Window in which violations are counted"`
+	MuteDuration    time.Duration `mapstructure:"mute_duration" reload:"true" env:"ANTIFLOOD_MUTE_DURATION" default:"10m" desc:"How long a muted user is ignored"`
+}
+
+// Alerts selects where operational problems such as handler panics are reported
+type Alerts struct {
+	ChatID      int64         `mapstructure:"chat_id" reload:"true" env:"ALERTS_CHAT_ID" default:"0" desc:"Chat receiving error reports, 0 only logs them"`
+	DedupWindow time.Duration `mapstructure:"dedup_window" reload:"true" env:"ALERTS_DEDUP_WINDOW" default:"1h" desc:"A recurring error is reported at most once per window, with its occurrence count"`
+}
+
+// Welcome holds the messages sent when the bot joins a group and when a user joins it,
+// {name} is replaced with the first name of the user and {chat} with the chat title
+type Welcome struct {
+	GroupMessage  string `mapstructure:"group_message" reload:"true" env:"WELCOME_GROUP_MESSAGE" desc:"Sent when the bot is added to a group, empty disables it"`
+	MemberMessage string `mapstructure:"member_message" reload:"true" env:"WELCOME_MEMBER_MESSAGE" desc:"Sent when a user joins a group the bot is in, empty disables it"`
+}
+
+// JoinRequests selects how requests to join the chats administered by the bot are handled
+type JoinRequests struct {
+	Enabled       bool              `mapstructure:"enabled" reload:"true" env:"JOIN_REQUESTS_ENABLED" default:"false" desc:"Handle join requests, otherwise they are left to the chat admins"`
+	Policy        string            `mapstructure:"policy" reload:"true" env:"JOIN_REQUESTS_POLICY" default:"review" desc:"Policy of chats without an override: approve, question or review"`
+	ChatPolicies  map[string]string `mapstructure:"chat_policies" reload:"true" desc:"Policy by chat ID, overriding policy"`
+	Question      string            `mapstructure:"question" reload:"true" env:"JOIN_REQUESTS_QUESTION" desc:"Question asked in a private chat by the question policy"`
+	Answers       []string          `mapstructure:"answers" reload:"true" desc:"Accepted answers to the question, case-insensitive, empty accepts any answer"`
+	AnswerTimeout time.Duration     `mapstructure:"answer_timeout" reload:"true" env:"JOIN_REQUESTS_ANSWER_TIMEOUT" default:"10m" desc:"Requests left unanswered longer are declined"`
+	ReviewChatID  int64             `mapstructure:"review_chat_id" reload:"true" env:"JOIN_REQUESTS_REVIEW_CHAT_ID" default:"0" desc:"Chat where the review policy posts requests with approve and decline buttons"`
+}
+
+// Moderation configures the /warn escalation and the /purge command
+type Moderation struct {
+	WarnLimit       int           `mapstructure:"warn_limit" reload:"true" env:"MODERATION_WARN_LIMIT" default:"3" desc:"Warnings after which the user is banned automatically, 0 never bans"`
+	WarnBanDuration time.Duration `mapstructure:"warn_ban_duration" reload:"true" env:"MODERATION_WARN_BAN_DURATION" default:"0s" desc:"Length of the automatic ban, at most 366 days, 0 bans forever"`
+	PurgeLimit      int           `mapstructure:"purge_limit" reload:"true" env:"MODERATION_PURGE_LIMIT" default:"200" desc:"Most messages one /purge may delete, 0 removes the limit"`
+}
+
+// Broadcast configures the background delivery of /broadcast announcements, it needs a database
+type Broadcast struct {
+	Enabled      bool          `mapstructure:"enabled" env:"BROADCAST_ENABLED" default:"true" desc:"Run the worker delivering confirmed broadcasts"`
+	PollInterval time.Duration `mapstructure:"poll_interval" env:"BROADCAST_POLL_INTERVAL" default:"10s" desc:"How often the worker looks for due broadcasts"`
+	BatchSize    int           `mapstructure:"batch_size" env:"BROADCAST_BATCH_SIZE" default:"50" desc:"Deliveries sent between two progress checkpoints"`
+	Lease        time.Duration `mapstructure:"lease" env:"BROADCAST_LEASE" default:"5m" desc:"How long a worker owns a broadcast without a checkpoint before another one resumes it"`
+}
+
+// Scheduler configures the commands run on a timer with /schedule, it needs a database
+type Scheduler struct {
+	Enabled      bool          `mapstructure:"enabled" env:"SCHEDULER_ENABLED" default:"true" desc:"Run the worker executing scheduled commands"`
+	PollInterval time.Duration `mapstructure:"poll_interval" env:"SCHEDULER_POLL_INTERVAL" default:"15s" desc:"How often the worker looks for due commands, runs may be late by up to this"`
+	Timezone     string        `mapstructure:"timezone" env:"SCHEDULER_TIMEZONE" default:"Asia/Ho_Chi_Minh" desc:"IANA time zone of chats which did not set one with /timezone"`
+	MaxPerChat   int           `mapstructure:"max_per_chat" env:"SCHEDULER_MAX_PER_CHAT" default:"20" desc:"Most scheduled commands a chat may have, 0 removes the limit"`
+
+	MaxRemindersPerUser int `mapstructure:"max_reminders_per_user" env:"SCHEDULER_MAX_REMINDERS_PER_USER" default:"50" desc:"Most pending /remind reminders a user may have, 0 removes the limit"`
+}
+
+// IPMonitor polls the WAN address and notifies the chats subscribed with /ip_subscribe of its changes
+type IPMonitor struct {
+	Enabled  bool          `mapstructure:"enabled" env:"IP_MONITOR_ENABLED" default:"true" desc:"Poll the WAN address in the background and record its changes"`
+	Interval time.Duration `mapstructure:"interval" env:"IP_MONITOR_INTERVAL" default:"5m" desc:"How often the WAN address is looked up, a change may be noticed this late"`
+}
+
+// DDNS points DNS records at the WAN address whenever the IP monitor looks it up and it differs from
+// what was last written, every enabled provider is updated
+type DDNS struct {
+	ReportChatID int64         `mapstructure:"report_chat_id" env:"DDNS_REPORT_CHAT_ID" default:"0" desc:"Chat told about successful updates and failures, 0 only logs them"`
+	Timeout      time.Duration `mapstructure:"timeout" env:"DDNS_TIMEOUT" default:"30s" desc:"Longest time one provider may take to update its records"`
+
+	Cloudflare DDNSCloudflare `mapstructure:"cloudflare"`
+	RFC2136    DDNSRFC2136    `mapstructure:"rfc2136"`
+	HTTP       DDNSHTTP       `mapstructure:"http"`
+}
+
+// DDNSCloudflare updates records through the Cloudflare API v4
+type DDNSCloudflare struct {
+	Enabled  bool     `mapstructure:"enabled" env:"DDNS_CLOUDFLARE_ENABLED" default:"false" desc:"Update records hosted by Cloudflare"`
+	APIToken Secret   `mapstructure:"api_token" env:"DDNS_CLOUDFLARE_API_TOKEN" desc:"API token with the DNS edit permission on the zone"`
+	ZoneID   string   `mapstructure:"zone_id" env:"DDNS_CLOUDFLARE_ZONE_ID" desc:"ID of the zone holding the records"`
+	Records  []string `mapstructure:"records" env:"DDNS_CLOUDFLARE_RECORDS" desc:"Names of the records, e.g. home.example.com, comma separated in the env var"`
+	TTL      int      `mapstructure:"ttl" env:"DDNS_CLOUDFLARE_TTL" default:"1" desc:"TTL of created and updated records in seconds, 1 lets Cloudflare choose"`
+	BaseURL  string   `mapstructure:"base_url" env:"DDNS_CLOUDFLARE_BASE_URL" default:"https://api.cloudflare.com/client/v4" desc:"API endpoint"`
+}
+
+// DDNSRFC2136 updates records with DNS UPDATE messages, RFC 2136, signed with TSIG
+type DDNSRFC2136 struct {
+	Enabled      bool     `mapstructure:"enabled" env:"DDNS_RFC2136_ENABLED" default:"false" desc:"Update records on a DNS server accepting dynamic updates"`
+	Server       string   `mapstructure:"server" env:"DDNS_RFC2136_SERVER" desc:"host:port of the primary server of the zone"`
+	Network      string   `mapstructure:"network" env:"DDNS_RFC2136_NETWORK" default:"udp" desc:"Transport: udp or tcp"`
+	Zone         string   `mapstructure:"zone" env:"DDNS_RFC2136_ZONE" desc:"Zone holding the records, e.g. example.com"`
+	Records      []string `mapstructure:"records" env:"DDNS_RFC2136_RECORDS" desc:"Fully qualified names of the records, comma separated in the env var"`
+	TTL          int      `mapstructure:"ttl" env:"DDNS_RFC2136_TTL" default:"300" desc:"TTL of the records in seconds"`
+	KeyName      string   `mapstructure:"key_name" env:"DDNS_RFC2136_KEY_NAME" desc:"Name of the TSIG key, empty sends unsigned updates"`
+	KeyAlgorithm string   `mapstructure:"key_algorithm" env:"DDNS_RFC2136_KEY_ALGORITHM" default:"hmac-sha256" desc:"TSIG algorithm: hmac-sha1, hmac-sha256 or hmac-sha512"`
+	KeySecret    Secret   `mapstructure:"key_secret" env:"DDNS_RFC2136_KEY_SECRET" desc:"Base64 TSIG secret, as printed by tsig-keygen"`
+}
+
+// DDNSHTTP calls an update URL such as those of DuckDNS or No-IP, {ip} is replaced with the address
+type DDNSHTTP struct {
+	Enabled        bool   `mapstructure:"enabled" env:"DDNS_HTTP_ENABLED" default:"false" desc:"Call the update URL below"`
+	Method         string `mapstructure:"method" env:"DDNS_HTTP_METHOD" default:"GET" desc:"HTTP method: GET or POST"`
+	URL            Secret `mapstructure:"url" env:"DDNS_HTTP_URL" desc:"Update URL with {ip}, credentials may go in the query or as user:password@"`
+	Body           string `mapstructure:"body" env:"DDNS_HTTP_BODY" desc:"Request body with {ip}, empty sends none"`
+	ContentType    string `mapstructure:"content_type" env:"DDNS_HTTP_CONTENT_TYPE" default:"application/x-www-form-urlencoded" desc:"Content-Type of the body"`
+	SuccessPattern string `mapstructure:"success_pattern" env:"DDNS_HTTP_SUCCESS_PATTERN" desc:"Regular expression the answer must match, e.g. ^OK for DuckDNS or ^(good|nochg) for No-IP"`
+}
+
+// GeoIP selects where /home_ip looks up the location and network of the WAN address
+type GeoIP struct {
+	Provider   string        `mapstructure:"provider" env:"GEOIP_PROVIDER" default:"ipinfo" desc:"Lookup provider: none, ipinfo, ip-api or mmdb"`
+	URL        string        `mapstructure:"url" env:"GEOIP_URL" desc:"Lookup URL with {ip} for ipinfo and ip-api, empty uses their public endpoint"`
+	Token      Secret        `mapstructure:"token" env:"GEOIP_TOKEN" desc:"ipinfo access token, the lookups are rate limited without one"`
+	CityDB     string        `mapstructure:"city_db" env:"GEOIP_CITY_DB" desc:"Path of a GeoLite2-City or DB-IP City Lite .mmdb file, for mmdb"`
+	ASNDB      string        `mapstructure:"asn_db" env:"GEOIP_ASN_DB" desc:"Path of a GeoLite2-ASN or DB-IP ASN Lite .mmdb file, for mmdb"`
+	Timeout    time.Duration `mapstructure:"timeout" env:"GEOIP_TIMEOUT" default:"10s" desc:"Longest time a lookup, reverse DNS included, may take"`
+	CacheTTL   time.Duration `mapstructure:"cache_ttl" env:"GEOIP_CACHE_TTL" default:"24h" desc:"How long the details of an address are reused, 0 looks them up every time"`
+	ReverseDNS bool          `mapstructure:"reverse_dns" env:"GEOIP_REVERSE_DNS" default:"true" desc:"Look up the PTR name of the address"`
+}
+
+// Tracing selects where spans of the update pipeline are exported
+type Tracing struct {
+	Enabled     bool   `mapstructure:"enabled" env:"TRACING_ENABLED" default:"false" desc:"Export spans, trace IDs are added to logs either way"`
+	Exporter    string `mapstructure:"exporter" env:"TRACING_EXPORTER" default:"stdout" desc:"Span exporter: stdout or otlp"`
+	ServiceName string `mapstructure:"service_name" env:"TRACING_SERVICE_NAME" default:"go-telegram-bot" desc:"service.name resource attribute"`
+
+	// OTLP/HTTP settings, Headers usually carries the collector's API key
+	Endpoint string            `mapstructure:"endpoint" env:"TRACING_OTLP_ENDPOINT" desc:"OTLP/HTTP collector URL, /v1/traces is appended"`
+	Headers  map[string]Secret `mapstructure:"headers" desc:"Extra headers sent to the collector"`
+
+	FlushInterval time.Duration `mapstructure:"flush_interval" env:"TRACING_FLUSH_INTERVAL" default:"5s" desc:"How often batched spans are exported"`
 }
 
 type Postgres struct {
 	// Connection settings
-	Host     string `mapstructure:"host" env:"POSTGRES_HOST"`
-	Port     int    `mapstructure:"port" env:"POSTGRES_PORT"`
-	User     string `mapstructure:"user" env:"POSTGRES_USER"`
-	Password string `mapstructure:"password" env:"POSTGRES_PASSWORD"`
-	Name     string `mapstructure:"name" env:"POSTGRES_DB"`
-	SSLMode  string `mapstructure:"ssl_mode" env:"POSTGRES_SSLMODE"`
+	Host     string `mapstructure:"host" env:"POSTGRES_HOST" default:"localhost" desc:"Database host"`
+	Port     int    `mapstructure:"port" env:"POSTGRES_PORT" default:"5432" desc:"Database port"`
+	User     string `mapstructure:"user" env:"POSTGRES_USER" desc:"Database user"`
+	Password Secret `mapstructure:"password" env:"POSTGRES_PASSWORD" desc:"Database password"`
+	Name     string `mapstructure:"name" env:"POSTGRES_DB" desc:"Database name"`
+	SSLMode  string `mapstructure:"ssl_mode" env:"POSTGRES_SSLMODE" default:"disable" desc:"libpq sslmode"`
 
 	// Pool settings
-	MaxOpenConns    int           `mapstructure:"max_open_conns" env:"POSTGRES_MAX_OPEN_CONNS"`
-	MaxIdleConns    int           `mapstructure:"max_idle_conns" env:"POSTGRES_MAX_IDLE_CONNS"`
-	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime" env:"POSTGRES_CONN_MAX_LIFETIME"`
-	ConnMaxIdleTime time.Duration `mapstructure:"conn_max_idle_time" env:"POSTGRES_CONN_MAX_IDLE_TIME"`
+	MaxOpenConns    int           `mapstructure:"max_open_conns" env:"POSTGRES_MAX_OPEN_CONNS" default:"100" desc:"Maximum open connections"`
+	MaxIdleConns    int           `mapstructure:"max_idle_conns" env:"POSTGRES_MAX_IDLE_CONNS" default:"10" desc:"Maximum idle connections"`
+	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime" env:"POSTGRES_CONN_MAX_LIFETIME" default:"1h" desc:"Maximum lifetime of a connection"`
+	ConnMaxIdleTime time.Duration `mapstructure:"conn_max_idle_time" env:"POSTGRES_CONN_MAX_IDLE_TIME" default:"10m" desc:"Maximum idle time of a connection"`
 }
 
 // ClientConfig holds configuration for the EnhancedTelegramBotService client
 type ClientConfig struct {
-	Token          string        `json:"token" env:"TELEGRAM_BOT_TOKEN"`
-	BaseURL        string        `json:"base_url,omitempty" env:"TELEGRAM_API_BASE_URL"`
-	Timeout        time.Duration `json:"timeout" env:"TELEGRAM_API_TIMEOUT"`
-	MaxRetries     int           `json:"max_retries" env:"TELEGRAM_API_MAX_RETRIES"`
-	RetryDelay     time.Duration `json:"retry_delay" env:"TELEGRAM_API_RETRY_DELAY"`
-	RateLimitDelay time.Duration `json:"rate_limit_delay" env:"TELEGRAM_API_RATE_LIMIT_DELAY"`
-	EnableMetrics  bool          `json:"enable_metrics" env:"TELEGRAM_API_ENABLE_METRICS"`
-	EnableLogging  bool          `json:"enable_logging" env:"TELEGRAM_API_ENABLE_LOGGING"`
-	UserAgent      string        `json:"user_agent,omitempty" env:"TELEGRAM_API_USER_AGENT"`
+	Token          Secret        `mapstructure:"token" env:"TELEGRAM_BOT_TOKEN" desc:"Bot token from @BotFather"`
+	BaseURL        string        `mapstructure:"base_url" env:"TELEGRAM_API_BASE_URL" default:"https://api.telegram.org/bot" desc:"Bot API prefix, the token is appended to it"`
+	Timeout        time.Duration `mapstructure:"timeout" env:"TELEGRAM_API_TIMEOUT" default:"30s" desc:"HTTP timeout of a single API call"`
+	MaxRetries     int           `mapstructure:"max_retries" reload:"true" env:"TELEGRAM_API_MAX_RETRIES" default:"3" desc:"Retries of a failed API call"`
+	RetryDelay     time.Duration `mapstructure:"retry_delay" reload:"true" env:"TELEGRAM_API_RETRY_DELAY" default:"1s" desc:"Base delay between retries, multiplied by the attempt"`
+	RateLimitDelay time.Duration `mapstructure:"rate_limit_delay" reload:"true" env:"TELEGRAM_API_RATE_LIMIT_DELAY" default:"1s" desc:"Back-off after a 429 without retry_after"`
+	EnableMetrics  bool          `mapstructure:"enable_metrics" reload:"true" env:"TELEGRAM_API_ENABLE_METRICS" default:"true" desc:"Collect client metrics"`
+	EnableLogging  bool          `mapstructure:"enable_logging" reload:"true" env:"TELEGRAM_API_ENABLE_LOGGING" default:"true" desc:"Log retries and rate limiting"`
+	UserAgent      string        `mapstructure:"user_agent" env:"TELEGRAM_API_USER_AGENT" default:"Go-Telegram-Bot/1.0" desc:"User-Agent header of API calls"`
+
+	RateLimit      RateLimitConfig      `mapstructure:"rate_limit"`
+	CircuitBreaker CircuitBreakerConfig `mapstructure:"circuit_breaker"`
+}
+
+// CircuitBreakerConfig controls when the client stops calling an API method that keeps failing
+type CircuitBreakerConfig struct {
+	FailureThreshold int           `mapstructure:"failure_threshold" reload:"true" env:"TELEGRAM_BREAKER_FAILURE_THRESHOLD" default:"5" desc:"Consecutive network or 5xx errors before a method is short-circuited"`
+	OpenTimeout      time.Duration `mapstructure:"open_timeout" reload:"true" env:"TELEGRAM_BREAKER_OPEN_TIMEOUT" default:"30s" desc:"How long a method stays short-circuited before a probe"`
+}
+
+// RateLimitConfig holds the outbound limits enforced by the client, zero values use Telegram's defaults
+type RateLimitConfig struct {
+	GlobalPerSecond  float64 `mapstructure:"global_per_second" reload:"true" env:"TELEGRAM_RATE_LIMIT_GLOBAL_PER_SECOND" default:"30" desc:"Messages per second across all chats"`
+	PrivatePerSecond float64 `mapstructure:"private_per_second" reload:"true" env:"TELEGRAM_RATE_LIMIT_PRIVATE_PER_SECOND" default:"1" desc:"Messages per second to one private chat"`
+	GroupPerMinute   float64 `mapstructure:"group_per_minute" reload:"true" env:"TELEGRAM_RATE_LIMIT_GROUP_PER_MINUTE" default:"20" desc:"Messages per minute to one group"`
 }
 
 func getFileConfig(env string) string {
@@ -76,8 +284,36 @@ func getFileConfig(env string) string {
 	}
 }
 
-// LoadConfig loads the configuration from file and environment variables
+// LoadConfig loads the configuration from file and environment variables and validates it
 func LoadConfig() (*Config, error) {
+	config, err := ReadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+
+	return config, nil
+}
+
+// ReadConfig loads the configuration from file and environment variables without validating it
+func ReadConfig() (*Config, error) {
+	v, err := newViper()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+
+	return decode(v)
+}
+
+// newViper creates a Viper instance for the config file selected by ENVIRONMENT
+func newViper() (*viper.Viper, error) {
 	_ = godotenv.Load() // Load .env file if exists, ignore error if not found
 	env := os.Getenv("ENVIRONMENT")
 
@@ -87,56 +323,44 @@ func LoadConfig() (*Config, error) {
 	v.SetConfigType(ConfigType)
 	v.AddConfigPath(ConfigPath)
 
-	// Enable automatic environment variable lookup
-	v.AutomaticEnv()
-	bindEnvironmentVariables(v)
-
-	if err := v.ReadInConfig(); err != nil {
-		return nil, fmt.Errorf("error reading config file: %w", err)
+	// Bind the env variables and defaults declared in the struct tags
+	if err := bindSchema(v); err != nil {
+		return nil, err
 	}
+	return v, nil
+}
 
+// decode unmarshals the settings read by v into a new Config
+func decode(v *viper.Viper) (*Config, error) {
 	config := &Config{} // Initialize an empty Config struct
-	if err := v.Unmarshal(config); err != nil {
+	// Reject keys that do not exist in the schema instead of silently ignoring them
+	if err := v.UnmarshalExact(config); err != nil {
 		return nil, fmt.Errorf("error unmarshaling config: %w", err)
 	}
 
-	config.Client.BaseURL = config.Client.BaseURL + config.Client.Token
+	return config, nil
+}
 
-	// Print the loaded configuration
-	fmt.Println("=== Loaded Configuration ===")
-	fmt.Printf("%+v\n", config)
-	fmt.Println("============================")
+// APIURL returns the Bot API endpoint prefix for the configured token
+func (c ClientConfig) APIURL() string {
+	baseURL := c.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultAPIBaseURL
+	}
+	return baseURL + c.Token.Value()
+}
 
-	return config, nil
+// plainConfig has the fields of Config without its methods, so printing it does not recurse into String
+type plainConfig Config
+
+// String renders the configuration with every Secret masked
+func (c Config) String() string {
+	return fmt.Sprintf("%+v", plainConfig(c))
 }
 
-// bindEnvironmentVariables binds specific environment variables to configuration keys
-func bindEnvironmentVariables(v *viper.Viper) {
-	// Bind app environment variables to config keys
-	v.BindEnv("app.environment", "ENVIRONMENT")
-
-	// Database configuration
-	v.BindEnv("postgres.host", "POSTGRES_HOST")
-	v.BindEnv("postgres.port", "POSTGRES_PORT")
-	v.BindEnv("postgres.user", "POSTGRES_USER")
-	v.BindEnv("postgres.password", "POSTGRES_PASSWORD")
-	v.BindEnv("postgres.name", "POSTGRES_NAME")
-	v.BindEnv("postgres.ssl_mode", "POSTGRES_SSL_MODE")
-	v.BindEnv("postgres.max_open_conns", "POSTGRES_MAX_OPEN_CONNS")
-	v.BindEnv("postgres.max_idle_conns", "POSTGRES_MAX_IDLE_CONNS")
-	v.BindEnv("postgres.conn_max_lifetime", "POSTGRES_CONN_MAX_LIFETIME")
-	v.BindEnv("postgres.conn_max_idle_time", "POSTGRES_CONN_MAX_IDLE_TIME")
-
-	// Client configuration
-	v.BindEnv("client.token", "TELEGRAM_BOT_TOKEN")
-	v.BindEnv("client.base_url", "TELEGRAM_API_BASE_URL")
-	v.BindEnv("client.timeout", "TELEGRAM_API_TIMEOUT")
-	v.BindEnv("client.max_retries", "TELEGRAM_API_MAX_RETRIES")
-	v.BindEnv("client.retry_delay", "TELEGRAM_API_RETRY_DELAY")
-	v.BindEnv("client.rate_limit_delay", "TELEGRAM_API_RATE_LIMIT_DELAY")
-	v.BindEnv("client.enable_metrics", "TELEGRAM_API_ENABLE_METRICS")
-	v.BindEnv("client.enable_logging", "TELEGRAM_API_ENABLE_LOGGING")
-	v.BindEnv("client.user_agent", "TELEGRAM_API_USER_AGENT")
+// LogValue renders the configuration with every Secret masked in structured log records
+func (c Config) LogValue() slog.Value {
+	return slog.StringValue(c.String())
 }
 
 // GetDatabaseURL constructs the database connection URL from the configuration.
@@ -146,7 +370,7 @@ func (c *Config) GetDatabaseURL() string {
 		c.Postgres.Host,
 		c.Postgres.Port,
 		c.Postgres.User,
-		c.Postgres.Password,
+		c.Postgres.Password.Value(),
 		c.Postgres.Name,
 		c.Postgres.SSLMode,
 	)
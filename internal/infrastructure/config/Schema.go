@@ -0,0 +1,139 @@
+package config
+
+//go:generate go run ../../../cmd/configdoc -o ../../../configs/REFERENCE.md
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Field describes one configuration key as declared by the struct tags of Config
+type Field struct {
+	Key         string // dotted viper key, e.g. "client.max_retries"
+	Env         string // environment variable overriding the key, empty when none
+	Default     string // default value in YAML notation, empty when none
+	Type        string // value type shown in the reference
+	Description string
+	Reloadable  bool // applied at runtime by Watcher, other keys require a restart
+
+	index []int
+}
+
+// Value returns the field's value in cfg, Secrets stay masked when printed
+func (f Field) Value(cfg *Config) any {
+	return reflect.ValueOf(cfg).Elem().FieldByIndex(f.index).Interface()
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Fields lists every configuration key in declaration order
+func Fields() []Field {
+	var fields []Field
+	collectFields(reflect.TypeOf(Config{}), "", nil, &fields)
+	return fields
+}
+
+func collectFields(t reflect.Type, prefix string, index []int, fields *[]Field) {
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		name := structField.Tag.Get("mapstructure")
+		if name == "" || name == "-" {
+			continue
+		}
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+		fieldIndex := append(append([]int(nil), index...), i)
+
+		if structField.Type.Kind() == reflect.Struct && structField.Type != durationType {
+			collectFields(structField.Type, key, fieldIndex, fields)
+			continue
+		}
+
+		*fields = append(*fields, Field{
+			Key:         key,
+			Env:         structField.Tag.Get("env"),
+			Default:     structField.Tag.Get("default"),
+			Type:        typeName(structField.Type),
+			Description: structField.Tag.Get("desc"),
+			Reloadable:  structField.Tag.Get("reload") == "true",
+			index:       fieldIndex,
+		})
+	}
+}
+
+func typeName(t reflect.Type) string {
+	switch {
+	case t == durationType:
+		return "duration"
+	case t == reflect.TypeOf(Secret("")):
+		return "secret"
+	case t.Kind() == reflect.Slice:
+		return "list of " + typeName(t.Elem())
+	case t.Kind() == reflect.Map:
+		return "map[string]" + typeName(t.Elem())
+	case t.Kind() == reflect.Float64:
+		return "number"
+	default:
+		return t.Kind().String()
+	}
+}
+
+// bindSchema registers the environment variable and default value of every field with v
+func bindSchema(v *viper.Viper) error {
+	for _, field := range Fields() {
+		if field.Env != "" {
+			if err := v.BindEnv(field.Key, field.Env); err != nil {
+				return fmt.Errorf("failed to bind %s to %s: %w", field.Env, field.Key, err)
+			}
+		}
+		if field.Default != "" {
+			v.SetDefault(field.Key, field.Default)
+		}
+	}
+	return nil
+}
+
+// WriteReference renders the Markdown reference of every key, environment variable and default
+func WriteReference(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("# Configuration reference\n\n")
+	b.WriteString("<!-- Code generated by cmd/configdoc from internal/infrastructure/config. DO NOT EDIT. -->\n\n")
+	b.WriteString("Keys are read from `configs/<name>.yml`, where the name is selected by `ENVIRONMENT`\n")
+	b.WriteString("(`config_dev` for development, `config_prod` for production, `config_testing` for testing and `config` otherwise).\n")
+	b.WriteString("Environment variables override the file, defaults apply when neither sets a key. Unknown keys are rejected.\n")
+	b.WriteString("Keys marked as reloadable are applied when the file changes or the process receives SIGHUP, others require a restart.\n")
+
+	section := ""
+	for _, field := range Fields() {
+		top, _, _ := strings.Cut(field.Key, ".")
+		if top != section {
+			section = top
+			fmt.Fprintf(&b, "\n## %s\n\n", section)
+			b.WriteString("| Key | Type | Env | Default | Reloadable | Description |\n")
+			b.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+		}
+		reloadable := ""
+		if field.Reloadable {
+			reloadable = "yes"
+		}
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s | %s | %s |\n",
+			field.Key, field.Type, code(field.Env), code(field.Default), reloadable, field.Description)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func code(value string) string {
+	if value == "" {
+		return ""
+	}
+	return "`" + value + "`"
+}
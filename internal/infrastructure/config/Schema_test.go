@@ -0,0 +1,69 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestConfigFiles_MatchSchema(t *testing.T) {
+	files, err := filepath.Glob("../../../configs/*.yml")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no config files found: %v", err)
+	}
+
+	for _, file := range files {
+		v := viper.New()
+		v.SetConfigFile(file)
+		if err := bindSchema(v); err != nil {
+			t.Fatal(err)
+		}
+		if err := v.ReadInConfig(); err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+
+		cfg := &Config{}
+		if err := v.UnmarshalExact(cfg); err != nil {
+			t.Errorf("%s does not match the schema: %v", file, err)
+		}
+	}
+}
+
+func TestBindSchema_EnvAndDefaults(t *testing.T) {
+	t.Setenv("TELEGRAM_API_MAX_RETRIES", "7")
+
+	v := viper.New()
+	if err := bindSchema(v); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &Config{}
+	if err := v.UnmarshalExact(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Client.MaxRetries != 7 {
+		t.Fatalf("env override not applied, got %d", cfg.Client.MaxRetries)
+	}
+	if cfg.Client.BaseURL != DefaultAPIBaseURL || cfg.Admin.MaxPollAge.String() != "1m0s" {
+		t.Fatalf("defaults not applied: %q %s", cfg.Client.BaseURL, cfg.Admin.MaxPollAge)
+	}
+}
+
+func TestReference_UpToDate(t *testing.T) {
+	committed, err := os.ReadFile("../../../configs/REFERENCE.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var generated strings.Builder
+	if err := WriteReference(&generated); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(committed) != generated.String() {
+		t.Fatal("configs/REFERENCE.md is stale, run go generate ./internal/infrastructure/config")
+	}
+}
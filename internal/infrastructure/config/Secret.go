@@ -0,0 +1,41 @@
+package config
+
+import (
+	"encoding/json"
+	"log/slog"
+)
+
+// redactedValue replaces secrets in configuration dumps
+const redactedValue = "[REDACTED]"
+
+// Secret is a sensitive configuration value that is masked whenever it is printed, logged or serialized.
+// Use Value to obtain the plain text.
+type Secret string
+
+// Value returns the secret in plain text
+func (s Secret) Value() string {
+	return string(s)
+}
+
+// String masks the secret for fmt verbs such as %v and %+v
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redactedValue
+}
+
+// GoString masks the secret for the %#v verb
+func (s Secret) GoString() string {
+	return `"` + s.String() + `"`
+}
+
+// LogValue masks the secret in structured log records
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(s.String())
+}
+
+// MarshalJSON masks the secret in JSON output such as the admin status endpoint
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
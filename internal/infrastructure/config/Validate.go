@@ -0,0 +1,327 @@
+package config
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// LogLevels are the accepted values of logger.log_level
+var LogLevels = []string{"debug", "info", "warn", "error", "dpanic", "panic", "fatal"}
+
+// TracingExporters are the accepted values of tracing.exporter
+var TracingExporters = []string{"stdout", "otlp"}
+
+// JoinRequestPolicies are the accepted values of join_requests.policy and join_requests.chat_policies
+var JoinRequestPolicies = []string{"approve", "question", "review"}
+
+// AntiFloodStores are the accepted values of anti_flood.store
+var AntiFloodStores = []string{"memory", "postgres"}
+
+// DDNSNetworks are the accepted values of ddns.rfc2136.network
+var DDNSNetworks = []string{"udp", "tcp"}
+
+// TSIGAlgorithms are the accepted values of ddns.rfc2136.key_algorithm
+var TSIGAlgorithms = []string{"hmac-sha1", "hmac-sha256", "hmac-sha512"}
+
+// DDNSHTTPMethods are the accepted values of ddns.http.method
+var DDNSHTTPMethods = []string{"GET", "POST"}
+
+// GeoIPProviders are the accepted values of geoip.provider
+var GeoIPProviders = []string{"none", "ipinfo", "ip-api", "mmdb"}
+
+// FieldError describes an invalid configuration value
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// validator collects every problem instead of stopping at the first one
+type validator struct {
+	errs []error
+}
+
+func (v *validator) fail(field, format string, args ...any) {
+	v.errs = append(v.errs, &FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) required(field, value string) {
+	if value == "" {
+		v.fail(field, "is required")
+	}
+}
+
+func (v *validator) positive(field string, value time.Duration) {
+	if value <= 0 {
+		v.fail(field, "must be a positive duration, got %s", value)
+	}
+}
+
+func (v *validator) notNegative(field string, value time.Duration) {
+	if value < 0 {
+		v.fail(field, "must not be negative, got %s", value)
+	}
+}
+
+func (v *validator) port(field string, value int) {
+	if value < 1 || value > 65535 {
+		v.fail(field, "must be between 1 and 65535, got %d", value)
+	}
+}
+
+func (v *validator) httpURL(field, value string) {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		v.fail(field, "must be an absolute http(s) URL, got %q", value)
+	}
+}
+
+func (v *validator) oneOf(field, value string, allowed []string) {
+	for _, candidate := range allowed {
+		if value == candidate {
+			return
+		}
+	}
+	v.fail(field, "must be one of %v, got %q", allowed, value)
+}
+
+// Validate checks the whole configuration and reports every problem at once.
+// The returned error joins one *FieldError per invalid field.
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	if c.Logger.LogLevel != "" {
+		v.oneOf("logger.log_level", c.Logger.LogLevel, LogLevels)
+	}
+
+	v.required("postgres.host", c.Postgres.Host)
+	v.port("postgres.port", c.Postgres.Port)
+	v.required("postgres.user", c.Postgres.User)
+	v.required("postgres.name", c.Postgres.Name)
+	v.notNegative("postgres.conn_max_lifetime", c.Postgres.ConnMaxLifetime)
+	v.notNegative("postgres.conn_max_idle_time", c.Postgres.ConnMaxIdleTime)
+
+	v.required("client.token", c.Client.Token.Value())
+	if c.Client.BaseURL != "" {
+		v.httpURL("client.base_url", c.Client.BaseURL)
+	}
+	v.positive("client.timeout", c.Client.Timeout)
+	if c.Client.MaxRetries < 0 {
+		v.fail("client.max_retries", "must not be negative, got %d", c.Client.MaxRetries)
+	}
+	v.positive("client.retry_delay", c.Client.RetryDelay)
+	v.positive("client.rate_limit_delay", c.Client.RateLimitDelay)
+	v.notNegative("client.circuit_breaker.open_timeout", c.Client.CircuitBreaker.OpenTimeout)
+
+	if c.Admin.Enabled {
+		v.port("admin.port", c.Admin.Port)
+		v.notNegative("admin.max_poll_age", c.Admin.MaxPollAge)
+	}
+
+	for i, owner := range c.Auth.Owners {
+		if owner <= 0 {
+			v.fail(fmt.Sprintf("auth.owners[%d]", i), "must be a Telegram user ID, got %d", owner)
+		}
+	}
+
+	v.notNegative("alerts.dedup_window", c.Alerts.DedupWindow)
+
+	if c.AntiFlood.Enabled {
+		c.AntiFlood.validate(v)
+	}
+
+	if c.JoinRequests.Enabled {
+		c.JoinRequests.validate(v)
+	}
+
+	if c.Moderation.WarnLimit < 0 {
+		v.fail("moderation.warn_limit", "must not be negative, got %d", c.Moderation.WarnLimit)
+	}
+	v.notNegative("moderation.warn_ban_duration", c.Moderation.WarnBanDuration)
+	if c.Moderation.WarnBanDuration > 366*24*time.Hour {
+		// Telegram would ban forever while the warning says otherwise
+		v.fail("moderation.warn_ban_duration", "must not exceed 366 days, got %s", c.Moderation.WarnBanDuration)
+	}
+	if c.Moderation.PurgeLimit < 0 {
+		v.fail("moderation.purge_limit", "must not be negative, got %d", c.Moderation.PurgeLimit)
+	}
+
+	if c.Broadcast.Enabled {
+		v.positive("broadcast.poll_interval", c.Broadcast.PollInterval)
+		if c.Broadcast.BatchSize <= 0 {
+			v.fail("broadcast.batch_size", "must be positive, got %d", c.Broadcast.BatchSize)
+		}
+		v.positive("broadcast.lease", c.Broadcast.Lease)
+	}
+
+	if c.Scheduler.Enabled {
+		v.positive("scheduler.poll_interval", c.Scheduler.PollInterval)
+	}
+	if c.Scheduler.Timezone != "" {
+		if _, err := time.LoadLocation(c.Scheduler.Timezone); err != nil || c.Scheduler.Timezone == "Local" {
+			v.fail("scheduler.timezone", "must be an IANA time zone such as Asia/Ho_Chi_Minh, got %q", c.Scheduler.Timezone)
+		}
+	}
+	if c.Scheduler.MaxPerChat < 0 {
+		v.fail("scheduler.max_per_chat", "must not be negative, got %d", c.Scheduler.MaxPerChat)
+	}
+	if c.Scheduler.MaxRemindersPerUser < 0 {
+		v.fail("scheduler.max_reminders_per_user", "must not be negative, got %d", c.Scheduler.MaxRemindersPerUser)
+	}
+
+	if c.IPMonitor.Enabled {
+		v.positive("ip_monitor.interval", c.IPMonitor.Interval)
+	}
+
+	c.DDNS.validate(v)
+	if (c.DDNS.Cloudflare.Enabled || c.DDNS.RFC2136.Enabled || c.DDNS.HTTP.Enabled) && !c.IPMonitor.Enabled {
+		v.fail("ip_monitor.enabled", "is required by ddns, which updates the addresses the monitor looks up")
+	}
+
+	c.GeoIP.validate(v)
+
+	if c.Tracing.Enabled {
+		if c.Tracing.Exporter != "" {
+			v.oneOf("tracing.exporter", c.Tracing.Exporter, TracingExporters)
+		}
+		if c.Tracing.Exporter == "otlp" {
+			v.httpURL("tracing.endpoint", c.Tracing.Endpoint)
+		}
+		v.notNegative("tracing.flush_interval", c.Tracing.FlushInterval)
+	}
+
+	return errors.Join(v.errs...)
+}
+
+func (a *AntiFlood) validate(v *validator) {
+	v.oneOf("anti_flood.store", a.Store, AntiFloodStores)
+	for _, limit := range []struct {
+		field string
+		value int
+	}{
+		{"anti_flood.user_limit", a.UserLimit},
+		{"anti_flood.chat_limit", a.ChatLimit},
+		{"anti_flood.warn_after", a.WarnAfter},
+		{"anti_flood.mute_after", a.MuteAfter},
+	} {
+		if limit.value < 0 {
+			v.fail(limit.field, "must not be negative, got %d", limit.value)
+		}
+	}
+	if a.UserLimit > 0 {
+		v.positive("anti_flood.user_window", a.UserWindow)
+	}
+	if a.ChatLimit > 0 {
+		v.positive("anti_flood.chat_window", a.ChatWindow)
+	}
+	if a.WarnAfter > 0 || a.MuteAfter > 0 {
+		v.positive("anti_flood.violation_window", a.ViolationWindow)
+	}
+	if a.MuteAfter > 0 {
+		v.positive("anti_flood.mute_duration", a.MuteDuration)
+		if a.WarnAfter > a.MuteAfter {
+			v.fail("anti_flood.warn_after", "must not exceed mute_after (%d), got %d", a.MuteAfter, a.WarnAfter)
+		}
+	}
+	for command, cooldown := range a.Cooldowns {
+		v.positive("anti_flood.cooldowns."+command, cooldown)
+	}
+}
+
+func (j *JoinRequests) validate(v *validator) {
+	policies := map[string]bool{j.Policy: true}
+	v.oneOf("join_requests.policy", j.Policy, JoinRequestPolicies)
+	for _, chatID := range slices.Sorted(maps.Keys(j.ChatPolicies)) {
+		policy := j.ChatPolicies[chatID]
+		if _, err := strconv.ParseInt(chatID, 10, 64); err != nil {
+			v.fail("join_requests.chat_policies."+chatID, "key must be a chat ID")
+		}
+		v.oneOf("join_requests.chat_policies."+chatID, policy, JoinRequestPolicies)
+		policies[policy] = true
+	}
+	if policies["question"] {
+		v.required("join_requests.question", j.Question)
+		v.positive("join_requests.answer_timeout", j.AnswerTimeout)
+	}
+	if policies["review"] && j.ReviewChatID == 0 {
+		v.fail("join_requests.review_chat_id", "is required by the review policy")
+	}
+}
+
+func (d *DDNS) validate(v *validator) {
+	if d.Cloudflare.Enabled || d.RFC2136.Enabled || d.HTTP.Enabled {
+		v.positive("ddns.timeout", d.Timeout)
+	}
+
+	if cf := d.Cloudflare; cf.Enabled {
+		v.required("ddns.cloudflare.api_token", cf.APIToken.Value())
+		v.required("ddns.cloudflare.zone_id", cf.ZoneID)
+		if len(cf.Records) == 0 {
+			v.fail("ddns.cloudflare.records", "is required")
+		}
+		v.httpURL("ddns.cloudflare.base_url", cf.BaseURL)
+	}
+
+	if ns := d.RFC2136; ns.Enabled {
+		if _, _, err := net.SplitHostPort(ns.Server); err != nil {
+			v.fail("ddns.rfc2136.server", "must be host:port, got %q", ns.Server)
+		}
+		v.oneOf("ddns.rfc2136.network", ns.Network, DDNSNetworks)
+		v.required("ddns.rfc2136.zone", ns.Zone)
+		if len(ns.Records) == 0 {
+			v.fail("ddns.rfc2136.records", "is required")
+		}
+		if ns.TTL < 0 {
+			v.fail("ddns.rfc2136.ttl", "must not be negative, got %d", ns.TTL)
+		}
+		if ns.KeyName != "" {
+			v.oneOf("ddns.rfc2136.key_algorithm", ns.KeyAlgorithm, TSIGAlgorithms)
+			if _, err := base64.StdEncoding.DecodeString(ns.KeySecret.Value()); err != nil || ns.KeySecret == "" {
+				v.fail("ddns.rfc2136.key_secret", "must be a base64 secret")
+			}
+		}
+	}
+
+	if h := d.HTTP; h.Enabled {
+		v.oneOf("ddns.http.method", h.Method, DDNSHTTPMethods)
+		// The URL usually holds a token, it is not quoted
+		if u, err := url.Parse(h.URL.Value()); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			v.fail("ddns.http.url", "must be an absolute http(s) URL")
+		}
+		if _, err := regexp.Compile(h.SuccessPattern); err != nil {
+			v.fail("ddns.http.success_pattern", "must be a regular expression: %v", err)
+		}
+	}
+}
+
+func (g *GeoIP) validate(v *validator) {
+	// An empty provider, as in a config without the section, looks nothing up like none
+	if g.Provider != "" {
+		v.oneOf("geoip.provider", g.Provider, GeoIPProviders)
+	}
+	switch g.Provider {
+	case "ipinfo", "ip-api":
+		if g.URL != "" {
+			v.httpURL("geoip.url", g.URL)
+		}
+	case "mmdb":
+		if g.CityDB == "" && g.ASNDB == "" {
+			v.fail("geoip.city_db", "is required by the mmdb provider unless geoip.asn_db is set")
+		}
+	}
+	if (g.Provider != "" && g.Provider != "none") || g.ReverseDNS {
+		v.positive("geoip.timeout", g.Timeout)
+	}
+	v.notNegative("geoip.cache_ttl", g.CacheTTL)
+}
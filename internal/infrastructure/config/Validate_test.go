@@ -0,0 +1,67 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func validConfig() *Config {
+	return &Config{
+		Logger:   Logger{LogLevel: "info"},
+		Postgres: Postgres{Host: "localhost", Port: 5432, User: "bot", Name: "bot", Password: "hunter2"},
+		Client: ClientConfig{
+			Token:          "123:abc",
+			BaseURL:        DefaultAPIBaseURL,
+			Timeout:        30 * time.Second,
+			RetryDelay:     time.Second,
+			RateLimitDelay: time.Second,
+		},
+	}
+}
+
+func TestConfig_ValidateAccepts(t *testing.T) {
+	if err := validConfig().Validate(); err != nil {
+		t.Fatalf("expected a valid config, got %v", err)
+	}
+}
+
+func TestConfig_ValidateReportsEveryProblem(t *testing.T) {
+	cfg := validConfig()
+	cfg.Logger.LogLevel = "verbose"
+	cfg.Client.Token = ""
+	cfg.Client.BaseURL = "api.telegram.org"
+	cfg.Client.Timeout = 0
+	cfg.Postgres.Port = 0
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+
+	var fields []string
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var fieldErr *FieldError
+		if !errors.As(e, &fieldErr) {
+			t.Fatalf("unexpected error type %T", e)
+		}
+		fields = append(fields, fieldErr.Field)
+	}
+
+	expected := []string{"logger.log_level", "postgres.port", "client.token", "client.base_url", "client.timeout"}
+	if strings.Join(fields, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected errors for %v, got %v", expected, fields)
+	}
+}
+
+func TestConfig_StringRedactsSecrets(t *testing.T) {
+	cfg := validConfig()
+
+	for _, out := range []string{cfg.String(), fmt.Sprintf("%+v", cfg), fmt.Sprintf("%#v", *cfg)} {
+		if strings.Contains(out, "123:abc") || strings.Contains(out, "hunter2") {
+			t.Fatalf("secret leaked: %s", out)
+		}
+	}
+}
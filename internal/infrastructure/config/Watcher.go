@@ -0,0 +1,142 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watcherLogger is the subset of the application logger used by Watcher
+type watcherLogger interface {
+	Info(msg string, fields ...any)
+	Warn(msg string, fields ...any)
+	Error(msg string, fields ...any)
+}
+
+// Watcher reloads the configuration when the file changes or the process receives SIGHUP.
+// Only keys tagged reload:"true" are applied, changes to any other key are logged and ignored
+// until the next restart. Subscribers receive every configuration that was applied.
+type Watcher struct {
+	logger  watcherLogger
+	current atomic.Pointer[Config]
+
+	mu          sync.Mutex // serializes reloads and guards subscribers
+	subscribers []func(cfg *Config)
+}
+
+// NewWatcher creates a watcher publishing changes relative to the initial configuration
+func NewWatcher(initial *Config, logger watcherLogger) *Watcher {
+	w := &Watcher{logger: logger}
+	w.current.Store(initial)
+	return w
+}
+
+// Current returns the configuration currently in effect, it must not be modified
+func (w *Watcher) Current() *Config {
+	return w.current.Load()
+}
+
+// Subscribe registers fn to be called with the new configuration after every applied reload
+func (w *Watcher) Subscribe(fn func(cfg *Config)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subscribers = append(w.subscribers, fn)
+}
+
+// Start watches the config file and SIGHUP until ctx is done
+func (w *Watcher) Start(ctx context.Context) error {
+	v, err := newViper()
+	if err != nil {
+		return err
+	}
+	if err := v.ReadInConfig(); err != nil {
+		return fmt.Errorf("error reading config file: %w", err)
+	}
+
+	v.OnConfigChange(func(event fsnotify.Event) {
+		if ctx.Err() != nil {
+			return
+		}
+		w.logger.Info("Config file changed, reloading", "file", event.Name)
+		w.reloadAndLog()
+	})
+	v.WatchConfig()
+
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(hangup)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hangup:
+				w.logger.Info("Received SIGHUP, reloading config")
+				w.reloadAndLog()
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (w *Watcher) reloadAndLog() {
+	if err := w.Reload(); err != nil {
+		w.logger.Error("Config reload rejected, keeping the current configuration", "error", err)
+	}
+}
+
+// Reload reads and validates the configuration and applies its reloadable keys
+func (w *Watcher) Reload() error {
+	next, err := ReadConfig()
+	if err != nil {
+		return err
+	}
+	if err := next.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return w.apply(next)
+}
+
+// apply copies the reloadable keys of next onto the current configuration and notifies subscribers
+func (w *Watcher) apply(next *Config) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	current := w.current.Load()
+	merged := *current
+	mergedValue := reflect.ValueOf(&merged).Elem()
+	nextValue := reflect.ValueOf(next).Elem()
+
+	var changed []string
+	for _, field := range Fields() {
+		if reflect.DeepEqual(field.Value(current), field.Value(next)) {
+			continue
+		}
+		if !field.Reloadable {
+			w.logger.Warn("Ignoring config change that requires a restart", "key", field.Key)
+			continue
+		}
+		mergedValue.FieldByIndex(field.index).Set(nextValue.FieldByIndex(field.index))
+		changed = append(changed, field.Key)
+	}
+
+	if len(changed) == 0 {
+		return nil
+	}
+
+	w.current.Store(&merged)
+	w.logger.Info("Config reloaded", "changed", changed)
+
+	for _, subscriber := range w.subscribers {
+		subscriber(&merged)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+type discardLogger struct{ warnings []any }
+
+func (l *discardLogger) Info(msg string, fields ...any)  {}
+func (l *discardLogger) Error(msg string, fields ...any) {}
+func (l *discardLogger) Warn(msg string, fields ...any) {
+	l.warnings = append(l.warnings, fields...)
+}
+
+func TestWatcher_AppliesOnlyReloadableKeys(t *testing.T) {
+	logger := &discardLogger{}
+	watcher := NewWatcher(validConfig(), logger)
+
+	var published *Config
+	watcher.Subscribe(func(cfg *Config) { published = cfg })
+
+	next := validConfig()
+	next.Logger.LogLevel = "debug"
+	next.Client.RetryDelay = 5 * time.Second
+	next.Client.Token = "456:def"
+	next.Postgres.Host = "db.internal"
+
+	if err := watcher.apply(next); err != nil {
+		t.Fatal(err)
+	}
+
+	if published == nil || published != watcher.Current() {
+		t.Fatal("subscriber was not notified with the current config")
+	}
+	if published.Logger.LogLevel != "debug" || published.Client.RetryDelay != 5*time.Second {
+		t.Fatalf("reloadable keys not applied: %+v", published)
+	}
+	if published.Client.Token.Value() != "123:abc" || published.Postgres.Host != "localhost" {
+		t.Fatal("structural keys must not change at runtime")
+	}
+	if len(logger.warnings) != 4 || logger.warnings[1] != "postgres.host" || logger.warnings[3] != "client.token" {
+		t.Fatalf("expected a warning per structural key, got %v", logger.warnings)
+	}
+}
@@ -0,0 +1,48 @@
+package database
+
+import (
+	"database/sql"
+
+	"go-telegram-bot/internal/shared/metrics"
+)
+
+// RegisterPoolMetrics exposes the connection pool statistics returned by stats as gauges and counters
+func RegisterPoolMetrics(reg *metrics.Registry, stats func() (sql.DBStats, error)) {
+	gauge := func(name, help string, value func(s sql.DBStats) float64) {
+		reg.RegisterFunc(name, help, metrics.TypeGauge, nil, func() []metrics.Sample {
+			s, err := stats()
+			if err != nil {
+				return nil
+			}
+			return []metrics.Sample{{Value: value(s)}}
+		})
+	}
+	counter := func(name, help string, value func(s sql.DBStats) float64) {
+		reg.RegisterFunc(name, help, metrics.TypeCounter, nil, func() []metrics.Sample {
+			s, err := stats()
+			if err != nil {
+				return nil
+			}
+			return []metrics.Sample{{Value: value(s)}}
+		})
+	}
+
+	gauge("db_pool_max_open_connections", "Maximum number of open connections to the database.",
+		func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) })
+	gauge("db_pool_open_connections", "Established connections, both in use and idle.",
+		func(s sql.DBStats) float64 { return float64(s.OpenConnections) })
+	gauge("db_pool_in_use_connections", "Connections currently in use.",
+		func(s sql.DBStats) float64 { return float64(s.InUse) })
+	gauge("db_pool_idle_connections", "Idle connections.",
+		func(s sql.DBStats) float64 { return float64(s.Idle) })
+	counter("db_pool_wait_count_total", "Connections waited for.",
+		func(s sql.DBStats) float64 { return float64(s.WaitCount) })
+	counter("db_pool_wait_duration_seconds_total", "Time blocked waiting for a new connection.",
+		func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() })
+	counter("db_pool_max_idle_closed_total", "Connections closed due to SetMaxIdleConns.",
+		func(s sql.DBStats) float64 { return float64(s.MaxIdleClosed) })
+	counter("db_pool_max_idle_time_closed_total", "Connections closed due to SetConnMaxIdleTime.",
+		func(s sql.DBStats) float64 { return float64(s.MaxIdleTimeClosed) })
+	counter("db_pool_max_lifetime_closed_total", "Connections closed due to SetConnMaxLifetime.",
+		func(s sql.DBStats) float64 { return float64(s.MaxLifetimeClosed) })
+}
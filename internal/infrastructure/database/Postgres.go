@@ -18,6 +18,10 @@ func NewPostgresConnection(databaseURL string) (*gorm.DB, error) {
 		return nil, err
 	}
 
+	if err := db.Use(&TracingPlugin{}); err != nil {
+		return nil, err
+	}
+
 	return db, nil
 }
 
@@ -0,0 +1,85 @@
+package database
+
+import (
+	"go-telegram-bot/internal/shared/tracing"
+
+	"gorm.io/gorm"
+)
+
+// tracingSpanKey stores the span of a statement between the before and after callbacks
+const tracingSpanKey = "tracing:span"
+
+// TracingPlugin is a GORM plugin recording a span for every statement,
+// spans join the update's trace when the query is made with db.WithContext(ctx)
+type TracingPlugin struct{}
+
+// Name returns the plugin name
+func (p *TracingPlugin) Name() string {
+	return "tracing"
+}
+
+// Initialize registers the tracing callbacks around every GORM operation
+func (p *TracingPlugin) Initialize(db *gorm.DB) error {
+	type register func(name string, fn func(*gorm.DB)) error
+
+	cb := db.Callback()
+	operations := []struct {
+		name          string
+		before, after register
+	}{
+		{"create", cb.Create().Before("gorm:create").Register, cb.Create().After("gorm:create").Register},
+		{"query", cb.Query().Before("gorm:query").Register, cb.Query().After("gorm:query").Register},
+		{"update", cb.Update().Before("gorm:update").Register, cb.Update().After("gorm:update").Register},
+		{"delete", cb.Delete().Before("gorm:delete").Register, cb.Delete().After("gorm:delete").Register},
+		{"row", cb.Row().Before("gorm:row").Register, cb.Row().After("gorm:row").Register},
+		{"raw", cb.Raw().Before("gorm:raw").Register, cb.Raw().After("gorm:raw").Register},
+	}
+
+	for _, op := range operations {
+		operation := op.name
+		if err := op.before("tracing:before_"+operation, func(tx *gorm.DB) {
+			startSpan(tx, operation)
+		}); err != nil {
+			return err
+		}
+		if err := op.after("tracing:after_"+operation, endSpan); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startSpan(tx *gorm.DB, operation string) {
+	if tx.Statement == nil || tx.Statement.Context == nil {
+		return
+	}
+	ctx, span := tracing.Start(tx.Statement.Context, "gorm."+operation,
+		tracing.String("db.system", "postgresql"),
+		tracing.String("db.operation", operation),
+	)
+	tx.Statement.Context = ctx
+	tx.InstanceSet(tracingSpanKey, span)
+}
+
+func endSpan(tx *gorm.DB) {
+	value, ok := tx.InstanceGet(tracingSpanKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(*tracing.Span)
+	if !ok {
+		return
+	}
+
+	if tx.Statement.Table != "" {
+		span.SetAttributes(tracing.String("db.table", tx.Statement.Table))
+	}
+	span.SetAttributes(
+		tracing.String("db.statement", tx.Statement.SQL.String()),
+		tracing.Int64("db.rows_affected", tx.Statement.RowsAffected),
+	)
+	if tx.Error != nil && tx.Error != gorm.ErrRecordNotFound {
+		span.RecordError(tx.Error)
+	}
+	span.End()
+}
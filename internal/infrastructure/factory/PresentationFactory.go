@@ -3,6 +3,7 @@ package factory
 import (
 	domainService "go-telegram-bot/internal/domain/service"
 	"go-telegram-bot/internal/presentation"
+	"go-telegram-bot/internal/presentation/middleware"
 	"go-telegram-bot/internal/presentation/service"
 )
 
@@ -18,9 +19,10 @@ func NewPresentationFactory() *PresentationFactory {
 func (f *PresentationFactory) CreateTelegramHandler(
 	botAppService *service.BotApplicationService,
 	telegramBot domainService.TelegramBotService,
+	chain *middleware.Chain,
 	logger domainService.Logger,
 ) *presentation.TelegramHandler {
-	return presentation.NewTelegramHandler(botAppService, telegramBot, logger)
+	return presentation.NewTelegramHandler(botAppService, telegramBot, chain, logger)
 }
 
 // CreatePresentationService creates a BotApplicationService
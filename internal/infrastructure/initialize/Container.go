@@ -1,23 +1,36 @@
 package initialize
 
 import (
+	"time"
+
+	appService "go-telegram-bot/internal/application/service"
 	"go-telegram-bot/internal/domain/repository"
 	domainService "go-telegram-bot/internal/domain/service"
 	"go-telegram-bot/internal/infrastructure/config"
 	"go-telegram-bot/internal/infrastructure/factory"
 	"go-telegram-bot/internal/presentation"
+	"go-telegram-bot/internal/presentation/admin"
+	"go-telegram-bot/internal/presentation/middleware"
 	"go-telegram-bot/internal/presentation/service"
+	"go-telegram-bot/internal/shared/tracing"
 
 	"gorm.io/gorm"
 )
 
 type Container struct {
-	Config *config.Config
+	StartedAt time.Time
+
+	// Config is the configuration loaded at startup, ConfigWatcher.Current reflects reloaded keys
+	Config        *config.Config
+	ConfigWatcher *config.Watcher
+
 	Logger domainService.Logger
 	DB     *gorm.DB
+	Tracer *tracing.Tracer
 
 	// Services
 	IPService   domainService.IPService
+	DDNS        []domainService.DDNSProvider // enabled providers, see config.DDNS
 	TelegramBot domainService.TelegramBotService
 	BotUseCase  domainService.BotUseCase
 
@@ -26,20 +39,43 @@ type Container struct {
 	UserProfileRepo repository.UserProfileRepository
 	ChatRepo        repository.ChatRepository
 	MessageRepo     repository.MessageRepository
+	RoleRepo        repository.RoleAssignmentRepository // nil without a database
+	ChatMemberRepo  repository.ChatMemberRepository     // nil without a database
+	JoinRequestRepo repository.JoinRequestRepository    // nil without a database
+	WarningRepo     repository.WarningRepository        // nil without a database
+	InviteLinkRepo  repository.InviteLinkRepository     // nil without a database
+	BroadcastRepo   repository.BroadcastRepository      // nil without a database
+	ScheduleRepo    repository.ScheduleRepository       // nil without a database
+	IPMonitorRepo   repository.IPMonitorRepository      // nil without a database
+	FloodEventRepo  repository.FloodEventRepository
 
 	// Factories
 	ApplicationFactory  *factory.ApplicationServiceFactory
 	PresentationFactory *factory.PresentationFactory
 
 	// Application Services
+	TransactionManager *appService.TransactionManager
+	AuthService        *appService.AuthorizationServiceImpl
+	MembershipService  *appService.MembershipServiceImpl
+	JoinRequestService *appService.JoinRequestServiceImpl
+	ModerationService  *appService.ModerationServiceImpl
+	InviteLinkService  *appService.InviteLinkServiceImpl
+	BroadcastService   *appService.BroadcastServiceImpl
+	SchedulerService   *appService.SchedulerServiceImpl
+	IPMonitorService   *appService.IPMonitorServiceImpl
+	DDNSService        *appService.DDNSServiceImpl
 
 	// Presentation Layer
+	AntiFlood             *middleware.AntiFloodMiddleware
+	Middleware            *middleware.Chain
+	AlertReporter         *middleware.AlertReporter
 	TelegramHandler       *presentation.TelegramHandler
 	BotApplicationService *service.BotApplicationService
+	AdminServer           *admin.Server
 }
 
 func NewContainer() (*Container, error) {
-	container := &Container{}
+	container := &Container{StartedAt: time.Now()}
 
 	// load config
 	if err := container.LoadConfig(); err != nil {
@@ -51,6 +87,11 @@ func NewContainer() (*Container, error) {
 		return nil, err
 	}
 
+	// init tracing
+	if err := container.InitTracing(); err != nil {
+		return nil, err
+	}
+
 	// init database connection
 	container.initDatabase()
 
@@ -58,7 +99,12 @@ func NewContainer() (*Container, error) {
 	container.InitRepositories()
 
 	// init services
-	container.InitServices()
+	if err := container.InitServices(); err != nil {
+		return nil, err
+	}
+
+	// init config hot reload
+	container.InitConfigWatcher()
 
 	// init factories
 	container.InitFactories()
@@ -66,8 +112,17 @@ func NewContainer() (*Container, error) {
 	// init application services
 	container.InitApplicationServices()
 
+	// init anti-flood protection
+	container.InitAntiFlood()
+
+	// init update middleware chain
+	container.InitMiddleware()
+
 	// init presentation layer
 	container.InitPresentationLayer()
 
+	// init admin server
+	container.InitAdminServer()
+
 	return container, nil
 }
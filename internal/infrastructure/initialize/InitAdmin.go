@@ -0,0 +1,129 @@
+package initialize
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"runtime"
+	"time"
+
+	"go-telegram-bot/internal/infrastructure/config"
+	"go-telegram-bot/internal/infrastructure/database"
+	"go-telegram-bot/internal/presentation"
+	"go-telegram-bot/internal/presentation/admin"
+	"go-telegram-bot/internal/shared/metrics"
+	"go-telegram-bot/internal/shared/version"
+)
+
+const (
+	// defaultMaxPollAge is used when admin.max_poll_age is not configured
+	defaultMaxPollAge = 60 * time.Second
+	// readinessTimeout bounds the time spent running all readiness checks
+	readinessTimeout = 5 * time.Second
+	// telegramCheckTTL avoids calling getMe on every readiness probe
+	telegramCheckTTL = 30 * time.Second
+)
+
+// statusResponse is the body of /debug/status
+type statusResponse struct {
+	Version   string                    `json:"version"`
+	Commit    string                    `json:"commit"`
+	GoVersion string                    `json:"go_version"`
+	StartedAt time.Time                 `json:"started_at"`
+	Uptime    string                    `json:"uptime"`
+	Poller    presentation.PollerStatus `json:"poller"`
+	Config    *config.Config            `json:"config"`
+}
+
+// InitAdminServer creates the admin HTTP server and registers its endpoints
+func (c *Container) InitAdminServer() {
+	if c.TransactionManager != nil {
+		database.RegisterPoolMetrics(metrics.Default, func() (sql.DBStats, error) {
+			return c.TransactionManager.GetStats(context.Background())
+		})
+	}
+
+	if !c.Config.Admin.Enabled {
+		return
+	}
+
+	c.AdminServer = admin.NewServer(c.Config.Admin.Address(), c.Logger)
+	c.AdminServer.Handle("/metrics", admin.MetricsHandler(metrics.Default))
+	c.AdminServer.Handle("/healthz", admin.LivenessHandler())
+	c.AdminServer.Handle("/readyz", admin.ReadinessHandler(readinessTimeout,
+		c.databaseCheck(),
+		admin.CachedCheck(c.telegramCheck(), telegramCheckTTL),
+		c.updatesCheck(),
+	))
+	c.AdminServer.Handle("/debug/status", admin.StatusHandler(c.status))
+}
+
+// databaseCheck pings the database
+func (c *Container) databaseCheck() admin.Check {
+	return admin.Check{
+		Name: "database",
+		Run: func(ctx context.Context) error {
+			if c.TransactionManager == nil {
+				return fmt.Errorf("database connection was not established")
+			}
+			return c.TransactionManager.HealthCheck(ctx)
+		},
+	}
+}
+
+// telegramCheck verifies the bot token against getMe
+func (c *Container) telegramCheck() admin.Check {
+	return admin.Check{
+		Name: "telegram",
+		Run: func(ctx context.Context) error {
+			_, err := c.TelegramBot.GetMeWithResponse(ctx)
+			return err
+		},
+	}
+}
+
+// updatesCheck verifies that updates are being received, either by recent polling or by a webhook
+func (c *Container) updatesCheck() admin.Check {
+	maxPollAge := c.Config.Admin.MaxPollAge
+	if maxPollAge <= 0 {
+		maxPollAge = defaultMaxPollAge
+	}
+
+	return admin.Check{
+		Name: "updates",
+		Run: func(ctx context.Context) error {
+			status := c.TelegramHandler.Status()
+			if status.Active {
+				if status.LastPollAt.IsZero() {
+					return fmt.Errorf("poller has not completed a poll yet")
+				}
+				if age := time.Since(status.LastPollAt); age > maxPollAge {
+					return fmt.Errorf("last successful poll was %s ago", age.Round(time.Second))
+				}
+				return nil
+			}
+
+			info, err := c.TelegramBot.GetWebhookInfo(ctx)
+			if err != nil {
+				return fmt.Errorf("poller inactive and webhook info unavailable: %w", err)
+			}
+			if info.Result == nil || info.Result.URL == "" {
+				return fmt.Errorf("poller inactive and no webhook registered")
+			}
+			return nil
+		},
+	}
+}
+
+// status builds the body of /debug/status
+func (c *Container) status() any {
+	return statusResponse{
+		Version:   version.Version,
+		Commit:    version.Commit,
+		GoVersion: runtime.Version(),
+		StartedAt: c.StartedAt,
+		Uptime:    time.Since(c.StartedAt).Round(time.Second).String(),
+		Poller:    c.TelegramHandler.Status(),
+		Config:    c.Config,
+	}
+}
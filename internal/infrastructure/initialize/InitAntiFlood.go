@@ -0,0 +1,55 @@
+package initialize
+
+import (
+	"strings"
+	"time"
+
+	"go-telegram-bot/internal/domain/types"
+	"go-telegram-bot/internal/infrastructure/config"
+	"go-telegram-bot/internal/infrastructure/repository"
+	"go-telegram-bot/internal/presentation/middleware"
+)
+
+// InitAntiFlood creates the anti-flood middleware, its counters are shared through Postgres when configured
+func (c *Container) InitAntiFlood() {
+	switch {
+	case c.Config.AntiFlood.Store == "postgres" && c.DB != nil:
+		c.FloodEventRepo = repository.NewFloodEventRepository(c.DB)
+	case c.Config.AntiFlood.Store == "postgres":
+		c.Logger.Warn("Database unavailable, anti-flood counters are kept in memory")
+		fallthrough
+	default:
+		c.FloodEventRepo = repository.NewMemoryFloodEventRepository()
+	}
+
+	c.AntiFlood = middleware.NewAntiFloodMiddleware(
+		c.FloodEventRepo,
+		antiFloodPolicy(c.Config.AntiFlood),
+		c.TelegramBot,
+		c.Logger,
+	)
+	c.ConfigWatcher.Subscribe(func(cfg *config.Config) {
+		c.AntiFlood.SetPolicy(antiFloodPolicy(cfg.AntiFlood))
+	})
+}
+
+// antiFloodPolicy converts the configuration, whose cooldowns are keyed by command without the slash
+func antiFloodPolicy(cfg config.AntiFlood) middleware.AntiFloodPolicy {
+	cooldowns := make(map[types.Command]time.Duration, len(cfg.Cooldowns))
+	for command, cooldown := range cfg.Cooldowns {
+		cooldowns[types.Command("/"+strings.TrimPrefix(strings.ToLower(command), "/"))] = cooldown
+	}
+
+	return middleware.AntiFloodPolicy{
+		Enabled:         cfg.Enabled,
+		UserLimit:       cfg.UserLimit,
+		UserWindow:      cfg.UserWindow,
+		ChatLimit:       cfg.ChatLimit,
+		ChatWindow:      cfg.ChatWindow,
+		Cooldowns:       cooldowns,
+		WarnAfter:       cfg.WarnAfter,
+		MuteAfter:       cfg.MuteAfter,
+		ViolationWindow: cfg.ViolationWindow,
+		MuteDuration:    cfg.MuteDuration,
+	}
+}
@@ -1,12 +1,161 @@
 package initialize
 
-import "go-telegram-bot/internal/application/service"
+import (
+	"strconv"
+	"time"
+
+	"go-telegram-bot/internal/application/service"
+	"go-telegram-bot/internal/domain/repository"
+	"go-telegram-bot/internal/domain/types"
+	"go-telegram-bot/internal/infrastructure/config"
+)
 
 func (c *Container) InitApplicationServices() {
+	// Owners come from the configuration and follow its reloads
+	c.AuthService = service.NewAuthorizationService(c.RoleRepo, ownerIDs(c.Config.Auth), c.Logger)
+	c.ConfigWatcher.Subscribe(func(cfg *config.Config) {
+		c.AuthService.SetOwners(ownerIDs(cfg.Auth))
+	})
+
+	// Without a database membership changes are not recorded, only welcome messages are sent
+	var chats repository.ChatRepository
+	if c.DB != nil {
+		chats = c.ChatRepo
+	}
+	c.MembershipService = service.NewMembershipService(
+		chats, c.ChatMemberRepo, c.TelegramBot, welcomeMessages(c.Config.Welcome), c.Logger,
+	)
+	c.ConfigWatcher.Subscribe(func(cfg *config.Config) {
+		c.MembershipService.SetWelcome(welcomeMessages(cfg.Welcome))
+	})
+
+	c.JoinRequestService = service.NewJoinRequestService(
+		c.JoinRequestRepo, c.AuthService, c.TelegramBot, joinRequestPolicy(c.Config.JoinRequests), c.Logger,
+	)
+	c.ConfigWatcher.Subscribe(func(cfg *config.Config) {
+		c.JoinRequestService.SetPolicy(joinRequestPolicy(cfg.JoinRequests))
+	})
+
+	c.ModerationService = service.NewModerationService(
+		c.WarningRepo, c.AuthService, c.TelegramBot, moderationPolicy(c.Config.Moderation), c.Logger,
+	)
+	c.ConfigWatcher.Subscribe(func(cfg *config.Config) {
+		c.ModerationService.SetPolicy(moderationPolicy(cfg.Moderation))
+	})
+
+	c.InviteLinkService = service.NewInviteLinkService(c.InviteLinkRepo, c.TelegramBot, c.Logger)
+
+	c.BroadcastService = service.NewBroadcastService(
+		c.BroadcastRepo, c.ChatRepo, c.AuthService, c.TelegramBot, broadcastOptions(c.Config.Broadcast), c.Logger,
+	)
+
+	c.SchedulerService = service.NewSchedulerService(
+		c.ScheduleRepo, c.ChatRepo, c.AuthService, c.TelegramBot, schedulerOptions(c.Config.Scheduler), c.Logger,
+	)
+
+	c.IPMonitorService = service.NewIPMonitorService(
+		c.IPMonitorRepo, c.IPService, c.TelegramBot, service.IPMonitorOptions{Interval: c.Config.IPMonitor.Interval}, c.Logger,
+	)
+	c.DDNSService = service.NewDDNSService(c.DDNS, c.TelegramBot, service.DDNSOptions{
+		ReportChatID: types.TelegramChatID(c.Config.DDNS.ReportChatID),
+		Timeout:      c.Config.DDNS.Timeout,
+	}, c.Logger)
+	// The records follow the addresses the monitor looks up
+	if len(c.DDNS) > 0 {
+		c.IPMonitorService.AddObserver(c.DDNSService)
+	}
+
 	// Create BotUseCase implementation
 	c.BotUseCase = service.NewBotUseCaseImpl(
 		c.IPService,
 		c.TelegramBot,
+		c.AuthService,
+		c.MembershipService,
+		c.JoinRequestService,
+		c.ModerationService,
+		c.InviteLinkService,
+		c.BroadcastService,
+		c.SchedulerService,
+		c.SchedulerService,
+		c.IPMonitorService,
+		c.DDNSService,
 		c.Logger,
 	)
+	// Scheduled jobs run their commands through the use case, as if their creator sent them
+	c.SchedulerService.SetRunner(c.BotUseCase)
+
+	// The transaction manager needs a live database connection
+	if c.DB != nil {
+		c.TransactionManager = service.NewTransactionManager(c.DB, c.Logger)
+	}
+}
+
+// ownerIDs converts the configured owner IDs to Telegram user IDs
+func ownerIDs(auth config.Auth) []types.TelegramUserID {
+	owners := make([]types.TelegramUserID, 0, len(auth.Owners))
+	for _, id := range auth.Owners {
+		owners = append(owners, types.TelegramUserID(id))
+	}
+	return owners
+}
+
+// welcomeMessages converts the configured welcome templates
+func welcomeMessages(welcome config.Welcome) service.WelcomeMessages {
+	return service.WelcomeMessages{
+		Group:  welcome.GroupMessage,
+		Member: welcome.MemberMessage,
+	}
+}
+
+// joinRequestPolicy converts the join request configuration, chat IDs were checked by Validate
+func joinRequestPolicy(cfg config.JoinRequests) service.JoinRequestPolicy {
+	chats := make(map[types.TelegramChatID]types.JoinRequestPolicy, len(cfg.ChatPolicies))
+	for chatID, policy := range cfg.ChatPolicies {
+		id, err := strconv.ParseInt(chatID, 10, 64)
+		if err != nil {
+			continue
+		}
+		chats[types.TelegramChatID(id)] = types.JoinRequestPolicy(policy)
+	}
+	return service.JoinRequestPolicy{
+		Enabled:       cfg.Enabled,
+		Default:       types.JoinRequestPolicy(cfg.Policy),
+		Chats:         chats,
+		Question:      cfg.Question,
+		Answers:       cfg.Answers,
+		AnswerTimeout: cfg.AnswerTimeout,
+		ReviewChatID:  types.TelegramChatID(cfg.ReviewChatID),
+	}
+}
+
+// moderationPolicy converts the moderation configuration
+func moderationPolicy(cfg config.Moderation) service.ModerationPolicy {
+	return service.ModerationPolicy{
+		WarnLimit:       cfg.WarnLimit,
+		WarnBanDuration: cfg.WarnBanDuration,
+		PurgeLimit:      cfg.PurgeLimit,
+	}
+}
+
+// broadcastOptions converts the broadcast worker configuration
+func broadcastOptions(cfg config.Broadcast) service.BroadcastOptions {
+	return service.BroadcastOptions{
+		PollInterval: cfg.PollInterval,
+		BatchSize:    cfg.BatchSize,
+		Lease:        cfg.Lease,
+	}
+}
+
+// schedulerOptions converts the scheduler configuration, the time zone was checked by Validate
+func schedulerOptions(cfg config.Scheduler) service.SchedulerOptions {
+	loc, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return service.SchedulerOptions{
+		PollInterval: cfg.PollInterval,
+		Timezone:     loc,
+		MaxPerChat:   cfg.MaxPerChat,
+		MaxReminders: cfg.MaxRemindersPerUser,
+	}
 }
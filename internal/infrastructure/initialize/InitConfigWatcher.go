@@ -0,0 +1,48 @@
+package initialize
+
+import (
+	"go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/infrastructure/config"
+)
+
+// levelSetter is implemented by loggers whose level can change at runtime
+type levelSetter interface {
+	SetLevel(level string) error
+}
+
+// clientConfigUpdater is implemented by Telegram clients accepting reloaded settings
+type clientConfigUpdater interface {
+	UpdateConfig(cfg config.ClientConfig)
+}
+
+// botUnwrapper is implemented by decorators of the Telegram client
+type botUnwrapper interface {
+	Unwrap() service.TelegramBotService
+}
+
+// InitConfigWatcher creates the config watcher and subscribes the components supporting hot reload
+func (c *Container) InitConfigWatcher() {
+	c.ConfigWatcher = config.NewWatcher(c.Config, c.Logger)
+
+	if logger, ok := c.Logger.(levelSetter); ok {
+		c.ConfigWatcher.Subscribe(func(cfg *config.Config) {
+			if err := logger.SetLevel(cfg.Logger.LogLevel); err != nil {
+				c.Logger.Error("Failed to apply reloaded log level", "error", err)
+			}
+		})
+	}
+
+	bot := c.TelegramBot
+	for {
+		decorator, ok := bot.(botUnwrapper)
+		if !ok {
+			break
+		}
+		bot = decorator.Unwrap()
+	}
+	if client, ok := bot.(clientConfigUpdater); ok {
+		c.ConfigWatcher.Subscribe(func(cfg *config.Config) {
+			client.UpdateConfig(cfg.Client)
+		})
+	}
+}
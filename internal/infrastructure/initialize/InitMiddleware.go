@@ -0,0 +1,30 @@
+package initialize
+
+import (
+	"go-telegram-bot/internal/domain/types"
+	"go-telegram-bot/internal/infrastructure/config"
+	"go-telegram-bot/internal/presentation/middleware"
+)
+
+// InitMiddleware builds the middleware chain every update passes through before command routing
+func (c *Container) InitMiddleware() {
+	c.AlertReporter = middleware.NewAlertReporter(
+		c.TelegramBot,
+		types.TelegramChatID(c.Config.Alerts.ChatID),
+		c.Config.Alerts.DedupWindow,
+		c.Logger,
+	)
+	c.ConfigWatcher.Subscribe(func(cfg *config.Config) {
+		c.AlertReporter.Configure(types.TelegramChatID(cfg.Alerts.ChatID), cfg.Alerts.DedupWindow)
+	})
+
+	c.Middleware = middleware.NewChain().
+		Use("recovery", middleware.PriorityRecovery,
+			middleware.NewRecoveryMiddleware(c.TelegramBot, c.AlertReporter, c.Logger).Wrap).
+		Use("logging", middleware.PriorityLogging,
+			middleware.NewLoggingMiddleware(c.Logger).Wrap).
+		Use("anti_flood", middleware.PriorityAntiFlood,
+			c.AntiFlood.Wrap, middleware.ForAnyCommand()).
+		Use("error_handling", middleware.PriorityErrorHandling,
+			middleware.NewErrorHandlingMiddleware(c.TelegramBot, c.Logger).Wrap)
+}
@@ -8,6 +8,7 @@ func (c *Container) InitPresentationLayer() {
 	c.TelegramHandler = c.PresentationFactory.CreateTelegramHandler(
 		c.BotApplicationService,
 		c.TelegramBot,
+		c.Middleware,
 		c.Logger,
 	)
 }
@@ -9,4 +9,16 @@ func (c *Container) InitRepositories() {
 	c.UserProfileRepo = repository.NewUserProfileRepo(c.DB, c.UserRepo)
 	c.ChatRepo = repository.NewChatRepository(c.DB)
 	c.MessageRepo = repository.NewMessageRepository(c.DB, c.UserRepo, c.ChatRepo)
+
+	// Without a database only the configured owners are authorized, see AuthorizationServiceImpl
+	if c.DB != nil {
+		c.RoleRepo = repository.NewRoleAssignmentRepository(c.DB)
+		c.ChatMemberRepo = repository.NewChatMemberRepository(c.DB)
+		c.JoinRequestRepo = repository.NewJoinRequestRepository(c.DB)
+		c.WarningRepo = repository.NewWarningRepository(c.DB)
+		c.InviteLinkRepo = repository.NewInviteLinkRepository(c.DB)
+		c.BroadcastRepo = repository.NewBroadcastRepository(c.DB)
+		c.ScheduleRepo = repository.NewScheduleRepository(c.DB)
+		c.IPMonitorRepo = repository.NewIPMonitorRepository(c.DB)
+	}
 }
@@ -1,10 +1,65 @@
 package initialize
 
-import "go-telegram-bot/internal/infrastructure/service"
+import (
+	"fmt"
 
-func (c *Container) InitServices() {
-	c.IPService = service.NewIPService()
+	domainService "go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/infrastructure/service"
+)
+
+func (c *Container) InitServices() error {
+	geo, err := c.initGeoProvider()
+	if err != nil {
+		return err
+	}
+	c.IPService = service.NewIPService(c.Config.GeoIP, geo, c.Logger)
 	c.TelegramBot = service.NewTelegramBot(
 		c.Config.Client, nil, c.Logger,
 	)
+
+	// Send failures deactivate or migrate the stored chats, which needs the database
+	if c.DB != nil {
+		c.TelegramBot = service.NewChatStateTracker(c.TelegramBot, c.ChatRepo, c.UserRepo, c.Logger)
+	}
+
+	return c.initDDNSProviders()
+}
+
+// initGeoProvider creates the geolocation provider selected by geoip.provider, nil for none
+func (c *Container) initGeoProvider() (domainService.IPGeoProvider, error) {
+	cfg := c.Config.GeoIP
+	switch cfg.Provider {
+	case "ipinfo", "ip-api":
+		return service.NewHTTPGeoProvider(cfg, nil), nil
+	case "mmdb":
+		provider, err := service.NewMMDBGeoProvider(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("geoip: %w", err)
+		}
+		return provider, nil
+	}
+	return nil, nil
+}
+
+// initDDNSProviders creates the enabled DDNS providers, their settings were checked by Validate
+func (c *Container) initDDNSProviders() error {
+	cfg := c.Config.DDNS
+	if cfg.Cloudflare.Enabled {
+		c.DDNS = append(c.DDNS, service.NewCloudflareDDNS(cfg.Cloudflare, nil))
+	}
+	if cfg.RFC2136.Enabled {
+		provider, err := service.NewRFC2136DDNS(cfg.RFC2136)
+		if err != nil {
+			return fmt.Errorf("ddns.rfc2136: %w", err)
+		}
+		c.DDNS = append(c.DDNS, provider)
+	}
+	if cfg.HTTP.Enabled {
+		provider, err := service.NewHTTPDDNS(cfg.HTTP, nil)
+		if err != nil {
+			return fmt.Errorf("ddns.http: %w", err)
+		}
+		c.DDNS = append(c.DDNS, provider)
+	}
+	return nil
 }
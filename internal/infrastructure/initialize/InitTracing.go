@@ -0,0 +1,47 @@
+package initialize
+
+import (
+	"fmt"
+
+	"go-telegram-bot/internal/shared/tracing"
+)
+
+// InitTracing installs the process-wide tracer and its exporter
+func (c *Container) InitTracing() error {
+	cfg := c.Config.Tracing
+	if !cfg.Enabled {
+		// Spans are still created so log lines carry trace IDs, they are just not exported
+		return nil
+	}
+
+	serviceName := cfg.ServiceName
+	if serviceName == "" {
+		serviceName = "go-telegram-bot"
+	}
+
+	var exporter tracing.Exporter
+	switch cfg.Exporter {
+	case "", "stdout":
+		exporter = tracing.NewStdoutExporter(nil)
+	case "otlp":
+		if cfg.Endpoint == "" {
+			return fmt.Errorf("tracing.endpoint is required for the otlp exporter")
+		}
+		headers := make(map[string]string, len(cfg.Headers))
+		for key, value := range cfg.Headers {
+			headers[key] = value.Value()
+		}
+		exporter = tracing.NewOTLPExporter(cfg.Endpoint, serviceName, headers)
+	default:
+		return fmt.Errorf("unknown tracing exporter: %s", cfg.Exporter)
+	}
+
+	processor := tracing.NewBatchProcessor(exporter, cfg.FlushInterval, func(err error) {
+		c.Logger.Warn("Failed to export spans", "error", err)
+	})
+	c.Tracer = tracing.NewTracer(processor)
+	tracing.SetTracer(c.Tracer)
+
+	c.Logger.Info("Tracing enabled", "exporter", cfg.Exporter, "service_name", serviceName)
+	return nil
+}
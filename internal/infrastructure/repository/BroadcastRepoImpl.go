@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-telegram-bot/internal/domain/entity"
+	"go-telegram-bot/internal/domain/errors"
+	"go-telegram-bot/internal/domain/repository"
+	"go-telegram-bot/internal/domain/types"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type broadcastRepository struct {
+	db *gorm.DB
+}
+
+// NewBroadcastRepository creates a new instance of BroadcastRepository.
+func NewBroadcastRepository(db *gorm.DB) repository.BroadcastRepository {
+	return &broadcastRepository{db: db}
+}
+
+// Create inserts a new broadcast job.
+func (r *broadcastRepository) Create(ctx context.Context, job *entity.BroadcastJob) error {
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+// Get retrieves a broadcast job by its ID.
+func (r *broadcastRepository) Get(ctx context.Context, id int64) (*entity.BroadcastJob, error) {
+	var job entity.BroadcastJob
+	if err := r.db.WithContext(ctx).
+		Where("id = ?", id).
+		First(&job).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrBroadcastNotFound
+		}
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// Latest retrieves the most recently created broadcast job.
+func (r *broadcastRepository) Latest(ctx context.Context) (*entity.BroadcastJob, error) {
+	var job entity.BroadcastJob
+	if err := r.db.WithContext(ctx).
+		Order("id DESC").
+		First(&job).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrBroadcastNotFound
+		}
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// CountTargets counts the active chats matching the filter.
+func (r *broadcastRepository) CountTargets(ctx context.Context, filter entity.BroadcastFilter) (int, error) {
+	var count int64
+	err := targetChats(r.db.WithContext(ctx), filter).Count(&count).Error
+	return int(count), err
+}
+
+// Transition moves the job to status when it is in one of from, stamping the end of final statuses.
+func (r *broadcastRepository) Transition(
+	ctx context.Context, id int64, from []types.BroadcastStatus, to types.BroadcastStatus,
+) (bool, error) {
+	updates := map[string]any{"status": to}
+	if to.IsFinal() {
+		updates["finished_at"] = time.Now()
+		updates["locked_until"] = nil
+	}
+	result := r.db.WithContext(ctx).
+		Model(&entity.BroadcastJob{}).
+		Where("id = ? AND status IN ?", id, from).
+		Updates(updates)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ClaimDue leases the oldest due job, SKIP LOCKED lets concurrent workers claim different jobs.
+func (r *broadcastRepository) ClaimDue(
+	ctx context.Context, now time.Time, lease time.Duration,
+) (*entity.BroadcastJob, error) {
+	var job entity.BroadcastJob
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status IN ? AND scheduled_at <= ? AND (locked_until IS NULL OR locked_until < ?)",
+				[]types.BroadcastStatus{types.BroadcastStatusScheduled, types.BroadcastStatusRunning}, now, now).
+			Order("scheduled_at, id").
+			First(&job).Error; err != nil {
+			return err
+		}
+
+		lockedUntil := now.Add(lease)
+		updates := map[string]any{
+			"status":       types.BroadcastStatusRunning,
+			"locked_until": lockedUntil,
+		}
+		if job.StartedAt == nil {
+			updates["started_at"] = now
+			job.StartedAt = &now
+		}
+		job.Status, job.LockedUntil = types.BroadcastStatusRunning, &lockedUntil
+		return tx.Model(&entity.BroadcastJob{}).Where("id = ?", job.ID).Updates(updates).Error
+	})
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// Plan inserts the pending deliveries with a single INSERT ... SELECT over the matching chats.
+func (r *broadcastRepository) Plan(ctx context.Context, job *entity.BroadcastJob, at time.Time) error {
+	if job.PlannedAt != nil {
+		return nil
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		targets := targetChats(tx.Session(&gorm.Session{NewDB: true}), job.Filter).
+			Select("CAST(? AS bigint), chats.telegram_chat_id, CAST(? AS varchar), CAST(? AS timestamp), CAST(? AS timestamp)",
+				job.ID, types.DeliveryStatusPending, at, at).
+			Order("chats.telegram_chat_id")
+		if err := tx.Exec(
+			"INSERT INTO broadcast_deliveries (job_id, telegram_chat_id, status, created_at, updated_at) ? "+
+				"ON CONFLICT (job_id, telegram_chat_id) DO NOTHING",
+			targets,
+		).Error; err != nil {
+			return err
+		}
+
+		var total int64
+		if err := tx.Model(&entity.BroadcastDelivery{}).
+			Where("job_id = ?", job.ID).
+			Count(&total).Error; err != nil {
+			return err
+		}
+		job.Total, job.PlannedAt = int(total), &at
+		return tx.Model(&entity.BroadcastJob{}).
+			Where("id = ?", job.ID).
+			Updates(map[string]any{"total": total, "planned_at": at}).Error
+	})
+}
+
+// PendingDeliveries retrieves the next deliveries of the job not attempted yet.
+func (r *broadcastRepository) PendingDeliveries(
+	ctx context.Context, jobID int64, limit int,
+) ([]*entity.BroadcastDelivery, error) {
+	var deliveries []*entity.BroadcastDelivery
+	if err := r.db.WithContext(ctx).
+		Where("job_id = ? AND status = ?", jobID, types.DeliveryStatusPending).
+		Order("id").
+		Limit(limit).
+		Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// UpdateDelivery stores the outcome of a delivery.
+func (r *broadcastRepository) UpdateDelivery(ctx context.Context, delivery *entity.BroadcastDelivery) error {
+	return r.db.WithContext(ctx).
+		Model(delivery).
+		Updates(map[string]any{
+			"status":     delivery.Status,
+			"error":      delivery.Error,
+			"message_id": delivery.MessageID,
+			"sent_at":    delivery.SentAt,
+		}).Error
+}
+
+// Checkpoint recounts the deliveries by status into the job and extends its lease.
+func (r *broadcastRepository) Checkpoint(
+	ctx context.Context, jobID int64, lockedUntil time.Time,
+) (*entity.BroadcastJob, error) {
+	var job entity.BroadcastJob
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var counts []struct {
+			Status types.DeliveryStatus
+			Count  int
+		}
+		if err := tx.Model(&entity.BroadcastDelivery{}).
+			Select("status, COUNT(*) AS count").
+			Where("job_id = ?", jobID).
+			Group("status").
+			Scan(&counts).Error; err != nil {
+			return err
+		}
+
+		updates := map[string]any{"sent": 0, "failed": 0, "blocked": 0}
+		for _, count := range counts {
+			if count.Status != types.DeliveryStatusPending {
+				updates[string(count.Status)] = count.Count
+			}
+		}
+		if err := tx.Model(&entity.BroadcastJob{}).
+			Where("id = ?", jobID).
+			Updates(updates).Error; err != nil {
+			return err
+		}
+		// A job cancelled meanwhile stays released
+		if err := tx.Model(&entity.BroadcastJob{}).
+			Where("id = ? AND status = ?", jobID, types.BroadcastStatusRunning).
+			Update("locked_until", lockedUntil).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", jobID).First(&job).Error
+	})
+	if err == gorm.ErrRecordNotFound {
+		return nil, errors.ErrBroadcastNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// Finish completes the running job and releases its lease.
+func (r *broadcastRepository) Finish(ctx context.Context, jobID int64, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.BroadcastJob{}).
+		Where("id = ? AND status = ?", jobID, types.BroadcastStatusRunning).
+		Updates(map[string]any{
+			"status":       types.BroadcastStatusCompleted,
+			"finished_at":  at,
+			"locked_until": nil,
+		}).Error
+}
+
+// targetChats selects the active chats matching the broadcast filter
+func targetChats(db *gorm.DB, filter entity.BroadcastFilter) *gorm.DB {
+	query := db.Model(&entity.Chat{}).Where("chats.is_active = ?", true)
+	if filter.ChatType != "" {
+		query = query.Where("chats.chat_type = ?", filter.ChatType)
+	}
+	if filter.Tag != "" {
+		query = query.Where("EXISTS (?)", db.Model(&entity.ChatTag{}).
+			Select("1").
+			Where("chat_tags.telegram_chat_id = chats.telegram_chat_id AND chat_tags.tag = ?", filter.Tag))
+	}
+	return query
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-telegram-bot/internal/domain/entity"
+	"go-telegram-bot/internal/domain/errors"
+	"go-telegram-bot/internal/domain/repository"
+	"go-telegram-bot/internal/domain/types"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type chatMemberRepository struct {
+	db *gorm.DB
+}
+
+// NewChatMemberRepository creates a new instance of ChatMemberRepository.
+func NewChatMemberRepository(db *gorm.DB) repository.ChatMemberRepository {
+	return &chatMemberRepository{db: db}
+}
+
+// Get retrieves the member of the chat.
+func (r *chatMemberRepository) Get(
+	ctx context.Context, chatID types.TelegramChatID, userID types.TelegramUserID,
+) (*entity.ChatMember, error) {
+	var member entity.ChatMember
+	if err := r.db.WithContext(ctx).
+		Where("telegram_chat_id = ? AND telegram_user_id = ?", chatID, userID).
+		First(&member).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrChatMemberNotFound
+		}
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+// Upsert inserts the member or replaces the status of the existing one.
+func (r *chatMemberRepository) Upsert(
+	ctx context.Context, member *entity.ChatMember,
+) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "telegram_chat_id"}, {Name: "telegram_user_id"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"status":     member.Status,
+				"is_bot":     member.IsBot,
+				"joined_at":  member.JoinedAt,
+				"left_at":    member.LeftAt,
+				"updated_at": time.Now(),
+				"deleted_at": nil,
+			}),
+		}).
+		Create(member).Error
+}
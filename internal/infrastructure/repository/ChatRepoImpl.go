@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"go-telegram-bot/internal/domain/entity"
 	"go-telegram-bot/internal/domain/errors"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type chatRepository struct {
@@ -105,3 +107,153 @@ func (r *chatRepository) GetActiveChats(
 
 	return chats, nil
 }
+
+// DeactivateChat marks the chat as unreachable for the bot.
+func (r *chatRepository) DeactivateChat(
+	ctx context.Context, telegramChatID types.TelegramChatID,
+) error {
+	return r.db.WithContext(ctx).Model(&entity.Chat{}).
+		Where("telegram_chat_id = ?", telegramChatID).
+		Updates(map[string]any{
+			"is_active": false,
+		}).Error
+}
+
+// AddTag labels the chat, restoring a removed label.
+func (r *chatRepository) AddTag(
+	ctx context.Context, telegramChatID types.TelegramChatID, tag string,
+) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "telegram_chat_id"}, {Name: "tag"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"updated_at": time.Now(),
+				"deleted_at": nil,
+			}),
+		}).
+		Create(&entity.ChatTag{TelegramChatID: telegramChatID, Tag: tag}).Error
+}
+
+// RemoveTag soft deletes the label of the chat.
+func (r *chatRepository) RemoveTag(
+	ctx context.Context, telegramChatID types.TelegramChatID, tag string,
+) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("telegram_chat_id = ? AND tag = ?", telegramChatID, tag).
+		Delete(&entity.ChatTag{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetTags retrieves the labels of the chat in alphabetical order.
+func (r *chatRepository) GetTags(
+	ctx context.Context, telegramChatID types.TelegramChatID,
+) ([]string, error) {
+	var tags []string
+	if err := r.db.WithContext(ctx).
+		Model(&entity.ChatTag{}).
+		Where("telegram_chat_id = ?", telegramChatID).
+		Order("tag").
+		Pluck("tag", &tags).Error; err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// SetTimezone stores the time zone of the chat, inserting the chat when it is not stored yet.
+func (r *chatRepository) SetTimezone(
+	ctx context.Context, telegramChatID types.TelegramChatID, chatType types.ChatType, timezone string,
+) error {
+	chat := entity.NewChat(telegramChatID, chatType)
+	chat.IsActive, chat.Timezone = true, timezone
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "telegram_chat_id"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"timezone":   timezone,
+				"updated_at": time.Now(),
+				"deleted_at": nil,
+			}),
+		}).
+		Create(chat).Error
+}
+
+// chatIDReferences lists the columns other than chats.telegram_chat_id holding a Telegram chat ID.
+// scope is the column sharing a unique index with the chat ID, rows already stored under the new ID win.
+var chatIDReferences = []struct {
+	table  string
+	column string
+	scope  string
+}{
+	{"role_assignments", "telegram_chat_id", "telegram_user_id"},
+	{"chat_members", "telegram_chat_id", "telegram_user_id"},
+	{"join_requests", "telegram_chat_id", "telegram_user_id"},
+	{"join_request_decisions", "telegram_chat_id", ""},
+	{"warnings", "telegram_chat_id", ""},
+	{"invite_links", "telegram_chat_id", ""},
+	{"chat_tags", "telegram_chat_id", "tag"},
+	{"broadcast_deliveries", "telegram_chat_id", "job_id"},
+	{"broadcast_jobs", "report_chat_id", ""},
+	{"scheduled_jobs", "telegram_chat_id", ""},
+	{"ip_subscriptions", "telegram_chat_id", ""},
+}
+
+// MigrateChatID moves the chat and every record referencing its Telegram ID to the new ID.
+// When the new chat is already stored, the messages of the old chat move to it and the old chat is removed.
+func (r *chatRepository) MigrateChatID(
+	ctx context.Context, from, to types.TelegramChatID,
+) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := migrateChat(tx, from, to); err != nil {
+			return err
+		}
+
+		for _, ref := range chatIDReferences {
+			if ref.scope != "" {
+				if err := tx.Exec(
+					"DELETE FROM "+ref.table+" WHERE "+ref.column+" = ? AND "+ref.scope+
+						" IN (SELECT "+ref.scope+" FROM "+ref.table+" WHERE "+ref.column+" = ?)",
+					from, to,
+				).Error; err != nil {
+					return err
+				}
+			}
+			if err := tx.Table(ref.table).
+				Where(ref.column+" = ?", from).
+				Update(ref.column, to).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// migrateChat renames the old chat or merges it into the new one when both are stored
+func migrateChat(tx *gorm.DB, from, to types.TelegramChatID) error {
+	var old entity.Chat
+	if err := tx.Where("telegram_chat_id = ?", from).First(&old).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		return err
+	}
+
+	var migrated entity.Chat
+	err := tx.Where("telegram_chat_id = ?", to).First(&migrated).Error
+	if err == gorm.ErrRecordNotFound {
+		return tx.Model(&old).Updates(map[string]any{
+			"telegram_chat_id": to,
+			"chat_type":        types.ChatTypeSupergroup,
+		}).Error
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Model(&entity.Message{}).
+		Where("chat_id = ?", old.ID).
+		Update("chat_id", migrated.ID).Error; err != nil {
+		return err
+	}
+	return tx.Delete(&old).Error
+}
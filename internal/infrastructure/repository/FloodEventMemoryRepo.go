@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go-telegram-bot/internal/domain/repository"
+)
+
+// floodSweepInterval is how often keys without recent events are dropped from memory
+const floodSweepInterval = time.Minute
+
+type floodEvents struct {
+	times  []time.Time
+	window time.Duration
+}
+
+type memoryFloodEventRepository struct {
+	mu        sync.Mutex
+	events    map[string]*floodEvents
+	lastSweep time.Time
+}
+
+// NewMemoryFloodEventRepository creates a FloodEventRepository local to this process.
+func NewMemoryFloodEventRepository() repository.FloodEventRepository {
+	return &memoryFloodEventRepository{events: make(map[string]*floodEvents)}
+}
+
+// Count returns the events of key within the window.
+func (r *memoryFloodEventRepository) Count(
+	_ context.Context, key string, now time.Time, window time.Duration,
+) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.events[key]
+	if !ok {
+		return 0, nil
+	}
+	entry.prune(now, window)
+	return len(entry.times), nil
+}
+
+// Take records an event of key when fewer than limit events happened within the window.
+func (r *memoryFloodEventRepository) Take(
+	_ context.Context, key string, now time.Time, window time.Duration, limit int,
+) (int, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sweep(now)
+
+	entry, ok := r.events[key]
+	if !ok {
+		entry = &floodEvents{}
+		r.events[key] = entry
+	}
+	entry.window = window
+	entry.prune(now, window)
+
+	if limit > 0 && len(entry.times) >= limit {
+		return len(entry.times), false, nil
+	}
+	entry.times = append(entry.times, now)
+	return len(entry.times), true, nil
+}
+
+// sweep drops keys whose last event left their window, so idle users do not accumulate
+func (r *memoryFloodEventRepository) sweep(now time.Time) {
+	if now.Sub(r.lastSweep) < floodSweepInterval {
+		return
+	}
+	r.lastSweep = now
+
+	for key, entry := range r.events {
+		entry.prune(now, entry.window)
+		if len(entry.times) == 0 {
+			delete(r.events, key)
+		}
+	}
+}
+
+// prune removes the events at or before now-window, events are kept in chronological order
+func (e *floodEvents) prune(now time.Time, window time.Duration) {
+	since := now.Add(-window)
+	i := 0
+	for i < len(e.times) && !e.times[i].After(since) {
+		i++
+	}
+	e.times = e.times[i:]
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-telegram-bot/internal/domain/entity"
+	"go-telegram-bot/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type floodEventRepository struct {
+	db *gorm.DB
+}
+
+// NewFloodEventRepository creates a FloodEventRepository shared by every instance using the database.
+func NewFloodEventRepository(db *gorm.DB) repository.FloodEventRepository {
+	return &floodEventRepository{db: db}
+}
+
+// Count returns the events of key within the window.
+func (r *floodEventRepository) Count(
+	ctx context.Context, key string, now time.Time, window time.Duration,
+) (int, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).
+		Model(&entity.FloodEvent{}).
+		Where("key = ? AND occurred_at > ?", key, now.Add(-window)).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+// Take records an event of key when fewer than limit events happened within the window.
+// An advisory lock on the key serializes concurrent instances.
+func (r *floodEventRepository) Take(
+	ctx context.Context, key string, now time.Time, window time.Duration, limit int,
+) (count int, taken bool, err error) {
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", key).Error; err != nil {
+			return err
+		}
+
+		since := now.Add(-window)
+		if err := tx.Where("key = ? AND occurred_at <= ?", key, since).
+			Delete(&entity.FloodEvent{}).Error; err != nil {
+			return err
+		}
+
+		var existing int64
+		if err := tx.Model(&entity.FloodEvent{}).
+			Where("key = ? AND occurred_at > ?", key, since).
+			Count(&existing).Error; err != nil {
+			return err
+		}
+
+		count = int(existing)
+		if limit > 0 && count >= limit {
+			return nil
+		}
+		if err := tx.Create(entity.NewFloodEvent(key, now)).Error; err != nil {
+			return err
+		}
+		count++
+		taken = true
+		return nil
+	})
+
+	return count, taken, err
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-telegram-bot/internal/domain/entity"
+	"go-telegram-bot/internal/domain/repository"
+	"go-telegram-bot/internal/domain/types"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ipMonitorRepository struct {
+	db *gorm.DB
+}
+
+// NewIPMonitorRepository creates a new instance of IPMonitorRepository.
+func NewIPMonitorRepository(db *gorm.DB) repository.IPMonitorRepository {
+	return &ipMonitorRepository{db: db}
+}
+
+// LatestChange retrieves the most recent change, nil when there is none.
+func (r *ipMonitorRepository) LatestChange(ctx context.Context) (*entity.IPChange, error) {
+	var change entity.IPChange
+	if err := r.db.WithContext(ctx).
+		Order("id DESC").
+		First(&change).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &change, nil
+}
+
+// RecordChange inserts the change unless one following the same change exists, which the unique
+// index on previous_id detects without locking.
+func (r *ipMonitorRepository) RecordChange(ctx context.Context, change *entity.IPChange) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(change)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ListChanges retrieves the last changes, most recent first.
+func (r *ipMonitorRepository) ListChanges(ctx context.Context, limit int) ([]*entity.IPChange, error) {
+	var changes []*entity.IPChange
+	if err := r.db.WithContext(ctx).
+		Order("id DESC").
+		Limit(limit).
+		Find(&changes).Error; err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// Subscribe inserts the subscription, or restores it when it was removed.
+func (r *ipMonitorRepository) Subscribe(ctx context.Context, subscription *entity.IPSubscription) (bool, error) {
+	var existing entity.IPSubscription
+	err := r.db.WithContext(ctx).
+		Where("telegram_chat_id = ?", subscription.TelegramChatID).
+		First(&existing).Error
+	if err == nil {
+		return false, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return false, err
+	}
+
+	return true, r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "telegram_chat_id"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"chat_type":     subscription.ChatType,
+				"subscribed_by": subscription.SubscribedBy,
+				"updated_at":    time.Now(),
+				"deleted_at":    nil,
+			}),
+		}).
+		Create(subscription).Error
+}
+
+// Unsubscribe soft deletes the subscription of the chat.
+func (r *ipMonitorRepository) Unsubscribe(ctx context.Context, chatID types.TelegramChatID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("telegram_chat_id = ?", chatID).
+		Delete(&entity.IPSubscription{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ListSubscriptions retrieves every subscribed chat.
+func (r *ipMonitorRepository) ListSubscriptions(ctx context.Context) ([]*entity.IPSubscription, error) {
+	var subscriptions []*entity.IPSubscription
+	if err := r.db.WithContext(ctx).
+		Order("id").
+		Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-telegram-bot/internal/domain/entity"
+	"go-telegram-bot/internal/domain/errors"
+	"go-telegram-bot/internal/domain/repository"
+	"go-telegram-bot/internal/domain/types"
+
+	"gorm.io/gorm"
+)
+
+type inviteLinkRepository struct {
+	db *gorm.DB
+}
+
+// NewInviteLinkRepository creates a new instance of InviteLinkRepository.
+func NewInviteLinkRepository(db *gorm.DB) repository.InviteLinkRepository {
+	return &inviteLinkRepository{db: db}
+}
+
+// Create inserts a new invite link.
+func (r *inviteLinkRepository) Create(ctx context.Context, link *entity.InviteLink) error {
+	return r.db.WithContext(ctx).Create(link).Error
+}
+
+// Get retrieves an invite link of the chat by its ID.
+func (r *inviteLinkRepository) Get(
+	ctx context.Context, chatID types.TelegramChatID, id int64,
+) (*entity.InviteLink, error) {
+	var link entity.InviteLink
+	if err := r.db.WithContext(ctx).
+		Where("telegram_chat_id = ? AND id = ?", chatID, id).
+		First(&link).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrInviteLinkNotFound
+		}
+		return nil, err
+	}
+
+	return &link, nil
+}
+
+// ListByChat retrieves the invite links of the chat, newest first.
+func (r *inviteLinkRepository) ListByChat(
+	ctx context.Context, chatID types.TelegramChatID,
+) ([]*entity.InviteLink, error) {
+	var links []*entity.InviteLink
+	if err := r.db.WithContext(ctx).
+		Where("telegram_chat_id = ?", chatID).
+		Order("id DESC").
+		Find(&links).Error; err != nil {
+		return nil, err
+	}
+
+	return links, nil
+}
+
+// MarkRevoked stamps the revocation of the invite link.
+func (r *inviteLinkRepository) MarkRevoked(ctx context.Context, id int64, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.InviteLink{}).
+		Where("id = ?", id).
+		Update("revoked_at", at).Error
+}
+
+// RecordJoin increments the join count of the invite link.
+func (r *inviteLinkRepository) RecordJoin(ctx context.Context, link string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.InviteLink{}).
+		Where("link = ?", link).
+		Update("join_count", gorm.Expr("join_count + 1"))
+	return result.RowsAffected > 0, result.Error
+}
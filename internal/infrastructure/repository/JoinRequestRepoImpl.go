@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-telegram-bot/internal/domain/entity"
+	"go-telegram-bot/internal/domain/errors"
+	"go-telegram-bot/internal/domain/repository"
+	"go-telegram-bot/internal/domain/types"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type joinRequestRepository struct {
+	db *gorm.DB
+}
+
+// NewJoinRequestRepository creates a new instance of JoinRequestRepository.
+func NewJoinRequestRepository(db *gorm.DB) repository.JoinRequestRepository {
+	return &joinRequestRepository{db: db}
+}
+
+// Upsert inserts the request or resets the existing one for the same chat and user.
+func (r *joinRequestRepository) Upsert(
+	ctx context.Context, request *entity.JoinRequest,
+) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "telegram_chat_id"}, {Name: "telegram_user_id"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"user_chat_id":  request.UserChatID,
+				"chat_title":    request.ChatTitle,
+				"policy":        request.Policy,
+				"status":        request.Status,
+				"bio":           request.Bio,
+				"invite_link":   request.InviteLink,
+				"expires_at":    request.ExpiresAt,
+				"decline_tries": 0,
+				"updated_at":    time.Now(),
+				"deleted_at":    nil,
+			}),
+		}).
+		Create(request).Error
+}
+
+// Get retrieves the request of the user to join the chat.
+func (r *joinRequestRepository) Get(
+	ctx context.Context, chatID types.TelegramChatID, userID types.TelegramUserID,
+) (*entity.JoinRequest, error) {
+	var request entity.JoinRequest
+	if err := r.db.WithContext(ctx).
+		Where("telegram_chat_id = ? AND telegram_user_id = ?", chatID, userID).
+		First(&request).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrJoinRequestNotFound
+		}
+		return nil, err
+	}
+
+	return &request, nil
+}
+
+// OldestPendingQuestion retrieves the oldest request of the user waiting for an answer.
+func (r *joinRequestRepository) OldestPendingQuestion(
+	ctx context.Context, userID types.TelegramUserID,
+) (*entity.JoinRequest, error) {
+	var request entity.JoinRequest
+	if err := r.db.WithContext(ctx).
+		Where("telegram_user_id = ? AND status = ? AND policy = ?",
+			userID, types.JoinRequestStatusPending, types.JoinRequestPolicyQuestion).
+		Order("updated_at").
+		First(&request).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrJoinRequestNotFound
+		}
+		return nil, err
+	}
+
+	return &request, nil
+}
+
+// ExpiredPendingQuestions retrieves the requests whose answer timeout passed without an answer.
+func (r *joinRequestRepository) ExpiredPendingQuestions(
+	ctx context.Context, now time.Time, limit int,
+) ([]*entity.JoinRequest, error) {
+	var requests []*entity.JoinRequest
+	if err := r.db.WithContext(ctx).
+		Where("status = ? AND policy = ? AND expires_at < ?",
+			types.JoinRequestStatusPending, types.JoinRequestPolicyQuestion, now).
+		Order("decline_tries, expires_at").
+		Limit(limit).
+		Find(&requests).Error; err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
+
+// CountDeclineTry increments the failed declines of the request.
+func (r *joinRequestRepository) CountDeclineTry(ctx context.Context, request *entity.JoinRequest) error {
+	return r.db.WithContext(ctx).Model(&entity.JoinRequest{}).
+		Where("telegram_chat_id = ? AND telegram_user_id = ?", request.TelegramChatID, request.TelegramUserID).
+		Update("decline_tries", gorm.Expr("decline_tries + 1")).Error
+}
+
+// Decide updates the status of the request and records the decision in one transaction.
+func (r *joinRequestRepository) Decide(
+	ctx context.Context, request *entity.JoinRequest, decision *entity.JoinRequestDecision,
+) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.JoinRequest{}).
+			Where("telegram_chat_id = ? AND telegram_user_id = ?", request.TelegramChatID, request.TelegramUserID).
+			Update("status", request.Status).Error; err != nil {
+			return err
+		}
+		return tx.Create(decision).Error
+	})
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-telegram-bot/internal/domain/entity"
+	"go-telegram-bot/internal/domain/errors"
+	"go-telegram-bot/internal/domain/repository"
+	"go-telegram-bot/internal/domain/types"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type roleAssignmentRepository struct {
+	db *gorm.DB
+}
+
+// NewRoleAssignmentRepository creates a new instance of RoleAssignmentRepository.
+func NewRoleAssignmentRepository(db *gorm.DB) repository.RoleAssignmentRepository {
+	return &roleAssignmentRepository{db: db}
+}
+
+// ListForUser retrieves the user's assignments in the chat and the global ones.
+func (r *roleAssignmentRepository) ListForUser(
+	ctx context.Context, userID types.TelegramUserID, chatID types.TelegramChatID,
+) ([]*entity.RoleAssignment, error) {
+	var assignments []*entity.RoleAssignment
+	if err := r.db.WithContext(ctx).
+		Where("telegram_user_id = ? AND telegram_chat_id IN ?", userID, []int64{int64(chatID), int64(entity.GlobalChatID)}).
+		Find(&assignments).Error; err != nil {
+		return nil, err
+	}
+
+	return assignments, nil
+}
+
+// Get retrieves the assignment of the user in the chat.
+func (r *roleAssignmentRepository) Get(
+	ctx context.Context, userID types.TelegramUserID, chatID types.TelegramChatID,
+) (*entity.RoleAssignment, error) {
+	var assignment entity.RoleAssignment
+	if err := r.db.WithContext(ctx).
+		Where("telegram_user_id = ? AND telegram_chat_id = ?", userID, int64(chatID)).
+		First(&assignment).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrRoleAssignmentNotFound
+		}
+		return nil, err
+	}
+
+	return &assignment, nil
+}
+
+// Upsert inserts the assignment or replaces the role of the existing one.
+func (r *roleAssignmentRepository) Upsert(
+	ctx context.Context, assignment *entity.RoleAssignment,
+) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "telegram_user_id"}, {Name: "telegram_chat_id"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"role":       assignment.Role,
+				"granted_by": assignment.GrantedBy,
+				"updated_at": time.Now(),
+				"deleted_at": nil,
+			}),
+		}).
+		Create(assignment).Error
+}
+
+// Delete removes the assignment of the user in the chat.
+func (r *roleAssignmentRepository) Delete(
+	ctx context.Context, userID types.TelegramUserID, chatID types.TelegramChatID,
+) error {
+	result := r.db.WithContext(ctx).
+		Unscoped().
+		Where("telegram_user_id = ? AND telegram_chat_id = ?", userID, int64(chatID)).
+		Delete(&entity.RoleAssignment{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.ErrRoleAssignmentNotFound
+	}
+
+	return nil
+}
+
+// ListByChat retrieves every assignment made in the chat.
+func (r *roleAssignmentRepository) ListByChat(
+	ctx context.Context, chatID types.TelegramChatID,
+) ([]*entity.RoleAssignment, error) {
+	var assignments []*entity.RoleAssignment
+	if err := r.db.WithContext(ctx).
+		Where("telegram_chat_id = ?", int64(chatID)).
+		Order("role, telegram_user_id").
+		Find(&assignments).Error; err != nil {
+		return nil, err
+	}
+
+	return assignments, nil
+}
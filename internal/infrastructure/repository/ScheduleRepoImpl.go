@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-telegram-bot/internal/domain/entity"
+	"go-telegram-bot/internal/domain/errors"
+	"go-telegram-bot/internal/domain/repository"
+	"go-telegram-bot/internal/domain/types"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type scheduleRepository struct {
+	db *gorm.DB
+}
+
+// NewScheduleRepository creates a new instance of ScheduleRepository.
+func NewScheduleRepository(db *gorm.DB) repository.ScheduleRepository {
+	return &scheduleRepository{db: db}
+}
+
+// Create inserts a new scheduled job.
+func (r *scheduleRepository) Create(ctx context.Context, job *entity.ScheduledJob) error {
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+// Get retrieves a scheduled job by its ID.
+func (r *scheduleRepository) Get(ctx context.Context, id int64) (*entity.ScheduledJob, error) {
+	var job entity.ScheduledJob
+	if err := r.db.WithContext(ctx).
+		Where("id = ?", id).
+		First(&job).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrScheduleNotFound
+		}
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// ListByChat retrieves the jobs of the kind in the chat which still have a run ahead, soonest first.
+func (r *scheduleRepository) ListByChat(
+	ctx context.Context, chatID types.TelegramChatID, kind types.ScheduleKind,
+) ([]*entity.ScheduledJob, error) {
+	var jobs []*entity.ScheduledJob
+	if err := r.db.WithContext(ctx).
+		Where("telegram_chat_id = ? AND kind = ? AND next_run_at IS NOT NULL", chatID, kind).
+		Order("next_run_at, id").
+		Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// ListByCreator retrieves the jobs of the kind created by the user which still have a run ahead, soonest first.
+func (r *scheduleRepository) ListByCreator(
+	ctx context.Context, user types.TelegramUserID, kind types.ScheduleKind,
+) ([]*entity.ScheduledJob, error) {
+	var jobs []*entity.ScheduledJob
+	if err := r.db.WithContext(ctx).
+		Where("created_by = ? AND kind = ? AND next_run_at IS NOT NULL", user, kind).
+		Order("next_run_at, id").
+		Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// CountByChat counts the jobs of the kind in the chat which still have a run ahead.
+func (r *scheduleRepository) CountByChat(
+	ctx context.Context, chatID types.TelegramChatID, kind types.ScheduleKind,
+) (int, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).
+		Model(&entity.ScheduledJob{}).
+		Where("telegram_chat_id = ? AND kind = ? AND next_run_at IS NOT NULL", chatID, kind).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+// Delete soft deletes the job, scoped to the chat so that a chat cannot remove the jobs of another.
+func (r *scheduleRepository) Delete(
+	ctx context.Context, chatID types.TelegramChatID, id int64,
+) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("telegram_chat_id = ? AND id = ?", chatID, id).
+		Delete(&entity.ScheduledJob{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Reschedule sets the next run of the job, bringing back a one-shot job which already ran.
+func (r *scheduleRepository) Reschedule(ctx context.Context, id int64, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.ScheduledJob{}).
+		Where("id = ?", id).
+		Update("next_run_at", at)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ClaimDue locks the oldest due job and moves it to its next run in the same transaction.
+// SKIP LOCKED lets concurrent instances claim different jobs, the update keeps them from claiming it again.
+func (r *scheduleRepository) ClaimDue(
+	ctx context.Context, now time.Time, next func(job *entity.ScheduledJob) *time.Time,
+) (*entity.ScheduledJob, error) {
+	var job entity.ScheduledJob
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("next_run_at <= ?", now).
+			Order("next_run_at, id").
+			First(&job).Error; err != nil {
+			return err
+		}
+
+		job.NextRunAt, job.LastRunAt = next(&job), &now
+		job.RunCount++
+		return tx.Model(&entity.ScheduledJob{}).
+			Where("id = ?", job.ID).
+			Updates(map[string]any{
+				"next_run_at": job.NextRunAt,
+				"last_run_at": now,
+				"run_count":   job.RunCount,
+			}).Error
+	})
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// RecordResult stores the error of the last run of the job.
+func (r *scheduleRepository) RecordResult(ctx context.Context, id int64, lastError string) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.ScheduledJob{}).
+		Where("id = ?", id).
+		Update("last_error", lastError).Error
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+
+	"go-telegram-bot/internal/domain/entity"
+	"go-telegram-bot/internal/domain/repository"
+	"go-telegram-bot/internal/domain/types"
+
+	"gorm.io/gorm"
+)
+
+type warningRepository struct {
+	db *gorm.DB
+}
+
+// NewWarningRepository creates a new instance of WarningRepository.
+func NewWarningRepository(db *gorm.DB) repository.WarningRepository {
+	return &warningRepository{db: db}
+}
+
+// Add inserts the warning and counts the active warnings of the user in the same transaction.
+func (r *warningRepository) Add(
+	ctx context.Context, warning *entity.Warning,
+) (int, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(warning).Error; err != nil {
+			return err
+		}
+		return tx.Model(&entity.Warning{}).
+			Where("telegram_chat_id = ? AND telegram_user_id = ?", warning.TelegramChatID, warning.TelegramUserID).
+			Count(&count).Error
+	})
+	return int(count), err
+}
+
+// Count returns the number of active warnings of the user in the chat.
+func (r *warningRepository) Count(
+	ctx context.Context, chatID types.TelegramChatID, userID types.TelegramUserID,
+) (int, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entity.Warning{}).
+		Where("telegram_chat_id = ? AND telegram_user_id = ?", chatID, userID).
+		Count(&count).Error
+	return int(count), err
+}
+
+// Clear soft deletes the active warnings of the user in the chat, keeping them for the record.
+func (r *warningRepository) Clear(
+	ctx context.Context, chatID types.TelegramChatID, userID types.TelegramUserID,
+) error {
+	return r.db.WithContext(ctx).
+		Where("telegram_chat_id = ? AND telegram_user_id = ?", chatID, userID).
+		Delete(&entity.Warning{}).Error
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	domainErrors "go-telegram-bot/internal/domain/errors"
+	"go-telegram-bot/internal/domain/repository"
+	domainService "go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+	"go-telegram-bot/internal/shared/metrics"
+	"go-telegram-bot/internal/shared/tracing"
+)
+
+// ChatStateChanges counts the chats deactivated or migrated after a failed send by change
+var ChatStateChanges = metrics.NewCounterVec("change")
+
+func init() {
+	metrics.Default.RegisterCounterVec("telegram_chat_state_changes_total",
+		"Chats deactivated or migrated after a Telegram Bot API send failed.", ChatStateChanges)
+}
+
+// chatStateTracker keeps the stored chats in sync with the failures of outgoing messages:
+// chats that blocked the bot or no longer exist are deactivated, and groups upgraded to
+// supergroups are migrated to their new ID before the send is retried transparently
+type chatStateTracker struct {
+	domainService.TelegramBotService
+	chats  repository.ChatRepository
+	users  repository.UserRepository
+	logger domainService.Logger
+
+	mu       sync.RWMutex
+	migrated map[types.TelegramChatID]types.TelegramChatID
+}
+
+// NewChatStateTracker wraps the bot so that send failures update the chat and user repositories
+func NewChatStateTracker(
+	bot domainService.TelegramBotService,
+	chats repository.ChatRepository,
+	users repository.UserRepository,
+	logger domainService.Logger,
+) domainService.TelegramBotService {
+	return &chatStateTracker{
+		TelegramBotService: bot,
+		chats:              chats,
+		users:              users,
+		logger:             logger,
+		migrated:           make(map[types.TelegramChatID]types.TelegramChatID),
+	}
+}
+
+// Unwrap returns the wrapped bot, for callers needing its concrete capabilities
+func (t *chatStateTracker) Unwrap() domainService.TelegramBotService {
+	return t.TelegramBotService
+}
+
+// SendMessageWithResponse sends a message, following chat migrations
+func (t *chatStateTracker) SendMessageWithResponse(
+	ctx context.Context, request *types.SendMessageRequest,
+) (*types.SendMessageResponse, error) {
+	return track(ctx, t, request.ChatID, func(chatID types.TelegramChatID) (*types.SendMessageResponse, error) {
+		retry := *request
+		retry.ChatID = chatID
+		return t.TelegramBotService.SendMessageWithResponse(ctx, &retry)
+	})
+}
+
+// SendMessageWithRetry sends a message with retries, following chat migrations
+func (t *chatStateTracker) SendMessageWithRetry(
+	ctx context.Context, request *types.SendMessageRequest, maxRetries int,
+) (*types.SendMessageResponse, error) {
+	return track(ctx, t, request.ChatID, func(chatID types.TelegramChatID) (*types.SendMessageResponse, error) {
+		retry := *request
+		retry.ChatID = chatID
+		return t.TelegramBotService.SendMessageWithRetry(ctx, &retry, maxRetries)
+	})
+}
+
+// SendMessages sends the messages in order, each one going through SendMessageWithResponse
+func (t *chatStateTracker) SendMessages(
+	ctx context.Context, requests []*types.SendMessageRequest,
+) ([]*types.SendMessageResponse, error) {
+	return sendBatch(ctx, requests, t.SendMessageWithResponse)
+}
+
+// EditMessageText edits a message, following chat migrations
+func (t *chatStateTracker) EditMessageText(
+	ctx context.Context, request *types.EditMessageTextRequest,
+) (*types.EditMessageTextResponse, error) {
+	if request.ChatID == nil {
+		return t.TelegramBotService.EditMessageText(ctx, request)
+	}
+	return track(ctx, t, *request.ChatID, func(chatID types.TelegramChatID) (*types.EditMessageTextResponse, error) {
+		retry := *request
+		retry.ChatID = &chatID
+		return t.TelegramBotService.EditMessageText(ctx, &retry)
+	})
+}
+
+// ForwardMessage forwards a message, following migrations of the destination chat
+func (t *chatStateTracker) ForwardMessage(
+	ctx context.Context, request *types.ForwardMessageRequest,
+) (*types.ForwardMessageResponse, error) {
+	return track(ctx, t, request.ChatID, func(chatID types.TelegramChatID) (*types.ForwardMessageResponse, error) {
+		retry := *request
+		retry.ChatID = chatID
+		return t.TelegramBotService.ForwardMessage(ctx, &retry)
+	})
+}
+
+// SendPhoto sends a photo, following chat migrations
+func (t *chatStateTracker) SendPhoto(
+	ctx context.Context, request *types.SendPhotoRequest,
+) (*types.SendPhotoResponse, error) {
+	return track(ctx, t, request.ChatID, func(chatID types.TelegramChatID) (*types.SendPhotoResponse, error) {
+		retry := *request
+		retry.ChatID = chatID
+		return t.TelegramBotService.SendPhoto(ctx, &retry)
+	})
+}
+
+// SendDocument sends a document, following chat migrations
+func (t *chatStateTracker) SendDocument(
+	ctx context.Context, request *types.SendDocumentRequest,
+) (*types.SendDocumentResponse, error) {
+	return track(ctx, t, request.ChatID, func(chatID types.TelegramChatID) (*types.SendDocumentResponse, error) {
+		retry := *request
+		retry.ChatID = chatID
+		return t.TelegramBotService.SendDocument(ctx, &retry)
+	})
+}
+
+// track sends to the current ID of the chat and reacts to the failures telling the chat changed
+func track[T any](
+	ctx context.Context, t *chatStateTracker, chatID types.TelegramChatID, send func(types.TelegramChatID) (T, error),
+) (T, error) {
+	chatID = t.currentID(chatID)
+	response, err := send(chatID)
+
+	var apiErr *types.ResponseError
+	if err == nil || !errors.As(err, &apiErr) || apiErr.Response == nil {
+		return response, err
+	}
+
+	switch {
+	case apiErr.Response.IsBotBlocked():
+		t.deactivate(ctx, chatID, "bot_blocked")
+	case apiErr.Response.IsChatNotFound():
+		t.deactivate(ctx, chatID, "chat_not_found")
+	default:
+		if newID, ok := apiErr.Response.MigrateToChatID(); ok {
+			t.migrate(ctx, chatID, newID)
+			return send(newID)
+		}
+	}
+	return response, err
+}
+
+// currentID returns the ID a chat was migrated to, or the ID itself
+func (t *chatStateTracker) currentID(chatID types.TelegramChatID) types.TelegramChatID {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if newID, ok := t.migrated[chatID]; ok {
+		return newID
+	}
+	return chatID
+}
+
+// deactivate marks the chat inactive, and the user too for private chats whose ID is the user ID
+func (t *chatStateTracker) deactivate(ctx context.Context, chatID types.TelegramChatID, reason string) {
+	logger := t.logger.WithContext(ctx)
+	logger.Warn("Chat is unreachable, deactivating it", "chat_id", chatID, "reason", reason)
+	tracing.SpanFromContext(ctx).AddEvent("chat_deactivated",
+		tracing.Int64("telegram.chat_id", int64(chatID)), tracing.String("reason", reason))
+	ChatStateChanges.With("deactivated").Inc()
+
+	if err := t.chats.DeactivateChat(ctx, chatID); err != nil && !errors.Is(err, domainErrors.ErrChatNotFound) {
+		logger.Error("Failed to deactivate chat", "chat_id", chatID, "error", err)
+	}
+	if chatID > 0 {
+		if err := t.users.DeactivateUser(ctx, types.TelegramUserID(chatID)); err != nil &&
+			!errors.Is(err, domainErrors.ErrUserNotFound) {
+			logger.Error("Failed to deactivate user", "user_id", chatID, "error", err)
+		}
+	}
+}
+
+// migrate moves the stored chat to its new ID and remembers it for the next sends
+func (t *chatStateTracker) migrate(ctx context.Context, from, to types.TelegramChatID) {
+	logger := t.logger.WithContext(ctx)
+	logger.Info("Group was upgraded to a supergroup, migrating chat", "from_chat_id", from, "to_chat_id", to)
+	tracing.SpanFromContext(ctx).AddEvent("chat_migrated",
+		tracing.Int64("telegram.chat_id", int64(from)), tracing.Int64("telegram.migrate_to_chat_id", int64(to)))
+	ChatStateChanges.With("migrated").Inc()
+
+	t.mu.Lock()
+	t.migrated[from] = to
+	t.mu.Unlock()
+
+	if err := t.chats.MigrateChatID(ctx, from, to); err != nil {
+		logger.Error("Failed to migrate chat", "from_chat_id", from, "to_chat_id", to, "error", err)
+	}
+}
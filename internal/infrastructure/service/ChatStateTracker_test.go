@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-telegram-bot/internal/domain/repository"
+	domainService "go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+	"go-telegram-bot/internal/infrastructure/config"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...any)                               {}
+func (nopLogger) Info(string, ...any)                                {}
+func (nopLogger) Warn(string, ...any)                                {}
+func (nopLogger) Error(string, ...any)                               {}
+func (nopLogger) Fatal(string, ...any)                               {}
+func (nopLogger) Panic(string, ...any)                               {}
+func (l nopLogger) WithContext(context.Context) domainService.Logger { return l }
+func (l nopLogger) WithField(string, any) domainService.Logger       { return l }
+func (l nopLogger) WithFields(map[string]any) domainService.Logger   { return l }
+
+// recordingChats records the state changes, every other method panics
+type recordingChats struct {
+	repository.ChatRepository
+	deactivated []types.TelegramChatID
+	migrations  [][2]types.TelegramChatID
+}
+
+func (r *recordingChats) DeactivateChat(_ context.Context, chatID types.TelegramChatID) error {
+	r.deactivated = append(r.deactivated, chatID)
+	return nil
+}
+
+func (r *recordingChats) MigrateChatID(_ context.Context, from, to types.TelegramChatID) error {
+	r.migrations = append(r.migrations, [2]types.TelegramChatID{from, to})
+	return nil
+}
+
+type recordingUsers struct {
+	repository.UserRepository
+	deactivated []types.TelegramUserID
+}
+
+func (r *recordingUsers) DeactivateUser(_ context.Context, userID types.TelegramUserID) error {
+	r.deactivated = append(r.deactivated, userID)
+	return nil
+}
+
+// chatStateServer answers like Telegram for a group upgraded to -1001 and a user who blocked the bot
+func chatStateServer(t *testing.T, sentTo *[]types.TelegramChatID) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var request types.SendMessageRequest
+		if err := json.Unmarshal(body, &request); err != nil {
+			t.Errorf("unexpected request body %s", body)
+		}
+		*sentTo = append(*sentTo, request.ChatID)
+
+		switch request.ChatID {
+		case -1:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, `{"ok":false,"error_code":400,"description":"Bad Request: group chat was upgraded to a supergroup chat","parameters":{"migrate_to_chat_id":-1001}}`)
+		case 7:
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = io.WriteString(w, `{"ok":false,"error_code":403,"description":"Forbidden: bot was blocked by the user"}`)
+		default:
+			_, _ = io.WriteString(w, `{"ok":true,"result":{"message_id":1}}`)
+		}
+	}))
+}
+
+func TestChatStateTracker_MigratesAndRetries(t *testing.T) {
+	var sentTo []types.TelegramChatID
+	server := chatStateServer(t, &sentTo)
+	defer server.Close()
+
+	chats, users := &recordingChats{}, &recordingUsers{}
+	bot := NewChatStateTracker(NewTelegramBot(config.ClientConfig{
+		BaseURL:    server.URL,
+		RetryDelay: time.Millisecond,
+		RateLimit:  config.RateLimitConfig{GroupPerMinute: 6000},
+	}, nil, nil), chats, users, nopLogger{})
+
+	for range 2 {
+		if _, err := bot.SendMessageWithResponse(context.Background(), &types.SendMessageRequest{ChatID: -1, Text: "hi"}); err != nil {
+			t.Fatalf("expected the send to succeed against the supergroup, got %v", err)
+		}
+	}
+
+	expected := []types.TelegramChatID{-1, -1001, -1001}
+	if len(sentTo) != len(expected) || sentTo[0] != expected[0] || sentTo[1] != expected[1] || sentTo[2] != expected[2] {
+		t.Fatalf("expected sends to %v, got %v", expected, sentTo)
+	}
+	if len(chats.migrations) != 1 || chats.migrations[0] != [2]types.TelegramChatID{-1, -1001} {
+		t.Fatalf("expected one migration from -1 to -1001, got %v", chats.migrations)
+	}
+}
+
+func TestChatStateTracker_DeactivatesBlockedPrivateChat(t *testing.T) {
+	var sentTo []types.TelegramChatID
+	server := chatStateServer(t, &sentTo)
+	defer server.Close()
+
+	chats, users := &recordingChats{}, &recordingUsers{}
+	bot := NewChatStateTracker(NewTelegramBot(config.ClientConfig{
+		BaseURL:    server.URL,
+		RetryDelay: time.Millisecond,
+		RateLimit:  config.RateLimitConfig{GroupPerMinute: 6000},
+	}, nil, nil), chats, users, nopLogger{})
+
+	if _, err := bot.SendMessageWithResponse(context.Background(), &types.SendMessageRequest{ChatID: 7, Text: "hi"}); err == nil {
+		t.Fatal("expected the blocked send to fail")
+	}
+	if len(chats.deactivated) != 1 || chats.deactivated[0] != 7 {
+		t.Fatalf("expected chat 7 to be deactivated, got %v", chats.deactivated)
+	}
+	if len(users.deactivated) != 1 || users.deactivated[0] != 7 {
+		t.Fatalf("expected user 7 to be deactivated, got %v", users.deactivated)
+	}
+}
+
+func TestChatStateTracker_SendMessagesContinuesAfterFailure(t *testing.T) {
+	var sentTo []types.TelegramChatID
+	server := chatStateServer(t, &sentTo)
+	defer server.Close()
+
+	bot := NewChatStateTracker(NewTelegramBot(config.ClientConfig{
+		BaseURL:    server.URL,
+		RetryDelay: time.Millisecond,
+		RateLimit:  config.RateLimitConfig{GroupPerMinute: 6000},
+	}, nil, nil), &recordingChats{}, &recordingUsers{}, nopLogger{})
+
+	responses, err := bot.SendMessages(context.Background(), []*types.SendMessageRequest{
+		{ChatID: 7, Text: "hi"},
+		{ChatID: -5, Text: "hi"},
+	})
+	if err == nil {
+		t.Fatal("expected the blocked send to be reported")
+	}
+	if len(responses) != 2 || responses[0] != nil || responses[1] == nil {
+		t.Fatalf("expected only the second message to be sent, got %v", responses)
+	}
+	if len(sentTo) != 2 || sentTo[1] != -5 {
+		t.Fatalf("expected the batch to go on after the failure, got %v", sentTo)
+	}
+}
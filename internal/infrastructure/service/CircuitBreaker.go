@@ -0,0 +1,240 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go-telegram-bot/internal/domain/errors"
+	"go-telegram-bot/internal/shared/metrics"
+)
+
+// Defaults used when the circuit breaker configuration is left empty
+const (
+	defaultBreakerFailureThreshold = 5
+	defaultBreakerOpenTimeout      = 30 * time.Second
+)
+
+// BreakerState is the state of a circuit breaker
+type BreakerState string
+
+const (
+	// BreakerClosed lets every request through
+	BreakerClosed BreakerState = "closed"
+	// BreakerOpen rejects requests until the open timeout elapses
+	BreakerOpen BreakerState = "open"
+	// BreakerHalfOpen lets a single probe request through
+	BreakerHalfOpen BreakerState = "half_open"
+)
+
+// CircuitOpenError is returned when a request is rejected by an open circuit breaker
+type CircuitOpenError struct {
+	Method  string
+	RetryAt time.Time
+}
+
+// Error implements the error interface
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit breaker open for %s until %s", e.Method, e.RetryAt.Format(time.RFC3339))
+}
+
+// GetRetryDelay returns how long until the breaker lets a probe request through
+func (e *CircuitOpenError) GetRetryDelay() time.Duration {
+	return max(time.Until(e.RetryAt), 0)
+}
+
+// Unwrap makes the error match errors.ErrServiceUnavailable
+func (e *CircuitOpenError) Unwrap() error {
+	return errors.ErrServiceUnavailable
+}
+
+// circuitBreaker opens after a number of consecutive failures and probes the upstream after a timeout
+type circuitBreaker struct {
+	mu               sync.Mutex
+	state            BreakerState
+	failures         int
+	openedAt         time.Time
+	probeInFlight    bool
+	failureThreshold int
+	openTimeout      time.Duration
+	now              func() time.Time
+}
+
+// newCircuitBreaker creates a closed circuit breaker
+func newCircuitBreaker(failureThreshold int, openTimeout time.Duration) *circuitBreaker {
+	failureThreshold, openTimeout = breakerSettings(failureThreshold, openTimeout)
+	return &circuitBreaker{
+		state:            BreakerClosed,
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		now:              time.Now,
+	}
+}
+
+// breakerSettings replaces unset settings with the defaults
+func breakerSettings(failureThreshold int, openTimeout time.Duration) (int, time.Duration) {
+	if failureThreshold <= 0 {
+		failureThreshold = defaultBreakerFailureThreshold
+	}
+	if openTimeout <= 0 {
+		openTimeout = defaultBreakerOpenTimeout
+	}
+	return failureThreshold, openTimeout
+}
+
+// Allow reports whether a request may proceed and returns the time the breaker retries otherwise
+func (cb *circuitBreaker) Allow() (bool, time.Time) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case BreakerOpen:
+		retryAt := cb.openedAt.Add(cb.openTimeout)
+		if cb.now().Before(retryAt) {
+			return false, retryAt
+		}
+		cb.state = BreakerHalfOpen
+		cb.probeInFlight = true
+		return true, time.Time{}
+	case BreakerHalfOpen:
+		// Only one probe at a time, everyone else waits for its outcome
+		if cb.probeInFlight {
+			return false, cb.now().Add(time.Second)
+		}
+		cb.probeInFlight = true
+		return true, time.Time{}
+	default:
+		return true, time.Time{}
+	}
+}
+
+// Success records a request that reached the upstream and closes the breaker
+func (cb *circuitBreaker) Success() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.state = BreakerClosed
+	cb.failures = 0
+	cb.probeInFlight = false
+}
+
+// Failure records a network or server error and reports whether the breaker just opened
+func (cb *circuitBreaker) Failure() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.probeInFlight = false
+	cb.failures++
+
+	if cb.state == BreakerHalfOpen || (cb.state == BreakerClosed && cb.failures >= cb.failureThreshold) {
+		cb.state = BreakerOpen
+		cb.openedAt = cb.now()
+		return true
+	}
+	return false
+}
+
+// Release gives back a probe slot when the request ended without reaching the upstream,
+// for example because the caller's context was cancelled
+func (cb *circuitBreaker) Release() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.probeInFlight = false
+}
+
+// State returns the current state without changing it
+func (cb *circuitBreaker) State() BreakerState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.state
+}
+
+// breakerSet keeps one circuit breaker per API method
+type breakerSet struct {
+	mu               sync.Mutex
+	breakers         map[string]*circuitBreaker
+	failureThreshold int
+	openTimeout      time.Duration
+}
+
+// newBreakerSet creates an empty set sharing the given settings
+func newBreakerSet(failureThreshold int, openTimeout time.Duration) *breakerSet {
+	return &breakerSet{
+		breakers:         make(map[string]*circuitBreaker),
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+	}
+}
+
+// configure changes the settings of new and existing breakers without resetting their state
+func (s *breakerSet) configure(failureThreshold int, openTimeout time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failureThreshold = failureThreshold
+	s.openTimeout = openTimeout
+
+	failureThreshold, openTimeout = breakerSettings(failureThreshold, openTimeout)
+	for _, breaker := range s.breakers {
+		breaker.mu.Lock()
+		breaker.failureThreshold = failureThreshold
+		breaker.openTimeout = openTimeout
+		breaker.mu.Unlock()
+	}
+}
+
+// get returns the breaker for the method, creating it on first use
+func (s *breakerSet) get(method string) *circuitBreaker {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	breaker, ok := s.breakers[method]
+	if !ok {
+		breaker = newCircuitBreaker(s.failureThreshold, s.openTimeout)
+		s.breakers[method] = breaker
+	}
+	return breaker
+}
+
+// states returns the state of every breaker keyed by method
+func (s *breakerSet) states() map[string]BreakerState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	states := make(map[string]BreakerState, len(s.breakers))
+	for method, breaker := range s.breakers {
+		states[method] = breaker.State()
+	}
+	return states
+}
+
+// register exposes the breaker states as a gauge set to 1 for the current state of each method
+func (s *breakerSet) register(reg *metrics.Registry) {
+	reg.RegisterFunc("telegram_api_circuit_breaker_state",
+		"Current circuit breaker state per Telegram Bot API method.",
+		metrics.TypeGauge, []string{"method", "state"},
+		func() []metrics.Sample {
+			states := s.states()
+			methods := make([]string, 0, len(states))
+			for method := range states {
+				methods = append(methods, method)
+			}
+			sort.Strings(methods)
+
+			samples := make([]metrics.Sample, 0, len(methods)*3)
+			for _, method := range methods {
+				for _, state := range []BreakerState{BreakerClosed, BreakerOpen, BreakerHalfOpen} {
+					value := 0.0
+					if states[method] == state {
+						value = 1
+					}
+					samples = append(samples, metrics.Sample{
+						LabelValues: []string{method, string(state)},
+						Value:       value,
+					})
+				}
+			}
+			return samples
+		})
+}
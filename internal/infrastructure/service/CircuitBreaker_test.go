@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	domainErrors "go-telegram-bot/internal/domain/errors"
+	"go-telegram-bot/internal/infrastructure/config"
+)
+
+func TestCircuitBreaker_OpensAfterThreshold(t *testing.T) {
+	now := time.Now()
+	breaker := newCircuitBreaker(3, time.Minute)
+	breaker.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if breaker.Failure() {
+			t.Fatalf("breaker opened after %d failures", i+1)
+		}
+	}
+	if !breaker.Failure() {
+		t.Fatal("breaker should open on the third failure")
+	}
+	if allowed, _ := breaker.Allow(); allowed {
+		t.Fatal("open breaker should reject requests")
+	}
+
+	now = now.Add(time.Minute)
+	if allowed, _ := breaker.Allow(); !allowed {
+		t.Fatal("breaker should let a probe through after the timeout")
+	}
+	if breaker.State() != BreakerHalfOpen {
+		t.Fatalf("expected half open, got %s", breaker.State())
+	}
+	if allowed, _ := breaker.Allow(); allowed {
+		t.Fatal("only one probe may be in flight")
+	}
+
+	breaker.Success()
+	if breaker.State() != BreakerClosed {
+		t.Fatalf("expected closed after a successful probe, got %s", breaker.State())
+	}
+}
+
+func TestCircuitBreaker_FailedProbeReopens(t *testing.T) {
+	now := time.Now()
+	breaker := newCircuitBreaker(1, time.Second)
+	breaker.now = func() time.Time { return now }
+
+	breaker.Failure()
+	now = now.Add(2 * time.Second)
+	breaker.Allow()
+
+	if !breaker.Failure() {
+		t.Fatal("a failed probe should reopen the breaker")
+	}
+	if breaker.State() != BreakerOpen {
+		t.Fatalf("expected open, got %s", breaker.State())
+	}
+}
+
+func TestTelegramBot_BreakerStopsRetryStorm(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	bot := NewTelegramBot(config.ClientConfig{
+		BaseURL:        server.URL,
+		MaxRetries:     1,
+		RetryDelay:     time.Millisecond,
+		EnableMetrics:  true,
+		CircuitBreaker: config.CircuitBreakerConfig{FailureThreshold: 2, OpenTimeout: time.Minute},
+	}, nil, nil).(*telegramBot)
+
+	if _, err := bot.GetMeWithResponse(context.Background()); err == nil {
+		t.Fatal("expected an error from a failing upstream")
+	}
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("expected the request and one retry, got %d calls", got)
+	}
+
+	_, err := bot.GetMeWithResponse(context.Background())
+	var openErr *CircuitOpenError
+	if !errors.As(err, &openErr) {
+		t.Fatalf("expected a circuit open error, got %v", err)
+	}
+	if !errors.Is(err, domainErrors.ErrServiceUnavailable) {
+		t.Fatal("circuit open error should match ErrServiceUnavailable")
+	}
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("open breaker should not call the upstream, got %d calls", got)
+	}
+	if state := bot.CircuitBreakerStates()["getMe"]; state != BreakerOpen {
+		t.Fatalf("expected getMe breaker to be open, got %s", state)
+	}
+
+	snapshot := bot.GetMetrics()
+	if snapshot.RequestCount != 2 || snapshot.RetryCount != 1 || snapshot.ErrorCount != 2 {
+		t.Fatalf("unexpected metrics %+v", snapshot)
+	}
+	if snapshot.Methods["getMe"].StatusCodes[http.StatusBadGateway] != 2 {
+		t.Fatalf("expected two 502 responses, got %v", snapshot.Methods["getMe"].StatusCodes)
+	}
+}
+
+func TestTelegramBot_MetricsFollowEnableMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	cfg := config.ClientConfig{BaseURL: server.URL, CircuitBreaker: config.CircuitBreakerConfig{FailureThreshold: 10}}
+	bot := NewTelegramBot(cfg, nil, nil).(*telegramBot)
+
+	if _, err := bot.GetMeWithResponse(context.Background()); err == nil {
+		t.Fatal("expected an error from a failing upstream")
+	}
+	if snapshot := bot.GetMetrics(); snapshot.RequestCount != 0 || snapshot.ErrorCount != 0 {
+		t.Fatalf("expected nothing recorded with metrics disabled, got %+v", snapshot)
+	}
+
+	// Turning metrics on by a reload applies to the next request
+	cfg.EnableMetrics = true
+	bot.UpdateConfig(cfg)
+	if _, err := bot.GetMeWithResponse(context.Background()); err == nil {
+		t.Fatal("expected an error from a failing upstream")
+	}
+	if snapshot := bot.GetMetrics(); snapshot.RequestCount != 1 || snapshot.ErrorCount != 1 {
+		t.Fatalf("expected the request to be recorded once enabled, got %+v", snapshot)
+	}
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go-telegram-bot/internal/shared/metrics"
+)
+
+// ClientMetrics tracks API performance metrics, every field is safe for concurrent use
+type ClientMetrics struct {
+	Requests          *metrics.CounterVec   // by method
+	Errors            *metrics.CounterVec   // by method, requests that failed after all retries
+	Retries           *metrics.CounterVec   // by method
+	RateLimitHits     *metrics.CounterVec   // by method
+	BreakerRejections *metrics.CounterVec   // by method
+	StatusCodes       *metrics.CounterVec   // by method and HTTP status, 0 for network errors
+	Latency           *metrics.HistogramVec // by method, one observation per HTTP attempt
+
+	lastRequest atomic.Int64
+}
+
+// MethodMetrics is a point-in-time view of the metrics of a single API method
+type MethodMetrics struct {
+	Requests       uint64         `json:"requests"`
+	Errors         uint64         `json:"errors"`
+	Retries        uint64         `json:"retries"`
+	AverageLatency time.Duration  `json:"average_latency"`
+	StatusCodes    map[int]uint64 `json:"status_codes"`
+}
+
+// ClientMetricsSnapshot is a point-in-time view of the client metrics
+type ClientMetricsSnapshot struct {
+	RequestCount    int64                    `json:"request_count"`
+	ErrorCount      int64                    `json:"error_count"`
+	RetryCount      int64                    `json:"retry_count"`
+	AverageLatency  time.Duration            `json:"average_latency"`
+	LastRequestTime time.Time                `json:"last_request_time"`
+	RateLimitHits   int64                    `json:"rate_limit_hits"`
+	Methods         map[string]MethodMetrics `json:"methods"`
+}
+
+// NewClientMetrics creates an empty set of client metrics
+func NewClientMetrics() *ClientMetrics {
+	return &ClientMetrics{
+		Requests:          metrics.NewCounterVec("method"),
+		Errors:            metrics.NewCounterVec("method"),
+		Retries:           metrics.NewCounterVec("method"),
+		RateLimitHits:     metrics.NewCounterVec("method"),
+		BreakerRejections: metrics.NewCounterVec("method"),
+		StatusCodes:       metrics.NewCounterVec("method", "code"),
+		Latency:           metrics.NewHistogramVec(metrics.DefaultLatencyBuckets, "method"),
+	}
+}
+
+// Register exposes the client metrics in the given registry
+func (m *ClientMetrics) Register(reg *metrics.Registry) {
+	reg.RegisterCounterVec("telegram_api_requests_total",
+		"HTTP attempts made to the Telegram Bot API, including retries.", m.Requests)
+	reg.RegisterCounterVec("telegram_api_errors_total",
+		"Telegram Bot API calls that failed after all retries.", m.Errors)
+	reg.RegisterCounterVec("telegram_api_retries_total",
+		"Retries of Telegram Bot API calls.", m.Retries)
+	reg.RegisterCounterVec("telegram_api_rate_limit_hits_total",
+		"Telegram Bot API responses with HTTP 429.", m.RateLimitHits)
+	reg.RegisterCounterVec("telegram_api_breaker_rejections_total",
+		"Telegram Bot API calls rejected by an open circuit breaker.", m.BreakerRejections)
+	reg.RegisterCounterVec("telegram_api_responses_total",
+		"Telegram Bot API responses by HTTP status code, 0 for network errors.", m.StatusCodes)
+	reg.RegisterHistogramVec("telegram_api_request_duration_seconds",
+		"Latency of single HTTP attempts to the Telegram Bot API.", m.Latency)
+}
+
+// observeAttempt records one HTTP attempt with its status code, 0 meaning no response
+func (m *ClientMetrics) observeAttempt(method string, status int, duration time.Duration) {
+	m.Requests.With(method).Inc()
+	m.StatusCodes.With(method, strconv.Itoa(status)).Inc()
+	m.Latency.With(method).ObserveDuration(duration)
+	m.lastRequest.Store(time.Now().UnixNano())
+}
+
+// Snapshot aggregates the current values into a ClientMetricsSnapshot
+func (m *ClientMetrics) Snapshot() *ClientMetricsSnapshot {
+	snapshot := &ClientMetricsSnapshot{
+		RequestCount:  int64(m.Requests.Total()),
+		ErrorCount:    int64(m.Errors.Total()),
+		RetryCount:    int64(m.Retries.Total()),
+		RateLimitHits: int64(m.RateLimitHits.Total()),
+		Methods:       make(map[string]MethodMetrics),
+	}
+	if last := m.lastRequest.Load(); last != 0 {
+		snapshot.LastRequestTime = time.Unix(0, last)
+	}
+
+	var totalSeconds float64
+	var totalCount uint64
+	m.Latency.Each(func(values []string, h *metrics.Histogram) {
+		method := values[0]
+		totalSeconds += h.Sum()
+		totalCount += h.Count()
+
+		snapshot.Methods[method] = MethodMetrics{
+			Requests:       m.Requests.With(method).Value(),
+			Errors:         m.Errors.With(method).Value(),
+			Retries:        m.Retries.With(method).Value(),
+			AverageLatency: time.Duration(h.Mean() * float64(time.Second)),
+			StatusCodes:    make(map[int]uint64),
+		}
+	})
+	if totalCount > 0 {
+		snapshot.AverageLatency = time.Duration(totalSeconds / float64(totalCount) * float64(time.Second))
+	}
+
+	m.StatusCodes.Each(func(values []string, c *metrics.Counter) {
+		method, code := values[0], values[1]
+		if methodMetrics, ok := snapshot.Methods[method]; ok {
+			status, _ := strconv.Atoi(code)
+			methodMetrics.StatusCodes[status] = c.Value()
+		}
+	})
+
+	return snapshot
+}
+
+// Reset zeroes every metric in place, so a request finishing during the reset still counts
+func (m *ClientMetrics) Reset() {
+	m.Requests.Reset()
+	m.Errors.Reset()
+	m.Retries.Reset()
+	m.RateLimitHits.Reset()
+	m.BreakerRejections.Reset()
+	m.StatusCodes.Reset()
+	m.Latency.Reset()
+	m.lastRequest.Store(0)
+}
+
+// methodLabel turns an endpoint such as "/sendMessage" into the metric label "sendMessage"
+func methodLabel(endpoint string) string {
+	return strings.TrimPrefix(endpoint, "/")
+}
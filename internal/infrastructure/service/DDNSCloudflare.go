@@ -0,0 +1,146 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+
+	domainService "go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/infrastructure/config"
+)
+
+// cloudflareDDNS points the records at the address through the Cloudflare API v4
+type cloudflareDDNS struct {
+	httpClient *http.Client
+	baseURL    string
+	token      string
+	zoneID     string
+	records    []string
+	ttl        int
+}
+
+// cloudflareRecord is the part of a DNS record of the Cloudflare API the updater reads and writes
+type cloudflareRecord struct {
+	ID      string `json:"id,omitempty"`
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Content string `json:"content"`
+	TTL     int    `json:"ttl"`
+	Proxied bool   `json:"proxied"`
+}
+
+// cloudflareResponse is the envelope of every Cloudflare API response
+type cloudflareResponse struct {
+	Success bool            `json:"success"`
+	Errors  []cloudflareErr `json:"errors"`
+	Result  json.RawMessage `json:"result"`
+}
+
+type cloudflareErr struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// NewCloudflareDDNS creates a DDNS provider using the Cloudflare API, httpClient may be nil
+func NewCloudflareDDNS(cfg config.DDNSCloudflare, httpClient *http.Client) domainService.DDNSProvider {
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+	return &cloudflareDDNS{
+		httpClient: httpClient,
+		baseURL:    strings.TrimSuffix(cfg.BaseURL, "/"),
+		token:      cfg.APIToken.Value(),
+		zoneID:     cfg.ZoneID,
+		records:    cfg.Records,
+		ttl:        cfg.TTL,
+	}
+}
+
+// Name identifies the provider
+func (p *cloudflareDDNS) Name() string {
+	return "cloudflare"
+}
+
+// Update points every record at ip, creating the missing ones. Records already pointing at ip are
+// left alone and existing ones keep their proxy setting.
+func (p *cloudflareDDNS) Update(ctx context.Context, ip string) error {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return fmt.Errorf("invalid IP %q: %w", ip, err)
+	}
+	recordType := "A"
+	if addr.Unmap().Is6() {
+		recordType = "AAAA"
+	}
+
+	for _, name := range p.records {
+		if err := p.updateRecord(ctx, recordType, name, addr.Unmap().String()); err != nil {
+			return fmt.Errorf("cloudflare record %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (p *cloudflareDDNS) updateRecord(ctx context.Context, recordType, name, ip string) error {
+	query := url.Values{"type": {recordType}, "name": {name}}
+	var existing []cloudflareRecord
+	if err := p.call(ctx, http.MethodGet, "/dns_records?"+query.Encode(), nil, &existing); err != nil {
+		return err
+	}
+
+	record := cloudflareRecord{Type: recordType, Name: name, Content: ip, TTL: p.ttl}
+	if len(existing) == 0 {
+		return p.call(ctx, http.MethodPost, "/dns_records", record, nil)
+	}
+	if existing[0].Content == ip {
+		return nil
+	}
+	record.Proxied = existing[0].Proxied
+	return p.call(ctx, http.MethodPut, "/dns_records/"+url.PathEscape(existing[0].ID), record, nil)
+}
+
+// call sends a request to the zone and decodes the result into result when it is not nil
+func (p *cloudflareDDNS) call(ctx context.Context, method, path string, body, result any) error {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL+"/zones/"+url.PathEscape(p.zoneID)+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var envelope cloudflareResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&envelope); err != nil {
+		return fmt.Errorf("unexpected response with status %d: %w", resp.StatusCode, err)
+	}
+	if !envelope.Success {
+		messages := make([]string, 0, len(envelope.Errors))
+		for _, e := range envelope.Errors {
+			messages = append(messages, fmt.Sprintf("%s (%d)", e.Message, e.Code))
+		}
+		return fmt.Errorf("%s %s failed with status %d: %s", method, path, resp.StatusCode, strings.Join(messages, ", "))
+	}
+	if result != nil {
+		return json.Unmarshal(envelope.Result, result)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-telegram-bot/internal/infrastructure/config"
+)
+
+// fakeCloudflare is a stand-in for the DNS records API of one zone
+type fakeCloudflare struct {
+	records map[string]*cloudflareRecord
+	writes  []string
+}
+
+func (f *fakeCloudflare) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer token" {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"success":false,"errors":[{"code":10000,"message":"Authentication error"}]}`))
+		return
+	}
+	reply := func(result any) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"success": true, "errors": []any{}, "result": result})
+	}
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/zones/zone/dns_records":
+		found := []*cloudflareRecord{}
+		if record, ok := f.records[r.URL.Query().Get("name")]; ok && record.Type == r.URL.Query().Get("type") {
+			found = append(found, record)
+		}
+		reply(found)
+	case r.Method == http.MethodPost && r.URL.Path == "/zones/zone/dns_records",
+		r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/zones/zone/dns_records/"):
+		var record cloudflareRecord
+		_ = json.NewDecoder(r.Body).Decode(&record)
+		f.writes = append(f.writes, r.Method+" "+record.Name+" "+record.Content)
+		f.records[record.Name] = &record
+		reply(record)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"success":false,"errors":[{"code":7003,"message":"Could not route"}]}`))
+	}
+}
+
+func TestCloudflareDDNS_Update(t *testing.T) {
+	api := &fakeCloudflare{records: map[string]*cloudflareRecord{
+		"home.example.com": {ID: "r1", Type: "A", Name: "home.example.com", Content: "198.51.100.4", Proxied: true},
+		"vpn.example.com":  {ID: "r2", Type: "A", Name: "vpn.example.com", Content: "203.0.113.7"},
+	}}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	cfg := config.DDNSCloudflare{
+		APIToken: "token",
+		ZoneID:   "zone",
+		Records:  []string{"home.example.com", "vpn.example.com", "new.example.com"},
+		TTL:      1,
+		BaseURL:  server.URL,
+	}
+	if err := NewCloudflareDDNS(cfg, server.Client()).Update(context.Background(), "203.0.113.7"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"PUT home.example.com 203.0.113.7", "POST new.example.com 203.0.113.7"}
+	if strings.Join(api.writes, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected the stale record updated and the missing one created, got %v", api.writes)
+	}
+	if !api.records["home.example.com"].Proxied {
+		t.Fatal("expected the updated record to stay proxied")
+	}
+
+	cfg.APIToken = "wrong"
+	err := NewCloudflareDDNS(cfg, server.Client()).Update(context.Background(), "203.0.113.8")
+	if err == nil || !strings.Contains(err.Error(), "Authentication error") {
+		t.Fatalf("expected the API error to be reported, got %v", err)
+	}
+}
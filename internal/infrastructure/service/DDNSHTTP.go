@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"strings"
+
+	domainService "go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/infrastructure/config"
+)
+
+// httpDDNS calls an update URL in the style of DuckDNS or No-IP, {ip} in the URL and the body is
+// replaced with the address
+type httpDDNS struct {
+	httpClient  *http.Client
+	method      string
+	url         string
+	body        string
+	contentType string
+	success     *regexp.Regexp
+}
+
+// NewHTTPDDNS creates a DDNS provider calling a URL template, httpClient may be nil
+func NewHTTPDDNS(cfg config.DDNSHTTP, httpClient *http.Client) (domainService.DDNSProvider, error) {
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+	p := &httpDDNS{
+		httpClient:  httpClient,
+		method:      cfg.Method,
+		url:         cfg.URL.Value(),
+		body:        cfg.Body,
+		contentType: cfg.ContentType,
+	}
+	if cfg.SuccessPattern != "" {
+		success, err := regexp.Compile(cfg.SuccessPattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid success pattern: %w", err)
+		}
+		p.success = success
+	}
+	return p, nil
+}
+
+// Name identifies the provider
+func (p *httpDDNS) Name() string {
+	return "http"
+}
+
+// Update calls the URL with ip. The update succeeds on a 2xx status whose body matches the success
+// pattern when one is set, providers such as DuckDNS answer 200 with KO on failure.
+func (p *httpDDNS) Update(ctx context.Context, ip string) error {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return fmt.Errorf("invalid IP %q: %w", ip, err)
+	}
+	ip = addr.Unmap().String()
+
+	var body io.Reader
+	if p.body != "" {
+		body = strings.NewReader(strings.ReplaceAll(p.body, "{ip}", ip))
+	}
+	req, err := http.NewRequestWithContext(ctx, p.method, strings.ReplaceAll(p.url, "{ip}", url.QueryEscape(ip)), body)
+	if err != nil {
+		// The error of url.Parse quotes the URL, which may hold the token
+		return errors.New("invalid update URL")
+	}
+	if body != nil && p.contentType != "" {
+		req.Header.Set("Content-Type", p.contentType)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		// url.Error quotes the URL, which may hold the token
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("update request to %s failed: %w", req.URL.Host, err)
+	}
+	defer resp.Body.Close()
+
+	answer, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return fmt.Errorf("failed to read the answer of %s: %w", req.URL.Host, err)
+	}
+	text := strings.TrimSpace(string(answer))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s answered status %d: %.200s", req.URL.Host, resp.StatusCode, text)
+	}
+	if p.success != nil && !p.success.MatchString(text) {
+		return fmt.Errorf("%s answered %.200q", req.URL.Host, text)
+	}
+	return nil
+}
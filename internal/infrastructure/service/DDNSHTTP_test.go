@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-telegram-bot/internal/infrastructure/config"
+)
+
+func TestHTTPDDNS_Update(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.RequestURI()+" "+string(body))
+		if r.URL.Query().Get("token") != "secret" {
+			_, _ = w.Write([]byte("KO"))
+			return
+		}
+		_, _ = w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+
+	// DuckDNS answers 200 either way, the pattern tells success from failure
+	duck, err := NewHTTPDDNS(config.DDNSHTTP{
+		Method:         "GET",
+		URL:            config.Secret(server.URL + "/update?domains=home&token=secret&ip={ip}"),
+		SuccessPattern: "^OK",
+	}, server.Client())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := duck.Update(context.Background(), "2001:db8::1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "GET /update?domains=home&token=secret&ip=2001%3Adb8%3A%3A1 "; requests[0] != want {
+		t.Fatalf("expected %q, got %q", want, requests[0])
+	}
+
+	post, _ := NewHTTPDDNS(config.DDNSHTTP{
+		Method:         "POST",
+		URL:            config.Secret(server.URL + "/update?token=wrong"),
+		Body:           "hostname=home&myip={ip}",
+		ContentType:    "application/x-www-form-urlencoded",
+		SuccessPattern: "^OK",
+	}, server.Client())
+	err = post.Update(context.Background(), "203.0.113.7")
+	if err == nil || !strings.Contains(err.Error(), `"KO"`) || strings.Contains(err.Error(), "wrong") {
+		t.Fatalf("expected the answer reported without the URL, got %v", err)
+	}
+	if want := "POST /update?token=wrong hostname=home&myip=203.0.113.7"; requests[1] != want {
+		t.Fatalf("expected %q, got %q", want, requests[1])
+	}
+}
@@ -0,0 +1,469 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net"
+	"net/netip"
+	"strings"
+	"time"
+
+	domainService "go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/infrastructure/config"
+)
+
+// DNS constants used by UPDATE messages, see RFC 1035, RFC 2136 and RFC 8945
+const (
+	dnsOpcodeUpdate = 5
+	dnsTypeA        = 1
+	dnsTypeSOA      = 6
+	dnsTypeAAAA     = 28
+	dnsTypeTSIG     = 250
+	dnsClassIN      = 1
+	dnsClassANY     = 255
+	dnsHeaderSize   = 12
+	dnsRcodeNotAuth = 9
+
+	// tsigFudge is the clock skew in seconds the server tolerates, the value recommended by RFC 8945
+	tsigFudge = 300
+	// dnsTimeout bounds an exchange when ctx has no deadline
+	dnsTimeout = 10 * time.Second
+)
+
+// tsigAlgorithms are the TSIG algorithms supported, keyed by the name used in configuration
+var tsigAlgorithms = map[string]func() hash.Hash{
+	"hmac-sha1":   sha1.New,
+	"hmac-sha256": sha256.New,
+	"hmac-sha512": sha512.New,
+}
+
+var dnsRcodes = map[int]string{
+	1: "FORMERR", 2: "SERVFAIL", 3: "NXDOMAIN", 4: "NOTIMP", 5: "REFUSED",
+	6: "YXDOMAIN", 7: "YXRRSET", 8: "NXRRSET", 9: "NOTAUTH", 10: "NOTZONE",
+}
+
+var tsigErrors = map[int]string{16: "BADSIG", 17: "BADKEY", 18: "BADTIME", 22: "BADTRUNC"}
+
+// rfc2136DDNS replaces the records with a DNS UPDATE message sent to the primary server of the zone,
+// signed with TSIG when a key is configured
+type rfc2136DDNS struct {
+	server    string
+	network   string
+	zone      string
+	records   []string
+	ttl       uint32
+	keyName   string
+	algorithm string
+	hash      func() hash.Hash
+	secret    []byte
+	now       func() time.Time
+}
+
+// NewRFC2136DDNS creates a DDNS provider sending DNS UPDATE messages, the TSIG secret is base64 encoded
+func NewRFC2136DDNS(cfg config.DDNSRFC2136) (domainService.DDNSProvider, error) {
+	p := &rfc2136DDNS{
+		server:  cfg.Server,
+		network: cfg.Network,
+		zone:    fqdn(cfg.Zone),
+		ttl:     uint32(cfg.TTL),
+		now:     time.Now,
+	}
+	for _, record := range cfg.Records {
+		p.records = append(p.records, fqdn(record))
+	}
+	if cfg.KeyName == "" {
+		return p, nil
+	}
+
+	p.hash = tsigAlgorithms[cfg.KeyAlgorithm]
+	if p.hash == nil {
+		return nil, fmt.Errorf("unsupported TSIG algorithm %q", cfg.KeyAlgorithm)
+	}
+	secret, err := base64.StdEncoding.DecodeString(cfg.KeySecret.Value())
+	if err != nil {
+		return nil, fmt.Errorf("invalid TSIG secret: %w", err)
+	}
+	p.keyName, p.algorithm, p.secret = strings.ToLower(fqdn(cfg.KeyName)), cfg.KeyAlgorithm+".", secret
+	return p, nil
+}
+
+// Name identifies the provider
+func (p *rfc2136DDNS) Name() string {
+	return "rfc2136"
+}
+
+// Update replaces the A or AAAA records with ip in a single UPDATE, so they change together
+func (p *rfc2136DDNS) Update(ctx context.Context, ip string) error {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return fmt.Errorf("invalid IP %q: %w", ip, err)
+	}
+	addr = addr.Unmap()
+	rrType := uint16(dnsTypeA)
+	if addr.Is6() {
+		rrType = dnsTypeAAAA
+	}
+
+	request, err := p.updateMessage(rrType, addr.AsSlice())
+	if err != nil {
+		return err
+	}
+	var requestMAC []byte
+	if p.secret != nil {
+		if request, requestMAC, err = p.sign(request); err != nil {
+			return err
+		}
+	}
+
+	response, err := p.exchange(ctx, request)
+	if err != nil {
+		return fmt.Errorf("DNS UPDATE to %s failed: %w", p.server, err)
+	}
+	return p.checkResponse(request, requestMAC, response)
+}
+
+// updateMessage builds the UPDATE deleting the RRset of every record then adding ip to it
+func (p *rfc2136DDNS) updateMessage(rrType uint16, rdata []byte) ([]byte, error) {
+	var id [2]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return nil, err
+	}
+	msg := make([]byte, dnsHeaderSize, 512)
+	copy(msg, id[:])
+	binary.BigEndian.PutUint16(msg[2:], dnsOpcodeUpdate<<11)
+	binary.BigEndian.PutUint16(msg[4:], 1)
+	binary.BigEndian.PutUint16(msg[8:], uint16(2*len(p.records)))
+
+	var err error
+	if msg, err = appendName(msg, p.zone); err != nil {
+		return nil, err
+	}
+	msg = binary.BigEndian.AppendUint16(msg, dnsTypeSOA)
+	msg = binary.BigEndian.AppendUint16(msg, dnsClassIN)
+
+	for _, record := range p.records {
+		// Class ANY with no data deletes the RRset, RFC 2136 section 2.5.2
+		if msg, err = appendName(msg, record); err != nil {
+			return nil, err
+		}
+		msg = binary.BigEndian.AppendUint16(msg, rrType)
+		msg = binary.BigEndian.AppendUint16(msg, dnsClassANY)
+		msg = binary.BigEndian.AppendUint32(msg, 0)
+		msg = binary.BigEndian.AppendUint16(msg, 0)
+
+		msg, _ = appendName(msg, record)
+		msg = binary.BigEndian.AppendUint16(msg, rrType)
+		msg = binary.BigEndian.AppendUint16(msg, dnsClassIN)
+		msg = binary.BigEndian.AppendUint32(msg, p.ttl)
+		msg = binary.BigEndian.AppendUint16(msg, uint16(len(rdata)))
+		msg = append(msg, rdata...)
+	}
+	return msg, nil
+}
+
+// sign appends the TSIG record to the message, it returns the signed message and its MAC
+func (p *rfc2136DDNS) sign(msg []byte) ([]byte, []byte, error) {
+	timeSigned := uint64(p.now().Unix())
+	mac := hmac.New(p.hash, p.secret)
+	mac.Write(msg)
+	variables, err := tsigVariables(p.keyName, p.algorithm, timeSigned, tsigFudge, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	mac.Write(variables)
+	sum := mac.Sum(nil)
+
+	signed, _ := appendName(msg, p.keyName)
+	signed = binary.BigEndian.AppendUint16(signed, dnsTypeTSIG)
+	signed = binary.BigEndian.AppendUint16(signed, dnsClassANY)
+	signed = binary.BigEndian.AppendUint32(signed, 0)
+
+	rdata, _ := appendName(nil, p.algorithm)
+	rdata = appendUint48(rdata, timeSigned)
+	rdata = binary.BigEndian.AppendUint16(rdata, tsigFudge)
+	rdata = binary.BigEndian.AppendUint16(rdata, uint16(len(sum)))
+	rdata = append(rdata, sum...)
+	rdata = append(rdata, msg[0:2]...) // original ID
+	rdata = binary.BigEndian.AppendUint16(rdata, 0)
+	rdata = binary.BigEndian.AppendUint16(rdata, 0)
+
+	signed = binary.BigEndian.AppendUint16(signed, uint16(len(rdata)))
+	signed = append(signed, rdata...)
+	binary.BigEndian.PutUint16(signed[10:], 1)
+	return signed, sum, nil
+}
+
+// exchange sends the message and reads the response, TCP messages are prefixed by their length
+func (p *rfc2136DDNS) exchange(ctx context.Context, msg []byte) ([]byte, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, dnsTimeout)
+		defer cancel()
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, p.network, p.server)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	if p.network == "tcp" {
+		if _, err := conn.Write(binary.BigEndian.AppendUint16(nil, uint16(len(msg)))); err != nil {
+			return nil, err
+		}
+		if _, err := conn.Write(msg); err != nil {
+			return nil, err
+		}
+		var length [2]byte
+		if _, err := io.ReadFull(conn, length[:]); err != nil {
+			return nil, err
+		}
+		response := make([]byte, binary.BigEndian.Uint16(length[:]))
+		_, err := io.ReadFull(conn, response)
+		return response, err
+	}
+
+	if _, err := conn.Write(msg); err != nil {
+		return nil, err
+	}
+	response := make([]byte, 65535)
+	n, err := conn.Read(response)
+	return response[:n], err
+}
+
+// checkResponse verifies the response answers the request, is signed with the key when the request
+// was, and reports success
+func (p *rfc2136DDNS) checkResponse(request, requestMAC, response []byte) error {
+	if len(response) < dnsHeaderSize || response[0] != request[0] || response[1] != request[1] || response[2]&0x80 == 0 {
+		return fmt.Errorf("DNS UPDATE to %s: malformed response", p.server)
+	}
+	rcode := int(binary.BigEndian.Uint16(response[2:]) & 0xf)
+
+	tsig, err := findTSIG(response)
+	if err != nil {
+		return fmt.Errorf("DNS UPDATE to %s: malformed response: %w", p.server, err)
+	}
+	if p.secret != nil {
+		switch {
+		case tsig != nil && tsig.err != 0:
+			return fmt.Errorf("DNS UPDATE to %s refused the TSIG key: %s", p.server, rcodeName(tsigErrors, tsig.err))
+		case tsig == nil && rcode == dnsRcodeNotAuth:
+			return fmt.Errorf("DNS UPDATE to %s: %s", p.server, rcodeName(dnsRcodes, rcode))
+		case tsig == nil:
+			return fmt.Errorf("DNS UPDATE to %s: response is not signed", p.server)
+		}
+		if err := p.verify(response, requestMAC, tsig); err != nil {
+			return fmt.Errorf("DNS UPDATE to %s: %w", p.server, err)
+		}
+	}
+	if rcode != 0 {
+		return fmt.Errorf("DNS UPDATE to %s: %s", p.server, rcodeName(dnsRcodes, rcode))
+	}
+	return nil
+}
+
+// verify checks the MAC of a response, which covers the MAC of the request, RFC 8945 section 5.3
+func (p *rfc2136DDNS) verify(response, requestMAC []byte, tsig *tsigRecord) error {
+	if tsig.name != p.keyName || tsig.algorithm != p.algorithm {
+		return fmt.Errorf("response signed with another key %s", tsig.name)
+	}
+	unsigned := append([]byte(nil), response[:tsig.offset]...)
+	copy(unsigned[0:2], tsig.originalID[:])
+	binary.BigEndian.PutUint16(unsigned[10:], binary.BigEndian.Uint16(unsigned[10:])-1)
+
+	mac := hmac.New(p.hash, p.secret)
+	mac.Write(binary.BigEndian.AppendUint16(nil, uint16(len(requestMAC))))
+	mac.Write(requestMAC)
+	mac.Write(unsigned)
+	variables, err := tsigVariables(tsig.name, tsig.algorithm, tsig.timeSigned, tsig.fudge, tsig.err)
+	if err != nil {
+		return err
+	}
+	mac.Write(variables)
+	if !hmac.Equal(mac.Sum(nil), tsig.mac) {
+		return errors.New("response signature is invalid")
+	}
+	return nil
+}
+
+// tsigRecord is the TSIG record closing a message, offset is where it starts
+type tsigRecord struct {
+	offset     int
+	name       string
+	algorithm  string
+	timeSigned uint64
+	fudge      uint16
+	mac        []byte
+	originalID [2]byte
+	err        int
+}
+
+// findTSIG returns the TSIG record of the message, nil when it is not signed
+func findTSIG(msg []byte) (*tsigRecord, error) {
+	off := dnsHeaderSize
+	questions := int(binary.BigEndian.Uint16(msg[4:]))
+	records := int(binary.BigEndian.Uint16(msg[6:])) + int(binary.BigEndian.Uint16(msg[8:]))
+	additional := int(binary.BigEndian.Uint16(msg[10:]))
+
+	var err error
+	for range questions {
+		if off, err = skipName(msg, off); err != nil {
+			return nil, err
+		}
+		off += 4
+	}
+	for i := range records + additional {
+		start := off
+		var name string
+		if name, off, err = readName(msg, off); err != nil {
+			return nil, err
+		}
+		if off+10 > len(msg) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		rrType := binary.BigEndian.Uint16(msg[off:])
+		length := int(binary.BigEndian.Uint16(msg[off+8:]))
+		off += 10
+		if off+length > len(msg) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if rrType == dnsTypeTSIG && i == records+additional-1 {
+			return parseTSIG(msg, start, strings.ToLower(name), msg[off:off+length])
+		}
+		off += length
+	}
+	return nil, nil
+}
+
+func parseTSIG(msg []byte, offset int, name string, rdata []byte) (*tsigRecord, error) {
+	algorithm, off, err := readName(rdata, 0)
+	if err != nil {
+		return nil, err
+	}
+	if off+10 > len(rdata) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	tsig := &tsigRecord{offset: offset, name: name, algorithm: strings.ToLower(algorithm)}
+	tsig.timeSigned = uint64(binary.BigEndian.Uint16(rdata[off:]))<<32 | uint64(binary.BigEndian.Uint32(rdata[off+2:]))
+	tsig.fudge = binary.BigEndian.Uint16(rdata[off+6:])
+	macSize := int(binary.BigEndian.Uint16(rdata[off+8:]))
+	off += 10
+	if off+macSize+6 > len(rdata) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	tsig.mac = rdata[off : off+macSize]
+	off += macSize
+	copy(tsig.originalID[:], rdata[off:])
+	tsig.err = int(binary.BigEndian.Uint16(rdata[off+2:]))
+	return tsig, nil
+}
+
+// tsigVariables encodes the fields of the TSIG record the MAC covers besides the message, without other data
+func tsigVariables(keyName, algorithm string, timeSigned uint64, fudge uint16, tsigErr int) ([]byte, error) {
+	variables, err := appendName(nil, keyName)
+	if err != nil {
+		return nil, err
+	}
+	variables = binary.BigEndian.AppendUint16(variables, dnsClassANY)
+	variables = binary.BigEndian.AppendUint32(variables, 0)
+	if variables, err = appendName(variables, algorithm); err != nil {
+		return nil, err
+	}
+	variables = appendUint48(variables, timeSigned)
+	variables = binary.BigEndian.AppendUint16(variables, fudge)
+	variables = binary.BigEndian.AppendUint16(variables, uint16(tsigErr))
+	return binary.BigEndian.AppendUint16(variables, 0), nil
+}
+
+// appendName encodes a fully qualified domain name without compression
+func appendName(msg []byte, name string) ([]byte, error) {
+	if len(name) > 254 {
+		return nil, fmt.Errorf("domain name %q is too long", name)
+	}
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		if label == "" && name != "." {
+			return nil, fmt.Errorf("domain name %q has an empty label", name)
+		}
+		if len(label) > 63 {
+			return nil, fmt.Errorf("domain name %q has a label longer than 63 bytes", name)
+		}
+		if label != "" {
+			msg = append(append(msg, byte(len(label))), label...)
+		}
+	}
+	return append(msg, 0), nil
+}
+
+// readName decodes the domain name at off, following compression pointers, and returns the offset after it
+func readName(msg []byte, off int) (string, int, error) {
+	var labels []string
+	end := -1
+	for jumps := 0; ; {
+		if off >= len(msg) {
+			return "", 0, io.ErrUnexpectedEOF
+		}
+		length := int(msg[off])
+		switch {
+		case length == 0:
+			if end < 0 {
+				end = off + 1
+			}
+			return strings.Join(labels, ".") + ".", end, nil
+		case length&0xc0 == 0xc0:
+			if off+1 >= len(msg) {
+				return "", 0, io.ErrUnexpectedEOF
+			}
+			if jumps++; jumps > 32 {
+				return "", 0, errors.New("compression loop")
+			}
+			if end < 0 {
+				end = off + 2
+			}
+			off = int(binary.BigEndian.Uint16(msg[off:]) & 0x3fff)
+		default:
+			if off+1+length > len(msg) {
+				return "", 0, io.ErrUnexpectedEOF
+			}
+			labels = append(labels, string(msg[off+1:off+1+length]))
+			off += 1 + length
+		}
+	}
+}
+
+func skipName(msg []byte, off int) (int, error) {
+	_, end, err := readName(msg, off)
+	return end, err
+}
+
+func appendUint48(b []byte, v uint64) []byte {
+	return append(b, byte(v>>40), byte(v>>32), byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+func rcodeName(names map[int]string, code int) string {
+	if name, ok := names[code]; ok {
+		return name
+	}
+	return fmt.Sprintf("code %d", code)
+}
+
+// fqdn adds the final dot of a fully qualified domain name
+func fqdn(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"go-telegram-bot/internal/infrastructure/config"
+)
+
+var tsigSecret = []byte("secret-key-material")
+
+// tsigTestVariables encodes the TSIG variables of the ddns-key. hmac-sha256 key by hand
+func tsigTestVariables(timeSigned []byte, tsigErr uint16) []byte {
+	variables := []byte("\x08ddns-key\x00\x00\xff\x00\x00\x00\x00\x0bhmac-sha256\x00")
+	variables = append(variables, timeSigned...)
+	variables = binary.BigEndian.AppendUint16(variables, tsigFudge)
+	variables = binary.BigEndian.AppendUint16(variables, tsigErr)
+	return append(variables, 0, 0)
+}
+
+// serveDNSUpdates is a stand-in primary server: it checks the signature of every UPDATE, records the
+// update section and answers with rcode, signed with the same key
+func serveDNSUpdates(t *testing.T, rcode uint16) (string, chan []byte) {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen on UDP: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	updates := make(chan []byte, 4)
+
+	go func() {
+		buf := make([]byte, 65535)
+		for {
+			n, from, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			request := append([]byte(nil), buf[:n]...)
+			tsig, err := findTSIG(request)
+			if err != nil || tsig == nil {
+				continue
+			}
+			unsigned := append([]byte(nil), request[:tsig.offset]...)
+			binary.BigEndian.PutUint16(unsigned[10:], 0)
+			timeSigned := appendUint48(nil, tsig.timeSigned)
+
+			mac := hmac.New(sha256.New, tsigSecret)
+			mac.Write(unsigned)
+			mac.Write(tsigTestVariables(timeSigned, 0))
+			responseCode, tsigErr, responseMAC := rcode, uint16(0), []byte(nil)
+			if !hmac.Equal(mac.Sum(nil), tsig.mac) {
+				// A bad signature is answered unsigned, RFC 8945 section 5.2.2
+				responseCode, tsigErr = dnsRcodeNotAuth, 16
+			} else {
+				updates <- unsigned
+			}
+
+			response := make([]byte, dnsHeaderSize)
+			copy(response, request[:2])
+			binary.BigEndian.PutUint16(response[2:], 0x8000|dnsOpcodeUpdate<<11|responseCode)
+			if tsigErr == 0 {
+				mac := hmac.New(sha256.New, tsigSecret)
+				mac.Write(binary.BigEndian.AppendUint16(nil, uint16(len(tsig.mac))))
+				mac.Write(tsig.mac)
+				mac.Write(response)
+				mac.Write(tsigTestVariables(timeSigned, 0))
+				responseMAC = mac.Sum(nil)
+			}
+			binary.BigEndian.PutUint16(response[10:], 1)
+			rdata := append([]byte("\x0bhmac-sha256\x00"), timeSigned...)
+			rdata = binary.BigEndian.AppendUint16(rdata, tsigFudge)
+			rdata = binary.BigEndian.AppendUint16(rdata, uint16(len(responseMAC)))
+			rdata = append(rdata, responseMAC...)
+			rdata = append(rdata, request[:2]...)
+			rdata = binary.BigEndian.AppendUint16(rdata, tsigErr)
+			rdata = binary.BigEndian.AppendUint16(rdata, 0)
+			response = append(response, "\x08ddns-key\x00\x00\xfa\x00\xff\x00\x00\x00\x00"...)
+			response = binary.BigEndian.AppendUint16(response, uint16(len(rdata)))
+			response = append(response, rdata...)
+			_, _ = conn.WriteTo(response, from)
+		}
+	}()
+	return conn.LocalAddr().String(), updates
+}
+
+func newTestRFC2136(t *testing.T, server string, secret []byte) *rfc2136DDNS {
+	t.Helper()
+	provider, err := NewRFC2136DDNS(config.DDNSRFC2136{
+		Server:       server,
+		Network:      "udp",
+		Zone:         "example.com",
+		Records:      []string{"home.example.com"},
+		TTL:          300,
+		KeyName:      "DDNS-Key",
+		KeyAlgorithm: "hmac-sha256",
+		KeySecret:    config.Secret(base64.StdEncoding.EncodeToString(secret)),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return provider.(*rfc2136DDNS)
+}
+
+func TestRFC2136DDNS_Update(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	server, updates := serveDNSUpdates(t, 0)
+
+	if err := newTestRFC2136(t, server, tsigSecret).Update(ctx, "203.0.113.7"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	update := <-updates
+	if counts := update[4:12]; !bytes.Equal(counts, []byte{0, 1, 0, 0, 0, 2, 0, 0}) {
+		t.Fatalf("expected one zone and two updates, got % x", counts)
+	}
+	// The RRset is deleted with class ANY, then the address is added with class IN
+	record := "\x04home\x07example\x03com\x00"
+	want := record + "\x00\x01\x00\xff\x00\x00\x00\x00\x00\x00" +
+		record + "\x00\x01\x00\x01\x00\x00\x01\x2c\x00\x04\xcb\x00\x71\x07"
+	if !strings.HasSuffix(string(update), want) {
+		t.Fatalf("unexpected update section % x", update)
+	}
+
+	err := newTestRFC2136(t, server, []byte("another key")).Update(ctx, "2001:db8::1")
+	if err == nil || !strings.Contains(err.Error(), "BADSIG") {
+		t.Fatalf("expected the wrong key to be reported, got %v", err)
+	}
+
+	refused, _ := serveDNSUpdates(t, 5)
+	err = newTestRFC2136(t, refused, tsigSecret).Update(ctx, "203.0.113.7")
+	if err == nil || !strings.Contains(err.Error(), "REFUSED") {
+		t.Fatalf("expected the refusal to be reported, got %v", err)
+	}
+}
+
+func TestRFC2136DDNS_SignKnownVector(t *testing.T) {
+	p := newTestRFC2136(t, "127.0.0.1:53", tsigSecret)
+	p.now = func() time.Time { return time.Unix(1760000000, 0) }
+	msg := []byte("\x12\x34\x28\x00\x00\x01\x00\x00\x00\x00\x00\x00\x07example\x03com\x00\x00\x06\x00\x01")
+
+	signed, mac, err := p.sign(msg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Computed independently with Python's hmac module over the message and the TSIG variables
+	if got := hex.EncodeToString(mac); got != "96460134845d51a379239e370ee5ff5107ae333b1302adbce583476f9659be03" {
+		t.Fatalf("unexpected MAC %s", got)
+	}
+	if tsig, err := findTSIG(signed); err != nil || tsig == nil || tsig.name != "ddns-key." || !bytes.Equal(tsig.mac, mac) {
+		t.Fatalf("expected the TSIG record appended, got %+v %v", tsig, err)
+	}
+}
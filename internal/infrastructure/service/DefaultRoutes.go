@@ -0,0 +1,100 @@
+package service
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Routing tables of Linux, other systems have neither and report no default route
+const (
+	procIPv4Routes = "/proc/net/route"
+	procIPv6Routes = "/proc/net/ipv6_route"
+)
+
+// Route flags of the kernel, see include/uapi/linux/route.h and ipv6_route.h
+const (
+	rtfUp     = 0x0001
+	rtfReject = 0x0200
+)
+
+// defaultRoutes maps the interfaces with a default route to the gateways of those routes, an
+// interface reaching the next hop directly, such as a VPN tunnel, has no gateway
+func defaultRoutes() map[string][]string {
+	routes := make(map[string][]string)
+	for _, table := range []struct {
+		path  string
+		parse func(io.Reader, map[string][]string)
+	}{
+		{procIPv4Routes, parseIPv4Routes},
+		{procIPv6Routes, parseIPv6Routes},
+	} {
+		file, err := os.Open(table.path)
+		if err != nil {
+			continue
+		}
+		table.parse(file, routes)
+		file.Close()
+	}
+	return routes
+}
+
+// addDefaultRoute records a default route through iface, the interface is a key of routes even
+// without a gateway
+func addDefaultRoute(routes map[string][]string, iface string, gateway netip.Addr) {
+	gateways := routes[iface]
+	if !gateway.IsUnspecified() {
+		gateways = append(gateways, gateway.String())
+	}
+	routes[iface] = gateways
+}
+
+// parseIPv4Routes reads /proc/net/route: a header then Iface, Destination, Gateway, Flags, RefCnt, Use,
+// Metric and Mask, addresses in hexadecimal in host byte order
+func parseIPv4Routes(r io.Reader, routes map[string][]string) {
+	scanner := bufio.NewScanner(r)
+	scanner.Scan()
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 8 || fields[1] != "00000000" || fields[7] != "00000000" {
+			continue
+		}
+		flags, err := strconv.ParseUint(fields[3], 16, 32)
+		if err != nil || flags&rtfUp == 0 || flags&rtfReject != 0 {
+			continue
+		}
+		gateway, err := strconv.ParseUint(fields[2], 16, 32)
+		if err != nil {
+			continue
+		}
+		var addr [4]byte
+		binary.LittleEndian.PutUint32(addr[:], uint32(gateway))
+		addDefaultRoute(routes, fields[0], netip.AddrFrom4(addr))
+	}
+}
+
+// parseIPv6Routes reads /proc/net/ipv6_route: destination, prefix length, source, source prefix length,
+// next hop, metric, reference count, use count, flags and interface, without a header
+func parseIPv6Routes(r io.Reader, routes map[string][]string) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || fields[0] != strings.Repeat("0", 32) || fields[1] != "00" {
+			continue
+		}
+		flags, err := strconv.ParseUint(fields[8], 16, 32)
+		if err != nil || flags&rtfUp == 0 || flags&rtfReject != 0 {
+			continue
+		}
+		nextHop, err := hex.DecodeString(fields[4])
+		if err != nil || len(nextHop) != 16 {
+			continue
+		}
+		addDefaultRoute(routes, fields[9], netip.AddrFrom16([16]byte(nextHop)))
+	}
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"go-telegram-bot/internal/domain/entity"
+)
+
+func TestParseDefaultRoutes(t *testing.T) {
+	routes := make(map[string][]string)
+	parseIPv4Routes(strings.NewReader(
+		"Iface\tDestination\tGateway \tFlags\tRefCnt\tUse\tMetric\tMask\t\tMTU\tWindow\tIRTT\n"+
+			"eth0\t00000000\t0101A8C0\t0003\t0\t0\t100\t00000000\t0\t0\t0\n"+
+			"eth0\t0001A8C0\t00000000\t0001\t0\t0\t100\t00FFFFFF\t0\t0\t0\n"+
+			"wg0\t00000000\t00000000\t0001\t0\t0\t200\t00000000\t0\t0\t0\n"+
+			"eth1\t00000000\t0100000A\t0000\t0\t0\t0\t00000000\t0\t0\t0\n",
+	), routes)
+	parseIPv6Routes(strings.NewReader(
+		"fe800000000000000000000000000000 40 00000000000000000000000000000000 00 00000000000000000000000000000000 00000100 00000002 00000000 00000001     eth0\n"+
+			"00000000000000000000000000000000 00 00000000000000000000000000000000 00 fe800000000000000000000000000001 00000400 00000001 00000000 00000003     eth0\n"+
+			"00000000000000000000000000000000 00 00000000000000000000000000000000 00 00000000000000000000000000000000 ffffffff 00000001 00000000 00200200       lo\n",
+	), routes)
+
+	want := map[string][]string{
+		"eth0": {"192.168.1.1", "fe80::1"},
+		"wg0":  nil,
+	}
+	if !reflect.DeepEqual(routes, want) {
+		t.Fatalf("expected %v, got %v", want, routes)
+	}
+}
+
+func TestLocalAddress(t *testing.T) {
+	interfaces := []entity.NetworkInterface{
+		{Name: "lo", Up: true, Loopback: true, Addresses: []string{"127.0.0.1/8", "::1/128"}},
+		{Name: "docker0", Up: true, Addresses: []string{"172.17.0.1/16"}},
+		{Name: "eth0", Up: true, DefaultRoute: true, Addresses: []string{"fe80::1/64", "2001:db8::10/64", "192.168.1.10/24"}},
+		{Name: "eth1", Addresses: []string{"10.0.0.5/24"}},
+	}
+	if ip := localAddress(interfaces); ip != "192.168.1.10" {
+		t.Fatalf("expected the IPv4 address of the default route, got %q", ip)
+	}
+
+	// An IPv6-only host answers its global address, never a link-local one
+	interfaces[1].Up = false
+	interfaces[2].Addresses = interfaces[2].Addresses[:2]
+	if ip := localAddress(interfaces); ip != "2001:db8::10" {
+		t.Fatalf("expected the global IPv6 address, got %q", ip)
+	}
+	if ip := localAddress(interfaces[:1]); ip != "" {
+		t.Fatalf("expected no address on an offline host, got %q", ip)
+	}
+}
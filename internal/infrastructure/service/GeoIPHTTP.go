@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"go-telegram-bot/internal/domain/entity"
+	domainService "go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/infrastructure/config"
+)
+
+// Public endpoints of the HTTP geolocation providers, {ip} is replaced with the address
+const (
+	IPInfoURL = "https://ipinfo.io/{ip}/json"
+	IPAPIURL  = "http://ip-api.com/json/{ip}?fields=status,message,country,countryCode,regionName,city,isp,org,as,reverse"
+)
+
+// httpGeoProvider looks addresses up with a JSON API in the style of ipinfo.io or ip-api.com
+type httpGeoProvider struct {
+	httpClient *http.Client
+	style      string
+	url        string
+	token      string
+}
+
+// ipinfoResponse is the answer of ipinfo.io, org is the AS number followed by its name
+type ipinfoResponse struct {
+	Hostname string `json:"hostname"`
+	City     string `json:"city"`
+	Region   string `json:"region"`
+	Country  string `json:"country"`
+	Org      string `json:"org"`
+	Bogon    bool   `json:"bogon"`
+	Error    *struct {
+		Title   string `json:"title"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// ipAPIResponse is the answer of ip-api.com, which reports failures with status "fail" and a 200
+type ipAPIResponse struct {
+	Status      string `json:"status"`
+	Message     string `json:"message"`
+	Country     string `json:"country"`
+	CountryCode string `json:"countryCode"`
+	RegionName  string `json:"regionName"`
+	City        string `json:"city"`
+	ISP         string `json:"isp"`
+	Org         string `json:"org"`
+	AS          string `json:"as"`
+	Reverse     string `json:"reverse"`
+}
+
+// NewHTTPGeoProvider creates the ipinfo or ip-api provider selected by cfg.Provider, httpClient may be nil
+func NewHTTPGeoProvider(cfg config.GeoIP, httpClient *http.Client) domainService.IPGeoProvider {
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+	p := &httpGeoProvider{
+		httpClient: httpClient,
+		style:      cfg.Provider,
+		url:        cfg.URL,
+		token:      cfg.Token.Value(),
+	}
+	if p.url == "" {
+		p.url = IPInfoURL
+		if p.style == "ip-api" {
+			p.url = IPAPIURL
+		}
+	}
+	return p
+}
+
+// Name identifies the provider
+func (p *httpGeoProvider) Name() string {
+	return p.style
+}
+
+// Lookup asks the API about ip
+func (p *httpGeoProvider) Lookup(ctx context.Context, ip string) (*entity.IPDetails, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.ReplaceAll(p.url, "{ip}", url.PathEscape(ip)), nil)
+	if err != nil {
+		// The error of url.Parse quotes the URL, which may hold a key
+		return nil, errors.New("invalid lookup URL")
+	}
+	req.Header.Set("Accept", "application/json")
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("lookup request to %s failed: %w", req.URL.Host, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the answer of %s: %w", req.URL.Host, err)
+	}
+	if p.style == "ip-api" {
+		return p.ipAPI(resp.StatusCode, body)
+	}
+	return p.ipinfo(resp.StatusCode, body)
+}
+
+func (p *httpGeoProvider) ipinfo(status int, body []byte) (*entity.IPDetails, error) {
+	var answer ipinfoResponse
+	if err := json.Unmarshal(body, &answer); err != nil {
+		return nil, fmt.Errorf("unexpected ipinfo answer with status %d: %.200s", status, body)
+	}
+	if answer.Error != nil {
+		return nil, fmt.Errorf("ipinfo answered status %d: %s: %s", status, answer.Error.Title, answer.Error.Message)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("ipinfo answered status %d", status)
+	}
+	if answer.Bogon {
+		return nil, errors.New("ipinfo has no details of a private or reserved address")
+	}
+
+	asn, org := parseASNumber(answer.Org)
+	return &entity.IPDetails{
+		CountryCode:  answer.Country,
+		Region:       answer.Region,
+		City:         answer.City,
+		ASN:          asn,
+		Organization: org,
+		ReverseDNS:   answer.Hostname,
+	}, nil
+}
+
+func (p *httpGeoProvider) ipAPI(status int, body []byte) (*entity.IPDetails, error) {
+	var answer ipAPIResponse
+	if err := json.Unmarshal(body, &answer); err != nil || status != http.StatusOK {
+		return nil, fmt.Errorf("unexpected ip-api answer with status %d: %.200s", status, body)
+	}
+	if answer.Status != "success" {
+		return nil, fmt.Errorf("ip-api lookup failed: %s", answer.Message)
+	}
+
+	asn, org := parseASNumber(answer.AS)
+	if org == "" {
+		org = answer.Org
+	}
+	if org == "" {
+		org = answer.ISP
+	}
+	return &entity.IPDetails{
+		Country:      answer.Country,
+		CountryCode:  answer.CountryCode,
+		Region:       answer.RegionName,
+		City:         answer.City,
+		ASN:          asn,
+		Organization: org,
+		ReverseDNS:   answer.Reverse,
+	}, nil
+}
+
+// parseASNumber splits "AS7552 Viettel Group" into the number and the name, a value without an AS
+// number is returned as the name
+func parseASNumber(value string) (uint32, string) {
+	number, name, _ := strings.Cut(strings.TrimSpace(value), " ")
+	if !strings.HasPrefix(number, "AS") {
+		return 0, strings.TrimSpace(value)
+	}
+	asn, err := strconv.ParseUint(number[2:], 10, 32)
+	if err != nil {
+		return 0, strings.TrimSpace(value)
+	}
+	return uint32(asn), strings.TrimSpace(name)
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-telegram-bot/internal/domain/entity"
+	"go-telegram-bot/internal/infrastructure/config"
+)
+
+func TestHTTPGeoProvider_Lookup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ipinfo/203.0.113.7/json":
+			if r.Header.Get("Authorization") != "Bearer token" {
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = w.Write([]byte(`{"status":403,"error":{"title":"Unknown token","message":"Please check your token"}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"ip":"203.0.113.7","hostname":"static.vnpt.vn","city":"Hanoi","region":"Hanoi",` +
+				`"country":"VN","org":"AS45899 VNPT Corp"}`))
+		case "/ip-api/2001:db8::1":
+			_, _ = w.Write([]byte(`{"status":"success","country":"Vietnam","countryCode":"VN","regionName":"Ho Chi Minh",` +
+				`"city":"Ho Chi Minh City","isp":"Viettel","org":"","as":"AS7552 Viettel Group","reverse":""}`))
+		default:
+			_, _ = w.Write([]byte(`{"status":"fail","message":"reserved range"}`))
+		}
+	}))
+	defer server.Close()
+
+	ipinfo := NewHTTPGeoProvider(config.GeoIP{Provider: "ipinfo", URL: server.URL + "/ipinfo/{ip}/json", Token: "token"}, server.Client())
+	details, err := ipinfo.Lookup(context.Background(), "203.0.113.7")
+	want := entity.IPDetails{CountryCode: "VN", Region: "Hanoi", City: "Hanoi", ASN: 45899, Organization: "VNPT Corp", ReverseDNS: "static.vnpt.vn"}
+	if err != nil || *details != want {
+		t.Fatalf("expected %+v, got %+v %v", want, details, err)
+	}
+
+	denied := NewHTTPGeoProvider(config.GeoIP{Provider: "ipinfo", URL: server.URL + "/ipinfo/{ip}/json"}, server.Client())
+	if _, err := denied.Lookup(context.Background(), "203.0.113.7"); err == nil || !strings.Contains(err.Error(), "Unknown token") {
+		t.Fatalf("expected the API error to be reported, got %v", err)
+	}
+
+	ipAPI := NewHTTPGeoProvider(config.GeoIP{Provider: "ip-api", URL: server.URL + "/ip-api/{ip}"}, server.Client())
+	details, err = ipAPI.Lookup(context.Background(), "2001:db8::1")
+	want = entity.IPDetails{Country: "Vietnam", CountryCode: "VN", Region: "Ho Chi Minh", City: "Ho Chi Minh City", ASN: 7552, Organization: "Viettel Group"}
+	if err != nil || *details != want {
+		t.Fatalf("expected %+v, got %+v %v", want, details, err)
+	}
+	if _, err := ipAPI.Lookup(context.Background(), "10.0.0.1"); err == nil || !strings.Contains(err.Error(), "reserved range") {
+		t.Fatalf("expected the failure to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+
+	"go-telegram-bot/internal/domain/entity"
+	domainService "go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/infrastructure/config"
+	"go-telegram-bot/internal/shared/mmdb"
+)
+
+// mmdbGeoProvider looks addresses up in MaxMind DB files without network access, the city and ASN
+// databases are separate downloads and either may be missing
+type mmdbGeoProvider struct {
+	city *mmdb.Reader
+	asn  *mmdb.Reader
+}
+
+// NewMMDBGeoProvider loads the databases configured in cfg into memory
+func NewMMDBGeoProvider(cfg config.GeoIP) (domainService.IPGeoProvider, error) {
+	p := &mmdbGeoProvider{}
+	var err error
+	if cfg.CityDB != "" {
+		if p.city, err = mmdb.Open(cfg.CityDB); err != nil {
+			return nil, fmt.Errorf("failed to open the city database: %w", err)
+		}
+	}
+	if cfg.ASNDB != "" {
+		if p.asn, err = mmdb.Open(cfg.ASNDB); err != nil {
+			return nil, fmt.Errorf("failed to open the ASN database: %w", err)
+		}
+	}
+	return p, nil
+}
+
+// Name identifies the provider
+func (p *mmdbGeoProvider) Name() string {
+	return "mmdb"
+}
+
+// Lookup reads the records of ip, names are in English which every database carries
+func (p *mmdbGeoProvider) Lookup(_ context.Context, ip string) (*entity.IPDetails, error) {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return nil, fmt.Errorf("invalid IP %q: %w", ip, err)
+	}
+
+	details := &entity.IPDetails{}
+	found := false
+	for _, db := range []*mmdb.Reader{p.city, p.asn} {
+		if db == nil {
+			continue
+		}
+		record, err := db.Lookup(addr)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", db.Metadata().DatabaseType, err)
+		}
+		if record == nil {
+			continue
+		}
+		found = true
+		// Both kinds of records are read from either file, some vendors combine them
+		str := func(path ...any) string {
+			value, _ := mmdb.Get(record, path...).(string)
+			return value
+		}
+		asn, _ := mmdb.Get(record, "autonomous_system_number").(uint64)
+		details.Merge(entity.IPDetails{
+			Country:      str("country", "names", "en"),
+			CountryCode:  str("country", "iso_code"),
+			Region:       str("subdivisions", 0, "names", "en"),
+			City:         str("city", "names", "en"),
+			ASN:          uint32(asn),
+			Organization: str("autonomous_system_organization"),
+		})
+	}
+	if !found {
+		return nil, errors.New("the databases have no record of the address")
+	}
+	return details, nil
+}
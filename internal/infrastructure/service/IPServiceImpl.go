@@ -1,26 +1,54 @@
 package service
 
 import (
+	"cmp"
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"net/netip"
 	"strings"
+	"sync"
 	"time"
 
 	"go-telegram-bot/internal/domain/entity"
 	domainService "go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/infrastructure/config"
 )
 
 type ipService struct {
-	httpClient *http.Client
-	ipUrls     []string
+	ipUrls []string
+
+	// Clients connecting over one address family, for the public address of each
+	ipv4Client *http.Client
+	ipv6Client *http.Client
+	ipv6Urls   []string
+
+	// Details of public addresses, see GetIPDetails
+	geo        domainService.IPGeoProvider
+	reverseDNS bool
+	timeout    time.Duration
+	cacheTTL   time.Duration
+	lookupAddr func(ctx context.Context, addr string) ([]string, error)
+	now        func() time.Time
+	logger     domainService.Logger
+
+	mu      sync.Mutex
+	details map[string]cachedDetails
 }
 
-// NewIPService creates a new instance of ipService.
-func NewIPService() domainService.IPService {
+// cachedDetails are the details of an address and when they have to be looked up again
+type cachedDetails struct {
+	details entity.IPDetails
+	expires time.Time
+}
+
+// NewIPService creates a new instance of ipService. geo may be nil, the details of the public
+// address then only hold its reverse DNS name when cfg enables it.
+func NewIPService(cfg config.GeoIP, geo domainService.IPGeoProvider, logger domainService.Logger) domainService.IPService {
 	// Create a custom transport with TLS configuration
 	transport := &http.Transport{
 		TLSClientConfig: &tls.Config{
@@ -32,58 +60,167 @@ func NewIPService() domainService.IPService {
 	}
 
 	return &ipService{
-		httpClient: &http.Client{
-			Timeout:   15 * time.Second,
-			Transport: transport,
-		},
-		// Multiple fallback URLs in case one fails
+		ipv4Client: familyClient(transport, "tcp4"),
+		ipv6Client: familyClient(transport, "tcp6"),
+		// Multiple fallback URLs in case one fails, reached over IPv4
 		ipUrls: []string{
 			"https://ipinfo.io/ip",
 			"https://api.ipify.org",
 			"https://icanhazip.com",
 			"http://ipinfo.io/ip", // HTTP fallback for TLS issues
 		},
+		// Services answering over IPv6, the ones above may only have IPv4 addresses
+		ipv6Urls: []string{
+			"https://api6.ipify.org",
+			"https://ipv6.icanhazip.com",
+			"https://v6.ident.me",
+		},
+		geo:        geo,
+		reverseDNS: cfg.ReverseDNS,
+		timeout:    cfg.Timeout,
+		cacheTTL:   cfg.CacheTTL,
+		lookupAddr: net.DefaultResolver.LookupAddr,
+		now:        time.Now,
+		logger:     logger,
+		details:    make(map[string]cachedDetails),
 	}
 }
 
-// GetPublicIP fetches the public IP address of the machine using an external service.
+// familyClient creates a client sharing the settings of transport which only dials network, tcp4 or tcp6
+func familyClient(transport *http.Transport, network string) *http.Client {
+	familyTransport := transport.Clone()
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	familyTransport.DialContext = func(ctx context.Context, _, addr string) (net.Conn, error) {
+		return dialer.DialContext(ctx, network, addr)
+	}
+	return &http.Client{
+		Timeout:   15 * time.Second,
+		Transport: familyTransport,
+	}
+}
+
+// GetLocalIP returns the address of the machine on its network, read from the interfaces so that it
+// works offline. IPv4 is preferred over IPv6 and interfaces with the default route over the others.
 func (s *ipService) GetLocalIP(ctx context.Context) (string, error) {
-	// Connect to a well-known address to determine the local IP address
-	conn, err := net.Dial("udp", "8.8.8.8:80")
+	interfaces, err := s.GetInterfaces(ctx)
 	if err != nil {
-		return "", fmt.Errorf("failed to dial UDP get local ip: %w", err)
+		return "", err
+	}
+	if ip := localAddress(interfaces); ip != "" {
+		return ip, nil
 	}
-	defer conn.Close() // close connection after function returns
+	return "", errors.New("no network interface has a usable address")
+}
 
-	// Extract the local address from the connection
-	localAddr := conn.LocalAddr().(*net.UDPAddr)
-	return localAddr.IP.String(), nil
+// GetInterfaces lists the network interfaces with their addresses, the default routes are read from
+// the routing tables of Linux
+func (s *ipService) GetInterfaces(ctx context.Context) ([]entity.NetworkInterface, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list network interfaces: %w", err)
+	}
+	routes := defaultRoutes()
+
+	interfaces := make([]entity.NetworkInterface, 0, len(ifaces))
+	for _, iface := range ifaces {
+		addrs, err := iface.Addrs()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the addresses of %s: %w", iface.Name, err)
+		}
+		gateways, defaultRoute := routes[iface.Name]
+		networkInterface := entity.NetworkInterface{
+			Name:         iface.Name,
+			MAC:          iface.HardwareAddr.String(),
+			Up:           iface.Flags&net.FlagUp != 0,
+			Loopback:     iface.Flags&net.FlagLoopback != 0,
+			DefaultRoute: defaultRoute,
+			Gateways:     gateways,
+		}
+		for _, addr := range addrs {
+			networkInterface.Addresses = append(networkInterface.Addresses, addr.String())
+		}
+		interfaces = append(interfaces, networkInterface)
+	}
+	return interfaces, nil
+}
+
+// localAddress picks the local address among the interfaces which are up, skipping loopback and
+// link-local addresses, empty when there is none
+func localAddress(interfaces []entity.NetworkInterface) string {
+	best, bestRank := "", -1
+	for _, iface := range interfaces {
+		if !iface.Up || iface.Loopback {
+			continue
+		}
+		for _, address := range iface.Addresses {
+			prefix, err := netip.ParsePrefix(address)
+			if err != nil {
+				continue
+			}
+			addr := prefix.Addr().Unmap()
+			if addr.IsLoopback() || addr.IsLinkLocalUnicast() || addr.IsUnspecified() {
+				continue
+			}
+			rank := 0
+			if iface.DefaultRoute {
+				rank += 2
+			}
+			if addr.Is4() {
+				rank++
+			}
+			if rank > bestRank {
+				best, bestRank = addr.String(), rank
+			}
+		}
+	}
+	return best
 }
 
-// GetPublicIP fetches the public IP address of the machine using an external service.
+// GetPublicIP fetches the public IPv4 address of the machine. The family is forced so that the WAN
+// monitor and DDNS, which compare successive answers, never see it alternate between IPv4 and IPv6.
 func (s *ipService) GetPublicIP(ctx context.Context) (string, error) {
-	var lastErr error
+	return s.GetPublicIPv4(ctx)
+}
+
+// GetPublicIPv4 fetches the public IPv4 address, the services are reached over IPv4 only
+func (s *ipService) GetPublicIPv4(ctx context.Context) (string, error) {
+	return s.fetchFamilyIP(ctx, s.ipv4Client, s.ipUrls, "IPv4", netip.Addr.Is4)
+}
+
+// GetPublicIPv6 fetches the public IPv6 address, the services are reached over IPv6 only
+func (s *ipService) GetPublicIPv6(ctx context.Context) (string, error) {
+	return s.fetchFamilyIP(ctx, s.ipv6Client, s.ipv6Urls, "IPv6", netip.Addr.Is6)
+}
 
-	// Try each URL until one succeeds
-	for _, url := range s.ipUrls {
-		ip, err := s.fetchIPFromURL(ctx, url)
-		if err == nil {
-			return ip, nil
+// fetchFamilyIP tries each URL until one answers an address of the family
+func (s *ipService) fetchFamilyIP(
+	ctx context.Context, client *http.Client, urls []string, family string, isFamily func(netip.Addr) bool,
+) (string, error) {
+	var lastErr error
+	for _, url := range urls {
+		ip, err := s.fetchIPFromURL(ctx, client, url)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		addr, err := netip.ParseAddr(ip)
+		if err != nil || !isFamily(addr.Unmap()) {
+			lastErr = fmt.Errorf("%s answered %.50q, not an %s address", url, ip, family)
+			continue
 		}
-		lastErr = err
+		return addr.Unmap().String(), nil
 	}
-
-	return "", fmt.Errorf("failed to get public IP from all sources, last error: %w", lastErr)
+	return "", fmt.Errorf("failed to get public %s from all sources, last error: %w", family, lastErr)
 }
 
 // fetchIPFromURL attempts to fetch the IP from a specific URL
-func (s *ipService) fetchIPFromURL(ctx context.Context, url string) (string, error) {
+func (s *ipService) fetchIPFromURL(ctx context.Context, client *http.Client, url string) (string, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to create request for %s: %w", url, err)
 	}
 
-	resp, err := s.httpClient.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("failed to perform request to %s: %w", url, err)
 	}
@@ -106,19 +243,94 @@ func (s *ipService) fetchIPFromURL(ctx context.Context, url string) (string, err
 	return publicIP, nil
 }
 
-// GetIPInfo retrieves both the local and public IP addresses and returns them in an IPInfo struct.
+// GetIPInfo retrieves the local and public IP addresses and returns them in an IPInfo struct, with
+// the interfaces and the public address of each family. It fails when neither family reaches out.
 func (s *ipService) GetIPInfo(ctx context.Context) (*entity.IPInfo, error) {
-	localIP, err := s.GetLocalIP(ctx)
+	interfaces, err := s.GetInterfaces(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get local IP: %w", err)
 	}
 
-	publicIP, err := s.GetPublicIP(ctx)
+	// Both families are looked up at once, a single-stack network only has one of them
+	var ipv4, ipv6 string
+	var ipv4Err, ipv6Err error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		ipv4, ipv4Err = s.GetPublicIPv4(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		ipv6, ipv6Err = s.GetPublicIPv6(ctx)
+	}()
+	wg.Wait()
+	if ipv4 == "" && ipv6 == "" {
+		return nil, fmt.Errorf("failed to get public IP: %w", errors.Join(ipv4Err, ipv6Err))
+	}
+	publicIP := cmp.Or(ipv4, ipv6)
+
+	info := entity.NewIPInfo(localAddress(interfaces), publicIP)
+	info.PublicIPv4, info.PublicIPv6, info.Interfaces = ipv4, ipv6, interfaces
+	// The addresses are the answer, missing details only make it shorter
+	if details, err := s.GetIPDetails(ctx, publicIP); err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to look up IP details", "ip", publicIP, "error", err)
+	} else {
+		info.IPDetails = *details
+	}
+	return info, nil
+}
+
+// GetIPDetails looks ip up with the geolocation provider and the reverse DNS. Successful lookups are
+// cached for the configured TTL, the WAN address rarely changes and the free APIs are rate limited.
+func (s *ipService) GetIPDetails(ctx context.Context, ip string) (*entity.IPDetails, error) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
 	if err != nil {
-		return nil, fmt.Errorf("failed to get public IP: %w", err)
+		return nil, fmt.Errorf("invalid IP %q: %w", ip, err)
+	}
+	ip = addr.Unmap().String()
+
+	s.mu.Lock()
+	cached, ok := s.details[ip]
+	s.mu.Unlock()
+	if ok && s.now().Before(cached.expires) {
+		details := cached.details
+		return &details, nil
+	}
+
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
 	}
+	details := entity.IPDetails{}
+	if s.geo != nil {
+		found, err := s.geo.Lookup(ctx, ip)
+		if err != nil {
+			return nil, fmt.Errorf("geolocation by %s failed: %w", s.geo.Name(), err)
+		}
+		details = *found
+	}
+	// A missing PTR record is common, the name the provider may know is kept then
+	if s.reverseDNS {
+		if names, err := s.lookupAddr(ctx, ip); err == nil && len(names) > 0 {
+			details.ReverseDNS = names[0]
+		}
+	}
+	details.ReverseDNS = strings.TrimSuffix(details.ReverseDNS, ".")
 
-	return entity.NewIPInfo(localIP, publicIP), nil
+	if s.cacheTTL > 0 {
+		now := s.now()
+		s.mu.Lock()
+		for cachedIP, entry := range s.details {
+			if !now.Before(entry.expires) {
+				delete(s.details, cachedIP)
+			}
+		}
+		s.details[ip] = cachedDetails{details: details, expires: now.Add(s.cacheTTL)}
+		s.mu.Unlock()
+	}
+	return &details, nil
 }
 
 // ValidateIP validates if the provided string is a valid IP address
@@ -2,12 +2,16 @@ package service
 
 import (
 	"context"
+	"net"
 	"testing"
 	"time"
+
+	"go-telegram-bot/internal/domain/entity"
+	"go-telegram-bot/internal/infrastructure/config"
 )
 
 func TestIPService_GetPublicIP(t *testing.T) {
-	service := NewIPService()
+	service := NewIPService(config.GeoIP{}, nil, nopLogger{})
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
@@ -24,7 +28,7 @@ func TestIPService_GetPublicIP(t *testing.T) {
 }
 
 func TestIPService_GetLocalIP(t *testing.T) {
-	service := NewIPService()
+	service := NewIPService(config.GeoIP{}, nil, nopLogger{})
 	ctx := context.Background()
 
 	localIP, err := service.GetLocalIP(ctx)
@@ -40,7 +44,7 @@ func TestIPService_GetLocalIP(t *testing.T) {
 }
 
 func TestIPService_GetIPInfo(t *testing.T) {
-	service := NewIPService()
+	service := NewIPService(config.GeoIP{}, nil, nopLogger{})
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
@@ -59,3 +63,48 @@ func TestIPService_GetIPInfo(t *testing.T) {
 
 	t.Logf("IP Info - Local: %s, Public: %s", ipInfo.LocalIP, ipInfo.PublicIP)
 }
+
+// countingGeoProvider answers every lookup with the same details and counts them
+type countingGeoProvider struct {
+	lookups int
+}
+
+func (p *countingGeoProvider) Name() string {
+	return "counting"
+}
+
+func (p *countingGeoProvider) Lookup(context.Context, string) (*entity.IPDetails, error) {
+	p.lookups++
+	return &entity.IPDetails{CountryCode: "VN", ASN: 7552, ReverseDNS: "provider.example."}, nil
+}
+
+func TestIPService_GetIPDetails(t *testing.T) {
+	geo := &countingGeoProvider{}
+	s := NewIPService(config.GeoIP{ReverseDNS: true, Timeout: time.Second, CacheTTL: time.Hour}, geo, nopLogger{}).(*ipService)
+	now := time.Date(2026, 10, 19, 6, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	s.lookupAddr = func(_ context.Context, addr string) ([]string, error) {
+		if addr == "203.0.113.7" {
+			return []string{"home.example.com."}, nil
+		}
+		return nil, &net.DNSError{Err: "no such host", IsNotFound: true}
+	}
+
+	details, err := s.GetIPDetails(context.Background(), "::ffff:203.0.113.7")
+	if err != nil || details.ReverseDNS != "home.example.com" || details.ASN != 7552 {
+		t.Fatalf("expected the PTR name over the provider's, got %+v %v", details, err)
+	}
+	if details, _ := s.GetIPDetails(context.Background(), "198.51.100.4"); details.ReverseDNS != "provider.example" {
+		t.Fatalf("expected the provider's name without a PTR record, got %+v", details)
+	}
+
+	_, _ = s.GetIPDetails(context.Background(), "203.0.113.7")
+	if geo.lookups != 2 {
+		t.Fatalf("expected the cached details to be reused, got %d lookups", geo.lookups)
+	}
+	now = now.Add(time.Hour)
+	_, _ = s.GetIPDetails(context.Background(), "203.0.113.7")
+	if geo.lookups != 3 {
+		t.Fatalf("expected expired details to be looked up again, got %d lookups", geo.lookups)
+	}
+}
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	domainService "go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+	"go-telegram-bot/internal/infrastructure/config"
+)
+
+// Default limits documented by Telegram for bots
+const (
+	defaultGlobalPerSecond  = 30.0
+	defaultPrivatePerSecond = 1.0
+	defaultGroupPerMinute   = 20.0
+
+	// idleBucketTTL is how long an unused per-chat bucket is kept in memory
+	idleBucketTTL = 10 * time.Minute
+
+	// maxWaitSlice bounds a single sleep so priority changes are noticed quickly
+	maxWaitSlice = 250 * time.Millisecond
+)
+
+// tokenBucket is a classic token bucket refilled continuously at rate tokens per second
+type tokenBucket struct {
+	tokens       float64
+	capacity     float64
+	rate         float64
+	last         time.Time
+	blockedUntil time.Time
+}
+
+// newTokenBucket creates a full bucket with the given rate and capacity
+func newTokenBucket(rate, capacity float64, now time.Time) *tokenBucket {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &tokenBucket{
+		tokens:   capacity,
+		capacity: capacity,
+		rate:     rate,
+		last:     now,
+	}
+}
+
+// refill adds the tokens accumulated since the last refill
+func (b *tokenBucket) refill(now time.Time) {
+	if now.After(b.last) {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+		b.last = now
+	}
+}
+
+// delay returns how long the caller has to wait before a token is available
+func (b *tokenBucket) delay(now time.Time) time.Duration {
+	b.refill(now)
+
+	if now.Before(b.blockedUntil) {
+		return b.blockedUntil.Sub(now)
+	}
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// take consumes one token, the caller must have checked delay first
+func (b *tokenBucket) take() {
+	b.tokens--
+}
+
+// block empties the bucket and prevents any take until the given time
+func (b *tokenBucket) block(until time.Time) {
+	if until.After(b.blockedUntil) {
+		b.blockedUntil = until
+	}
+	b.tokens = 0
+}
+
+// rateLimiter coordinates outbound sends with a global bucket and one bucket per chat.
+// Interactive sends always go before broadcast sends waiting for the global capacity.
+type rateLimiter struct {
+	mu     sync.Mutex
+	global *tokenBucket
+	chats  map[types.TelegramChatID]*tokenBucket
+	lastGC time.Time
+
+	// globalWaiters counts the interactive sends the global bucket holds back, broadcasts yield to them
+	globalWaiters int
+
+	privateRate float64
+	groupRate   float64
+
+	now func() time.Time
+}
+
+// newRateLimiter creates a rate limiter from the client configuration, falling back to Telegram's defaults
+func newRateLimiter(cfg config.RateLimitConfig) *rateLimiter {
+	globalPerSecond, privatePerSecond, groupPerSecond := rateLimits(cfg)
+
+	now := time.Now()
+	return &rateLimiter{
+		global:      newTokenBucket(globalPerSecond, globalPerSecond, now),
+		chats:       make(map[types.TelegramChatID]*tokenBucket),
+		lastGC:      now,
+		privateRate: privatePerSecond,
+		groupRate:   groupPerSecond,
+		now:         time.Now,
+	}
+}
+
+// rateLimits returns the configured rates in tokens per second, falling back to Telegram's defaults
+func rateLimits(cfg config.RateLimitConfig) (global, private, group float64) {
+	global = cfg.GlobalPerSecond
+	if global <= 0 {
+		global = defaultGlobalPerSecond
+	}
+	private = cfg.PrivatePerSecond
+	if private <= 0 {
+		private = defaultPrivatePerSecond
+	}
+	groupPerMinute := cfg.GroupPerMinute
+	if groupPerMinute <= 0 {
+		groupPerMinute = defaultGroupPerMinute
+	}
+	return global, private, groupPerMinute / 60
+}
+
+// SetLimits changes the rates of the global bucket and of every chat bucket, keeping accumulated tokens
+func (l *rateLimiter) SetLimits(cfg config.RateLimitConfig) {
+	globalPerSecond, privatePerSecond, groupPerSecond := rateLimits(cfg)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.global.refill(now)
+	l.global.rate = globalPerSecond
+	l.global.capacity = max(globalPerSecond, 1)
+
+	l.privateRate = privatePerSecond
+	l.groupRate = groupPerSecond
+	for chatID, bucket := range l.chats {
+		bucket.refill(now)
+		if chatID < 0 {
+			bucket.rate = groupPerSecond
+		} else {
+			bucket.rate = privatePerSecond
+		}
+	}
+}
+
+// waiter is the state of one Wait call
+type waiter struct {
+	priority domainService.SendPriority
+	// onGlobal is set while the global bucket, rather than the chat bucket, holds an interactive send back
+	onGlobal bool
+}
+
+// Wait blocks until a message may be sent to the given chat or the context is done
+func (l *rateLimiter) Wait(ctx context.Context, chatID types.TelegramChatID) error {
+	w := &waiter{priority: domainService.SendPriorityFromContext(ctx)}
+	defer func() {
+		l.mu.Lock()
+		l.setOnGlobal(w, false)
+		l.mu.Unlock()
+	}()
+
+	for {
+		wait := l.reserve(chatID, w)
+		if wait == 0 {
+			return nil
+		}
+		if wait > maxWaitSlice {
+			wait = maxWaitSlice
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve takes a token from both buckets if possible, otherwise it returns the time to wait
+func (l *rateLimiter) reserve(chatID types.TelegramChatID, w *waiter) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.collectIdle(now)
+
+	// Broadcasts yield while an interactive send is waiting for global capacity
+	if w.priority == domainService.SendPriorityBroadcast && l.globalWaiters > 0 {
+		return maxWaitSlice
+	}
+
+	chat := l.chatBucket(chatID, now)
+	globalWait, chatWait := l.global.delay(now), chat.delay(now)
+	if w.priority == domainService.SendPriorityInteractive {
+		// A send held by its own chat, such as a retry_after penalty, does not stop the other chats
+		l.setOnGlobal(w, globalWait > 0 && globalWait >= chatWait)
+	}
+	if wait := max(globalWait, chatWait); wait > 0 {
+		return wait
+	}
+
+	l.global.take()
+	chat.take()
+	return 0
+}
+
+// setOnGlobal records whether the global bucket holds the waiter back, l.mu must be held
+func (l *rateLimiter) setOnGlobal(w *waiter, onGlobal bool) {
+	if w.onGlobal == onGlobal {
+		return
+	}
+	w.onGlobal = onGlobal
+	if onGlobal {
+		l.globalWaiters++
+	} else {
+		l.globalWaiters--
+	}
+}
+
+// Penalize applies a retry_after from a 429 response to the chat, or to the global bucket when no chat is known
+func (l *rateLimiter) Penalize(chatID types.TelegramChatID, retryAfter time.Duration) {
+	if retryAfter <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if chatID == 0 {
+		l.global.block(now.Add(retryAfter))
+		return
+	}
+	l.chatBucket(chatID, now).block(now.Add(retryAfter))
+}
+
+// chatBucket returns the bucket for the chat, creating it with the limit matching the chat kind
+func (l *rateLimiter) chatBucket(chatID types.TelegramChatID, now time.Time) *tokenBucket {
+	bucket, ok := l.chats[chatID]
+	if !ok {
+		// Positive IDs are private chats, groups and channels have negative IDs
+		rate := l.privateRate
+		if chatID < 0 {
+			rate = l.groupRate
+		}
+		bucket = newTokenBucket(rate, 1, now)
+		l.chats[chatID] = bucket
+	}
+	return bucket
+}
+
+// collectIdle drops per-chat buckets that have been unused and unblocked for a while
+func (l *rateLimiter) collectIdle(now time.Time) {
+	if now.Sub(l.lastGC) < idleBucketTTL {
+		return
+	}
+	l.lastGC = now
+
+	for chatID, bucket := range l.chats {
+		if now.Sub(bucket.last) > idleBucketTTL && now.After(bucket.blockedUntil) {
+			delete(l.chats, chatID)
+		}
+	}
+}
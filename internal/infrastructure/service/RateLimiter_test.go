@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domainService "go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+	"go-telegram-bot/internal/infrastructure/config"
+)
+
+func newTestLimiter(now *time.Time) *rateLimiter {
+	limiter := newRateLimiter(config.RateLimitConfig{
+		GlobalPerSecond:  2,
+		PrivatePerSecond: 1,
+		GroupPerMinute:   20,
+	})
+	limiter.now = func() time.Time { return *now }
+	limiter.global.last = *now
+	return limiter
+}
+
+func interactive() *waiter {
+	return &waiter{priority: domainService.SendPriorityInteractive}
+}
+
+func broadcast() *waiter {
+	return &waiter{priority: domainService.SendPriorityBroadcast}
+}
+
+func TestRateLimiter_PerChatBucket(t *testing.T) {
+	now := time.Now()
+	limiter := newTestLimiter(&now)
+	chatID := types.TelegramChatID(42)
+
+	if wait := limiter.reserve(chatID, interactive()); wait != 0 {
+		t.Fatalf("first send should not wait, got %v", wait)
+	}
+	if wait := limiter.reserve(chatID, interactive()); wait <= 0 {
+		t.Fatal("second send to the same private chat should wait")
+	}
+
+	now = now.Add(time.Second)
+	if wait := limiter.reserve(chatID, interactive()); wait != 0 {
+		t.Fatalf("send after refill should not wait, got %v", wait)
+	}
+}
+
+func TestRateLimiter_GroupRate(t *testing.T) {
+	now := time.Now()
+	limiter := newTestLimiter(&now)
+	groupID := types.TelegramChatID(-100123)
+
+	limiter.reserve(groupID, interactive())
+	wait := limiter.reserve(groupID, interactive())
+	if wait < 2*time.Second || wait > 3*time.Second {
+		t.Fatalf("expected about 3s between group messages, got %v", wait)
+	}
+}
+
+func TestRateLimiter_GlobalBucket(t *testing.T) {
+	now := time.Now()
+	limiter := newTestLimiter(&now)
+
+	for chatID := types.TelegramChatID(1); chatID <= 2; chatID++ {
+		if wait := limiter.reserve(chatID, interactive()); wait != 0 {
+			t.Fatalf("send %d should not wait, got %v", chatID, wait)
+		}
+	}
+	if wait := limiter.reserve(3, interactive()); wait <= 0 {
+		t.Fatal("global bucket should be exhausted")
+	}
+}
+
+func TestRateLimiter_Penalize(t *testing.T) {
+	now := time.Now()
+	limiter := newTestLimiter(&now)
+	chatID := types.TelegramChatID(7)
+
+	limiter.Penalize(chatID, 5*time.Second)
+	if wait := limiter.reserve(chatID, interactive()); wait != 5*time.Second {
+		t.Fatalf("expected retry_after to be honoured, got %v", wait)
+	}
+	if wait := limiter.reserve(8, interactive()); wait != 0 {
+		t.Fatalf("other chats should not be penalized, got %v", wait)
+	}
+}
+
+func TestRateLimiter_BroadcastYieldsToInteractive(t *testing.T) {
+	now := time.Now()
+	limiter := newTestLimiter(&now)
+	for chatID := types.TelegramChatID(1); chatID <= 2; chatID++ {
+		limiter.reserve(chatID, broadcast())
+	}
+
+	// The global bucket is empty, the interactive send queues for it
+	waiting := interactive()
+	if wait := limiter.reserve(9, waiting); wait == 0 {
+		t.Fatal("interactive send should wait for the global bucket")
+	}
+	now = now.Add(time.Second)
+	if wait := limiter.reserve(10, broadcast()); wait == 0 {
+		t.Fatal("broadcast should wait while interactive sends are queued")
+	}
+	if wait := limiter.reserve(9, waiting); wait != 0 {
+		t.Fatalf("interactive send should proceed, got %v", wait)
+	}
+	if wait := limiter.reserve(10, broadcast()); wait != 0 {
+		t.Fatalf("broadcast should proceed once the interactive send went, got %v", wait)
+	}
+}
+
+func TestRateLimiter_BroadcastIgnoresInteractiveHeldByChat(t *testing.T) {
+	now := time.Now()
+	limiter := newTestLimiter(&now)
+	limiter.Penalize(7, 30*time.Second)
+
+	// An interactive send penalized in its chat does not stop broadcasts to other chats,
+	// even when the global bucket runs short meanwhile
+	waiting := interactive()
+	if wait := limiter.reserve(7, waiting); wait != 30*time.Second {
+		t.Fatalf("expected the penalty to be honoured, got %v", wait)
+	}
+	for chatID := types.TelegramChatID(8); chatID <= 9; chatID++ {
+		if wait := limiter.reserve(chatID, broadcast()); wait != 0 {
+			t.Fatalf("broadcast to chat %d should not wait, got %v", chatID, wait)
+		}
+	}
+	if wait := limiter.reserve(7, waiting); wait != 30*time.Second {
+		t.Fatalf("expected the penalty to be honoured, got %v", wait)
+	}
+	now = now.Add(time.Second)
+	if wait := limiter.reserve(10, broadcast()); wait != 0 {
+		t.Fatalf("broadcast should not yield to a send held by its chat, got %v", wait)
+	}
+
+	// The same through Wait, with the interactive send blocked for the whole test
+	limiter = newRateLimiter(config.RateLimitConfig{})
+	limiter.Penalize(7, time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = limiter.Wait(ctx, 7) }()
+	time.Sleep(20 * time.Millisecond)
+
+	broadcastCtx, cancelBroadcast := context.WithTimeout(
+		domainService.WithSendPriority(context.Background(), domainService.SendPriorityBroadcast), time.Second)
+	defer cancelBroadcast()
+	if err := limiter.Wait(broadcastCtx, 8); err != nil {
+		t.Fatalf("broadcast should not wait for the penalized chat, got %v", err)
+	}
+}
+
+func TestRateLimiter_WaitHonoursContext(t *testing.T) {
+	limiter := newRateLimiter(config.RateLimitConfig{})
+	chatID := types.TelegramChatID(10)
+	limiter.Penalize(chatID, time.Minute)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := limiter.Wait(ctx, chatID); err != context.DeadlineExceeded {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
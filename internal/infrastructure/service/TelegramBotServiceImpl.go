@@ -4,31 +4,27 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"sync/atomic"
 	"time"
 
 	domainService "go-telegram-bot/internal/domain/service"
 	"go-telegram-bot/internal/domain/types"
 	"go-telegram-bot/internal/infrastructure/config"
+	"go-telegram-bot/internal/shared/metrics"
+	"go-telegram-bot/internal/shared/tracing"
 )
 
 type telegramBot struct {
-	config     config.ClientConfig
+	settings   atomic.Pointer[config.ClientConfig] // replaced as a whole by UpdateConfig
 	httpClient *http.Client
 	logger     domainService.Logger
 	metrics    *ClientMetrics
-}
-
-// ClientMetrics tracks API performance metrics
-type ClientMetrics struct {
-	RequestCount    int64         `json:"request_count"`
-	ErrorCount      int64         `json:"error_count"`
-	RetryCount      int64         `json:"retry_count"`
-	AverageLatency  time.Duration `json:"average_latency"`
-	LastRequestTime time.Time     `json:"last_request_time"`
-	RateLimitHits   int64         `json:"rate_limit_hits"`
+	limiter    *rateLimiter
+	breakers   *breakerSet
 }
 
 // NewTelegramBot creates a new instance of TelegramBotService.
@@ -41,23 +37,23 @@ func NewTelegramBot(
 		}
 	}
 
-	// // Ensure BaseURL includes the bot token
-	// if config.BaseURL != "" && config.Token != "" {
-	// 	// If BaseURL doesn't end with the token, append it
-	// 	if config.BaseURL == "https://api.telegram.org/bot" {
-	// 		config.BaseURL = config.BaseURL + config.Token
-	// 	}
-	// } else if config.Token != "" {
-	// 	// Default BaseURL with token
-	// 	config.BaseURL = "https://api.telegram.org/bot" + config.Token
-	// }
-
-	return &telegramBot{
-		config:     config,
+	bot := &telegramBot{
 		logger:     logger,
 		httpClient: httpClient,
-		metrics:    &ClientMetrics{},
+		metrics:    NewClientMetrics(),
+		limiter:    newRateLimiter(config.RateLimit),
+		breakers: newBreakerSet(
+			config.CircuitBreaker.FailureThreshold, config.CircuitBreaker.OpenTimeout,
+		),
 	}
+
+	bot.settings.Store(&config)
+
+	// Expose client metrics on the admin /metrics endpoint
+	bot.metrics.Register(metrics.Default)
+	bot.breakers.register(metrics.Default)
+
+	return bot
 }
 
 // SendMessageWithResponse sends a message and returns the full response for detailed handling
@@ -71,7 +67,7 @@ func (b *telegramBot) SendMessageWithResponse(
 		return nil, fmt.Errorf("failed to marshal request body: %w", err)
 	}
 
-	response, err := b.makeRequestWithRetry(ctx, "POST", "/sendMessage", requestBody, nil)
+	response, err := b.makeRequestWithRetry(ctx, "POST", "/sendMessage", requestBody, request.ChatID, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -81,10 +77,7 @@ func (b *telegramBot) SendMessageWithResponse(
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
-	// Update metrics
-	b.updateMetrics(startTime, err)
-
-	if b.config.EnableLogging && b.logger != nil {
+	if b.settings.Load().EnableLogging && b.logger != nil {
 		b.logger.Debug("SendMessage completed",
 			"chat_id", request.ChatID,
 			"success", sendResponse.IsSuccess(),
@@ -99,8 +92,6 @@ func (b *telegramBot) SendMessageWithResponse(
 func (b *telegramBot) GetUpdatesWithResponse(
 	ctx context.Context, request *types.GetUpdatesRequest,
 ) (*types.GetUpdatesResponse, error) {
-	startTime := time.Now()
-
 	// Build query parameters
 	params := make(map[string]any)
 	if request.Offset > 0 {
@@ -121,7 +112,7 @@ func (b *telegramBot) GetUpdatesWithResponse(
 		return nil, fmt.Errorf("failed to marshal request body: %w", err)
 	}
 
-	response, err := b.makeRequestWithRetry(ctx, "POST", "/getUpdates", requestBody, nil)
+	response, err := b.makeRequestWithRetry(ctx, "POST", "/getUpdates", requestBody, 0, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -142,9 +133,6 @@ func (b *telegramBot) GetUpdatesWithResponse(
 		}
 	}
 
-	// Update metrics
-	b.updateMetrics(startTime, err)
-
 	return &updatesResponse, nil
 }
 
@@ -152,9 +140,7 @@ func (b *telegramBot) GetUpdatesWithResponse(
 func (b *telegramBot) GetMeWithResponse(
 	ctx context.Context,
 ) (*types.GetMeResponse, error) {
-	startTime := time.Now()
-
-	response, err := b.makeRequestWithRetry(ctx, "GET", "/getMe", nil, nil)
+	response, err := b.makeRequestWithRetry(ctx, "GET", "/getMe", nil, 0, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -175,9 +161,6 @@ func (b *telegramBot) GetMeWithResponse(
 		}
 	}
 
-	// Update metrics
-	b.updateMetrics(startTime, err)
-
 	return &getMeResponse, nil
 }
 
@@ -185,9 +168,7 @@ func (b *telegramBot) GetMeWithResponse(
 func (b *telegramBot) DeleteWebhookWithResponse(
 	ctx context.Context,
 ) (*types.DeleteWebhookResponse, error) {
-	startTime := time.Now()
-
-	response, err := b.makeRequestWithRetry(ctx, "POST", "/deleteWebhook", nil, nil)
+	response, err := b.makeRequestWithRetry(ctx, "POST", "/deleteWebhook", nil, 0, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -208,46 +189,55 @@ func (b *telegramBot) DeleteWebhookWithResponse(
 		}
 	}
 
-	// Update metrics
-	b.updateMetrics(startTime, err)
-
 	return &deleteResponse, nil
 }
 
-// SendMessages sends multiple messages in a batch, respecting rate limits
+// SendMessages sends multiple messages in a batch on the broadcast lane of the rate limiter
 func (b *telegramBot) SendMessages(
 	ctx context.Context, requests []*types.SendMessageRequest,
+) ([]*types.SendMessageResponse, error) {
+	return sendBatch(ctx, requests, b.SendMessageWithResponse)
+}
+
+// sendBatch sends the requests in order on the broadcast lane. A failed message does not stop the
+// batch: its response is nil and its error is joined into the returned one. Cancelling ctx stops it.
+func sendBatch(
+	ctx context.Context,
+	requests []*types.SendMessageRequest,
+	send func(context.Context, *types.SendMessageRequest) (*types.SendMessageResponse, error),
 ) ([]*types.SendMessageResponse, error) {
 	responses := make([]*types.SendMessageResponse, len(requests))
 
+	// Batches must not delay replies to interactive commands
+	ctx = domainService.WithSendPriority(ctx, domainService.SendPriorityBroadcast)
+
+	var errs []error
 	for i, request := range requests {
-		response, err := b.SendMessageWithResponse(ctx, request)
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		response, err := send(ctx, request)
 		if err != nil {
-			return nil, fmt.Errorf("failed to send message to chat_id %v: %w", request.ChatID, err)
+			errs = append(errs, fmt.Errorf("failed to send message to chat_id %v: %w", request.ChatID, err))
+			continue
 		}
 		responses[i] = response
-
-		// Add a small delay between requests to avoid hitting rate limits
-		if i < len(requests)-1 {
-			time.Sleep(b.config.RateLimitDelay)
-		}
 	}
 
-	return responses, nil
+	return responses, errors.Join(errs...)
 }
 
 // SendMessageWithRetry sends a message with retry logic for transient errors
 func (b *telegramBot) SendMessageWithRetry(
 	ctx context.Context, request *types.SendMessageRequest, maxRetries int,
 ) (*types.SendMessageResponse, error) {
-	startTime := time.Now()
-
 	requestBody, err := json.Marshal(request)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request body: %w", err)
 	}
 
-	response, err := b.makeRequestWithRetry(ctx, "POST", "/sendMessage", requestBody, &maxRetries)
+	response, err := b.makeRequestWithRetry(ctx, "POST", "/sendMessage", requestBody, request.ChatID, &maxRetries)
 	if err != nil {
 		return nil, err
 	}
@@ -257,9 +247,6 @@ func (b *telegramBot) SendMessageWithRetry(
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
-	// Update metrics
-	b.updateMetrics(startTime, err)
-
 	return &sendResponse, nil
 }
 
@@ -267,8 +254,6 @@ func (b *telegramBot) SendMessageWithRetry(
 func (b *telegramBot) GetUpdatesWithRetry(
 	ctx context.Context, request *types.GetUpdatesRequest, maxRetries int,
 ) (*types.GetUpdatesResponse, error) {
-	startTime := time.Now()
-
 	// Build query parameters
 	params := make(map[string]any)
 	if request.Offset > 0 {
@@ -289,7 +274,7 @@ func (b *telegramBot) GetUpdatesWithRetry(
 		return nil, fmt.Errorf("failed to marshal request body: %w", err)
 	}
 
-	response, err := b.makeRequestWithRetry(ctx, "POST", "/getUpdates", requestBody, &maxRetries)
+	response, err := b.makeRequestWithRetry(ctx, "POST", "/getUpdates", requestBody, 0, &maxRetries)
 	if err != nil {
 		return nil, err
 	}
@@ -299,9 +284,6 @@ func (b *telegramBot) GetUpdatesWithRetry(
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
-	// Update metrics
-	b.updateMetrics(startTime, err)
-
 	return &updatesResponse, nil
 }
 
@@ -311,9 +293,30 @@ func (b *telegramBot) SetWebhook(ctx context.Context, request *types.SetWebhookR
 	return nil, fmt.Errorf("not implemented")
 }
 
+// GetWebhookInfo returns the current webhook status
 func (b *telegramBot) GetWebhookInfo(ctx context.Context) (*types.GetWebhookInfoResponse, error) {
-	// Implementation here
-	return nil, fmt.Errorf("not implemented")
+	response, err := b.makeRequestWithRetry(ctx, "GET", "/getWebhookInfo", nil, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var webhookResponse types.GetWebhookInfoResponse
+	if err := json.Unmarshal(response, &webhookResponse); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	// Check if the API returned an error even with HTTP 200
+	if webhookResponse.HasError() {
+		return nil, &types.ResponseError{
+			Method:      "/getWebhookInfo",
+			RequestData: map[string]any{},
+			Response:    &webhookResponse.BaseResponse,
+			HTTPStatus:  200,
+			Timestamp:   time.Now(),
+		}
+	}
+
+	return &webhookResponse, nil
 }
 
 func (b *telegramBot) GetFile(ctx context.Context, fileID string) (*types.GetFileResponse, error) {
@@ -326,14 +329,27 @@ func (b *telegramBot) DownloadFile(ctx context.Context, filePath string) ([]byte
 	return nil, fmt.Errorf("not implemented")
 }
 
+// EditMessageText replaces the text and inline keyboard of a message
 func (b *telegramBot) EditMessageText(ctx context.Context, request *types.EditMessageTextRequest) (*types.EditMessageTextResponse, error) {
-	// Implementation here
-	return nil, fmt.Errorf("not implemented")
+	var chatID types.TelegramChatID
+	if request.ChatID != nil {
+		chatID = *request.ChatID
+	}
+	return callAPI[types.TelegramMessage](ctx, b, "/editMessageText", request, chatID)
 }
 
+// DeleteMessage deletes a message, bots may only delete messages of the last 48 hours
 func (b *telegramBot) DeleteMessage(ctx context.Context, chatID types.TelegramChatID, messageID int64) (*types.DeleteMessageResponse, error) {
-	// Implementation here
-	return nil, fmt.Errorf("not implemented")
+	request := map[string]any{"chat_id": chatID, "message_id": messageID}
+	return callAPI[bool](ctx, b, "/deleteMessage", request, 0)
+}
+
+// DeleteMessages deletes up to types.MaxDeleteMessages messages at once, skipping those that cannot be deleted
+func (b *telegramBot) DeleteMessages(ctx context.Context, request *types.DeleteMessagesRequest) (*types.DeleteMessagesResponse, error) {
+	if len(request.MessageIDs) > types.MaxDeleteMessages {
+		return nil, fmt.Errorf("cannot delete more than %d messages at once, got %d", types.MaxDeleteMessages, len(request.MessageIDs))
+	}
+	return callAPI[bool](ctx, b, "/deleteMessages", request, 0)
 }
 
 func (b *telegramBot) ForwardMessage(ctx context.Context, request *types.ForwardMessageRequest) (*types.ForwardMessageResponse, error) {
@@ -356,14 +372,29 @@ func (b *telegramBot) GetChat(ctx context.Context, chatID types.TelegramChatID)
 	return nil, fmt.Errorf("not implemented")
 }
 
+// BanChatMember removes the user from the chat, they cannot return until UntilDate or until unbanned
 func (b *telegramBot) BanChatMember(ctx context.Context, request *types.BanChatMemberRequest) (*types.BanChatMemberResponse, error) {
-	// Implementation here
-	return nil, fmt.Errorf("not implemented")
+	return callAPI[bool](ctx, b, "/banChatMember", request, 0)
 }
 
+// UnbanChatMember lifts the ban of the user, who may join again through a link
 func (b *telegramBot) UnbanChatMember(ctx context.Context, request *types.UnbanChatMemberRequest) (*types.UnbanChatMemberResponse, error) {
-	// Implementation here
-	return nil, fmt.Errorf("not implemented")
+	return callAPI[bool](ctx, b, "/unbanChatMember", request, 0)
+}
+
+// RestrictChatMember replaces the permissions of the user in a supergroup until UntilDate
+func (b *telegramBot) RestrictChatMember(ctx context.Context, request *types.RestrictChatMemberRequest) (*types.RestrictChatMemberResponse, error) {
+	return callAPI[bool](ctx, b, "/restrictChatMember", request, 0)
+}
+
+// PromoteChatMember grants administrator rights to the user, all rights unset demotes them
+func (b *telegramBot) PromoteChatMember(ctx context.Context, request *types.PromoteChatMemberRequest) (*types.PromoteChatMemberResponse, error) {
+	return callAPI[bool](ctx, b, "/promoteChatMember", request, 0)
+}
+
+// SetChatAdministratorCustomTitle sets the title shown next to an administrator promoted by the bot
+func (b *telegramBot) SetChatAdministratorCustomTitle(ctx context.Context, request *types.SetChatAdministratorCustomTitleRequest) (*types.SetChatAdministratorCustomTitleResponse, error) {
+	return callAPI[bool](ctx, b, "/setChatAdministratorCustomTitle", request, 0)
 }
 
 func (b *telegramBot) GetChatMember(ctx context.Context, chatID types.TelegramChatID, userID types.TelegramUserID) (*types.GetChatMemberResponse, error) {
@@ -376,9 +407,39 @@ func (b *telegramBot) GetChatMembersCount(ctx context.Context, chatID types.Tele
 	return nil, fmt.Errorf("not implemented")
 }
 
+// CreateChatInviteLink creates an additional invite link, the bot must be an administrator allowed to invite users
+func (b *telegramBot) CreateChatInviteLink(ctx context.Context, request *types.CreateChatInviteLinkRequest) (*types.CreateChatInviteLinkResponse, error) {
+	return callAPI[types.TelegramChatInviteLink](ctx, b, "/createChatInviteLink", request, 0)
+}
+
+// EditChatInviteLink replaces the settings of an invite link created by the bot
+func (b *telegramBot) EditChatInviteLink(ctx context.Context, request *types.EditChatInviteLinkRequest) (*types.EditChatInviteLinkResponse, error) {
+	return callAPI[types.TelegramChatInviteLink](ctx, b, "/editChatInviteLink", request, 0)
+}
+
+// RevokeChatInviteLink revokes an invite link created by the bot, a revoked primary link is replaced
+func (b *telegramBot) RevokeChatInviteLink(ctx context.Context, request *types.RevokeChatInviteLinkRequest) (*types.RevokeChatInviteLinkResponse, error) {
+	return callAPI[types.TelegramChatInviteLink](ctx, b, "/revokeChatInviteLink", request, 0)
+}
+
+// ExportChatInviteLink replaces the primary invite link of the chat and returns the new one
+func (b *telegramBot) ExportChatInviteLink(ctx context.Context, chatID types.TelegramChatID) (*types.ExportChatInviteLinkResponse, error) {
+	return callAPI[string](ctx, b, "/exportChatInviteLink", map[string]any{"chat_id": chatID}, 0)
+}
+
+// ApproveChatJoinRequest lets the user join the chat
+func (b *telegramBot) ApproveChatJoinRequest(ctx context.Context, request *types.ApproveChatJoinRequestRequest) (*types.ApproveChatJoinRequestResponse, error) {
+	return callAPI[bool](ctx, b, "/approveChatJoinRequest", request, 0)
+}
+
+// DeclineChatJoinRequest rejects the request of the user to join the chat
+func (b *telegramBot) DeclineChatJoinRequest(ctx context.Context, request *types.DeclineChatJoinRequestRequest) (*types.DeclineChatJoinRequestResponse, error) {
+	return callAPI[bool](ctx, b, "/declineChatJoinRequest", request, 0)
+}
+
+// AnswerCallbackQuery acknowledges a pressed inline keyboard button, optionally with a notification
 func (b *telegramBot) AnswerCallbackQuery(ctx context.Context, request *types.AnswerCallbackQueryRequest) (*types.AnswerCallbackQueryResponse, error) {
-	// Implementation here
-	return nil, fmt.Errorf("not implemented")
+	return callAPI[bool](ctx, b, "/answerCallbackQuery", request, 0)
 }
 
 func (b *telegramBot) AnswerInlineQuery(ctx context.Context, request *types.AnswerInlineQueryRequest) (*types.AnswerInlineQueryResponse, error) {
@@ -386,13 +447,79 @@ func (b *telegramBot) AnswerInlineQuery(ctx context.Context, request *types.Answ
 	return nil, fmt.Errorf("not implemented")
 }
 
-// makeRequestWithRetry makes an HTTP request with retry logic for transient errors
+// callAPI posts the request as JSON to the endpoint and decodes the result of type T,
+// turning an error reported with HTTP 200 into a ResponseError. A non-zero chatID is rate limited.
+func callAPI[T any](
+	ctx context.Context, b *telegramBot, endpoint string, request any, chatID types.TelegramChatID,
+) (*types.APIResponse[T], error) {
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	response, err := b.makeRequestWithRetry(ctx, "POST", endpoint, requestBody, chatID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiResponse types.APIResponse[T]
+	if err := json.Unmarshal(response, &apiResponse); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	// Check if the API returned an error even with HTTP 200
+	if apiResponse.HasError() {
+		return nil, &types.ResponseError{
+			Method:      endpoint,
+			RequestData: map[string]any{"body": string(requestBody)},
+			Response:    &apiResponse.BaseResponse,
+			HTTPStatus:  200,
+			Timestamp:   time.Now(),
+		}
+	}
+
+	return &apiResponse, nil
+}
+
+// makeRequestWithRetry makes an HTTP request with retry logic for transient errors.
+// A non-zero chatID marks the request as a send to that chat and subjects it to the rate limiter.
 func (b *telegramBot) makeRequestWithRetry(
-	ctx context.Context, method, endpoint string, body []byte, maxRetries *int,
+	ctx context.Context, method, endpoint string, body []byte, chatID types.TelegramChatID, maxRetries *int,
+) ([]byte, error) {
+	label := methodLabel(endpoint)
+
+	ctx, span := tracing.Start(ctx, "telegram.api "+label,
+		tracing.String("telegram.method", label),
+		tracing.String("http.method", method),
+	)
+	defer span.End()
+	if chatID != 0 {
+		span.SetAttributes(tracing.Int64("telegram.chat_id", int64(chatID)))
+	}
+
+	response, err := b.retryRequest(ctx, span, method, endpoint, body, chatID, maxRetries)
+	span.RecordError(err)
+	return response, err
+}
+
+// retryRequest runs the attempts of makeRequestWithRetry, recording retries as events on span
+func (b *telegramBot) retryRequest(
+	ctx context.Context, span *tracing.Span,
+	method, endpoint string, body []byte, chatID types.TelegramChatID, maxRetries *int,
 ) ([]byte, error) {
+	// Settings may be replaced by a config reload, use one snapshot for every attempt
+	cfg := b.settings.Load()
+
 	// Check if maxRetries is nil or invalid, use default config value
 	if maxRetries == nil || *maxRetries < 0 {
-		maxRetries = &b.config.MaxRetries
+		maxRetries = &cfg.MaxRetries
+	}
+
+	label := methodLabel(endpoint)
+	breaker := b.breakers.get(label)
+	logger := b.logger
+	if logger != nil {
+		logger = logger.WithContext(ctx)
 	}
 
 	// Track the last error to return if all retries fail
@@ -403,10 +530,14 @@ func (b *telegramBot) makeRequestWithRetry(
 
 		// If this is a retry attempt, wait before retrying
 		if attempt > 0 {
-			retryDelay := b.config.RetryDelay * time.Duration(attempt)
-
-			if b.config.EnableLogging && b.logger != nil {
-				b.logger.Debug("Retrying request",
+			retryDelay := cfg.RetryDelay * time.Duration(attempt)
+			span.AddEvent("retry",
+				tracing.Int("attempt", attempt),
+				tracing.String("delay", retryDelay.String()),
+			)
+
+			if cfg.EnableLogging && logger != nil {
+				logger.Debug("Retrying request",
 					"attempt", attempt,
 					"delay", retryDelay,
 					"endpoint", endpoint,
@@ -420,39 +551,92 @@ func (b *telegramBot) makeRequestWithRetry(
 			case <-time.After(retryDelay):
 			}
 
-			b.metrics.RetryCount++
+			if cfg.EnableMetrics {
+				b.metrics.Retries.With(label).Inc()
+			}
+		}
+
+		// Fail fast while the upstream is known to be down instead of adding to a retry storm
+		if allowed, retryAt := breaker.Allow(); !allowed {
+			if cfg.EnableMetrics {
+				b.metrics.BreakerRejections.With(label).Inc()
+				b.metrics.Errors.With(label).Inc()
+			}
+			return nil, &CircuitOpenError{Method: label, RetryAt: retryAt}
+		}
+
+		// Wait for rate limit capacity before every attempt of a chat-bound request
+		if chatID != 0 {
+			if err := b.limiter.Wait(ctx, chatID); err != nil {
+				breaker.Release()
+				return nil, err
+			}
 		}
 
 		// Make the actual request
 		response, err := b.makeRequest(ctx, method, endpoint, body)
 		if err == nil {
+			breaker.Success()
 			return response, nil
 		}
 
 		lastErr = err
+		span.AddEvent("attempt_failed", tracing.Int("attempt", attempt), tracing.String("error", err.Error()))
 
 		// check if error retryable
-		if respErr, ok := err.(*types.ResponseError); ok {
-			if !respErr.ShouldRetry() {
-				break
+		respErr, ok := err.(*types.ResponseError)
+		if !ok {
+			breaker.Release()
+			break
+		}
+
+		switch {
+		case ctx.Err() != nil:
+			// The caller gave up, this says nothing about Telegram's health
+			breaker.Release()
+			return nil, ctx.Err()
+		case respErr.IsNetworkFailure() || respErr.IsServerError():
+			if breaker.Failure() && logger != nil {
+				logger.Warn("Circuit breaker opened",
+					"method", label,
+					"http_status", respErr.HTTPStatus,
+					"error", respErr,
+				)
 			}
+		default:
+			// Any other answer proves the API is reachable
+			breaker.Success()
+		}
 
-			// Handle rate limiting (HTTP 429)
-			if respErr.Response != nil && respErr.Response.IsRateLimited() {
-				b.metrics.RateLimitHits++
+		if !respErr.ShouldRetry() {
+			break
+		}
 
-				delay := respErr.GetRetryDelay()
-				if delay <= 0 {
-					delay = b.config.RateLimitDelay
-				}
+		// Handle rate limiting (HTTP 429)
+		if respErr.Response != nil && respErr.Response.IsRateLimited() {
+			if cfg.EnableMetrics {
+				b.metrics.RateLimitHits.With(label).Inc()
+			}
 
-				if b.config.EnableLogging && b.logger != nil {
-					b.logger.Warn("Rate limit hit, backing off",
-						"endpoint", endpoint,
-						"retry_after", delay,
-					)
-				}
+			delay := respErr.GetRetryDelay()
+			if delay <= 0 {
+				delay = cfg.RateLimitDelay
+			}
+			span.AddEvent("rate_limited", tracing.String("retry_after", delay.String()))
 
+			if cfg.EnableLogging && logger != nil {
+				logger.Warn("Rate limit hit, backing off",
+					"endpoint", endpoint,
+					"chat_id", chatID,
+					"retry_after", delay,
+				)
+			}
+
+			// Feed retry_after back into the buckets so every sender to this chat backs off
+			b.limiter.Penalize(chatID, delay)
+
+			// Requests that are not chat-bound do not pass through the limiter, so sleep here
+			if chatID == 0 {
 				select {
 				case <-ctx.Done():
 					return nil, ctx.Err()
@@ -460,10 +644,11 @@ func (b *telegramBot) makeRequestWithRetry(
 				}
 			}
 		}
-
 	}
 
-	b.metrics.ErrorCount++
+	if cfg.EnableMetrics {
+		b.metrics.Errors.With(label).Inc()
+	}
 	return nil, lastErr
 }
 
@@ -471,10 +656,10 @@ func (b *telegramBot) makeRequestWithRetry(
 func (b *telegramBot) makeRequest(
 	ctx context.Context, method, endpoint string, body []byte,
 ) ([]byte, error) {
-	b.metrics.RequestCount++
-	b.metrics.LastRequestTime = time.Now()
+	startTime := time.Now()
 
-	url := b.config.BaseURL + endpoint
+	cfg := b.settings.Load()
+	url := cfg.APIURL() + endpoint
 
 	var bodyReader io.Reader
 	if body != nil {
@@ -488,16 +673,24 @@ func (b *telegramBot) makeRequest(
 
 	resp, err := b.httpClient.Do(req)
 	if err != nil {
+		if cfg.EnableMetrics {
+			b.metrics.observeAttempt(methodLabel(endpoint), 0, time.Since(startTime))
+		}
 		return nil, &types.ResponseError{
 			Method:      endpoint,
 			RequestData: map[string]any{"body": string(body)},
 			HTTPStatus:  0,
 			Timestamp:   time.Now(),
+			Duration:    time.Since(startTime),
+			Cause:       err,
 		}
 	}
 	defer resp.Body.Close()
 
 	responseBody, err := io.ReadAll(resp.Body)
+	if cfg.EnableMetrics {
+		b.metrics.observeAttempt(methodLabel(endpoint), resp.StatusCode, time.Since(startTime))
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
@@ -512,6 +705,7 @@ func (b *telegramBot) makeRequest(
 				Response:    &baseResponse,
 				HTTPStatus:  resp.StatusCode,
 				Timestamp:   time.Now(),
+				Duration:    time.Since(startTime),
 			}
 		}
 
@@ -521,6 +715,7 @@ func (b *telegramBot) makeRequest(
 			RequestData: map[string]any{"body": string(body)},
 			HTTPStatus:  resp.StatusCode,
 			Timestamp:   time.Now(),
+			Duration:    time.Since(startTime),
 		}
 	}
 
@@ -537,36 +732,38 @@ func (b *telegramBot) makeHttpRequest(
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	if b.config.UserAgent != "" {
-		req.Header.Set("User-Agent", b.config.UserAgent)
+	if b.settings.Load().UserAgent != "" {
+		req.Header.Set("User-Agent", b.settings.Load().UserAgent)
 	}
 
 	return req, nil
 }
 
-// updateMetrics updates the client metrics based on the request duration and error status
-func (b *telegramBot) updateMetrics(
-	startTime time.Time, err error,
-) {
-	duration := time.Since(startTime)
+// UpdateConfig applies reloaded retry, rate limit, circuit breaker, logging and metrics settings to
+// requests started afterwards.
+// The token, base URL and HTTP timeout are fixed for the lifetime of the client.
+func (b *telegramBot) UpdateConfig(cfg config.ClientConfig) {
+	current := b.settings.Load()
+	cfg.Token = current.Token
+	cfg.BaseURL = current.BaseURL
+	cfg.Timeout = current.Timeout
+	b.settings.Store(&cfg)
 
-	// Update average latency (simple moving average)
-	if b.metrics.RequestCount > 0 {
-		b.metrics.AverageLatency = time.Duration(
-			(int64(b.metrics.AverageLatency)*b.metrics.RequestCount + int64(duration)) /
-				(b.metrics.RequestCount + 1),
-		)
-	} else {
-		b.metrics.AverageLatency = duration
-	}
+	b.limiter.SetLimits(cfg.RateLimit)
+	b.breakers.configure(cfg.CircuitBreaker.FailureThreshold, cfg.CircuitBreaker.OpenTimeout)
 }
 
-// GetMetrics returns the current client metrics
-func (b *telegramBot) GetMetrics() *ClientMetrics {
-	return b.metrics
+// GetMetrics returns a snapshot of the current client metrics
+func (b *telegramBot) GetMetrics() *ClientMetricsSnapshot {
+	return b.metrics.Snapshot()
 }
 
 // ResetMetrics resets the client metrics to initial state
 func (b *telegramBot) ResetMetrics() {
-	b.metrics = &ClientMetrics{}
+	b.metrics.Reset()
+}
+
+// CircuitBreakerStates returns the state of the circuit breaker of every API method used so far
+func (b *telegramBot) CircuitBreakerStates() map[string]BreakerState {
+	return b.breakers.states()
 }
@@ -2,39 +2,60 @@ package presentation
 
 import (
 	"context"
+	"sync/atomic"
 	"time"
 
 	domainService "go-telegram-bot/internal/domain/service"
 	"go-telegram-bot/internal/domain/types"
 	"go-telegram-bot/internal/presentation/middleware"
 	"go-telegram-bot/internal/presentation/service"
+	"go-telegram-bot/internal/shared/tracing"
 )
 
 // TelegramHandler handles Telegram bot updates and commands
 type TelegramHandler struct {
-	bot               domainService.TelegramBotService
-	botAppService     *service.BotApplicationService
-	loggingMiddleware *middleware.LoggingMiddleware
-	errorMiddleware   *middleware.ErrorHandlingMiddleware
-	logger            domainService.Logger
+	bot           domainService.TelegramBotService
+	botAppService *service.BotApplicationService
+	handler       middleware.HandlerFunc
+	logger        domainService.Logger
+
+	// Polling state reported by the admin status endpoints
+	polling    atomic.Bool
+	offset     atomic.Int64
+	lastPollAt atomic.Int64
+	inFlight   atomic.Int64
+}
+
+// PollerStatus describes the state of the long polling loop
+type PollerStatus struct {
+	Active     bool      `json:"active"`
+	Offset     int64     `json:"offset"`
+	LastPollAt time.Time `json:"last_poll_at"`
+	InFlight   int64     `json:"in_flight"`
+}
+
+// allowedUpdates are the update types requested from Telegram, chat_member must be listed explicitly
+var allowedUpdates = []string{
+	string(types.UpdateTypeMessage),
+	string(types.UpdateTypeEditedMessage),
+	string(types.UpdateTypeCallbackQuery),
+	string(types.UpdateTypeMyChatMember),
+	string(types.UpdateTypeChatMember),
+	string(types.UpdateTypeChatJoinRequest),
 }
 
 // NewTelegramHandler creates a new instance of TelegramHandler
 func NewTelegramHandler(
 	botAppService *service.BotApplicationService,
 	bot domainService.TelegramBotService,
+	chain *middleware.Chain,
 	logger domainService.Logger,
 ) *TelegramHandler {
-	// Create middleware
-	loggingMiddleware := middleware.NewLoggingMiddleware(logger)
-	errorMiddleware := middleware.NewErrorHandlingMiddleware(bot, logger)
-
 	return &TelegramHandler{
-		bot:               bot,
-		botAppService:     botAppService,
-		loggingMiddleware: loggingMiddleware,
-		errorMiddleware:   errorMiddleware,
-		logger:            logger,
+		bot:           bot,
+		botAppService: botAppService,
+		handler:       chain.Then(botAppService.ProcessUpdate),
+		logger:        logger,
 	}
 }
 
@@ -43,14 +64,26 @@ func (h *TelegramHandler) ProcessUpdate(
 	ctx context.Context,
 	update types.TelegramUpdate,
 ) error {
-	// Apply middleware chain: logging -> error handling -> command routing
-	return h.loggingMiddleware.Process(
-		ctx, update, func(ctx context.Context, update types.TelegramUpdate) error {
-			return h.errorMiddleware.Process(
-				ctx, update, func(ctx context.Context, update types.TelegramUpdate) error {
-					return h.botAppService.ProcessUpdate(ctx, update)
-				})
-		})
+	updateType := string(update.Type())
+	middleware.UpdatesTotal.With(updateType).Inc()
+	start := time.Now()
+	defer func() {
+		middleware.UpdateDuration.With(updateType).ObserveDuration(time.Since(start))
+	}()
+
+	// The update span is the root of the trace unless the receiver already started one
+	ctx, span := tracing.Start(ctx, "telegram.update")
+	defer span.End()
+	span.Root().SetAttributes(
+		tracing.Int64("telegram.update_id", update.UpdateID),
+		tracing.String("telegram.update_type", updateType),
+		tracing.Int64("telegram.chat_id", int64(update.ChatID())),
+	)
+
+	// Apply the middleware chain, then route the command
+	err := h.handler(ctx, update)
+	span.RecordError(err)
+	return err
 }
 
 // StartPolling starts polling for updates from Telegram
@@ -68,6 +101,9 @@ func (h *TelegramHandler) StartPolling(ctx context.Context) error {
 
 	var offset int64 = 0
 
+	h.polling.Store(true)
+	defer h.polling.Store(false)
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -75,7 +111,8 @@ func (h *TelegramHandler) StartPolling(ctx context.Context) error {
 			return ctx.Err()
 		default:
 			request := types.GetUpdatesRequest{
-				Offset: offset,
+				Offset:         offset,
+				AllowedUpdates: allowedUpdates,
 			}
 			resp, err := h.bot.GetUpdatesWithResponse(ctx, &request)
 			if err != nil {
@@ -84,6 +121,8 @@ func (h *TelegramHandler) StartPolling(ctx context.Context) error {
 				continue
 			}
 
+			h.lastPollAt.Store(time.Now().UnixNano())
+
 			if resp.Result == nil {
 				time.Sleep(5 * time.Second)
 				continue
@@ -95,15 +134,24 @@ func (h *TelegramHandler) StartPolling(ctx context.Context) error {
 			for _, update := range updates {
 				if update.UpdateID >= offset {
 					offset = update.UpdateID + 1
+					h.offset.Store(offset)
 				}
 
 				// process update in a separate goroutine
+				h.inFlight.Add(1)
 				go func(update types.TelegramUpdate) {
+					defer h.inFlight.Add(-1)
+
 					updateCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 					defer cancel()
 
+					updateCtx, span := tracing.Start(updateCtx, "telegram.receive",
+						tracing.String("telegram.receive.source", "polling"),
+					)
+					defer span.End()
+
 					if err := h.ProcessUpdate(updateCtx, update); err != nil {
-						h.logger.Error("❌ Failed to process update", "error", err, "update_id", update.UpdateID)
+						h.logger.WithContext(updateCtx).Error("❌ Failed to process update", "error", err, "update_id", update.UpdateID)
 					}
 				}(update)
 			}
@@ -114,3 +162,16 @@ func (h *TelegramHandler) StartPolling(ctx context.Context) error {
 		}
 	}
 }
+
+// Status returns a snapshot of the polling loop state
+func (h *TelegramHandler) Status() PollerStatus {
+	status := PollerStatus{
+		Active:   h.polling.Load(),
+		Offset:   h.offset.Load(),
+		InFlight: h.inFlight.Load(),
+	}
+	if last := h.lastPollAt.Load(); last != 0 {
+		status.LastPollAt = time.Unix(0, last)
+	}
+	return status
+}
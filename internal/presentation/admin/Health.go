@@ -0,0 +1,116 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Check is a named readiness probe returning nil when the dependency is usable
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// checkResult is the JSON representation of a single check outcome
+type checkResult struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// healthResponse is the JSON body of the health endpoints
+type healthResponse struct {
+	Status string                 `json:"status"`
+	Checks map[string]checkResult `json:"checks,omitempty"`
+}
+
+// LivenessHandler reports that the process is up and able to serve HTTP
+func LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, healthResponse{Status: "ok"})
+	})
+}
+
+// ReadinessHandler runs every check concurrently and answers 503 when any of them fails
+func ReadinessHandler(timeout time.Duration, checks ...Check) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		response := healthResponse{Status: "ok", Checks: make(map[string]checkResult, len(checks))}
+
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		for _, check := range checks {
+			wg.Add(1)
+			go func(check Check) {
+				defer wg.Done()
+
+				start := time.Now()
+				err := check.Run(ctx)
+				result := checkResult{Status: "ok", Duration: time.Since(start).String()}
+				if err != nil {
+					result.Status = "fail"
+					result.Error = err.Error()
+				}
+
+				mu.Lock()
+				defer mu.Unlock()
+				response.Checks[check.Name] = result
+				if err != nil {
+					response.Status = "fail"
+				}
+			}(check)
+		}
+		wg.Wait()
+
+		status := http.StatusOK
+		if response.Status != "ok" {
+			status = http.StatusServiceUnavailable
+		}
+		writeJSON(w, status, response)
+	})
+}
+
+// CachedCheck wraps a check whose result is reused for ttl, for probes that call external APIs
+func CachedCheck(check Check, ttl time.Duration) Check {
+	var mu sync.Mutex
+	var lastRun time.Time
+	var lastErr error
+
+	return Check{
+		Name: check.Name,
+		Run: func(ctx context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			if !lastRun.IsZero() && time.Since(lastRun) < ttl {
+				return lastErr
+			}
+			lastErr = check.Run(ctx)
+			lastRun = time.Now()
+			return lastErr
+		},
+	}
+}
+
+// StatusHandler serves the value returned by status as JSON
+func StatusHandler(status func() any) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, status())
+	})
+}
+
+// writeJSON writes body as indented JSON with the given status code
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(body)
+}
@@ -0,0 +1,62 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"go-telegram-bot/internal/domain/service"
+)
+
+// Server is the HTTP server exposing operational endpoints such as /metrics
+type Server struct {
+	httpServer *http.Server
+	mux        *http.ServeMux
+	logger     service.Logger
+}
+
+// NewServer creates an admin server listening on addr once started
+func NewServer(addr string, logger service.Logger) *Server {
+	mux := http.NewServeMux()
+	return &Server{
+		httpServer: &http.Server{
+			Addr:              addr,
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       10 * time.Second,
+			WriteTimeout:      30 * time.Second,
+		},
+		mux:    mux,
+		logger: logger,
+	}
+}
+
+// Handle registers a handler for the given pattern
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// Start binds the listening socket and serves requests in the background
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("Admin server listening", "address", listener.Addr().String())
+
+	go func() {
+		if err := s.httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("Admin server stopped unexpectedly", "error", err)
+		}
+	}()
+
+	return nil
+}
+
+// Shutdown gracefully stops the server
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.httpServer.Shutdown(ctx)
+}
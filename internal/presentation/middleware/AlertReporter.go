@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+)
+
+// maxAlertValueLength truncates panic values in reports so one message never hits Telegram's size limit
+const maxAlertValueLength = 300
+
+// PanicReport describes a recovered panic and the update that caused it
+type PanicReport struct {
+	Value      string
+	StackHash  string
+	Frames     []string // innermost first, "function (file:line)"
+	UpdateID   int64
+	UpdateType types.UpdateType
+	Command    types.Command
+	ChatID     types.TelegramChatID
+	UserID     types.TelegramUserID
+}
+
+type alertOccurrence struct {
+	total        int
+	unreported   int
+	lastReported time.Time
+}
+
+// AlertReporter forwards error reports to an admin chat. Reports with the same stack hash
+// are sent at most once per dedup window, later ones carry the occurrences in between.
+type AlertReporter struct {
+	telegramBot service.TelegramBotService
+	logger      service.Logger
+	now         func() time.Time
+
+	mu          sync.Mutex
+	chatID      types.TelegramChatID
+	dedupWindow time.Duration
+	seen        map[string]*alertOccurrence
+}
+
+// NewAlertReporter creates a reporter sending to chatID, a zero chat only counts occurrences
+func NewAlertReporter(
+	telegramBot service.TelegramBotService,
+	chatID types.TelegramChatID,
+	dedupWindow time.Duration,
+	logger service.Logger,
+) *AlertReporter {
+	return &AlertReporter{
+		telegramBot: telegramBot,
+		logger:      logger,
+		now:         time.Now,
+		chatID:      chatID,
+		dedupWindow: dedupWindow,
+		seen:        make(map[string]*alertOccurrence),
+	}
+}
+
+// Configure changes the admin chat and dedup window, used when the configuration is reloaded
+func (r *AlertReporter) Configure(chatID types.TelegramChatID, dedupWindow time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.chatID = chatID
+	r.dedupWindow = dedupWindow
+}
+
+// ReportPanic records the panic and sends it to the admin chat unless it was reported within the dedup window
+func (r *AlertReporter) ReportPanic(ctx context.Context, report PanicReport) {
+	r.mu.Lock()
+	now := r.now()
+	occurrence, ok := r.seen[report.StackHash]
+	if !ok {
+		occurrence = &alertOccurrence{}
+		r.seen[report.StackHash] = occurrence
+	}
+	occurrence.total++
+	occurrence.unreported++
+
+	if !occurrence.lastReported.IsZero() && now.Sub(occurrence.lastReported) < r.dedupWindow {
+		r.mu.Unlock()
+		return
+	}
+	chatID := r.chatID
+	total, since := occurrence.total, occurrence.unreported
+	occurrence.unreported = 0
+	occurrence.lastReported = now
+	r.mu.Unlock()
+
+	if chatID == 0 {
+		return
+	}
+
+	_, err := r.telegramBot.SendMessageWithResponse(ctx, &types.SendMessageRequest{
+		ChatID: chatID,
+		Text:   formatPanicReport(report, total, since),
+	})
+	if err != nil {
+		r.logger.WithContext(ctx).Error("Failed to send panic report", "error", err, "stack_hash", report.StackHash)
+	}
+}
+
+// formatPanicReport renders the report as plain text, so panic values need no escaping
+func formatPanicReport(report PanicReport, total, since int) string {
+	// Cut by runes, Telegram refuses a message whose text is not valid UTF-8
+	value := report.Value
+	if utf8.RuneCountInString(value) > maxAlertValueLength {
+		value = string([]rune(value)[:maxAlertValueLength]) + "…"
+	}
+	command := string(report.Command)
+	if command == "" {
+		command = "-"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "🚨 Panic: %s\n\n", value)
+	fmt.Fprintf(&b, "Command: %s (%s, update %d)\n", command, report.UpdateType, report.UpdateID)
+	fmt.Fprintf(&b, "Chat: %d, user: %d\n", report.ChatID, report.UserID)
+	fmt.Fprintf(&b, "Stack hash: %s\n", report.StackHash)
+	fmt.Fprintf(&b, "Occurrences: %d total, %d since the last report\n", total, since)
+	if len(report.Frames) > 0 {
+		b.WriteString("\n")
+		for _, frame := range report.Frames {
+			fmt.Fprintf(&b, "at %s\n", frame)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,201 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"go-telegram-bot/internal/domain/repository"
+	"go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+	"go-telegram-bot/internal/shared/tracing"
+)
+
+// Reasons a command is dropped, used as metric label values
+const (
+	floodReasonUser     = "user_limit"
+	floodReasonChat     = "chat_limit"
+	floodReasonCooldown = "cooldown"
+	floodReasonMuted    = "muted"
+)
+
+// AntiFloodPolicy holds the limits enforced by AntiFloodMiddleware, a zero limit or count disables its check
+type AntiFloodPolicy struct {
+	Enabled bool
+
+	UserLimit  int
+	UserWindow time.Duration
+	ChatLimit  int
+	ChatWindow time.Duration
+	Cooldowns  map[types.Command]time.Duration
+
+	WarnAfter       int
+	MuteAfter       int
+	ViolationWindow time.Duration
+	MuteDuration    time.Duration
+}
+
+// AntiFloodMiddleware drops commands of users and chats exceeding their sliding-window limits or
+// repeating a command during its cooldown. Repeated violations of a user escalate: they are dropped
+// silently, one is answered with a warning, then the user is ignored for a while.
+// Only commands are counted, other updates pass through.
+type AntiFloodMiddleware struct {
+	store       repository.FloodEventRepository
+	telegramBot service.TelegramBotService
+	logger      service.Logger
+	policy      atomic.Pointer[AntiFloodPolicy]
+	now         func() time.Time
+}
+
+// NewAntiFloodMiddleware creates a new anti-flood middleware counting events in store
+func NewAntiFloodMiddleware(
+	store repository.FloodEventRepository,
+	policy AntiFloodPolicy,
+	telegramBot service.TelegramBotService,
+	logger service.Logger,
+) *AntiFloodMiddleware {
+	m := &AntiFloodMiddleware{
+		store:       store,
+		telegramBot: telegramBot,
+		logger:      logger,
+		now:         time.Now,
+	}
+	m.SetPolicy(policy)
+	return m
+}
+
+// SetPolicy replaces the enforced limits, used when the configuration is reloaded
+func (m *AntiFloodMiddleware) SetPolicy(policy AntiFloodPolicy) {
+	m.policy.Store(&policy)
+}
+
+// Wrap passes the update to next unless its command is flooding
+func (m *AntiFloodMiddleware) Wrap(next HandlerFunc) HandlerFunc {
+	return func(ctx context.Context, update types.TelegramUpdate) error {
+		policy := m.policy.Load()
+		req := types.NewCommandRequest(update.Message)
+		if !policy.Enabled || req == nil || req.UserID == 0 {
+			return next(ctx, update)
+		}
+
+		span := tracing.SpanFromContext(ctx)
+		logger := m.logger.WithContext(ctx)
+
+		reason, err := m.check(ctx, policy, req)
+		if err != nil {
+			// Fail open: losing flood protection is better than ignoring every command
+			MiddlewareErrors.With("anti_flood").Inc()
+			span.RecordError(err)
+			logger.Error("Anti-flood check failed, letting the command through", "error", err)
+			return next(ctx, update)
+		}
+		if reason == "" {
+			return next(ctx, update)
+		}
+
+		FloodDropped.With(reason).Inc()
+		span.SetAttributes(tracing.String("anti_flood.reason", reason))
+		logger.Info("Dropped flooding command",
+			"reason", reason, "command", req.Command, "user_id", req.UserID, "chat_id", req.ChatID)
+		return nil
+	}
+}
+
+// check records the command and returns why it must be dropped, or an empty reason
+func (m *AntiFloodMiddleware) check(
+	ctx context.Context, policy *AntiFloodPolicy, req *types.CommandRequest,
+) (string, error) {
+	now := m.now()
+
+	if policy.MuteAfter > 0 {
+		muted, err := m.store.Count(ctx, muteKey(req.UserID), now, policy.MuteDuration)
+		if err != nil {
+			return "", err
+		}
+		if muted > 0 {
+			return floodReasonMuted, nil
+		}
+	}
+
+	if policy.UserLimit > 0 {
+		_, taken, err := m.store.Take(ctx, fmt.Sprintf("user:%d", req.UserID), now, policy.UserWindow, policy.UserLimit)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return floodReasonUser, m.escalate(ctx, policy, req, now)
+		}
+	}
+
+	if policy.ChatLimit > 0 {
+		// A busy chat is not the fault of the sender, so chat violations do not escalate
+		_, taken, err := m.store.Take(ctx, fmt.Sprintf("chat:%d", req.ChatID), now, policy.ChatWindow, policy.ChatLimit)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return floodReasonChat, nil
+		}
+	}
+
+	if cooldown := policy.Cooldowns[req.Command]; cooldown > 0 {
+		key := fmt.Sprintf("cooldown:%d:%s", req.UserID, req.Command)
+		_, taken, err := m.store.Take(ctx, key, now, cooldown, 1)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return floodReasonCooldown, m.escalate(ctx, policy, req, now)
+		}
+	}
+
+	return "", nil
+}
+
+// escalate counts a violation of the user, warning them once and muting them when they persist
+func (m *AntiFloodMiddleware) escalate(
+	ctx context.Context, policy *AntiFloodPolicy, req *types.CommandRequest, now time.Time,
+) error {
+	if policy.WarnAfter == 0 && policy.MuteAfter == 0 {
+		return nil
+	}
+
+	violations, _, err := m.store.Take(ctx, fmt.Sprintf("violation:%d", req.UserID), now, policy.ViolationWindow, 0)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case policy.MuteAfter > 0 && violations >= policy.MuteAfter:
+		m.logger.WithContext(ctx).Warn("Muting flooding user",
+			"user_id", req.UserID, "violations", violations, "duration", policy.MuteDuration)
+		_, _, err = m.store.Take(ctx, muteKey(req.UserID), now, policy.MuteDuration, 0)
+		return err
+	case violations == policy.WarnAfter:
+		m.warn(ctx, policy, req)
+	}
+	return nil
+}
+
+// warn answers the flooding command once, a failure to send is only logged
+func (m *AntiFloodMiddleware) warn(ctx context.Context, policy *AntiFloodPolicy, req *types.CommandRequest) {
+	text := "⚠️ Bạn đang gửi lệnh quá nhanh, vui lòng chậm lại."
+	if policy.MuteAfter > 0 {
+		text += fmt.Sprintf(" Nếu tiếp tục, bot sẽ bỏ qua bạn trong %s.", policy.MuteDuration)
+	}
+
+	replyTo := req.MessageID
+	_, err := m.telegramBot.SendMessageWithResponse(ctx, &types.SendMessageRequest{
+		ChatID:           req.ChatID,
+		Text:             text,
+		ReplyToMessageID: &replyTo,
+	})
+	if err != nil {
+		m.logger.WithContext(ctx).Error("Failed to send flood warning", "error", err)
+	}
+}
+
+func muteKey(userID types.TelegramUserID) string {
+	return fmt.Sprintf("mute:%d", userID)
+}
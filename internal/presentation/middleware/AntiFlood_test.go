@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...any)                         {}
+func (nopLogger) Info(string, ...any)                          {}
+func (nopLogger) Warn(string, ...any)                          {}
+func (nopLogger) Error(string, ...any)                         {}
+func (nopLogger) Fatal(string, ...any)                         {}
+func (nopLogger) Panic(string, ...any)                         {}
+func (l nopLogger) WithContext(context.Context) service.Logger { return l }
+func (l nopLogger) WithField(string, any) service.Logger       { return l }
+func (l nopLogger) WithFields(map[string]any) service.Logger   { return l }
+
+// recordingBot records the messages sent, every other method panics
+type recordingBot struct {
+	service.TelegramBotService
+	sent []string
+}
+
+func (b *recordingBot) SendMessageWithResponse(
+	_ context.Context, request *types.SendMessageRequest,
+) (*types.SendMessageResponse, error) {
+	b.sent = append(b.sent, request.Text)
+	return &types.SendMessageResponse{}, nil
+}
+
+// sliceStore is a FloodEventRepository keeping every event
+type sliceStore map[string][]time.Time
+
+func (s sliceStore) Count(_ context.Context, key string, now time.Time, window time.Duration) (int, error) {
+	count := 0
+	for _, at := range s[key] {
+		if at.After(now.Add(-window)) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (s sliceStore) Take(
+	ctx context.Context, key string, now time.Time, window time.Duration, limit int,
+) (int, bool, error) {
+	count, _ := s.Count(ctx, key, now, window)
+	if limit > 0 && count >= limit {
+		return count, false, nil
+	}
+	s[key] = append(s[key], now)
+	return count + 1, true, nil
+}
+
+func commandUpdate(text string, userID types.TelegramUserID) types.TelegramUpdate {
+	return types.TelegramUpdate{
+		UpdateID: 1,
+		Message: &types.TelegramMessage{
+			Text: &text,
+			Chat: &types.TelegramChat{ID: types.TelegramChatID(userID), Type: types.ChatTypePrivate},
+			From: &types.TelegramUser{ID: userID},
+		},
+	}
+}
+
+func TestAntiFlood_EscalatesFromDropToWarningToMute(t *testing.T) {
+	bot := &recordingBot{}
+	m := NewAntiFloodMiddleware(sliceStore{}, AntiFloodPolicy{
+		Enabled:         true,
+		UserLimit:       2,
+		UserWindow:      time.Minute,
+		WarnAfter:       2,
+		MuteAfter:       3,
+		ViolationWindow: 10 * time.Minute,
+		MuteDuration:    10 * time.Minute,
+	}, bot, nopLogger{})
+	now := time.Unix(1_700_000_000, 0)
+	m.now = func() time.Time { return now }
+
+	handled := 0
+	next := func(context.Context, types.TelegramUpdate) error { handled++; return nil }
+	send := func() { _ = m.Wrap(next)(context.Background(), commandUpdate("/help", 7)) }
+
+	for range 4 {
+		send()
+	}
+	if handled != 2 || len(bot.sent) != 1 {
+		t.Fatalf("expected 2 handled and 1 warning after 2 violations, got %d and %v", handled, bot.sent)
+	}
+
+	send() // third violation mutes the user
+	now = now.Add(2 * time.Minute)
+	send()
+	if handled != 2 || len(bot.sent) != 1 {
+		t.Fatalf("expected a muted user to be ignored silently, got %d handled and %v", handled, bot.sent)
+	}
+
+	now = now.Add(10 * time.Minute)
+	send()
+	if handled != 3 {
+		t.Fatalf("expected the user to be heard again after the mute, got %d handled", handled)
+	}
+}
+
+func TestAntiFlood_CooldownAppliesPerCommand(t *testing.T) {
+	m := NewAntiFloodMiddleware(sliceStore{}, AntiFloodPolicy{
+		Enabled:   true,
+		Cooldowns: map[types.Command]time.Duration{types.CommandGetHomeIP: 30 * time.Second},
+	}, &recordingBot{}, nopLogger{})
+	now := time.Unix(1_700_000_000, 0)
+	m.now = func() time.Time { return now }
+
+	var handled []string
+	next := func(_ context.Context, update types.TelegramUpdate) error {
+		handled = append(handled, *update.Message.Text)
+		return nil
+	}
+	for _, text := range []string{"/home_ip", "/home_ip", "/help", "not a command"} {
+		_ = m.Wrap(next)(context.Background(), commandUpdate(text, 7))
+	}
+	now = now.Add(31 * time.Second)
+	_ = m.Wrap(next)(context.Background(), commandUpdate("/home_ip", 7))
+
+	expected := []string{"/home_ip", "/help", "not a command", "/home_ip"}
+	if len(handled) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, handled)
+	}
+	for i := range expected {
+		if handled[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, handled)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"go-telegram-bot/internal/domain/types"
+	"go-telegram-bot/internal/shared/tracing"
+)
+
+// HandlerFunc processes one update
+type HandlerFunc func(ctx context.Context, update types.TelegramUpdate) error
+
+// Middleware wraps a handler with behaviour that runs before and after it
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// Matcher selects the updates a middleware applies to
+type Matcher func(update types.TelegramUpdate) bool
+
+// Priorities of the built-in middlewares, lower priorities run first and see the result of later ones
+const (
+	PriorityRecovery      = 0
+	PriorityLogging       = 100
+	PriorityAntiFlood     = 200
+	PriorityErrorHandling = 300
+)
+
+// HandlerErrorLabel is the middleware label of the errors returned by the final handler
+const HandlerErrorLabel = "handler"
+
+type chainEntry struct {
+	name       string
+	priority   int
+	middleware Middleware
+	matchers   []Matcher
+}
+
+// Chain composes middlewares ordered by priority, middlewares with equal priority keep their registration order
+type Chain struct {
+	entries []chainEntry
+}
+
+// NewChain creates an empty middleware chain
+func NewChain() *Chain {
+	return &Chain{}
+}
+
+// Use adds a middleware to the chain. With matchers, the middleware only runs for updates
+// accepted by all of them, other updates skip it. Each middleware gets a span named
+// "middleware.<name>" and the errors it produces, not the ones it passes on, are counted by name.
+func (c *Chain) Use(name string, priority int, middleware Middleware, matchers ...Matcher) *Chain {
+	c.entries = append(c.entries, chainEntry{
+		name:       name,
+		priority:   priority,
+		middleware: middleware,
+		matchers:   matchers,
+	})
+	return c
+}
+
+// Names lists the middlewares in the order they run
+func (c *Chain) Names() []string {
+	entries := c.sorted()
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry.name
+	}
+	return names
+}
+
+// Then wraps final with every middleware of the chain
+func (c *Chain) Then(final HandlerFunc) HandlerFunc {
+	entries := c.sorted()
+	handler := func(ctx context.Context, update types.TelegramUpdate) error {
+		err := final(ctx, update)
+		if err != nil {
+			MiddlewareErrors.With(HandlerErrorLabel).Inc()
+		}
+		return err
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		handler = entries[i].wrap(handler)
+	}
+	return handler
+}
+
+func (c *Chain) sorted() []chainEntry {
+	entries := append([]chainEntry(nil), c.entries...)
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].priority < entries[j].priority })
+	return entries
+}
+
+// downstreamKey finds in the context where an entry stores the error returned past it, the field
+// keeps every key distinct
+type downstreamKey struct{ _ byte }
+
+// wrap instruments the middleware and applies its matchers
+func (e chainEntry) wrap(next HandlerFunc) HandlerFunc {
+	key := &downstreamKey{}
+	wrapped := e.middleware(func(ctx context.Context, update types.TelegramUpdate) error {
+		err := next(ctx, update)
+		if downstream, ok := ctx.Value(key).(*error); ok {
+			*downstream = err
+		}
+		return err
+	})
+	return func(ctx context.Context, update types.TelegramUpdate) error {
+		for _, matches := range e.matchers {
+			if !matches(update) {
+				return next(ctx, update)
+			}
+		}
+
+		ctx, span := tracing.Start(ctx, "middleware."+e.name)
+		defer span.End()
+
+		// An error coming back from further down the chain was counted where it was produced
+		var downstream error
+		err := wrapped(context.WithValue(ctx, key, &downstream), update)
+		if err != nil {
+			if downstream == nil || !errors.Is(err, downstream) {
+				MiddlewareErrors.With(e.name).Inc()
+			}
+			span.RecordError(err)
+		}
+		return err
+	}
+}
+
+// ForUpdateTypes matches updates of the given types
+func ForUpdateTypes(updateTypes ...types.UpdateType) Matcher {
+	return func(update types.TelegramUpdate) bool {
+		updateType := update.Type()
+		for _, t := range updateTypes {
+			if updateType == t {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// ForCommands matches messages carrying one of the given commands
+func ForCommands(commands ...types.Command) Matcher {
+	return func(update types.TelegramUpdate) bool {
+		req := types.NewCommandRequest(update.Message)
+		if req == nil {
+			return false
+		}
+		for _, command := range commands {
+			if req.Command == command {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// ForAnyCommand matches messages carrying a command
+func ForAnyCommand() Matcher {
+	return func(update types.TelegramUpdate) bool {
+		return types.NewCommandRequest(update.Message) != nil
+	}
+}
@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"go-telegram-bot/internal/domain/types"
+)
+
+func recordingMiddleware(name string, calls *[]string) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, update types.TelegramUpdate) error {
+			*calls = append(*calls, name)
+			return next(ctx, update)
+		}
+	}
+}
+
+func TestChain_OrdersByPriorityThenRegistration(t *testing.T) {
+	var calls []string
+	chain := NewChain().
+		Use("error_handling", PriorityErrorHandling, recordingMiddleware("error_handling", &calls)).
+		Use("logging", PriorityLogging, recordingMiddleware("logging", &calls)).
+		Use("audit", PriorityErrorHandling, recordingMiddleware("audit", &calls))
+
+	handler := chain.Then(func(context.Context, types.TelegramUpdate) error {
+		calls = append(calls, "handler")
+		return nil
+	})
+	if err := handler(context.Background(), commandUpdate("/start", 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"logging", "error_handling", "audit", "handler"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Fatalf("expected %v, got %v", expected, calls)
+	}
+	if names := chain.Names(); !reflect.DeepEqual(names, expected[:3]) {
+		t.Fatalf("expected names %v, got %v", expected[:3], names)
+	}
+}
+
+func TestChain_MatchersSkipOtherUpdates(t *testing.T) {
+	var calls []string
+	handler := NewChain().
+		Use("home_ip", 0, recordingMiddleware("home_ip", &calls), ForCommands(types.CommandGetHomeIP)).
+		Use("callbacks", 0, recordingMiddleware("callbacks", &calls), ForUpdateTypes(types.UpdateTypeCallbackQuery)).
+		Then(func(context.Context, types.TelegramUpdate) error { return nil })
+
+	_ = handler(context.Background(), commandUpdate("/help", 1))
+	_ = handler(context.Background(), commandUpdate("/home_ip@my_bot", 1))
+
+	if !reflect.DeepEqual(calls, []string{"home_ip"}) {
+		t.Fatalf("expected only the /home_ip middleware to run once, got %v", calls)
+	}
+}
+
+func TestChain_CountsErrorsPerMiddleware(t *testing.T) {
+	names := []string{"chain_test_outer", "chain_test_inner", "chain_test_reject", HandlerErrorLabel}
+	counts := func() []uint64 {
+		values := make([]uint64, len(names))
+		for i, name := range names {
+			values[i] = MiddlewareErrors.With(name).Value()
+		}
+		return values
+	}
+	reject := func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, update types.TelegramUpdate) error {
+			if update.UpdateID == 2 {
+				return errors.New("rejected")
+			}
+			return next(ctx, update)
+		}
+	}
+	handler := NewChain().
+		Use("chain_test_outer", 0, recordingMiddleware("chain_test_outer", new([]string))).
+		Use("chain_test_reject", 1, reject).
+		Use("chain_test_inner", 2, recordingMiddleware("chain_test_inner", new([]string))).
+		Then(func(context.Context, types.TelegramUpdate) error { return errors.New("boom") })
+
+	// The handler error passes back through every middleware but is counted once, as the handler's
+	before := counts()
+	if err := handler(context.Background(), commandUpdate("/start", 1)); err == nil {
+		t.Fatal("expected the handler error to propagate")
+	}
+	after := counts()
+	if want := []uint64{before[0], before[1], before[2], before[3] + 1}; !reflect.DeepEqual(after, want) {
+		t.Fatalf("expected one handler error, got %v after %v", after, before)
+	}
+
+	update := commandUpdate("/start", 1)
+	update.UpdateID = 2
+	if err := handler(context.Background(), update); err == nil {
+		t.Fatal("expected the rejection to propagate")
+	}
+	if got := counts(); !reflect.DeepEqual(got, []uint64{after[0], after[1], after[2] + 1, after[3]}) {
+		t.Fatalf("expected one error of the rejecting middleware, got %v after %v", got, after)
+	}
+}
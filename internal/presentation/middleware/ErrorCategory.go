@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	domainErrors "go-telegram-bot/internal/domain/errors"
+	"go-telegram-bot/internal/domain/types"
+	"go-telegram-bot/internal/shared/i18n"
+)
+
+// ErrorCategory groups errors by what the user is told about them
+type ErrorCategory string
+
+const (
+	// CategoryInvalidInput is a command used wrongly, the reply shows its usage when known
+	CategoryInvalidInput ErrorCategory = "invalid_input"
+	// CategoryPermissionDenied is a command the user may not run
+	CategoryPermissionDenied ErrorCategory = "permission_denied"
+	// CategoryUnavailable is a temporary upstream failure, the reply suggests retrying
+	CategoryUnavailable ErrorCategory = "unavailable"
+	// CategoryUnreachable is a chat the bot cannot write to, no reply is attempted
+	CategoryUnreachable ErrorCategory = "unreachable"
+	// CategoryInternal is every other error
+	CategoryInternal ErrorCategory = "internal"
+)
+
+// invalidInputErrors are the domain errors caused by what the user sent
+var invalidInputErrors = []error{
+	domainErrors.ErrInvalidInput,
+	domainErrors.ErrInvalidRole,
+	domainErrors.ErrInvalidUserData,
+	domainErrors.ErrInvalidChatData,
+	domainErrors.ErrInvalidMessageData,
+	domainErrors.ErrInvalidCallbackData,
+	domainErrors.ErrInvalidTimezone,
+	domainErrors.ErrInvalidAvatarURL,
+	domainErrors.ErrBioTooLong,
+	domainErrors.ErrInappropriateContent,
+	domainErrors.ErrTooManyPreferences,
+	domainErrors.ErrTooManySchedules,
+}
+
+// ClassifyError maps an error returned by a handler to the category shown to the user
+func ClassifyError(err error) ErrorCategory {
+	var responseErr *types.ResponseError
+	if errors.As(err, &responseErr) {
+		switch {
+		case responseErr.IsChatUnreachable():
+			return CategoryUnreachable
+		case responseErr.ShouldRetry():
+			return CategoryUnavailable
+		default:
+			return CategoryInternal
+		}
+	}
+
+	switch {
+	case errors.Is(err, domainErrors.ErrPermissionDenied):
+		return CategoryPermissionDenied
+	case errors.Is(err, domainErrors.ErrServiceUnavailable),
+		errors.Is(err, context.DeadlineExceeded):
+		return CategoryUnavailable
+	}
+	for _, target := range invalidInputErrors {
+		if errors.Is(err, target) {
+			return CategoryInvalidInput
+		}
+	}
+	return CategoryInternal
+}
+
+var errorTemplates = map[ErrorCategory]i18n.Messages{
+	CategoryInvalidInput: {
+		i18n.Vietnamese: "❌ Yêu cầu không hợp lệ.",
+		i18n.English:    "❌ Invalid request.",
+	},
+	CategoryPermissionDenied: {
+		i18n.Vietnamese: "⛔ Bạn không có quyền thực hiện thao tác này.",
+		i18n.English:    "⛔ You are not allowed to do this.",
+	},
+	CategoryUnavailable: {
+		i18n.Vietnamese: "⏳ Dịch vụ tạm thời không khả dụng. Vui lòng thử lại sau ít phút.",
+		i18n.English:    "⏳ The service is temporarily unavailable. Please try again in a few minutes.",
+	},
+	CategoryInternal: {
+		i18n.Vietnamese: "⚠️ Đã xảy ra lỗi khi xử lý yêu cầu của bạn. Vui lòng thử lại sau.",
+		i18n.English:    "⚠️ Something went wrong while handling your request. Please try again later.",
+	},
+}
+
+var (
+	usageLabel = i18n.Messages{
+		i18n.Vietnamese: "Cách dùng:",
+		i18n.English:    "Usage:",
+	}
+	retryAfterHint = i18n.Messages{
+		i18n.Vietnamese: "Thử lại sau %d giây.",
+		i18n.English:    "Try again in %d seconds.",
+	}
+)
+
+// retryDelayer is implemented by errors knowing when the upstream accepts requests again
+type retryDelayer interface {
+	GetRetryDelay() time.Duration
+}
+
+// errorReply renders the reply for err, empty when the user must not be answered
+func errorReply(category ErrorCategory, err error, lang i18n.Language) string {
+	template, ok := errorTemplates[category]
+	if !ok {
+		return ""
+	}
+	text := template.Get(lang)
+
+	switch category {
+	case CategoryInvalidInput:
+		var usageErr *domainErrors.UsageError
+		if errors.As(err, &usageErr) && usageErr.Usage != "" {
+			text += "\n\n" + usageLabel.Get(lang) + "\n" + usageErr.Usage
+		}
+	case CategoryUnavailable:
+		var delayer retryDelayer
+		if errors.As(err, &delayer) {
+			if seconds := int(delayer.GetRetryDelay().Round(time.Second).Seconds()); seconds > 0 {
+				text += " " + fmt.Sprintf(retryAfterHint.Get(lang), seconds)
+			}
+		}
+	}
+	return text
+}
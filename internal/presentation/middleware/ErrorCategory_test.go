@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	domainErrors "go-telegram-bot/internal/domain/errors"
+	"go-telegram-bot/internal/domain/types"
+	"go-telegram-bot/internal/shared/i18n"
+)
+
+func apiError(code int, description string, retryAfter int) *types.ResponseError {
+	response := &types.BaseResponse{ErrorCode: &code, Description: &description}
+	if retryAfter > 0 {
+		response.Parameters = &types.ResponseParams{RetryAfter: &retryAfter}
+	}
+	return &types.ResponseError{Method: "sendMessage", HTTPStatus: code, Response: response}
+}
+
+func TestClassifyError(t *testing.T) {
+	cases := []struct {
+		err  error
+		want ErrorCategory
+	}{
+		{domainErrors.NewUsageError("missing user", "/revoke <user_id>"), CategoryInvalidInput},
+		{fmt.Errorf("grant: %w", domainErrors.ErrInvalidRole), CategoryInvalidInput},
+		{fmt.Errorf("grant: %w", domainErrors.ErrPermissionDenied), CategoryPermissionDenied},
+		{fmt.Errorf("ip: %w: %w", domainErrors.ErrServiceUnavailable, errors.New("dial tcp")), CategoryUnavailable},
+		{context.DeadlineExceeded, CategoryUnavailable},
+		{fmt.Errorf("send: %w", apiError(403, "Forbidden: bot was blocked by the user", 0)), CategoryUnreachable},
+		{apiError(400, "Bad Request: chat not found", 0), CategoryUnreachable},
+		{apiError(429, "Too Many Requests: retry after 7", 7), CategoryUnavailable},
+		{apiError(400, "Bad Request: can't parse entities", 0), CategoryInternal},
+		{errors.New("boom"), CategoryInternal},
+	}
+	for _, c := range cases {
+		if got := ClassifyError(c.err); got != c.want {
+			t.Errorf("ClassifyError(%v) = %s, want %s", c.err, got, c.want)
+		}
+	}
+}
+
+func TestErrorReply_AddsUsageAndRetryHints(t *testing.T) {
+	usage := errorReply(CategoryInvalidInput, domainErrors.NewUsageError("", "/revoke <user_id>"), i18n.English)
+	if !strings.HasSuffix(usage, "Usage:\n/revoke <user_id>") {
+		t.Errorf("expected the usage in the reply, got %q", usage)
+	}
+
+	retry := errorReply(CategoryUnavailable, apiError(429, "Too Many Requests", 7), i18n.Vietnamese)
+	if !strings.HasSuffix(retry, "Thử lại sau 7 giây.") {
+		t.Errorf("expected a retry hint in the reply, got %q", retry)
+	}
+
+	if reply := errorReply(CategoryUnreachable, apiError(403, "Forbidden", 0), i18n.English); reply != "" {
+		t.Errorf("expected no reply for an unreachable chat, got %q", reply)
+	}
+}
@@ -5,9 +5,10 @@ import (
 
 	"go-telegram-bot/internal/domain/service"
 	"go-telegram-bot/internal/domain/types"
+	"go-telegram-bot/internal/shared/i18n"
 )
 
-// ErrorHandlingMiddleware handles errors and sends user-friendly messages
+// ErrorHandlingMiddleware answers errors with a message matching their category
 type ErrorHandlingMiddleware struct {
 	telegramBot service.TelegramBotService
 	logger      service.Logger
@@ -23,30 +24,48 @@ func NewErrorHandlingMiddleware(
 	}
 }
 
-// Process handles errors from the next handler and sends user-friendly messages
-func (m *ErrorHandlingMiddleware) Process(
-	ctx context.Context,
-	update types.TelegramUpdate,
-	next func(context.Context, types.TelegramUpdate) error,
-) error {
-	err := next(ctx, update)
-	if err != nil {
-		// Log the error
-		m.logger.Error("🔥 Error in handler chain:", "error", err)
-
-		// Send user-friendly error message if it's a message update
-		if update.Message != nil && update.Message.Chat != nil {
-			errorMsg := "⚠️ Đã xảy ra lỗi khi xử lý yêu cầu của bạn. Vui lòng thử lại sau."
-
-			_, err := m.telegramBot.SendMessageWithResponse(ctx, &types.SendMessageRequest{
-				ChatID: update.Message.Chat.ID,
-				Text:   errorMsg,
-			})
-			if err != nil {
-				m.logger.Error("❌ Failed to send error message:", "error", err)
-			}
+// Wrap handles errors from the next handler and sends user-friendly messages
+func (m *ErrorHandlingMiddleware) Wrap(next HandlerFunc) HandlerFunc {
+	return func(ctx context.Context, update types.TelegramUpdate) error {
+		logger := m.logger.WithContext(ctx)
+
+		err := next(ctx, update)
+		if err == nil {
+			return nil
+		}
+
+		category := ClassifyError(err)
+		ErrorsByCategory.With(string(category)).Inc()
+
+		switch category {
+		case CategoryInternal:
+			logger.Error("🔥 Error in handler chain:", "error", err, "category", category)
+		default:
+			logger.Warn("Error in handler chain", "error", err, "category", category)
+		}
+
+		// Answer message updates, unless the chat is the problem
+		if update.Message == nil || update.Message.Chat == nil || category == CategoryUnreachable {
+			return err
 		}
-	}
 
-	return err
+		var languageCode *string
+		if update.Message.From != nil {
+			languageCode = update.Message.From.LanguageCode
+		}
+		text := errorReply(category, err, i18n.FromCode(languageCode))
+		if text == "" {
+			return err
+		}
+
+		_, sendErr := m.telegramBot.SendMessageWithResponse(ctx, &types.SendMessageRequest{
+			ChatID: update.Message.Chat.ID,
+			Text:   text,
+		})
+		if sendErr != nil {
+			logger.Error("❌ Failed to send error message:", "error", sendErr)
+		}
+
+		return err
+	}
 }
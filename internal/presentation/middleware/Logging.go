@@ -18,31 +18,31 @@ func NewLoggingMiddleware(logger service.Logger) *LoggingMiddleware {
 	}
 }
 
-func (m *LoggingMiddleware) Process(
-	ctx context.Context,
-	update types.TelegramUpdate,
-	next func(ctx context.Context, update types.TelegramUpdate) error,
-) error {
-	start := time.Now()
-
-	// Log the incoming update
-	if update.Message != nil {
-		m.logger.Info("Received message", "chat_id", update.Message.Chat.ID, "text", update.Message.Text)
-	} else {
-		m.logger.Info("Received update", "update_id", update.UpdateID)
+// Wrap logs every update and the outcome of processing it
+func (m *LoggingMiddleware) Wrap(next HandlerFunc) HandlerFunc {
+	return func(ctx context.Context, update types.TelegramUpdate) error {
+		start := time.Now()
+		logger := m.logger.WithContext(ctx)
+
+		// Log the incoming update
+		if update.Message != nil {
+			logger.Info("Received message", "chat_id", update.Message.Chat.ID, "text", update.Message.Text)
+		} else {
+			logger.Info("Received update", "update_id", update.UpdateID)
+		}
+
+		// Call the next handler in the chain
+		err := next(ctx, update)
+
+		// Log the result of processing
+		duration := time.Since(start)
+
+		if err != nil {
+			logger.Error("Error processing update", "error", err, "duration", duration)
+		} else {
+			logger.Info("Processed update successfully", "duration", duration)
+		}
+
+		return err
 	}
-
-	// Call the next handler in the chain
-	err := next(ctx, update)
-
-	// Log the result of processing
-	duration := time.Since(start)
-
-	if err != nil {
-		m.logger.Error("Error processing update", "error", err, "duration", duration)
-	} else {
-		m.logger.Info("Processed update successfully", "duration", duration)
-	}
-
-	return err
 }
@@ -0,0 +1,34 @@
+package middleware
+
+import "go-telegram-bot/internal/shared/metrics"
+
+// Update pipeline metrics, exposed on the admin /metrics endpoint
+var (
+	// UpdatesTotal counts received updates by update type
+	UpdatesTotal = metrics.NewCounterVec("type")
+	// UpdateDuration observes the time spent processing an update by update type
+	UpdateDuration = metrics.NewHistogramVec(metrics.DefaultLatencyBuckets, "type")
+	// MiddlewareErrors counts errors by the middleware which produced them, HandlerErrorLabel for the handler
+	MiddlewareErrors = metrics.NewCounterVec("middleware")
+	// FloodDropped counts commands dropped by the anti-flood middleware by reason
+	FloodDropped = metrics.NewCounterVec("reason")
+	// PanicsRecovered counts panics turned into errors by the recovery middleware by update type
+	PanicsRecovered = metrics.NewCounterVec("update_type")
+	// ErrorsByCategory counts handler errors by the category shown to the user
+	ErrorsByCategory = metrics.NewCounterVec("category")
+)
+
+func init() {
+	metrics.Default.RegisterCounterVec("bot_updates_total",
+		"Updates received from Telegram by update type.", UpdatesTotal)
+	metrics.Default.RegisterHistogramVec("bot_update_duration_seconds",
+		"Time spent processing an update by update type.", UpdateDuration)
+	metrics.Default.RegisterCounterVec("bot_middleware_errors_total",
+		"Errors produced by each middleware of the update pipeline, middleware=\"handler\" for the final handler.", MiddlewareErrors)
+	metrics.Default.RegisterCounterVec("bot_antiflood_dropped_total",
+		"Commands dropped by the anti-flood middleware by reason.", FloodDropped)
+	metrics.Default.RegisterCounterVec("bot_panics_recovered_total",
+		"Panics recovered while processing updates by update type.", PanicsRecovered)
+	metrics.Default.RegisterCounterVec("bot_errors_total",
+		"Handler errors by user-facing category.", ErrorsByCategory)
+}
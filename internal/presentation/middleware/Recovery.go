@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"go-telegram-bot/internal/domain/service"
+	"go-telegram-bot/internal/domain/types"
+	"go-telegram-bot/internal/shared/i18n"
+)
+
+// ErrPanic wraps the value of a panic recovered while processing an update
+var ErrPanic = errors.New("panic while processing update")
+
+// reportedFrames is how many stack frames are shown in panic reports
+const reportedFrames = 5
+
+var apologyMessages = i18n.Messages{
+	i18n.Vietnamese: "😔 Xin lỗi, bot gặp sự cố khi xử lý yêu cầu của bạn. Quản trị viên đã được thông báo.",
+	i18n.English:    "😔 Sorry, something went wrong while handling your request. The admins have been notified.",
+}
+
+// RecoveryMiddleware turns a panic in the rest of the chain into an error, so one bad update
+// cannot crash the bot. The user gets an apology in their language and the admins a report.
+type RecoveryMiddleware struct {
+	telegramBot service.TelegramBotService
+	reporter    *AlertReporter
+	logger      service.Logger
+}
+
+// NewRecoveryMiddleware creates a new recovery middleware reporting panics to reporter
+func NewRecoveryMiddleware(
+	telegramBot service.TelegramBotService, reporter *AlertReporter, logger service.Logger,
+) *RecoveryMiddleware {
+	return &RecoveryMiddleware{
+		telegramBot: telegramBot,
+		reporter:    reporter,
+		logger:      logger,
+	}
+}
+
+// Wrap recovers panics of next and returns them as an error wrapping ErrPanic
+func (m *RecoveryMiddleware) Wrap(next HandlerFunc) HandlerFunc {
+	return func(ctx context.Context, update types.TelegramUpdate) (err error) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+
+			frames := panicFrames()
+			report := PanicReport{
+				Value:      fmt.Sprint(recovered),
+				StackHash:  stackHash(recovered, frames),
+				UpdateID:   update.UpdateID,
+				UpdateType: update.Type(),
+				ChatID:     update.ChatID(),
+			}
+			if len(frames) > reportedFrames {
+				frames = frames[:reportedFrames]
+			}
+			report.Frames = frames
+			if user := update.From(); user != nil {
+				report.UserID = user.ID
+			}
+			if req := types.NewCommandRequest(update.Message); req != nil {
+				report.Command = req.Command
+			}
+
+			PanicsRecovered.With(string(report.UpdateType)).Inc()
+			m.logger.WithContext(ctx).Error("Recovered panic while processing update",
+				"panic", report.Value,
+				"stack_hash", report.StackHash,
+				"update_id", report.UpdateID,
+				"update_type", report.UpdateType,
+				"command", report.Command,
+				"chat_id", report.ChatID,
+				"user_id", report.UserID,
+				"stack", string(debug.Stack()),
+			)
+
+			m.apologize(ctx, update)
+			m.reporter.ReportPanic(ctx, report)
+			err = fmt.Errorf("%w: %s", ErrPanic, report.Value)
+		}()
+
+		return next(ctx, update)
+	}
+}
+
+// apologize tells the user their request failed, in their language when known
+func (m *RecoveryMiddleware) apologize(ctx context.Context, update types.TelegramUpdate) {
+	chatID := update.ChatID()
+	if chatID == 0 {
+		return
+	}
+
+	var languageCode *string
+	if user := update.From(); user != nil {
+		languageCode = user.LanguageCode
+	}
+
+	_, err := m.telegramBot.SendMessageWithResponse(ctx, &types.SendMessageRequest{
+		ChatID: chatID,
+		Text:   apologyMessages.Get(i18n.FromCode(languageCode)),
+	})
+	if err != nil {
+		m.logger.WithContext(ctx).Error("Failed to send panic apology", "error", err)
+	}
+}
+
+// panicFrames lists the frames of the panicking goroutine below runtime.gopanic, innermost first.
+// It must be called from the deferred function that recovered.
+func panicFrames() []string {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(1, pcs)
+	iter := runtime.CallersFrames(pcs[:n])
+
+	var frames []string
+	panicking := false
+	for {
+		frame, more := iter.Next()
+		if panicking && !strings.HasPrefix(frame.Function, "runtime.") {
+			frames = append(frames, fmt.Sprintf("%s (%s:%d)", frame.Function, filepath.Base(frame.File), frame.Line))
+		}
+		if frame.Function == "runtime.gopanic" {
+			panicking = true
+		}
+		if !more {
+			break
+		}
+	}
+	return frames
+}
+
+// stackHash identifies a bug by the panic value's type and the functions on the stack,
+// ignoring line numbers so unrelated edits do not split its occurrences
+func stackHash(recovered any, frames []string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%T\n", recovered)
+	for _, frame := range frames {
+		function, _, _ := strings.Cut(frame, " (")
+		fmt.Fprintln(h, function)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"go-telegram-bot/internal/domain/types"
+	"go-telegram-bot/internal/shared/i18n"
+)
+
+func panickingHandler(context.Context, types.TelegramUpdate) error {
+	var profile map[string]string
+	profile["name"] = "boom" // assignment to entry in nil map
+	return nil
+}
+
+func TestRecovery_ReturnsErrorApologizesAndReports(t *testing.T) {
+	bot := &recordingBot{}
+	reporter := NewAlertReporter(bot, -42, time.Hour, nopLogger{})
+	handler := NewRecoveryMiddleware(bot, reporter, nopLogger{}).Wrap(panickingHandler)
+
+	update := commandUpdate("/home_ip", 7)
+	english := "en-US"
+	update.Message.From.LanguageCode = &english
+
+	err := handler(context.Background(), update)
+	if !errors.Is(err, ErrPanic) {
+		t.Fatalf("expected ErrPanic, got %v", err)
+	}
+	if len(bot.sent) != 2 {
+		t.Fatalf("expected an apology and a report, got %v", bot.sent)
+	}
+	if bot.sent[0] != apologyMessages[i18n.English] {
+		t.Errorf("expected the English apology, got %q", bot.sent[0])
+	}
+	report := bot.sent[1]
+	for _, want := range []string{"nil map", "Command: /home_ip", "Chat: 7, user: 7", "panickingHandler (Recovery_test.go:"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report misses %q:\n%s", want, report)
+		}
+	}
+}
+
+func TestAlertReporter_DeduplicatesByStackHash(t *testing.T) {
+	bot := &recordingBot{}
+	reporter := NewAlertReporter(bot, -42, time.Hour, nopLogger{})
+	now := time.Unix(1_700_000_000, 0)
+	reporter.now = func() time.Time { return now }
+
+	report := PanicReport{Value: "boom", StackHash: "abc"}
+	for range 3 {
+		reporter.ReportPanic(context.Background(), report)
+	}
+	reporter.ReportPanic(context.Background(), PanicReport{Value: "other", StackHash: "def"})
+	if len(bot.sent) != 2 {
+		t.Fatalf("expected one report per stack hash, got %d", len(bot.sent))
+	}
+
+	now = now.Add(time.Hour)
+	reporter.ReportPanic(context.Background(), report)
+	if len(bot.sent) != 3 || !strings.Contains(bot.sent[2], "Occurrences: 4 total, 3 since the last report") {
+		t.Fatalf("expected a new report with the occurrences since the last one, got %v", bot.sent)
+	}
+}
+
+func TestFormatPanicReport_TruncatesByRunes(t *testing.T) {
+	// A byte cut at the limit would fall inside a three-byte rune
+	value := "x" + strings.Repeat("ỗ", maxAlertValueLength)
+	report := formatPanicReport(PanicReport{Value: value, StackHash: "abc"}, 1, 1)
+	if !utf8.ValidString(report) {
+		t.Fatalf("expected valid UTF-8, got %q", report)
+	}
+	want := "🚨 Panic: " + string([]rune(value)[:maxAlertValueLength]) + "…\n"
+	if !strings.HasPrefix(report, want) {
+		t.Fatalf("expected the value cut at %d runes, got %q", maxAlertValueLength, report)
+	}
+}
@@ -0,0 +1,198 @@
+// Package cron parses the standard five-field cron expressions and computes their next run
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// macros are the nicknames accepted in place of the five fields
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var dayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// searchLimit bounds the search of the next run, an expression such as "0 0 30 2 *" never matches
+const searchLimit = 5 * 366 * 24 * time.Hour
+
+// Schedule is a parsed expression: minute, hour, day of month, month and day of week.
+// As in Vixie cron, a day matches either day field when both are restricted.
+type Schedule struct {
+	minute, hour, dom, month, dow bitset
+	domAny, dowAny                bool
+}
+
+// bitset holds the accepted values of a field, bit i for value i
+type bitset uint64
+
+func (b bitset) has(value int) bool {
+	return b&(1<<uint(value)) != 0
+}
+
+// Parse parses "minute hour day-of-month month day-of-week" or one of the @ macros.
+// Fields accept *, values, ranges a-b, lists a,b and steps */n or a-b/n, months and days also by name.
+func Parse(expr string) (*Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, ok := macros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron: expected 5 fields, got %d in %q", len(fields), expr)
+	}
+
+	var s Schedule
+	var err error
+	if s.minute, err = parseField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("cron: minute: %w", err)
+	}
+	if s.hour, err = parseField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("cron: hour: %w", err)
+	}
+	if s.dom, err = parseField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("cron: day of month: %w", err)
+	}
+	if s.month, err = parseField(fields[3], 1, 12, monthNames); err != nil {
+		return nil, fmt.Errorf("cron: month: %w", err)
+	}
+	// 7 is Sunday too
+	if s.dow, err = parseField(fields[4], 0, 7, dayNames); err != nil {
+		return nil, fmt.Errorf("cron: day of week: %w", err)
+	}
+	if s.dow.has(7) {
+		s.dow |= 1
+	}
+	s.domAny, s.dowAny = fields[2] == "*", fields[4] == "*"
+	return &s, nil
+}
+
+// Next returns the first matching minute strictly after t in the location of t, the zero time when
+// there is none within five years. Runs in an hour skipped by daylight saving happen right after it,
+// runs in an hour repeated by daylight saving happen once.
+func (s *Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	next := t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(searchLimit)
+
+	for next.Before(limit) {
+		year, month, day := next.Date()
+		switch {
+		case !s.month.has(int(month)):
+			next = time.Date(year, month+1, 1, 0, 0, 0, 0, loc)
+		case !s.dayMatches(next):
+			next = time.Date(year, month, day+1, 0, 0, 0, 0, loc)
+		case !s.hour.has(next.Hour()):
+			following := next.Hour() + 1
+			wall := time.Date(year, month, day, following, 0, 0, 0, loc)
+			// A matching hour skipped by daylight saving runs as soon as the clock is moved forward
+			if following < 24 && wall.Hour() != following && s.hour.has(following) {
+				return wall
+			}
+			next = advance(next, wall, time.Hour)
+		case !s.minute.has(next.Minute()):
+			next = advance(next, time.Date(year, month, day, next.Hour(), next.Minute()+1, 0, 0, loc), time.Minute)
+		default:
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+// advance moves to the wall clock time following current. When a daylight saving change makes that
+// wall clock time ambiguous and it resolves to the past, it moves by step instead.
+func advance(current, wall time.Time, step time.Duration) time.Time {
+	if wall.After(current) {
+		return wall
+	}
+	return current.Truncate(step).Add(step)
+}
+
+// dayMatches applies the day of month and day of week fields to the date of t
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dom, dow := s.dom.has(t.Day()), s.dow.has(int(t.Weekday()))
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dow
+	case s.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// parseField parses the comma separated parts of a field with values between min and max
+func parseField(field string, min, max int, names map[string]int) (bitset, error) {
+	var set bitset
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepPart); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepPart)
+			}
+		}
+
+		low, high := min, max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			from, to, _ := strings.Cut(rangePart, "-")
+			var err error
+			if low, err = parseValue(from, min, max, names); err != nil {
+				return 0, err
+			}
+			if high, err = parseValue(to, min, max, names); err != nil {
+				return 0, err
+			}
+			if low > high {
+				return 0, fmt.Errorf("invalid range %q", rangePart)
+			}
+		default:
+			value, err := parseValue(rangePart, min, max, names)
+			if err != nil {
+				return 0, err
+			}
+			low = value
+			// "5/15" means from 5 to the end by 15
+			if !hasStep {
+				high = value
+			}
+		}
+
+		for value := low; value <= high; value += step {
+			set |= 1 << uint(value)
+		}
+	}
+	return set, nil
+}
+
+// parseValue parses a number or a name between min and max
+func parseValue(value string, min, max int, names map[string]int) (int, error) {
+	if number, ok := names[strings.ToLower(value)]; ok {
+		return number, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number < min || number > max {
+		return 0, fmt.Errorf("invalid value %q, expected %d to %d", value, min, max)
+	}
+	return number, nil
+}
@@ -0,0 +1,76 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *",
+		"* * * * 8", "*/0 * * * *", "5-1 * * * *", "@often", "a * * * *",
+	} {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Parse(%q) succeeded", expr)
+		}
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	hcm, err := time.LoadLocation("Asia/Ho_Chi_Minh")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	// Monday 2026-10-19 08:15:30 in Ho Chi Minh City
+	from := time.Date(2026, 10, 19, 8, 15, 30, 0, hcm)
+
+	cases := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 10, 19, 8, 16, 0, 0, hcm)},
+		{"@hourly", time.Date(2026, 10, 19, 9, 0, 0, 0, hcm)},
+		{"0 9 * * *", time.Date(2026, 10, 19, 9, 0, 0, 0, hcm)},
+		{"0 8 * * *", time.Date(2026, 10, 20, 8, 0, 0, 0, hcm)},
+		{"*/20 8 * * *", time.Date(2026, 10, 19, 8, 20, 0, 0, hcm)},
+		{"0 7 * * sat,sun", time.Date(2026, 10, 24, 7, 0, 0, 0, hcm)},
+		{"0 0 1 jan *", time.Date(2027, 1, 1, 0, 0, 0, 0, hcm)},
+		{"30 6 29 2 *", time.Date(2028, 2, 29, 6, 30, 0, 0, hcm)},
+		// Either day field matches when both are restricted: the 1st or a Friday
+		{"0 12 1 * 5", time.Date(2026, 10, 23, 12, 0, 0, 0, hcm)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, c := range cases {
+		schedule, err := Parse(c.expr)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", c.expr, err)
+		}
+		if got := schedule.Next(from); !got.Equal(c.want) {
+			t.Errorf("Next(%q) = %v, want %v", c.expr, got, c.want)
+		}
+	}
+}
+
+func TestSchedule_NextAcrossDaylightSaving(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	schedule, err := Parse("30 2 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 02:30 does not exist on 2026-03-29, the run moves to the next existing time
+	spring := schedule.Next(time.Date(2026, 3, 28, 12, 0, 0, 0, berlin))
+	if spring.Day() != 29 || spring.Hour() != 3 {
+		t.Errorf("expected the skipped run on the morning of the change, got %v", spring)
+	}
+
+	// 02:30 happens twice on 2026-10-25, the job runs once
+	first := schedule.Next(time.Date(2026, 10, 24, 12, 0, 0, 0, berlin))
+	second := schedule.Next(first)
+	if first.Day() != 25 || second.Day() != 26 {
+		t.Errorf("expected one run on the day of the change, got %v then %v", first, second)
+	}
+}
@@ -0,0 +1,40 @@
+package i18n
+
+import "strings"
+
+// Language is a language the bot answers in, identified by its IETF code
+type Language string
+
+const (
+	Vietnamese Language = "vi"
+	English    Language = "en"
+
+	// Default is used when the user's language is unknown
+	Default = Vietnamese
+)
+
+// FromCode picks the language for a Telegram language_code such as "vi" or "en-US".
+// Unknown codes fall back to English, a missing code to Default.
+func FromCode(code *string) Language {
+	if code == nil || *code == "" {
+		return Default
+	}
+	base, _, _ := strings.Cut(strings.ToLower(*code), "-")
+	switch Language(base) {
+	case Vietnamese:
+		return Vietnamese
+	default:
+		return English
+	}
+}
+
+// Messages holds the translations of one message
+type Messages map[Language]string
+
+// Get returns the translation for lang, or the Default one when it is missing
+func (m Messages) Get(lang Language) string {
+	if text, ok := m[lang]; ok {
+		return text
+	}
+	return m[Default]
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	domainService "go-telegram-bot/internal/domain/service"
 	"go-telegram-bot/internal/infrastructure/config"
+	"go-telegram-bot/internal/shared/tracing"
 	"os"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 // ZapLogger implements domainService.Logger interface using Uber's Zap library.
 type ZapLogger struct {
 	logger *zap.Logger
+	level  zap.AtomicLevel // shared by every logger derived with WithField and friends
 }
 
 // NewZapLogger creates a new instance of ZapLogger based on the provided configuration.
@@ -28,10 +30,11 @@ func NewZapLogger(cfg *config.Config) (*ZapLogger, error) {
 	if cfg.Logger.LogLevel == "" {
 		cfg.Logger.LogLevel = "info"
 	}
-	zapLevel, err := getLogLevel(cfg)
+	zapLevel, err := getLogLevel(cfg.Logger.LogLevel)
 	if err != nil {
 		return nil, err
 	}
+	level := zap.NewAtomicLevelAt(*zapLevel)
 
 	// Configure the encoder and output
 	encoder := getEncoderLogger()
@@ -52,7 +55,7 @@ func NewZapLogger(cfg *config.Config) (*ZapLogger, error) {
 
 	// Create a sampler to limit log volume for high-frequency logs
 	core := zapcore.NewSamplerWithOptions(
-		zapcore.NewCore(encoder, write, zapLevel),
+		zapcore.NewCore(encoder, write, level),
 		time.Second,
 		100,
 		1,
@@ -63,14 +66,24 @@ func NewZapLogger(cfg *config.Config) (*ZapLogger, error) {
 		core, zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel),
 	)
 
-	return &ZapLogger{logger}, nil
+	return &ZapLogger{logger: logger, level: level}, nil
+}
+
+// SetLevel changes the minimum level of this logger and every logger derived from it
+func (z *ZapLogger) SetLevel(level string) error {
+	zapLevel, err := getLogLevel(level)
+	if err != nil {
+		return err
+	}
+	z.level.SetLevel(*zapLevel)
+	return nil
 }
 
 // getLogLevel maps string log levels from config to zapcore.Level.
-func getLogLevel(cfg *config.Config) (*zapcore.Level, error) {
+func getLogLevel(level string) (*zapcore.Level, error) {
 	var zapLevel zapcore.Level
 
-	switch cfg.Logger.LogLevel {
+	switch level {
 	case "debug":
 		zapLevel = zap.DebugLevel
 	case "info":
@@ -86,7 +99,7 @@ func getLogLevel(cfg *config.Config) (*zapcore.Level, error) {
 	case "fatal":
 		zapLevel = zap.FatalLevel
 	default:
-		return nil, fmt.Errorf("invalid log level: %s", cfg.Logger.LogLevel)
+		return nil, fmt.Errorf("invalid log level: %s", level)
 	}
 	return &zapLevel, nil
 }
@@ -160,17 +173,23 @@ func (z *ZapLogger) convertFields(fields ...any) []zap.Field {
 	return zapFields
 }
 
-// WithContext returns a logger with context (for now, returns the same logger as zap doesn't directly support context)
+// WithContext returns a logger tagged with the trace and span IDs of the span carried by ctx
 func (z *ZapLogger) WithContext(ctx context.Context) domainService.Logger {
-	// For now, we return the same logger. In a more advanced implementation,
-	// we could extract trace/span information from context
-	return z
+	span := tracing.SpanFromContext(ctx)
+	if span == nil {
+		return z
+	}
+	newLogger := z.logger.With(
+		zap.String("trace_id", span.TraceID().String()),
+		zap.String("span_id", span.SpanID().String()),
+	)
+	return &ZapLogger{logger: newLogger, level: z.level}
 }
 
 // WithField returns a logger with a single field
 func (z *ZapLogger) WithField(key string, value any) domainService.Logger {
 	newLogger := z.logger.With(zap.Any(key, value))
-	return &ZapLogger{logger: newLogger}
+	return &ZapLogger{logger: newLogger, level: z.level}
 }
 
 // WithFields returns a logger with multiple fields
@@ -180,5 +199,5 @@ func (z *ZapLogger) WithFields(fields map[string]any) domainService.Logger {
 		zapFields = append(zapFields, zap.Any(key, value))
 	}
 	newLogger := z.logger.With(zapFields...)
-	return &ZapLogger{logger: newLogger}
+	return &ZapLogger{logger: newLogger, level: z.level}
 }
@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultLatencyBuckets are upper bounds in seconds suited to HTTP and database calls
+var DefaultLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// Histogram counts observations into cumulative buckets, safe for concurrent use
+type Histogram struct {
+	bounds  []float64
+	counts  []atomic.Uint64
+	count   atomic.Uint64
+	sumBits atomic.Uint64
+}
+
+// HistogramSnapshot is a consistent enough copy of a histogram for reporting
+type HistogramSnapshot struct {
+	Bounds []float64
+	// Cumulative holds the number of observations less or equal to each bound
+	Cumulative []uint64
+	Count      uint64
+	Sum        float64
+}
+
+// NewHistogram creates a histogram with the given bucket upper bounds
+func NewHistogram(bounds []float64) *Histogram {
+	sorted := append([]float64(nil), bounds...)
+	sort.Float64s(sorted)
+	return &Histogram{
+		bounds: sorted,
+		counts: make([]atomic.Uint64, len(sorted)),
+	}
+}
+
+// Observe records a single value
+func (h *Histogram) Observe(value float64) {
+	idx := sort.SearchFloat64s(h.bounds, value)
+	if idx < len(h.counts) {
+		h.counts[idx].Add(1)
+	}
+	h.count.Add(1)
+
+	for {
+		old := h.sumBits.Load()
+		updated := math.Float64bits(math.Float64frombits(old) + value)
+		if h.sumBits.CompareAndSwap(old, updated) {
+			return
+		}
+	}
+}
+
+// ObserveDuration records a duration in seconds
+func (h *Histogram) ObserveDuration(d time.Duration) {
+	h.Observe(d.Seconds())
+}
+
+// Count returns the number of observations
+func (h *Histogram) Count() uint64 {
+	return h.count.Load()
+}
+
+// Sum returns the sum of all observations
+func (h *Histogram) Sum() float64 {
+	return math.Float64frombits(h.sumBits.Load())
+}
+
+// Mean returns the average observation or zero when nothing was observed
+func (h *Histogram) Mean() float64 {
+	count := h.Count()
+	if count == 0 {
+		return 0
+	}
+	return h.Sum() / float64(count)
+}
+
+// Snapshot returns the cumulative bucket counts
+func (h *Histogram) Snapshot() HistogramSnapshot {
+	snapshot := HistogramSnapshot{
+		Bounds:     h.bounds,
+		Cumulative: make([]uint64, len(h.bounds)),
+		Count:      h.Count(),
+		Sum:        h.Sum(),
+	}
+
+	var cumulative uint64
+	for i := range h.counts {
+		cumulative += h.counts[i].Load()
+		snapshot.Cumulative[i] = cumulative
+	}
+	return snapshot
+}
+
+// Reset clears all observations
+func (h *Histogram) Reset() {
+	for i := range h.counts {
+		h.counts[i].Store(0)
+	}
+	h.count.Store(0)
+	h.sumBits.Store(0)
+}
+
+// HistogramVec is a set of histograms partitioned by label values
+type HistogramVec struct {
+	vec[Histogram]
+}
+
+// NewHistogramVec creates a histogram vector with the given buckets and label names
+func NewHistogramVec(bounds []float64, labels ...string) *HistogramVec {
+	return &HistogramVec{newVec(labels, func() *Histogram { return NewHistogram(bounds) })}
+}
+
+// With returns the histogram for the given label values
+func (v *HistogramVec) With(values ...string) *Histogram {
+	return v.with(values...)
+}
+
+// Each calls fn for every histogram in the vector
+func (v *HistogramVec) Each(fn func(values []string, histogram *Histogram)) {
+	v.each(fn)
+}
+
+// Reset clears the observations of every histogram in the vector
+func (v *HistogramVec) Reset() {
+	v.reset((*Histogram).Reset)
+}
@@ -0,0 +1,202 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// labelSeparator joins label values into a single map key, it cannot appear in sane label values
+const labelSeparator = "\xff"
+
+// Counter is a monotonically increasing value safe for concurrent use
+type Counter struct {
+	value atomic.Uint64
+}
+
+// Inc increments the counter by one
+func (c *Counter) Inc() {
+	c.value.Add(1)
+}
+
+// Add increments the counter by n
+func (c *Counter) Add(n uint64) {
+	c.value.Add(n)
+}
+
+// Value returns the current counter value
+func (c *Counter) Value() uint64 {
+	return c.value.Load()
+}
+
+// Reset sets the counter back to zero
+func (c *Counter) Reset() {
+	c.value.Store(0)
+}
+
+// Gauge is a value that can go up and down, safe for concurrent use
+type Gauge struct {
+	bits atomic.Uint64
+}
+
+// Set stores the given value
+func (g *Gauge) Set(value float64) {
+	g.bits.Store(math.Float64bits(value))
+}
+
+// Add adds delta to the gauge, delta may be negative
+func (g *Gauge) Add(delta float64) {
+	for {
+		old := g.bits.Load()
+		updated := math.Float64bits(math.Float64frombits(old) + delta)
+		if g.bits.CompareAndSwap(old, updated) {
+			return
+		}
+	}
+}
+
+// Inc adds one to the gauge
+func (g *Gauge) Inc() {
+	g.Add(1)
+}
+
+// Dec subtracts one from the gauge
+func (g *Gauge) Dec() {
+	g.Add(-1)
+}
+
+// Value returns the current gauge value
+func (g *Gauge) Value() float64 {
+	return math.Float64frombits(g.bits.Load())
+}
+
+// vec holds one child metric per distinct combination of label values
+type vec[T any] struct {
+	labels   []string
+	mu       sync.RWMutex
+	children map[string]*T
+	values   map[string][]string
+	newChild func() *T
+}
+
+func newVec[T any](labels []string, newChild func() *T) vec[T] {
+	return vec[T]{
+		labels:   labels,
+		children: make(map[string]*T),
+		values:   make(map[string][]string),
+		newChild: newChild,
+	}
+}
+
+// with returns the child for the label values, creating it on first use
+func (v *vec[T]) with(values ...string) *T {
+	if len(values) != len(v.labels) {
+		panic("metrics: label value count does not match label names")
+	}
+	key := strings.Join(values, labelSeparator)
+
+	v.mu.RLock()
+	child, ok := v.children[key]
+	v.mu.RUnlock()
+	if ok {
+		return child
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if child, ok := v.children[key]; ok {
+		return child
+	}
+	child = v.newChild()
+	v.children[key] = child
+	v.values[key] = append([]string(nil), values...)
+	return child
+}
+
+// each calls fn for every child in a stable order of label values
+func (v *vec[T]) each(fn func(values []string, child *T)) {
+	v.mu.RLock()
+	keys := make([]string, 0, len(v.children))
+	for key := range v.children {
+		keys = append(keys, key)
+	}
+	v.mu.RUnlock()
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		v.mu.RLock()
+		child, values := v.children[key], v.values[key]
+		v.mu.RUnlock()
+		if child != nil {
+			fn(values, child)
+		}
+	}
+}
+
+// reset zeroes every child in place, a writer still holding a child keeps counting into the vector
+func (v *vec[T]) reset(zero func(child *T)) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	for _, child := range v.children {
+		zero(child)
+	}
+}
+
+// CounterVec is a set of counters partitioned by label values
+type CounterVec struct {
+	vec[Counter]
+}
+
+// NewCounterVec creates a counter vector with the given label names
+func NewCounterVec(labels ...string) *CounterVec {
+	return &CounterVec{newVec(labels, func() *Counter { return &Counter{} })}
+}
+
+// With returns the counter for the given label values
+func (v *CounterVec) With(values ...string) *Counter {
+	return v.with(values...)
+}
+
+// Each calls fn for every counter in the vector
+func (v *CounterVec) Each(fn func(values []string, counter *Counter)) {
+	v.each(fn)
+}
+
+// Total returns the sum of all counters in the vector
+func (v *CounterVec) Total() uint64 {
+	var total uint64
+	v.each(func(_ []string, c *Counter) { total += c.Value() })
+	return total
+}
+
+// Reset sets every counter in the vector back to zero
+func (v *CounterVec) Reset() {
+	v.reset((*Counter).Reset)
+}
+
+// GaugeVec is a set of gauges partitioned by label values
+type GaugeVec struct {
+	vec[Gauge]
+}
+
+// NewGaugeVec creates a gauge vector with the given label names
+func NewGaugeVec(labels ...string) *GaugeVec {
+	return &GaugeVec{newVec(labels, func() *Gauge { return &Gauge{} })}
+}
+
+// With returns the gauge for the given label values
+func (v *GaugeVec) With(values ...string) *Gauge {
+	return v.with(values...)
+}
+
+// Each calls fn for every gauge in the vector
+func (v *GaugeVec) Each(fn func(values []string, gauge *Gauge)) {
+	v.each(fn)
+}
+
+// Reset sets every gauge in the vector back to zero
+func (v *GaugeVec) Reset() {
+	v.reset(func(g *Gauge) { g.Set(0) })
+}
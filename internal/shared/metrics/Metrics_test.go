@@ -0,0 +1,26 @@
+package metrics
+
+import "testing"
+
+func TestVec_ResetKeepsChildren(t *testing.T) {
+	requests := NewCounterVec("method")
+	counter := requests.With("sendMessage")
+	counter.Add(3)
+	latency := NewHistogramVec([]float64{0.1, 1}, "method")
+	histogram := latency.With("sendMessage")
+	histogram.Observe(0.5)
+
+	requests.Reset()
+	latency.Reset()
+	if requests.Total() != 0 || histogram.Count() != 0 || histogram.Sum() != 0 {
+		t.Fatalf("expected zeroed metrics, got %d requests and %d observations", requests.Total(), histogram.Count())
+	}
+
+	// A writer which took its child before the reset still counts into the vector
+	counter.Inc()
+	histogram.Observe(0.05)
+	if requests.With("sendMessage").Value() != 1 || latency.With("sendMessage").Count() != 1 {
+		t.Fatalf("expected the increments after the reset to be kept, got %d requests and %d observations",
+			requests.Total(), latency.With("sendMessage").Count())
+	}
+}
@@ -0,0 +1,173 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Metric family types as named by the Prometheus text format
+const (
+	TypeCounter   = "counter"
+	TypeGauge     = "gauge"
+	TypeHistogram = "histogram"
+)
+
+// Default is the process-wide registry served by the admin server
+var Default = NewRegistry()
+
+// Sample is a single value with its label values, used by collector functions
+type Sample struct {
+	LabelValues []string
+	Value       float64
+}
+
+// family is a registered metric family able to write itself in the text format
+type family struct {
+	name  string
+	help  string
+	kind  string
+	write func(w *bufio.Writer, f *family)
+}
+
+// Registry holds metric families and renders them in the Prometheus text exposition format
+type Registry struct {
+	mu       sync.RWMutex
+	families map[string]*family
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]*family)}
+}
+
+// register adds a family, replacing any family previously registered under the same name
+func (r *Registry) register(f *family) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.families[f.name] = f
+}
+
+// RegisterCounterVec exposes a counter vector under the given name
+func (r *Registry) RegisterCounterVec(name, help string, v *CounterVec) {
+	r.register(&family{name: name, help: help, kind: TypeCounter, write: func(w *bufio.Writer, f *family) {
+		v.Each(func(values []string, c *Counter) {
+			writeSample(w, f.name, v.labels, values, "", "", float64(c.Value()))
+		})
+	}})
+}
+
+// RegisterGaugeVec exposes a gauge vector under the given name
+func (r *Registry) RegisterGaugeVec(name, help string, v *GaugeVec) {
+	r.register(&family{name: name, help: help, kind: TypeGauge, write: func(w *bufio.Writer, f *family) {
+		v.Each(func(values []string, g *Gauge) {
+			writeSample(w, f.name, v.labels, values, "", "", g.Value())
+		})
+	}})
+}
+
+// RegisterHistogramVec exposes a histogram vector under the given name
+func (r *Registry) RegisterHistogramVec(name, help string, v *HistogramVec) {
+	r.register(&family{name: name, help: help, kind: TypeHistogram, write: func(w *bufio.Writer, f *family) {
+		v.Each(func(values []string, h *Histogram) {
+			snapshot := h.Snapshot()
+			for i, bound := range snapshot.Bounds {
+				writeSample(w, f.name+"_bucket", v.labels, values, "le", formatFloat(bound), float64(snapshot.Cumulative[i]))
+			}
+			writeSample(w, f.name+"_bucket", v.labels, values, "le", "+Inf", float64(snapshot.Count))
+			writeSample(w, f.name+"_sum", v.labels, values, "", "", snapshot.Sum)
+			writeSample(w, f.name+"_count", v.labels, values, "", "", float64(snapshot.Count))
+		})
+	}})
+}
+
+// RegisterFunc exposes values computed at scrape time, kind is TypeCounter or TypeGauge
+func (r *Registry) RegisterFunc(name, help, kind string, labels []string, collect func() []Sample) {
+	r.register(&family{name: name, help: help, kind: kind, write: func(w *bufio.Writer, f *family) {
+		for _, sample := range collect() {
+			writeSample(w, f.name, labels, sample.LabelValues, "", "", sample.Value)
+		}
+	}})
+}
+
+// WritePrometheus renders every family sorted by name
+func (r *Registry) WritePrometheus(out io.Writer) error {
+	r.mu.RLock()
+	families := make([]*family, 0, len(r.families))
+	for _, f := range r.families {
+		families = append(families, f)
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(families, func(i, j int) bool { return families[i].name < families[j].name })
+
+	w := bufio.NewWriter(out)
+	for _, f := range families {
+		fmt.Fprintf(w, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+		fmt.Fprintf(w, "# TYPE %s %s\n", f.name, f.kind)
+		f.write(w, f)
+	}
+	return w.Flush()
+}
+
+// writeSample writes one sample line, extraName and extraValue add a label such as "le"
+func writeSample(w *bufio.Writer, name string, labels, values []string, extraName, extraValue string, value float64) {
+	w.WriteString(name)
+
+	if len(labels) > 0 || extraName != "" {
+		w.WriteByte('{')
+		for i, label := range labels {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			var labelValue string
+			if i < len(values) {
+				labelValue = values[i]
+			}
+			fmt.Fprintf(w, "%s=\"%s\"", label, escapeLabelValue(labelValue))
+		}
+		if extraName != "" {
+			if len(labels) > 0 {
+				w.WriteByte(',')
+			}
+			fmt.Fprintf(w, "%s=\"%s\"", extraName, extraValue)
+		}
+		w.WriteByte('}')
+	}
+
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(value))
+	w.WriteByte('\n')
+}
+
+// formatFloat renders a float the way Prometheus expects it
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
+
+var (
+	helpReplacer  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(help string) string {
+	return helpReplacer.Replace(help)
+}
+
+func escapeLabelValue(value string) string {
+	return labelReplacer.Replace(value)
+}
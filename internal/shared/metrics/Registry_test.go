@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegistry_WritePrometheus(t *testing.T) {
+	reg := NewRegistry()
+
+	requests := NewCounterVec("method")
+	requests.With("sendMessage").Add(3)
+	reg.RegisterCounterVec("api_requests_total", "Requests made.", requests)
+
+	latency := NewHistogramVec([]float64{0.1, 1}, "method")
+	latency.With("getMe").Observe(0.05)
+	latency.With("getMe").Observe(0.5)
+	latency.With("getMe").Observe(2)
+	reg.RegisterHistogramVec("api_latency_seconds", "Latency.", latency)
+
+	reg.RegisterFunc("pool_open", "Open connections.", TypeGauge, nil, func() []Sample {
+		return []Sample{{Value: 4}}
+	})
+
+	var out strings.Builder
+	if err := reg.WritePrometheus(&out); err != nil {
+		t.Fatalf("WritePrometheus failed: %v", err)
+	}
+
+	expected := `# HELP api_latency_seconds Latency.
+# TYPE api_latency_seconds histogram
+api_latency_seconds_bucket{method="getMe",le="0.1"} 1
+api_latency_seconds_bucket{method="getMe",le="1"} 2
+api_latency_seconds_bucket{method="getMe",le="+Inf"} 3
+api_latency_seconds_sum{method="getMe"} 2.55
+api_latency_seconds_count{method="getMe"} 3
+# HELP api_requests_total Requests made.
+# TYPE api_requests_total counter
+api_requests_total{method="sendMessage"} 3
+# HELP pool_open Open connections.
+# TYPE pool_open gauge
+pool_open 4
+`
+	if out.String() != expected {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+}
+
+func TestRegistry_EscapesLabelValues(t *testing.T) {
+	reg := NewRegistry()
+	counter := NewCounterVec("command")
+	counter.With(`say "hi"`).Inc()
+	reg.RegisterCounterVec("commands_total", "Commands.", counter)
+
+	var out strings.Builder
+	reg.WritePrometheus(&out)
+
+	if !strings.Contains(out.String(), `commands_total{command="say \"hi\""} 1`) {
+		t.Fatalf("label value not escaped:\n%s", out.String())
+	}
+}
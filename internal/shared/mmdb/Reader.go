@@ -0,0 +1,362 @@
+// Package mmdb reads MaxMind DB files, the format of the GeoLite2 and DB-IP Lite databases, see
+// https://maxmind.github.io/MaxMind-DB/
+package mmdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net/netip"
+	"os"
+)
+
+// metadataMarker precedes the metadata map at the end of the file
+var metadataMarker = []byte("\xab\xcd\xefMaxMind.com")
+
+// metadataMaxSize bounds the search of the marker, the metadata is at most 128KiB
+const metadataMaxSize = 128 * 1024
+
+// maxDepth bounds the nesting of decoded values, so that a corrupt file cannot exhaust the stack
+const maxDepth = 64
+
+// ErrInvalidDatabase is returned for files which do not follow the format
+var ErrInvalidDatabase = errors.New("invalid MaxMind DB")
+
+// Data types of the data section
+const (
+	typeExtended = iota
+	typePointer
+	typeString
+	typeDouble
+	typeBytes
+	typeUint16
+	typeUint32
+	typeMap
+	typeInt32
+	typeUint64
+	typeUint128
+	typeArray
+	typeContainer
+	typeEndMarker
+	typeBoolean
+	typeFloat
+)
+
+// Metadata describes a database
+type Metadata struct {
+	DatabaseType string
+	Languages    []string
+	IPVersion    uint64
+	NodeCount    uint64
+	RecordSize   uint64
+	BuildEpoch   uint64
+}
+
+// Reader looks up addresses in a database held in memory, it is safe for concurrent use
+type Reader struct {
+	buf       []byte
+	metadata  Metadata
+	treeSize  uint64
+	ipv4Start uint64
+}
+
+// Open reads the database at path
+func Open(path string) (*Reader, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	r, err := FromBytes(buf)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return r, nil
+}
+
+// FromBytes reads a database from its content, buf must not be modified afterwards
+func FromBytes(buf []byte) (*Reader, error) {
+	start := max(0, len(buf)-metadataMaxSize)
+	marker := bytes.LastIndex(buf[start:], metadataMarker)
+	if marker < 0 {
+		return nil, fmt.Errorf("%w: metadata not found", ErrInvalidDatabase)
+	}
+	metadataStart := start + marker + len(metadataMarker)
+	value, _, err := decoder{data: buf[metadataStart:]}.decode(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%w: metadata: %w", ErrInvalidDatabase, err)
+	}
+	m, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: metadata is not a map", ErrInvalidDatabase)
+	}
+
+	r := &Reader{buf: buf}
+	r.metadata.DatabaseType, _ = m["database_type"].(string)
+	r.metadata.IPVersion, _ = m["ip_version"].(uint64)
+	r.metadata.NodeCount, _ = m["node_count"].(uint64)
+	r.metadata.RecordSize, _ = m["record_size"].(uint64)
+	r.metadata.BuildEpoch, _ = m["build_epoch"].(uint64)
+	languages, _ := m["languages"].([]any)
+	for _, language := range languages {
+		if language, ok := language.(string); ok {
+			r.metadata.Languages = append(r.metadata.Languages, language)
+		}
+	}
+
+	switch r.metadata.RecordSize {
+	case 24, 28, 32:
+	default:
+		return nil, fmt.Errorf("%w: unsupported record size %d", ErrInvalidDatabase, r.metadata.RecordSize)
+	}
+	if r.metadata.IPVersion != 4 && r.metadata.IPVersion != 6 {
+		return nil, fmt.Errorf("%w: unsupported IP version %d", ErrInvalidDatabase, r.metadata.IPVersion)
+	}
+	// The tree and the 16 zero bytes separating it from the data section come before the metadata
+	r.treeSize = r.metadata.NodeCount * r.metadata.RecordSize / 4
+	if r.treeSize+16 > uint64(start+marker) {
+		return nil, fmt.Errorf("%w: search tree larger than the file", ErrInvalidDatabase)
+	}
+
+	// IPv4 addresses are stored as ::a.b.c.d in IPv6 databases, their subtree is found once
+	if r.metadata.IPVersion == 6 {
+		for i := 0; i < 96 && r.ipv4Start < r.metadata.NodeCount; i++ {
+			r.ipv4Start = r.record(r.ipv4Start, 0)
+		}
+	}
+	return r, nil
+}
+
+// Metadata describes the database
+func (r *Reader) Metadata() Metadata {
+	return r.metadata
+}
+
+// Lookup returns the record of the network holding addr, nil when the database has none.
+// Maps are map[string]any, arrays []any and unsigned integers uint64.
+func (r *Reader) Lookup(addr netip.Addr) (any, error) {
+	addr = addr.Unmap()
+	node, bits := uint64(0), addr.AsSlice()
+	if addr.Is4() {
+		node = r.ipv4Start
+	} else if r.metadata.IPVersion == 4 {
+		return nil, nil
+	}
+
+	for i := 0; i < len(bits)*8 && node < r.metadata.NodeCount; i++ {
+		bit := bits[i/8] >> (7 - i%8) & 1
+		node = r.record(node, bit)
+	}
+	switch {
+	case node == r.metadata.NodeCount:
+		return nil, nil
+	case node < r.metadata.NodeCount:
+		return nil, fmt.Errorf("%w: search tree deeper than the address", ErrInvalidDatabase)
+	}
+
+	data := r.buf[r.treeSize+16:]
+	offset := node - r.metadata.NodeCount - 16
+	if offset >= uint64(len(data)) {
+		return nil, fmt.Errorf("%w: record outside the data section", ErrInvalidDatabase)
+	}
+	value, _, err := decoder{data: data}.decode(int(offset), 0)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDatabase, err)
+	}
+	return value, nil
+}
+
+// record reads the left (0) or right (1) record of a node
+func (r *Reader) record(node uint64, bit byte) uint64 {
+	size := r.metadata.RecordSize
+	b := r.buf[node*size/4:]
+	switch size {
+	case 24:
+		b = b[uint64(bit)*3:]
+		return uint64(b[0])<<16 | uint64(b[1])<<8 | uint64(b[2])
+	case 28:
+		// The middle byte holds the high nibble of both records
+		if bit == 0 {
+			return uint64(b[3]&0xf0)<<20 | uint64(b[0])<<16 | uint64(b[1])<<8 | uint64(b[2])
+		}
+		return uint64(b[3]&0x0f)<<24 | uint64(b[4])<<16 | uint64(b[5])<<8 | uint64(b[6])
+	default:
+		return uint64(binary.BigEndian.Uint32(b[uint64(bit)*4:]))
+	}
+}
+
+// Get walks record along path, map keys as strings and array indexes as ints, nil when a step is missing
+func Get(record any, path ...any) any {
+	for _, step := range path {
+		switch key := step.(type) {
+		case string:
+			m, ok := record.(map[string]any)
+			if !ok {
+				return nil
+			}
+			record = m[key]
+		case int:
+			a, ok := record.([]any)
+			if !ok || key < 0 || key >= len(a) {
+				return nil
+			}
+			record = a[key]
+		default:
+			return nil
+		}
+	}
+	return record
+}
+
+// decoder decodes values of a data section, pointers are offsets in data
+type decoder struct {
+	data []byte
+}
+
+var errTruncated = errors.New("value past the end of the data")
+
+// decode returns the value at offset and the offset following it
+func (d decoder) decode(offset, depth int) (any, int, error) {
+	if depth > maxDepth {
+		return nil, 0, errors.New("values nested too deeply")
+	}
+	if offset >= len(d.data) {
+		return nil, 0, errTruncated
+	}
+	control := d.data[offset]
+	offset++
+	kind := int(control >> 5)
+
+	if kind == typePointer {
+		pointer, next, err := d.pointer(control, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		value, _, err := d.decode(pointer, depth+1)
+		return value, next, err
+	}
+	if kind == typeExtended {
+		if offset >= len(d.data) {
+			return nil, 0, errTruncated
+		}
+		kind = 7 + int(d.data[offset])
+		offset++
+	}
+
+	size := int(control & 0x1f)
+	if size >= 29 {
+		extra := size - 28
+		if offset+extra > len(d.data) {
+			return nil, 0, errTruncated
+		}
+		n := 0
+		for _, b := range d.data[offset : offset+extra] {
+			n = n<<8 | int(b)
+		}
+		size = [...]int{29, 285, 65821}[extra-1] + n
+		offset += extra
+	}
+
+	switch kind {
+	case typeMap:
+		m := make(map[string]any, size)
+		for range size {
+			key, next, err := d.decode(offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			name, ok := key.(string)
+			if !ok {
+				return nil, 0, fmt.Errorf("map key of type %T", key)
+			}
+			m[name], offset, err = d.decode(next, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+		return m, offset, nil
+	case typeArray:
+		a := make([]any, 0, min(size, len(d.data)))
+		for range size {
+			value, next, err := d.decode(offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			a = append(a, value)
+			offset = next
+		}
+		return a, offset, nil
+	case typeBoolean:
+		return size != 0, offset, nil
+	case typeEndMarker, typeContainer:
+		return nil, 0, fmt.Errorf("unexpected data type %d", kind)
+	}
+
+	if offset+size > len(d.data) {
+		return nil, 0, errTruncated
+	}
+	b := d.data[offset : offset+size]
+	offset += size
+	switch kind {
+	case typeString:
+		return string(b), offset, nil
+	case typeBytes:
+		return bytes.Clone(b), offset, nil
+	case typeDouble:
+		if size != 8 {
+			return nil, 0, fmt.Errorf("double of %d bytes", size)
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), offset, nil
+	case typeFloat:
+		if size != 4 {
+			return nil, 0, fmt.Errorf("float of %d bytes", size)
+		}
+		return math.Float32frombits(binary.BigEndian.Uint32(b)), offset, nil
+	case typeUint16, typeUint32, typeUint64:
+		if size > [...]int{typeUint16: 2, typeUint32: 4, typeUint64: 8}[kind] {
+			return nil, 0, fmt.Errorf("unsigned integer of %d bytes", size)
+		}
+		var n uint64
+		for _, c := range b {
+			n = n<<8 | uint64(c)
+		}
+		return n, offset, nil
+	case typeInt32:
+		if size > 4 {
+			return nil, 0, fmt.Errorf("int32 of %d bytes", size)
+		}
+		var n uint32
+		for _, c := range b {
+			n = n<<8 | uint32(c)
+		}
+		return int64(int32(n)), offset, nil
+	case typeUint128:
+		if size > 16 {
+			return nil, 0, fmt.Errorf("uint128 of %d bytes", size)
+		}
+		return new(big.Int).SetBytes(b), offset, nil
+	}
+	return nil, 0, fmt.Errorf("unknown data type %d", kind)
+}
+
+// pointer reads the pointer whose control byte is control, its bytes start at offset
+func (d decoder) pointer(control byte, offset int) (int, int, error) {
+	size := int(control>>3)&0x3 + 1
+	if offset+size > len(d.data) {
+		return 0, 0, errTruncated
+	}
+	b := d.data[offset : offset+size]
+	var pointer int
+	if size == 4 {
+		pointer = int(binary.BigEndian.Uint32(b))
+	} else {
+		pointer = int(control & 0x7)
+		for _, c := range b {
+			pointer = pointer<<8 | int(c)
+		}
+		pointer += [...]int{0, 2048, 526336}[size-1]
+	}
+	return pointer, offset + size, nil
+}
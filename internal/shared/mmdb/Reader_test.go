@@ -0,0 +1,254 @@
+package mmdb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"net/netip"
+	"slices"
+	"testing"
+)
+
+// encoder writes values of the data section, the types of the Go values pick the MaxMind DB types
+type encoder struct {
+	buf []byte
+}
+
+type pointerTo int
+
+func (e *encoder) control(kind, size int) {
+	// Types past 7 are extended, the control byte holds 0 and the next byte the type minus 7
+	var first byte
+	if kind <= 7 {
+		first = byte(kind << 5)
+	}
+	var extra []byte
+	switch {
+	case size < 29:
+		first |= byte(size)
+	case size < 285:
+		first |= 29
+		extra = []byte{byte(size - 29)}
+	default:
+		first |= 30
+		extra = binary.BigEndian.AppendUint16(nil, uint16(size-285))
+	}
+	e.buf = append(e.buf, first)
+	if kind > 7 {
+		e.buf = append(e.buf, byte(kind-7))
+	}
+	e.buf = append(e.buf, extra...)
+}
+
+func (e *encoder) encode(value any) int {
+	offset := len(e.buf)
+	switch v := value.(type) {
+	case pointerTo:
+		e.buf = append(e.buf, byte(typePointer<<5|int(v)>>8), byte(v))
+	case string:
+		e.control(typeString, len(v))
+		e.buf = append(e.buf, v...)
+	case uint16:
+		e.control(typeUint16, 2)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, v)
+	case uint32:
+		e.control(typeUint32, 4)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, v)
+	case uint64:
+		e.control(typeUint64, 8)
+		e.buf = binary.BigEndian.AppendUint64(e.buf, v)
+	case int32:
+		e.control(typeInt32, 4)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(v))
+	case float64:
+		e.control(typeDouble, 8)
+		e.buf = binary.BigEndian.AppendUint64(e.buf, math.Float64bits(v))
+	case bool:
+		size := 0
+		if v {
+			size = 1
+		}
+		e.control(typeBoolean, size)
+	case []any:
+		e.control(typeArray, len(v))
+		for _, item := range v {
+			e.encode(item)
+		}
+	case map[string]any:
+		e.control(typeMap, len(v))
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			e.encode(key)
+			e.encode(v[key])
+		}
+	default:
+		panic(fmt.Sprintf("cannot encode %T", value))
+	}
+	return offset
+}
+
+// trieNode is a node of the search tree, a child is a *trieNode, a data offset or nil
+type trieNode struct {
+	children [2]any
+}
+
+// buildDatabase lays out the networks, each mapped to a data offset, as a database with recordSize
+func buildDatabase(t *testing.T, ipVersion, recordSize int, networks map[netip.Prefix]int, data []byte) []byte {
+	t.Helper()
+	root := &trieNode{}
+	for prefix, offset := range networks {
+		node, bits, length := root, prefix.Addr().AsSlice(), prefix.Bits()
+		if ipVersion == 6 && prefix.Addr().Is4() {
+			// IPv4 networks live below ::/96
+			bits, length = append(make([]byte, 12), bits...), length+96
+		}
+		for i := range length {
+			bit := bits[i/8] >> (7 - i%8) & 1
+			if i == length-1 {
+				node.children[bit] = offset
+				break
+			}
+			next, ok := node.children[bit].(*trieNode)
+			if !ok {
+				next = &trieNode{}
+				node.children[bit] = next
+			}
+			node = next
+		}
+	}
+
+	var nodes []*trieNode
+	ids := map[*trieNode]int{}
+	var number func(*trieNode)
+	number = func(n *trieNode) {
+		ids[n] = len(nodes)
+		nodes = append(nodes, n)
+		for _, child := range n.children {
+			if child, ok := child.(*trieNode); ok {
+				number(child)
+			}
+		}
+	}
+	number(root)
+
+	count := len(nodes)
+	var tree []byte
+	for _, n := range nodes {
+		var records [2]uint32
+		for i, child := range n.children {
+			switch child := child.(type) {
+			case *trieNode:
+				records[i] = uint32(ids[child])
+			case int:
+				records[i] = uint32(count + 16 + child)
+			default:
+				records[i] = uint32(count)
+			}
+		}
+		switch recordSize {
+		case 24:
+			tree = append(tree, byte(records[0]>>16), byte(records[0]>>8), byte(records[0]),
+				byte(records[1]>>16), byte(records[1]>>8), byte(records[1]))
+		case 28:
+			tree = append(tree, byte(records[0]>>16), byte(records[0]>>8), byte(records[0]),
+				byte(records[0]>>24)<<4|byte(records[1]>>24),
+				byte(records[1]>>16), byte(records[1]>>8), byte(records[1]))
+		default:
+			tree = binary.BigEndian.AppendUint32(tree, records[0])
+			tree = binary.BigEndian.AppendUint32(tree, records[1])
+		}
+	}
+
+	db := append(tree, make([]byte, 16)...)
+	db = append(db, data...)
+	db = append(db, metadataMarker...)
+	metadata := &encoder{}
+	metadata.encode(map[string]any{
+		"binary_format_major_version": uint16(2),
+		"build_epoch":                 uint64(1760000000),
+		"database_type":               "Test-City",
+		"ip_version":                  uint16(ipVersion),
+		"languages":                   []any{"en", "vi"},
+		"node_count":                  uint32(count),
+		"record_size":                 uint16(recordSize),
+	})
+	return append(db, metadata.buf...)
+}
+
+func TestReader_Lookup(t *testing.T) {
+	data := &encoder{}
+	org := data.encode("An organisation whose name needs the extended size byte")
+	city := data.encode(map[string]any{
+		"city":         map[string]any{"names": map[string]any{"en": "Hanoi"}},
+		"country":      map[string]any{"iso_code": "VN", "names": map[string]any{"en": "Vietnam"}},
+		"location":     map[string]any{"latitude": 21.0292, "longitude": 105.8526},
+		"subdivisions": []any{map[string]any{"names": map[string]any{"en": "Ha Noi"}}},
+	})
+	asn := data.encode(map[string]any{
+		"autonomous_system_number":       uint32(64500),
+		"autonomous_system_organization": pointerTo(org),
+		"is_anycast":                     true,
+		"offset":                         int32(-7),
+	})
+
+	for _, recordSize := range []int{24, 28, 32} {
+		buf := buildDatabase(t, 6, recordSize, map[netip.Prefix]int{
+			netip.MustParsePrefix("203.0.113.0/24"): city,
+			netip.MustParsePrefix("2001:db8::/32"):  asn,
+		}, data.buf)
+		r, err := FromBytes(buf)
+		if err != nil {
+			t.Fatalf("record size %d: unexpected error: %v", recordSize, err)
+		}
+		if m := r.Metadata(); m.DatabaseType != "Test-City" || m.RecordSize != uint64(recordSize) || len(m.Languages) != 2 {
+			t.Fatalf("record size %d: unexpected metadata %+v", recordSize, m)
+		}
+
+		for _, ip := range []string{"203.0.113.9", "::ffff:203.0.113.200"} {
+			record, err := r.Lookup(netip.MustParseAddr(ip))
+			if err != nil || Get(record, "subdivisions", 0, "names", "en") != "Ha Noi" ||
+				Get(record, "country", "iso_code") != "VN" || Get(record, "location", "latitude") != 21.0292 {
+				t.Fatalf("record size %d: unexpected record for %s: %v %v", recordSize, ip, record, err)
+			}
+		}
+		record, err := r.Lookup(netip.MustParseAddr("2001:db8:1::7"))
+		if err != nil || Get(record, "autonomous_system_number") != uint64(64500) ||
+			Get(record, "autonomous_system_organization") != "An organisation whose name needs the extended size byte" ||
+			Get(record, "is_anycast") != true || Get(record, "offset") != int64(-7) {
+			t.Fatalf("record size %d: unexpected record %v %v", recordSize, record, err)
+		}
+		for _, ip := range []string{"203.0.114.1", "2001:db9::1", "::1"} {
+			if record, err := r.Lookup(netip.MustParseAddr(ip)); record != nil || err != nil {
+				t.Fatalf("record size %d: expected no record for %s, got %v %v", recordSize, ip, record, err)
+			}
+		}
+	}
+
+	r, err := FromBytes(buildDatabase(t, 4, 24, map[netip.Prefix]int{netip.MustParsePrefix("203.0.113.0/24"): city}, data.buf))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record, err := r.Lookup(netip.MustParseAddr("203.0.113.9")); err != nil || Get(record, "city", "names", "en") != "Hanoi" {
+		t.Fatalf("unexpected record %v %v", record, err)
+	}
+	if record, err := r.Lookup(netip.MustParseAddr("2001:db8::1")); record != nil || err != nil {
+		t.Fatalf("expected IPv6 to be absent from an IPv4 database, got %v %v", record, err)
+	}
+}
+
+func TestReader_Invalid(t *testing.T) {
+	if _, err := FromBytes([]byte("not a database")); !errors.Is(err, ErrInvalidDatabase) {
+		t.Fatalf("expected ErrInvalidDatabase, got %v", err)
+	}
+
+	// A record pointing past the data section is refused instead of read out of bounds
+	buf := buildDatabase(t, 4, 24, map[netip.Prefix]int{netip.MustParsePrefix("203.0.113.0/24"): 1000}, []byte{0x40})
+	r, err := FromBytes(buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := r.Lookup(netip.MustParseAddr("203.0.113.9")); !errors.Is(err, ErrInvalidDatabase) {
+		t.Fatalf("expected ErrInvalidDatabase, got %v", err)
+	}
+}
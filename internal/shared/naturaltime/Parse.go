@@ -0,0 +1,566 @@
+// Package naturaltime reads times written the way people type them in English and Vietnamese,
+// such as "2h", "in 10 minutes", "tomorrow 9:00", "8h tối mai" or "thứ 6 lúc 17h30"
+package naturaltime
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNoTime is returned when the text does not start with a time
+	ErrNoTime = errors.New("no time found")
+	// ErrPast is returned for a time which already passed
+	ErrPast = errors.New("time is in the past")
+)
+
+// defaultHour is the time of a day named without a time of day
+const defaultHour = 9
+
+// period is a part of the day which turns "8" into 8:00 or 20:00
+type period int
+
+const (
+	periodNone period = iota
+	periodAM
+	periodPM
+	periodMorning
+	periodNoon
+	periodAfternoon
+	periodEvening
+	periodNight
+)
+
+// periodHours is the time of a part of the day named without a time of day
+var periodHours = map[period]int{
+	periodMorning:   8,
+	periodNoon:      12,
+	periodAfternoon: 15,
+	periodEvening:   20,
+	periodNight:     22,
+}
+
+var periodWords = map[string]period{
+	"am": periodAM, "pm": periodPM,
+	"morning": periodMorning, "noon": periodNoon, "afternoon": periodAfternoon,
+	"evening": periodEvening, "night": periodNight,
+	"sang": periodMorning, "trua": periodNoon, "chieu": periodAfternoon, "toi": periodEvening, "dem": periodNight,
+}
+
+// dayWords are the days named relative to today, multi-word ones written with spaces
+var dayWords = map[string]int{
+	"today": 0, "tomorrow": 1,
+	"hom nay": 0, "mai": 1, "ngay mai": 1, "ngay kia": 2, "mot": 2,
+}
+
+// weekdayWords leave out the abbreviations, "sun" or "sat" are more often words of the reminder
+var weekdayWords = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+	"chu nhat": time.Sunday, "cn": time.Sunday,
+}
+
+// vietnameseWeekdays follow "thứ": thứ 2 or thứ hai is Monday
+var vietnameseWeekdays = map[string]time.Weekday{
+	"2": time.Monday, "3": time.Tuesday, "4": time.Wednesday, "5": time.Thursday, "6": time.Friday, "7": time.Saturday,
+	"hai": time.Monday, "ba": time.Tuesday, "tu": time.Wednesday, "nam": time.Thursday, "sau": time.Friday, "bay": time.Saturday,
+}
+
+var durationUnits = map[string]time.Duration{
+	"m": time.Minute, "min": time.Minute, "mins": time.Minute, "minute": time.Minute, "minutes": time.Minute,
+	"p": time.Minute, "phut": time.Minute,
+	"h": time.Hour, "hr": time.Hour, "hrs": time.Hour, "hour": time.Hour, "hours": time.Hour,
+	"gio": time.Hour, "tieng": time.Hour,
+	"d": 24 * time.Hour, "day": 24 * time.Hour, "days": 24 * time.Hour, "ngay": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour, "week": 7 * 24 * time.Hour, "weeks": 7 * 24 * time.Hour, "tuan": 7 * 24 * time.Hour,
+}
+
+var (
+	durationPart = regexp.MustCompile(`(\d+)([a-z]+)`)
+	durationHM   = regexp.MustCompile(`^(\d+)[hg](\d{2})$`)
+	clockColon   = regexp.MustCompile(`^(\d{1,2}):(\d{2})(am|pm)?$`)
+	clockH       = regexp.MustCompile(`^(\d{1,2})[hg](\d{2})?$`)
+	clockAMPM    = regexp.MustCompile(`^(\d{1,2})(am|pm)$`)
+	dateISO      = regexp.MustCompile(`^(\d{4})-(\d{1,2})-(\d{1,2})$`)
+	dateDMY      = regexp.MustCompile(`^(\d{1,2})/(\d{1,2})(?:/(\d{2}|\d{4}))?$`)
+	number       = regexp.MustCompile(`^\d{1,2}$`)
+	word         = regexp.MustCompile(`\S+`)
+)
+
+// token is a word of the text folded to lower case ASCII, raw keeps its diacritics
+type token struct {
+	word string
+	raw  string
+	end  int
+}
+
+// parser accumulates what the tokens read so far say about the time
+type parser struct {
+	tokens []token
+	pos    int
+	now    time.Time
+
+	relative bool
+	offset   time.Duration
+
+	day          *time.Time // midnight of the day named
+	yearless     bool       // the day was written without its year
+	hour, minute int
+	clock        bool
+	period       period
+}
+
+// Parse reads the time at the start of text and returns the instant it names in the location of now,
+// with the rest of the text. A time of day without a day is the next one, a day without a time of day
+// is at 9:00. It returns ErrNoTime when text does not start with a time and ErrPast for a past time.
+func Parse(text string, now time.Time) (time.Time, string, error) {
+	p := &parser{now: now}
+	for _, loc := range word.FindAllStringIndex(text, -1) {
+		raw := strings.ToLower(strings.TrimRight(text[loc[0]:loc[1]], ",.;!?"))
+		p.tokens = append(p.tokens, token{word: fold(raw), raw: raw, end: loc[1]})
+	}
+
+	for p.pos < len(p.tokens) && p.step() {
+	}
+	if p.pos == 0 {
+		return time.Time{}, text, ErrNoTime
+	}
+	rest := strings.TrimSpace(text[p.tokens[p.pos-1].end:])
+
+	at, err := p.resolve()
+	if err != nil {
+		return time.Time{}, text, err
+	}
+	return at, rest, nil
+}
+
+// step reads the next part of the time, it reports false when the next token is not part of it
+func (p *parser) step() bool {
+	absolute := p.day != nil || p.clock || p.period != periodNone
+	// After a day, "9h" is a time of day rather than nine hours
+	if absolute && !p.relative && p.readClock() {
+		return true
+	}
+	if !absolute && p.readRelative() {
+		return true
+	}
+	if p.relative {
+		return false
+	}
+	return p.readDay() || p.readPeriod() || p.readWeekday() || p.readDate() || p.readClock()
+}
+
+// readRelative reads "2h", "1h30m", "2h30", "10 phút nữa", "in 2 hours" or "sau 3 ngày"
+func (p *parser) readRelative() bool {
+	intro := p.is(0, "in", "sau", "trong")
+	start := 0
+	if intro {
+		start = 1
+	}
+	d, n, ok := p.duration(start, intro)
+	if !ok {
+		return false
+	}
+	p.pos += start + n
+	if p.is(0, "nua", "later") {
+		p.pos++
+	}
+	p.relative, p.offset = true, p.offset+d
+	return true
+}
+
+// duration reads a duration at offset i, "9 giờ" reads as a time of day unless intro or "nữa" says otherwise
+func (p *parser) duration(i int, intro bool) (time.Duration, int, bool) {
+	first := p.at(i)
+	if first == nil {
+		return 0, 0, false
+	}
+	// A following part of the day makes "2h chiều" a time of day
+	if p.isPeriod(i+1) && !intro {
+		return 0, 0, false
+	}
+
+	// "2h30" is two and a half hours like "2h" is two hours, "lúc 2h30" is the time of day
+	if m := durationHM.FindStringSubmatch(first.word); m != nil {
+		hours, _ := strconv.Atoi(m[1])
+		minutes, _ := strconv.Atoi(m[2])
+		if minutes > 59 || hours+minutes == 0 {
+			return 0, 0, false
+		}
+		return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute, 1, true
+	}
+
+	if parts := durationPart.FindAllStringSubmatch(first.word, -1); parts != nil {
+		var total time.Duration
+		length := 0
+		for _, part := range parts {
+			unit, ok := durationUnits[part[2]]
+			if !ok {
+				return 0, 0, false
+			}
+			count, _ := strconv.Atoi(part[1])
+			total += time.Duration(count) * unit
+			length += len(part[0])
+		}
+		if length == len(first.word) && total > 0 {
+			return total, 1, true
+		}
+		return 0, 0, false
+	}
+
+	second := p.at(i + 1)
+	count, err := strconv.Atoi(first.word)
+	if err != nil || second == nil || count <= 0 {
+		return 0, 0, false
+	}
+	unit, ok := durationUnits[second.word]
+	if !ok || len(second.word) == 1 {
+		return 0, 0, false
+	}
+	if second.word == "gio" && !intro && !p.is(i+2, "nua") {
+		return 0, 0, false
+	}
+	return time.Duration(count) * unit, 2, true
+}
+
+// readDay reads today, tomorrow, hôm nay, mai, ngày mai, ngày kia, or tonight
+func (p *parser) readDay() bool {
+	if p.day != nil {
+		return false
+	}
+	if p.is(0, "tonight") {
+		p.setDay(0)
+		p.period = periodEvening
+		p.pos++
+		return true
+	}
+	for n := 2; n >= 1; n-- {
+		if days, ok := dayWords[p.words(0, n)]; ok {
+			p.setDay(days)
+			p.pos += n
+			return true
+		}
+	}
+	return false
+}
+
+// readPeriod reads a part of the day, "tối nay" and "sáng mai" also name the day
+func (p *parser) readPeriod() bool {
+	if p.period != periodNone || !p.isPeriod(0) || p.at(0).word == "am" || p.at(0).word == "pm" {
+		return false
+	}
+	p.period = periodWords[p.at(0).word]
+	p.pos++
+	if p.day == nil {
+		switch {
+		case p.is(0, "nay"):
+			p.setDay(0)
+			p.pos++
+		case p.is(0, "mai"):
+			p.setDay(1)
+			p.pos++
+		}
+	}
+	return true
+}
+
+// readWeekday reads "friday", "next friday", "thứ 6", "thứ sáu tới" or "chủ nhật", the next one after today
+func (p *parser) readWeekday() bool {
+	if p.day != nil {
+		return false
+	}
+	i := 0
+	if p.is(0, "next", "on") {
+		i = 1
+	}
+
+	weekday, n, found := time.Sunday, 0, false
+	if p.is(i, "thu") {
+		weekday, found = vietnameseWeekdays[p.words(i+1, 1)]
+		n = 2
+	}
+	for length := 2; length >= 1 && !found; length-- {
+		weekday, found = weekdayWords[p.words(i, length)]
+		n = length
+	}
+	if !found {
+		return false
+	}
+
+	today := p.today()
+	days := (int(weekday) - int(today.Weekday()) + 7) % 7
+	if days == 0 {
+		days = 7
+	}
+	p.setDay(days)
+	p.pos += i + n
+	// "tới" and "này" only insist it is the coming one
+	if p.at(0) != nil && (p.at(0).raw == "tới" || p.at(0).raw == "này") {
+		p.pos++
+	}
+	return true
+}
+
+// readDate reads 2026-10-20, 20/10 or 20/10/2026, optionally after "on" or "ngày"
+func (p *parser) readDate() bool {
+	if p.day != nil {
+		return false
+	}
+	i := 0
+	if p.is(0, "on", "ngay") {
+		i = 1
+	}
+	next := p.at(i)
+	if next == nil {
+		return false
+	}
+
+	var year, month, day int
+	yearless := false
+	if m := dateISO.FindStringSubmatch(next.word); m != nil {
+		year, _ = strconv.Atoi(m[1])
+		month, _ = strconv.Atoi(m[2])
+		day, _ = strconv.Atoi(m[3])
+	} else if m := dateDMY.FindStringSubmatch(next.word); m != nil {
+		day, _ = strconv.Atoi(m[1])
+		month, _ = strconv.Atoi(m[2])
+		year = p.now.Year()
+		switch {
+		case m[3] == "":
+			yearless = true
+		case len(m[3]) == 2:
+			year, _ = strconv.Atoi(m[3])
+			year += 2000
+		default:
+			year, _ = strconv.Atoi(m[3])
+		}
+	} else {
+		return false
+	}
+
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, p.now.Location())
+	if date.Day() != day || int(date.Month()) != month {
+		return false // 31/02 and the like
+	}
+	p.day, p.yearless = &date, yearless
+	p.pos += i + 1
+	return true
+}
+
+// readClock reads 9:30, 21:30, 9h, 9h30, 9am, 9:30pm, 9 giờ, 9 giờ 30, 9 giờ rưỡi, or after "at",
+// "lúc" or "vào lúc" a bare hour, then an optional part of the day
+func (p *parser) readClock() bool {
+	if p.clock {
+		return false
+	}
+	i := 0
+	switch {
+	case p.is(0, "vao") && p.is(1, "luc"):
+		i = 2
+	case p.is(0, "at", "luc", "vao"):
+		i = 1
+	}
+	next := p.at(i)
+	if next == nil {
+		return false
+	}
+
+	hour, minute, n := -1, 0, 1
+	var suffix period
+	if m := clockColon.FindStringSubmatch(next.word); m != nil {
+		hour, _ = strconv.Atoi(m[1])
+		minute, _ = strconv.Atoi(m[2])
+		suffix = periodWords[m[3]]
+	} else if m := clockH.FindStringSubmatch(next.word); m != nil {
+		// "2h" and "2h30" alone are durations, unless the context makes them a time of day
+		absolute := p.day != nil || p.period != periodNone || i > 0 || p.isPeriod(i+1)
+		if !absolute {
+			return false
+		}
+		hour, _ = strconv.Atoi(m[1])
+		if m[2] != "" {
+			minute, _ = strconv.Atoi(m[2])
+		}
+	} else if m := clockAMPM.FindStringSubmatch(next.word); m != nil {
+		hour, _ = strconv.Atoi(m[1])
+		suffix = periodWords[m[2]]
+	} else if number.MatchString(next.word) {
+		hour, _ = strconv.Atoi(next.word)
+		switch {
+		case p.is(i+1, "gio"):
+			n = 2
+			if p.is(i+2, "ruoi") {
+				minute, n = 30, 3
+			} else if after := p.at(i + 2); after != nil && number.MatchString(after.word) {
+				minute, _ = strconv.Atoi(after.word)
+				n = 3
+				if p.is(i+3, "phut") {
+					n = 4
+				}
+			}
+		case p.is(i+1, "am", "pm"):
+			suffix, n = periodWords[p.at(i+1).word], 2
+		case i == 0 && !p.isPeriod(1):
+			return false // a bare number is a time of day only after "at" or "lúc"
+		}
+	} else {
+		return false
+	}
+	if hour > 23 || minute > 59 || (suffix != periodNone && (hour < 1 || hour > 12)) {
+		return false
+	}
+
+	p.hour, p.minute, p.clock = hour, minute, true
+	if suffix != periodNone {
+		p.period = suffix
+	}
+	p.pos += i + n
+	// "9h tối", "9pm tonight", "8h sáng mai"
+	if suffix == periodNone && p.period == periodNone && p.isPeriod(0) {
+		p.readPeriod()
+	}
+	if p.is(0, "am", "pm") && p.period == periodNone {
+		p.period = periodWords[p.at(0).word]
+		p.pos++
+	}
+	return true
+}
+
+// resolve turns what was read into an instant
+func (p *parser) resolve() (time.Time, error) {
+	if p.relative {
+		return p.now.Add(p.offset), nil
+	}
+
+	day := p.today()
+	if p.day != nil {
+		day = *p.day
+	}
+	hour, minute := defaultHour, 0
+	if h, ok := periodHours[p.period]; ok {
+		hour = h
+	}
+	if p.clock {
+		hour, minute = adjustHour(p.hour, p.period), p.minute
+	}
+
+	at := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, p.now.Location())
+	switch {
+	case at.After(p.now):
+		return at, nil
+	case p.day == nil:
+		// A time of day already passed today is tomorrow
+		return time.Date(day.Year(), day.Month(), day.Day()+1, hour, minute, 0, 0, p.now.Location()), nil
+	case p.yearless:
+		return time.Date(day.Year()+1, day.Month(), day.Day(), hour, minute, 0, 0, p.now.Location()), nil
+	default:
+		return time.Time{}, ErrPast
+	}
+}
+
+// adjustHour moves the hour written on a 12-hour clock to the part of the day
+func adjustHour(hour int, period period) int {
+	switch period {
+	case periodAM:
+		if hour == 12 {
+			return 0
+		}
+	case periodPM, periodAfternoon, periodEvening:
+		if hour < 12 {
+			return hour + 12
+		}
+	case periodNoon:
+		// "1 giờ trưa" is 13:00, "11 giờ trưa" stays 11:00
+		if hour < 6 {
+			return hour + 12
+		}
+	case periodNight:
+		// "11 giờ đêm" is 23:00, "2 giờ đêm" is 2:00
+		if hour >= 6 && hour < 12 {
+			return hour + 12
+		}
+	}
+	return hour
+}
+
+// setDay names the day days after today
+func (p *parser) setDay(days int) {
+	today := p.today()
+	day := time.Date(today.Year(), today.Month(), today.Day()+days, 0, 0, 0, 0, p.now.Location())
+	p.day = &day
+}
+
+// today returns midnight of the day of now
+func (p *parser) today() time.Time {
+	return time.Date(p.now.Year(), p.now.Month(), p.now.Day(), 0, 0, 0, 0, p.now.Location())
+}
+
+// at returns the token i after the position, nil past the end
+func (p *parser) at(i int) *token {
+	if p.pos+i >= len(p.tokens) {
+		return nil
+	}
+	return &p.tokens[p.pos+i]
+}
+
+// is reports whether the token i after the position is one of words
+func (p *parser) is(i int, words ...string) bool {
+	t := p.at(i)
+	if t == nil {
+		return false
+	}
+	for _, w := range words {
+		if t.word == w {
+			return true
+		}
+	}
+	return false
+}
+
+// isPeriod reports whether the token i after the position names a part of the day
+func (p *parser) isPeriod(i int) bool {
+	t := p.at(i)
+	if t == nil {
+		return false
+	}
+	_, ok := periodWords[t.word]
+	// "tới" folds to "toi" but means "coming", not the evening
+	return ok && t.raw != "tới"
+}
+
+// words joins the n tokens from the token i after the position, empty past the end
+func (p *parser) words(i, n int) string {
+	if p.pos+i+n > len(p.tokens) {
+		return ""
+	}
+	parts := make([]string, n)
+	for j := range parts {
+		parts[j] = p.tokens[p.pos+i+j].word
+	}
+	return strings.Join(parts, " ")
+}
+
+// vietnamese maps the letters with diacritics to their base letter
+var vietnamese = strings.NewReplacer(
+	"à", "a", "á", "a", "ả", "a", "ã", "a", "ạ", "a",
+	"ă", "a", "ằ", "a", "ắ", "a", "ẳ", "a", "ẵ", "a", "ặ", "a",
+	"â", "a", "ầ", "a", "ấ", "a", "ẩ", "a", "ẫ", "a", "ậ", "a",
+	"è", "e", "é", "e", "ẻ", "e", "ẽ", "e", "ẹ", "e",
+	"ê", "e", "ề", "e", "ế", "e", "ể", "e", "ễ", "e", "ệ", "e",
+	"ì", "i", "í", "i", "ỉ", "i", "ĩ", "i", "ị", "i",
+	"ò", "o", "ó", "o", "ỏ", "o", "õ", "o", "ọ", "o",
+	"ô", "o", "ồ", "o", "ố", "o", "ổ", "o", "ỗ", "o", "ộ", "o",
+	"ơ", "o", "ờ", "o", "ớ", "o", "ở", "o", "ỡ", "o", "ợ", "o",
+	"ù", "u", "ú", "u", "ủ", "u", "ũ", "u", "ụ", "u",
+	"ư", "u", "ừ", "u", "ứ", "u", "ử", "u", "ữ", "u", "ự", "u",
+	"ỳ", "y", "ý", "y", "ỷ", "y", "ỹ", "y", "ỵ", "y",
+	"đ", "d",
+)
+
+// fold removes the Vietnamese diacritics so that "tối" and "toi" read the same
+func fold(s string) string {
+	return vietnamese.Replace(s)
+}
@@ -0,0 +1,83 @@
+package naturaltime
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	hcm, err := time.LoadLocation("Asia/Ho_Chi_Minh")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	// Monday 2026-10-19 08:15 in Ho Chi Minh City
+	now := time.Date(2026, 10, 19, 8, 15, 0, 0, hcm)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 10, day, hour, minute, 0, 0, hcm)
+	}
+
+	cases := []struct {
+		text string
+		want time.Time
+		rest string
+	}{
+		{"2h check router", now.Add(2 * time.Hour), "check router"},
+		{"1h30m gọi lại", now.Add(90 * time.Minute), "gọi lại"},
+		{"2h30 uống thuốc", now.Add(150 * time.Minute), "uống thuốc"},
+		{"sau 1g05 gọi lại", now.Add(65 * time.Minute), "gọi lại"},
+		{"lúc 2h30 uống thuốc", at(20, 2, 30), "uống thuốc"},
+		{"2h30 chiều uống thuốc", at(19, 14, 30), "uống thuốc"},
+		{"mai 2h30 uống thuốc", at(20, 2, 30), "uống thuốc"},
+		{"in 10 minutes stand up", now.Add(10 * time.Minute), "stand up"},
+		{"10 phút nữa uống thuốc", now.Add(10 * time.Minute), "uống thuốc"},
+		{"sau 3 ngày gia hạn", now.Add(72 * time.Hour), "gia hạn"},
+		{"2 tiếng nữa tắt máy", now.Add(2 * time.Hour), "tắt máy"},
+		{"tomorrow 9:00 renew domain", at(20, 9, 0), "renew domain"},
+		{"tomorrow at 9pm call mom", at(20, 21, 0), "call mom"},
+		{"tomorrow renew domain", at(20, 9, 0), "renew domain"},
+		{"tonight backup", at(19, 20, 0), "backup"},
+		{"7:30 daily", at(20, 7, 30), "daily"},
+		{"at 17 họp", at(19, 17, 0), "họp"},
+		{"mai 9h đóng tiền điện", at(20, 9, 0), "đóng tiền điện"},
+		{"8h tối mai xem bóng đá", at(20, 20, 0), "xem bóng đá"},
+		{"toi nay 10 gio goi dien", at(19, 22, 0), "goi dien"},
+		{"sáng mai họp", at(20, 8, 0), "họp"},
+		{"lúc 15h30 đón con", at(19, 15, 30), "đón con"},
+		{"2 giờ chiều nộp báo cáo", at(19, 14, 0), "nộp báo cáo"},
+		{"9 giờ rưỡi tối nay gọi", at(19, 21, 30), "gọi"},
+		{"thứ 6 lúc 17h30 nhậu", at(23, 17, 30), "nhậu"},
+		{"thứ hai tới họp", at(26, 9, 0), "họp"},
+		{"chủ nhật 10:00 đi chợ", at(25, 10, 0), "đi chợ"},
+		{"next friday 6pm demo", at(23, 18, 0), "demo"},
+		{"friday demo", at(23, 9, 0), "demo"},
+		{"25/12 Giáng sinh", time.Date(2026, 12, 25, 9, 0, 0, 0, hcm), "Giáng sinh"},
+		{"ngày 1/1 10h năm mới", time.Date(2027, 1, 1, 10, 0, 0, 0, hcm), "năm mới"},
+		{"2026-11-02 14:00 kick-off", time.Date(2026, 11, 2, 14, 0, 0, 0, hcm), "kick-off"},
+		{"18/10 sinh nhật", time.Date(2027, 10, 18, 9, 0, 0, 0, hcm), "sinh nhật"},
+	}
+	for _, c := range cases {
+		got, rest, err := Parse(c.text, now)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", c.text, err)
+			continue
+		}
+		if !got.Equal(c.want) || rest != c.rest {
+			t.Errorf("Parse(%q) = %v, %q, want %v, %q", c.text, got, rest, c.want, c.rest)
+		}
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	now := time.Date(2026, 10, 19, 8, 15, 0, 0, time.UTC)
+	for _, text := range []string{"", "check router", "at the office", "31/02 nothing"} {
+		if _, _, err := Parse(text, now); !errors.Is(err, ErrNoTime) {
+			t.Errorf("Parse(%q) = %v, want ErrNoTime", text, err)
+		}
+	}
+	for _, text := range []string{"today 7:00 late", "2026-10-01 too late"} {
+		if _, _, err := Parse(text, now); !errors.Is(err, ErrPast) {
+			t.Errorf("Parse(%q) = %v, want ErrPast", text, err)
+		}
+	}
+}
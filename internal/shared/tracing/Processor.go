@@ -0,0 +1,125 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Exporter sends finished spans to a tracing backend
+type Exporter interface {
+	Export(ctx context.Context, spans []*SpanData) error
+	Shutdown(ctx context.Context) error
+}
+
+// SpanProcessor receives every span when it ends
+type SpanProcessor interface {
+	OnEnd(span *SpanData)
+	Shutdown(ctx context.Context) error
+}
+
+// ErrorHandler is notified when an export fails
+type ErrorHandler func(err error)
+
+const (
+	defaultBatchSize     = 256
+	defaultQueueSize     = 2048
+	defaultFlushInterval = 5 * time.Second
+	defaultExportTimeout = 10 * time.Second
+)
+
+// BatchProcessor buffers finished spans and exports them in batches from a background goroutine.
+// Spans are dropped rather than blocking the update pipeline when the queue is full.
+type BatchProcessor struct {
+	exporter      Exporter
+	onError       ErrorHandler
+	batchSize     int
+	flushInterval time.Duration
+
+	queue    chan *SpanData
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewBatchProcessor starts a batch processor exporting through exporter
+func NewBatchProcessor(exporter Exporter, flushInterval time.Duration, onError ErrorHandler) *BatchProcessor {
+	if flushInterval <= 0 {
+		flushInterval = defaultFlushInterval
+	}
+	p := &BatchProcessor{
+		exporter:      exporter,
+		onError:       onError,
+		batchSize:     defaultBatchSize,
+		flushInterval: flushInterval,
+		queue:         make(chan *SpanData, defaultQueueSize),
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+// OnEnd queues a span for export
+func (p *BatchProcessor) OnEnd(span *SpanData) {
+	select {
+	case <-p.stop:
+	case p.queue <- span:
+	default:
+		// queue full, drop the span
+	}
+}
+
+// Shutdown exports the queued spans and stops the exporter
+func (p *BatchProcessor) Shutdown(ctx context.Context) error {
+	p.stopOnce.Do(func() { close(p.stop) })
+
+	select {
+	case <-p.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return p.exporter.Shutdown(ctx)
+}
+
+func (p *BatchProcessor) run() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*SpanData, 0, p.batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), defaultExportTimeout)
+		if err := p.exporter.Export(ctx, batch); err != nil && p.onError != nil {
+			p.onError(err)
+		}
+		cancel()
+		batch = make([]*SpanData, 0, p.batchSize)
+	}
+
+	for {
+		select {
+		case span := <-p.queue:
+			batch = append(batch, span)
+			if len(batch) >= p.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-p.stop:
+			for {
+				select {
+				case span := <-p.queue:
+					batch = append(batch, span)
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
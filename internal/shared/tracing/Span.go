@@ -0,0 +1,211 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// TraceID identifies a trace, it is shared by every span of one update
+type TraceID [16]byte
+
+// SpanID identifies a single span within a trace
+type SpanID [8]byte
+
+// String returns the lowercase hex encoding used by OTLP and in log lines
+func (t TraceID) String() string { return hex.EncodeToString(t[:]) }
+
+// IsValid reports whether the ID is non-zero
+func (t TraceID) IsValid() bool { return t != TraceID{} }
+
+// String returns the lowercase hex encoding used by OTLP and in log lines
+func (s SpanID) String() string { return hex.EncodeToString(s[:]) }
+
+// IsValid reports whether the ID is non-zero
+func (s SpanID) IsValid() bool { return s != SpanID{} }
+
+// StatusCode is the outcome of a span
+type StatusCode int
+
+const (
+	StatusUnset StatusCode = iota
+	StatusOK
+	StatusError
+)
+
+// Attribute is a key-value pair attached to spans and events
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// String creates a string attribute
+func String(key, value string) Attribute { return Attribute{Key: key, Value: value} }
+
+// Int64 creates an integer attribute
+func Int64(key string, value int64) Attribute { return Attribute{Key: key, Value: value} }
+
+// Int creates an integer attribute
+func Int(key string, value int) Attribute { return Attribute{Key: key, Value: int64(value)} }
+
+// Bool creates a boolean attribute
+func Bool(key string, value bool) Attribute { return Attribute{Key: key, Value: value} }
+
+// Float64 creates a floating point attribute
+func Float64(key string, value float64) Attribute { return Attribute{Key: key, Value: value} }
+
+// Event is a timestamped annotation on a span, such as a retry
+type Event struct {
+	Name       string
+	Time       time.Time
+	Attributes []Attribute
+}
+
+// SpanData is the immutable view of a finished span handed to exporters
+type SpanData struct {
+	Name          string
+	TraceID       TraceID
+	SpanID        SpanID
+	ParentSpanID  SpanID
+	StartTime     time.Time
+	EndTime       time.Time
+	Attributes    []Attribute
+	Events        []Event
+	StatusCode    StatusCode
+	StatusMessage string
+}
+
+// Span is an operation in progress, all methods are safe to call on a nil span
+type Span struct {
+	tracer *Tracer
+	root   *Span
+
+	mu    sync.Mutex
+	data  SpanData
+	ended bool
+}
+
+// SetAttributes adds or replaces attributes on the span
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, attr := range attrs {
+		replaced := false
+		for i := range s.data.Attributes {
+			if s.data.Attributes[i].Key == attr.Key {
+				s.data.Attributes[i].Value = attr.Value
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			s.data.Attributes = append(s.data.Attributes, attr)
+		}
+	}
+}
+
+// AddEvent records a named event at the current time
+func (s *Span) AddEvent(name string, attrs ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Events = append(s.data.Events, Event{Name: name, Time: time.Now(), Attributes: attrs})
+}
+
+// RecordError marks the span as failed and records the error as an event
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.AddEvent("exception", String("exception.message", err.Error()))
+	s.SetStatus(StatusError, err.Error())
+}
+
+// SetStatus sets the outcome of the span
+func (s *Span) SetStatus(code StatusCode, message string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.StatusCode = code
+	s.data.StatusMessage = message
+}
+
+// End finishes the span and hands it to the tracer's processor, later calls are ignored
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.EndTime = time.Now()
+	data := s.data
+	s.mu.Unlock()
+
+	s.tracer.onEnd(&data)
+}
+
+// Root returns the first span of the trace started in this process
+func (s *Span) Root() *Span {
+	if s == nil {
+		return nil
+	}
+	return s.root
+}
+
+// TraceID returns the trace the span belongs to
+func (s *Span) TraceID() TraceID {
+	if s == nil {
+		return TraceID{}
+	}
+	return s.data.TraceID
+}
+
+// SpanID returns the ID of the span
+func (s *Span) SpanID() SpanID {
+	if s == nil {
+		return SpanID{}
+	}
+	return s.data.SpanID
+}
+
+type spanContextKey struct{}
+
+// ContextWithSpan returns a copy of ctx carrying span as the current span
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, span)
+}
+
+// SpanFromContext returns the current span, or nil when ctx carries none
+func SpanFromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+	span, _ := ctx.Value(spanContextKey{}).(*Span)
+	return span
+}
+
+func newTraceID() TraceID {
+	var id TraceID
+	rand.Read(id[:])
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	rand.Read(id[:])
+	return id
+}
@@ -0,0 +1,98 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// StdoutExporter writes each span as one JSON line, intended for local development
+type StdoutExporter struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// NewStdoutExporter creates an exporter writing to out, or to os.Stdout when out is nil
+func NewStdoutExporter(out io.Writer) *StdoutExporter {
+	if out == nil {
+		out = os.Stdout
+	}
+	return &StdoutExporter{out: out}
+}
+
+type stdoutEvent struct {
+	Name       string         `json:"name"`
+	Time       time.Time      `json:"time"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+type stdoutSpan struct {
+	Name         string         `json:"name"`
+	TraceID      string         `json:"trace_id"`
+	SpanID       string         `json:"span_id"`
+	ParentSpanID string         `json:"parent_span_id,omitempty"`
+	Start        time.Time      `json:"start"`
+	Duration     string         `json:"duration"`
+	Status       string         `json:"status,omitempty"`
+	Error        string         `json:"error,omitempty"`
+	Attributes   map[string]any `json:"attributes,omitempty"`
+	Events       []stdoutEvent  `json:"events,omitempty"`
+}
+
+// Export writes the spans to the output
+func (e *StdoutExporter) Export(ctx context.Context, spans []*SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	encoder := json.NewEncoder(e.out)
+	for _, span := range spans {
+		line := stdoutSpan{
+			Name:       span.Name,
+			TraceID:    span.TraceID.String(),
+			SpanID:     span.SpanID.String(),
+			Start:      span.StartTime,
+			Duration:   span.EndTime.Sub(span.StartTime).String(),
+			Attributes: attributeMap(span.Attributes),
+		}
+		if span.ParentSpanID.IsValid() {
+			line.ParentSpanID = span.ParentSpanID.String()
+		}
+		switch span.StatusCode {
+		case StatusOK:
+			line.Status = "ok"
+		case StatusError:
+			line.Status = "error"
+			line.Error = span.StatusMessage
+		}
+		for _, event := range span.Events {
+			line.Events = append(line.Events, stdoutEvent{
+				Name:       event.Name,
+				Time:       event.Time,
+				Attributes: attributeMap(event.Attributes),
+			})
+		}
+		if err := encoder.Encode(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Shutdown does nothing, the output is not owned by the exporter
+func (e *StdoutExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func attributeMap(attrs []Attribute) map[string]any {
+	if len(attrs) == 0 {
+		return nil
+	}
+	m := make(map[string]any, len(attrs))
+	for _, attr := range attrs {
+		m[attr.Key] = attr.Value
+	}
+	return m
+}
@@ -0,0 +1,72 @@
+package tracing
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// Tracer creates spans and passes finished ones to a processor
+type Tracer struct {
+	processor SpanProcessor
+}
+
+// NewTracer creates a tracer, a nil processor creates spans for correlation without exporting them
+func NewTracer(processor SpanProcessor) *Tracer {
+	return &Tracer{processor: processor}
+}
+
+var global atomic.Pointer[Tracer]
+
+func init() {
+	global.Store(NewTracer(nil))
+}
+
+// SetTracer replaces the process-wide tracer used by Start
+func SetTracer(tracer *Tracer) {
+	if tracer == nil {
+		tracer = NewTracer(nil)
+	}
+	global.Store(tracer)
+}
+
+// Start begins a span as a child of the span in ctx using the process-wide tracer
+func Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, *Span) {
+	return global.Load().Start(ctx, name, attrs...)
+}
+
+// Start begins a span as a child of the span in ctx, or a new trace when ctx carries none
+func (t *Tracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, *Span) {
+	span := &Span{tracer: t}
+	span.data = SpanData{
+		Name:       name,
+		SpanID:     newSpanID(),
+		StartTime:  time.Now(),
+		Attributes: append([]Attribute(nil), attrs...),
+	}
+
+	if parent := SpanFromContext(ctx); parent != nil {
+		span.data.TraceID = parent.data.TraceID
+		span.data.ParentSpanID = parent.data.SpanID
+		span.root = parent.root
+	} else {
+		span.data.TraceID = newTraceID()
+		span.root = span
+	}
+
+	return ContextWithSpan(ctx, span), span
+}
+
+// Shutdown flushes and stops the tracer's processor
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	if t.processor == nil {
+		return nil
+	}
+	return t.processor.Shutdown(ctx)
+}
+
+func (t *Tracer) onEnd(span *SpanData) {
+	if t != nil && t.processor != nil {
+		t.processor.OnEnd(span)
+	}
+}
@@ -0,0 +1,83 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type recordingProcessor struct {
+	spans []*SpanData
+}
+
+func (p *recordingProcessor) OnEnd(span *SpanData)               { p.spans = append(p.spans, span) }
+func (p *recordingProcessor) Shutdown(ctx context.Context) error { return nil }
+
+func TestTracer_ChildSpansShareTraceAndRoot(t *testing.T) {
+	processor := &recordingProcessor{}
+	tracer := NewTracer(processor)
+
+	ctx, root := tracer.Start(context.Background(), "update", Int64("update_id", 1))
+	_, child := tracer.Start(ctx, "handler")
+	child.Root().SetAttributes(String("command", "/start"))
+	child.AddEvent("retry", Int("attempt", 1))
+	child.End()
+	root.End()
+
+	if len(processor.spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(processor.spans))
+	}
+	handler, update := processor.spans[0], processor.spans[1]
+	if handler.TraceID != update.TraceID {
+		t.Fatal("child span has a different trace ID")
+	}
+	if handler.ParentSpanID != update.SpanID {
+		t.Fatal("child span is not parented to the root span")
+	}
+	if len(update.Attributes) != 2 || update.Attributes[1].Value != "/start" {
+		t.Fatalf("root attributes not updated: %+v", update.Attributes)
+	}
+	if len(handler.Events) != 1 || handler.Events[0].Name != "retry" {
+		t.Fatalf("event not recorded: %+v", handler.Events)
+	}
+}
+
+func TestOTLPExporter_PostsJSON(t *testing.T) {
+	var received otlpRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("missing configured header")
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &received); err != nil {
+			t.Errorf("invalid body: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	exporter := NewOTLPExporter(server.URL, "bot", map[string]string{"Authorization": "Bearer secret"})
+	processor := NewBatchProcessor(exporter, time.Hour, nil)
+	tracer := NewTracer(processor)
+
+	_, span := tracer.Start(context.Background(), "sendMessage", Int64("chat_id", 42))
+	span.End()
+
+	if err := tracer.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown failed: %v", err)
+	}
+
+	if len(received.ResourceSpans) != 1 || len(received.ResourceSpans[0].ScopeSpans[0].Spans) != 1 {
+		t.Fatalf("unexpected payload: %+v", received)
+	}
+	got := received.ResourceSpans[0].ScopeSpans[0].Spans[0]
+	if got.Name != "sendMessage" || got.Attributes[0].Value.IntValue == nil || *got.Attributes[0].Value.IntValue != "42" {
+		t.Fatalf("unexpected span: %+v", got)
+	}
+}
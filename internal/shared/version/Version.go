@@ -0,0 +1,9 @@
+package version
+
+// Build information, overridden at link time:
+//
+//	go build -ldflags "-X go-telegram-bot/internal/shared/version.Version=1.2.3 -X go-telegram-bot/internal/shared/version.Commit=abc123"
+var (
+	Version = "dev"
+	Commit  = "unknown"
+)